@@ -16,6 +16,8 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 */
 package datetime
 
+import "context"
+
 type FromUnixTimestampOutput struct {
 	UnixTimestamp int64
 	UtcTimestamp  string
@@ -23,4 +25,5 @@ type FromUnixTimestampOutput struct {
 
 type Interface interface {
 	FromUnitTimestamp(unixTime int64) FromUnixTimestampOutput
+	FromUnitTimestampContext(ctx context.Context, unixTime int64) (FromUnixTimestampOutput, error)
 }
@@ -2,7 +2,11 @@
 
 package datetime
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // MockInterface is an autogenerated mock type for the Interface type
 type MockInterface struct {
@@ -22,3 +26,24 @@ func (_m *MockInterface) FromUnitTimestamp(unixTime int64) FromUnixTimestampOutp
 
 	return r0
 }
+
+// FromUnitTimestampContext provides a mock function with given fields: ctx, unixTime
+func (_m *MockInterface) FromUnitTimestampContext(ctx context.Context, unixTime int64) (FromUnixTimestampOutput, error) {
+	ret := _m.Called(ctx, unixTime)
+
+	var r0 FromUnixTimestampOutput
+	if rf, ok := ret.Get(0).(func(context.Context, int64) FromUnixTimestampOutput); ok {
+		r0 = rf(ctx, unixTime)
+	} else {
+		r0 = ret.Get(0).(FromUnixTimestampOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, unixTime)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
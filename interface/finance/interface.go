@@ -16,6 +16,11 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 */
 package finance
 
+import (
+	"context"
+	"time"
+)
+
 type CompoundInterestsDetailOutput struct {
 	FinalAmount        float64
 	TotalContributions float64
@@ -28,10 +33,108 @@ type CompoundInterestsHistoryEntryOutput struct {
 }
 
 type CompoundInterestsOutput struct {
-	Total   CompoundInterestsDetailOutput
-	History []CompoundInterestsHistoryEntryOutput
+	Currency string
+	Total    CompoundInterestsDetailOutput
+	History  []CompoundInterestsHistoryEntryOutput
+}
+
+type CurrencyOutput struct {
+	Code       string
+	Numeric    string
+	Name       string
+	Symbol     string
+	MinorUnits int
+}
+
+type ConvertCurrencyOutput struct {
+	Amount          float64
+	From            string
+	To              string
+	Rate            float64
+	ConvertedAmount float64
+	RatesDate       time.Time
+	RatesFetchedAt  time.Time
+}
+
+type FormatAmountOutput struct {
+	Amount    float64
+	Currency  string
+	Locale    string
+	Formatted string
+}
+
+type FormattedCompoundInterestsDetailOutput struct {
+	FinalAmount        string
+	TotalContributions string
+	Interests          string
+}
+
+type FormattedCompoundInterestsHistoryEntryOutput struct {
+	Period string
+	Totals FormattedCompoundInterestsDetailOutput
+}
+
+type FormattedCompoundInterestsOutput struct {
+	Locale   string
+	Currency string
+	Total    FormattedCompoundInterestsDetailOutput
+	History  []FormattedCompoundInterestsHistoryEntryOutput
+}
+
+// Table is a tabular result to export, e.g. to export other results
+// than the compound interests.
+type Table struct {
+	// Currency is the ISO 4217 code of the amounts, used with a locale
+	Currency string
+	Columns  []TableColumn
+	// Rows have one cell per column
+	Rows [][]TableCell
+}
+
+type TableColumn struct {
+	// Key names the column in the options and in JSON Lines
+	Key    string
+	Header string
+}
+
+// TableCell is a text or, if IsAmount is true, an amount.
+type TableCell struct {
+	Text     string
+	Amount   float64
+	IsAmount bool
+}
+
+type ExportOptions struct {
+	// Format is one of csv, tsv, markdown, jsonl or xlsx
+	Format string
+	// Columns selects and orders the exported columns, all if empty
+	Columns []string
+	// Locale formats the amounts, e.g. pt-PT, raw numbers if empty
+	Locale string
+}
+
+type ExportOutput struct {
+	Format        string
+	ContentType   string
+	FileExtension string
+	Data          []byte
 }
 
 type Interface interface {
 	CalculateCompoundInterests(p, n, t, m, y, rInt float64) (CompoundInterestsOutput, error)
+	CalculateCompoundInterestsContext(ctx context.Context, p, n, t, m, y, rInt float64) (CompoundInterestsOutput, error)
+	GetCurrency(code string) (CurrencyOutput, error)
+	GetCurrencyContext(ctx context.Context, code string) (CurrencyOutput, error)
+	ConvertCurrency(amount float64, from, to string) (ConvertCurrencyOutput, error)
+	ConvertCurrencyContext(ctx context.Context, amount float64, from, to string) (ConvertCurrencyOutput, error)
+	ConvertCompoundInterests(input CompoundInterestsOutput, from, to string) (CompoundInterestsOutput, error)
+	ConvertCompoundInterestsContext(ctx context.Context, input CompoundInterestsOutput, from, to string) (CompoundInterestsOutput, error)
+	FormatAmount(amount float64, currency, locale string) (FormatAmountOutput, error)
+	FormatAmountContext(ctx context.Context, amount float64, currency, locale string) (FormatAmountOutput, error)
+	FormatCompoundInterests(input CompoundInterestsOutput, locale string) (FormattedCompoundInterestsOutput, error)
+	FormatCompoundInterestsContext(ctx context.Context, input CompoundInterestsOutput, locale string) (FormattedCompoundInterestsOutput, error)
+	ExportCompoundInterests(input CompoundInterestsOutput, options ExportOptions) (ExportOutput, error)
+	ExportCompoundInterestsContext(ctx context.Context, input CompoundInterestsOutput, options ExportOptions) (ExportOutput, error)
+	ExportTable(input Table, options ExportOptions) (ExportOutput, error)
+	ExportTableContext(ctx context.Context, input Table, options ExportOptions) (ExportOutput, error)
 }
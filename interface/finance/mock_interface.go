@@ -2,7 +2,11 @@
 
 package finance
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // MockInterface is an autogenerated mock type for the Interface type
 type MockInterface struct {
@@ -29,3 +33,318 @@ func (_m *MockInterface) CalculateCompoundInterests(p float64, n float64, t floa
 
 	return r0, r1
 }
+
+// CalculateCompoundInterestsContext provides a mock function with given fields: ctx, p, n, t, m, y, rInt
+func (_m *MockInterface) CalculateCompoundInterestsContext(ctx context.Context, p float64, n float64, t float64, m float64, y float64, rInt float64) (CompoundInterestsOutput, error) {
+	ret := _m.Called(ctx, p, n, t, m, y, rInt)
+
+	var r0 CompoundInterestsOutput
+	if rf, ok := ret.Get(0).(func(context.Context, float64, float64, float64, float64, float64, float64) CompoundInterestsOutput); ok {
+		r0 = rf(ctx, p, n, t, m, y, rInt)
+	} else {
+		r0 = ret.Get(0).(CompoundInterestsOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, float64, float64, float64, float64, float64, float64) error); ok {
+		r1 = rf(ctx, p, n, t, m, y, rInt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConvertCompoundInterests provides a mock function with given fields: input, from, to
+func (_m *MockInterface) ConvertCompoundInterests(input CompoundInterestsOutput, from string, to string) (CompoundInterestsOutput, error) {
+	ret := _m.Called(input, from, to)
+
+	var r0 CompoundInterestsOutput
+	if rf, ok := ret.Get(0).(func(CompoundInterestsOutput, string, string) CompoundInterestsOutput); ok {
+		r0 = rf(input, from, to)
+	} else {
+		r0 = ret.Get(0).(CompoundInterestsOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(CompoundInterestsOutput, string, string) error); ok {
+		r1 = rf(input, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConvertCompoundInterestsContext provides a mock function with given fields: ctx, input, from, to
+func (_m *MockInterface) ConvertCompoundInterestsContext(ctx context.Context, input CompoundInterestsOutput, from string, to string) (CompoundInterestsOutput, error) {
+	ret := _m.Called(ctx, input, from, to)
+
+	var r0 CompoundInterestsOutput
+	if rf, ok := ret.Get(0).(func(context.Context, CompoundInterestsOutput, string, string) CompoundInterestsOutput); ok {
+		r0 = rf(ctx, input, from, to)
+	} else {
+		r0 = ret.Get(0).(CompoundInterestsOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, CompoundInterestsOutput, string, string) error); ok {
+		r1 = rf(ctx, input, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConvertCurrency provides a mock function with given fields: amount, from, to
+func (_m *MockInterface) ConvertCurrency(amount float64, from string, to string) (ConvertCurrencyOutput, error) {
+	ret := _m.Called(amount, from, to)
+
+	var r0 ConvertCurrencyOutput
+	if rf, ok := ret.Get(0).(func(float64, string, string) ConvertCurrencyOutput); ok {
+		r0 = rf(amount, from, to)
+	} else {
+		r0 = ret.Get(0).(ConvertCurrencyOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(float64, string, string) error); ok {
+		r1 = rf(amount, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConvertCurrencyContext provides a mock function with given fields: ctx, amount, from, to
+func (_m *MockInterface) ConvertCurrencyContext(ctx context.Context, amount float64, from string, to string) (ConvertCurrencyOutput, error) {
+	ret := _m.Called(ctx, amount, from, to)
+
+	var r0 ConvertCurrencyOutput
+	if rf, ok := ret.Get(0).(func(context.Context, float64, string, string) ConvertCurrencyOutput); ok {
+		r0 = rf(ctx, amount, from, to)
+	} else {
+		r0 = ret.Get(0).(ConvertCurrencyOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, float64, string, string) error); ok {
+		r1 = rf(ctx, amount, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportCompoundInterests provides a mock function with given fields: input, options
+func (_m *MockInterface) ExportCompoundInterests(input CompoundInterestsOutput, options ExportOptions) (ExportOutput, error) {
+	ret := _m.Called(input, options)
+
+	var r0 ExportOutput
+	if rf, ok := ret.Get(0).(func(CompoundInterestsOutput, ExportOptions) ExportOutput); ok {
+		r0 = rf(input, options)
+	} else {
+		r0 = ret.Get(0).(ExportOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(CompoundInterestsOutput, ExportOptions) error); ok {
+		r1 = rf(input, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportCompoundInterestsContext provides a mock function with given fields: ctx, input, options
+func (_m *MockInterface) ExportCompoundInterestsContext(ctx context.Context, input CompoundInterestsOutput, options ExportOptions) (ExportOutput, error) {
+	ret := _m.Called(ctx, input, options)
+
+	var r0 ExportOutput
+	if rf, ok := ret.Get(0).(func(context.Context, CompoundInterestsOutput, ExportOptions) ExportOutput); ok {
+		r0 = rf(ctx, input, options)
+	} else {
+		r0 = ret.Get(0).(ExportOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, CompoundInterestsOutput, ExportOptions) error); ok {
+		r1 = rf(ctx, input, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportTable provides a mock function with given fields: input, options
+func (_m *MockInterface) ExportTable(input Table, options ExportOptions) (ExportOutput, error) {
+	ret := _m.Called(input, options)
+
+	var r0 ExportOutput
+	if rf, ok := ret.Get(0).(func(Table, ExportOptions) ExportOutput); ok {
+		r0 = rf(input, options)
+	} else {
+		r0 = ret.Get(0).(ExportOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(Table, ExportOptions) error); ok {
+		r1 = rf(input, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportTableContext provides a mock function with given fields: ctx, input, options
+func (_m *MockInterface) ExportTableContext(ctx context.Context, input Table, options ExportOptions) (ExportOutput, error) {
+	ret := _m.Called(ctx, input, options)
+
+	var r0 ExportOutput
+	if rf, ok := ret.Get(0).(func(context.Context, Table, ExportOptions) ExportOutput); ok {
+		r0 = rf(ctx, input, options)
+	} else {
+		r0 = ret.Get(0).(ExportOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, Table, ExportOptions) error); ok {
+		r1 = rf(ctx, input, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FormatAmount provides a mock function with given fields: amount, currency, locale
+func (_m *MockInterface) FormatAmount(amount float64, currency string, locale string) (FormatAmountOutput, error) {
+	ret := _m.Called(amount, currency, locale)
+
+	var r0 FormatAmountOutput
+	if rf, ok := ret.Get(0).(func(float64, string, string) FormatAmountOutput); ok {
+		r0 = rf(amount, currency, locale)
+	} else {
+		r0 = ret.Get(0).(FormatAmountOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(float64, string, string) error); ok {
+		r1 = rf(amount, currency, locale)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FormatAmountContext provides a mock function with given fields: ctx, amount, currency, locale
+func (_m *MockInterface) FormatAmountContext(ctx context.Context, amount float64, currency string, locale string) (FormatAmountOutput, error) {
+	ret := _m.Called(ctx, amount, currency, locale)
+
+	var r0 FormatAmountOutput
+	if rf, ok := ret.Get(0).(func(context.Context, float64, string, string) FormatAmountOutput); ok {
+		r0 = rf(ctx, amount, currency, locale)
+	} else {
+		r0 = ret.Get(0).(FormatAmountOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, float64, string, string) error); ok {
+		r1 = rf(ctx, amount, currency, locale)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FormatCompoundInterests provides a mock function with given fields: input, locale
+func (_m *MockInterface) FormatCompoundInterests(input CompoundInterestsOutput, locale string) (FormattedCompoundInterestsOutput, error) {
+	ret := _m.Called(input, locale)
+
+	var r0 FormattedCompoundInterestsOutput
+	if rf, ok := ret.Get(0).(func(CompoundInterestsOutput, string) FormattedCompoundInterestsOutput); ok {
+		r0 = rf(input, locale)
+	} else {
+		r0 = ret.Get(0).(FormattedCompoundInterestsOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(CompoundInterestsOutput, string) error); ok {
+		r1 = rf(input, locale)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FormatCompoundInterestsContext provides a mock function with given fields: ctx, input, locale
+func (_m *MockInterface) FormatCompoundInterestsContext(ctx context.Context, input CompoundInterestsOutput, locale string) (FormattedCompoundInterestsOutput, error) {
+	ret := _m.Called(ctx, input, locale)
+
+	var r0 FormattedCompoundInterestsOutput
+	if rf, ok := ret.Get(0).(func(context.Context, CompoundInterestsOutput, string) FormattedCompoundInterestsOutput); ok {
+		r0 = rf(ctx, input, locale)
+	} else {
+		r0 = ret.Get(0).(FormattedCompoundInterestsOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, CompoundInterestsOutput, string) error); ok {
+		r1 = rf(ctx, input, locale)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCurrency provides a mock function with given fields: code
+func (_m *MockInterface) GetCurrency(code string) (CurrencyOutput, error) {
+	ret := _m.Called(code)
+
+	var r0 CurrencyOutput
+	if rf, ok := ret.Get(0).(func(string) CurrencyOutput); ok {
+		r0 = rf(code)
+	} else {
+		r0 = ret.Get(0).(CurrencyOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCurrencyContext provides a mock function with given fields: ctx, code
+func (_m *MockInterface) GetCurrencyContext(ctx context.Context, code string) (CurrencyOutput, error) {
+	ret := _m.Called(ctx, code)
+
+	var r0 CurrencyOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string) CurrencyOutput); ok {
+		r0 = rf(ctx, code)
+	} else {
+		r0 = ret.Get(0).(CurrencyOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,176 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"fmt"
+	"time"
+)
+
+// InvalidMediumInputError is returned when the input given as a Medium
+// post is neither a post ID nor a URL of a post.
+type InvalidMediumInputError struct {
+	Input  string
+	Reason string
+}
+
+func (e *InvalidMediumInputError) Error() string {
+	return fmt.Sprintf("invalid medium post %q: %s", e.Input, e.Reason)
+}
+
+// InvalidHtmlInputError is returned when the input given as a web page
+// is neither a URL nor HTML, or the URL is not of an HTML page.
+type InvalidHtmlInputError struct {
+	Input  string
+	Reason string
+}
+
+func (e *InvalidHtmlInputError) Error() string {
+	return fmt.Sprintf("invalid web page %q: %s", e.Input, e.Reason)
+}
+
+// InvalidPostInputError is returned when the input given as a post is
+// not from any of the supported sources.
+type InvalidPostInputError struct {
+	Input  string
+	Reason string
+}
+
+func (e *InvalidPostInputError) Error() string {
+	return fmt.Sprintf("invalid post %q: %s", e.Input, e.Reason)
+}
+
+// InvalidUrlError is returned when a URL, or a component of one, can not
+// be parsed or is not valid for the operation.
+type InvalidUrlError struct {
+	Url    string
+	Reason string
+}
+
+func (e *InvalidUrlError) Error() string {
+	return fmt.Sprintf("invalid url %q: %s", e.Url, e.Reason)
+}
+
+// InvalidIpError is returned when an IP address or prefix can not be
+// parsed.
+type InvalidIpError struct {
+	Input  string
+	Reason string
+}
+
+func (e *InvalidIpError) Error() string {
+	return fmt.Sprintf("invalid ip address or prefix %q: %s", e.Input, e.Reason)
+}
+
+// InvalidCertificateError is returned when the input has no certificates
+// or requests, or one of them can not be parsed.
+type InvalidCertificateError struct {
+	Reason string
+}
+
+func (e *InvalidCertificateError) Error() string {
+	return fmt.Sprintf("invalid certificate: %s", e.Reason)
+}
+
+// InvalidEmailError is returned when a message, or one of its parts,
+// can not be parsed.
+type InvalidEmailError struct {
+	Reason string
+}
+
+func (e *InvalidEmailError) Error() string {
+	return fmt.Sprintf("invalid email message: %s", e.Reason)
+}
+
+// PostNotFoundError is returned when a source other than Medium has no
+// post for the input.
+type PostNotFoundError struct {
+	Source string
+	Input  string
+}
+
+func (e *PostNotFoundError) Error() string {
+	return fmt.Sprintf("%s post %q not found", e.Source, e.Input)
+}
+
+// MediumPostNotFoundError is returned when Medium has no post with the
+// ID, or it was deleted.
+type MediumPostNotFoundError struct {
+	PostId string
+}
+
+func (e *MediumPostNotFoundError) Error() string {
+	return fmt.Sprintf("medium post %q not found", e.PostId)
+}
+
+// MediumSourceNotFoundError is returned when Medium has no user or
+// publication with the name.
+type MediumSourceNotFoundError struct {
+	Kind string
+	Name string
+}
+
+func (e *MediumSourceNotFoundError) Error() string {
+	return fmt.Sprintf("medium %s %q not found", e.Kind, e.Name)
+}
+
+// MediumRateLimitedError is returned when Medium keeps rejecting the
+// requests for being too many, even after retrying.
+type MediumRateLimitedError struct {
+	// RetryAfter is how long Medium asked to wait before retrying, zero
+	// if it did not say
+	RetryAfter time.Duration
+}
+
+func (e *MediumRateLimitedError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("medium rate limit exceeded, retry after %s", e.RetryAfter)
+	}
+
+	return "medium rate limit exceeded"
+}
+
+// MediumPaywalledError is returned when the post is only available to
+// Medium members.
+type MediumPaywalledError struct {
+	PostId string
+}
+
+func (e *MediumPaywalledError) Error() string {
+	return fmt.Sprintf("medium post %q is only available to members", e.PostId)
+}
+
+// MediumUpstreamError is returned when Medium fails to answer a query,
+// with an unexpected HTTP status or GraphQL errors.
+type MediumUpstreamError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *MediumUpstreamError) Error() string {
+	return fmt.Sprintf("medium failed with status %d: %s", e.StatusCode, e.Message)
+}
+
+// MediumUnauthorizedError is returned when Medium rejects the token used
+// to publish, because it is missing, invalid or revoked.
+type MediumUnauthorizedError struct {
+	Message string
+}
+
+func (e *MediumUnauthorizedError) Error() string {
+	return fmt.Sprintf("medium rejected the token: %s", e.Message)
+}
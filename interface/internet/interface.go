@@ -16,11 +16,796 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 */
 package internet
 
+import (
+	"context"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/datetime"
+)
+
+type ConvertMediumToMdOptions struct {
+	// FrontMatter is the format of the front matter prepended to the
+	// Markdown: yaml or toml, none if empty
+	FrontMatter string
+	// Flavour is the static site generator the front matter is for: hugo,
+	// jekyll or astro, hugo by default
+	Flavour string
+	// BypassCache gets the post from Medium even if it is cached, the
+	// cache is still updated
+	BypassCache bool
+}
+
+// Cache statuses, telling where a post came from when the service has a
+// cache.
+const (
+	// CacheHit is a post served from the cache without asking Medium
+	CacheHit = "hit"
+	// CacheRevalidated is a post served from the cache after Medium
+	// confirmed it did not change
+	CacheRevalidated = "revalidated"
+	// CacheMiss is a post got from Medium, not cached or changed
+	CacheMiss = "miss"
+	// CacheStale is a post served from the cache, although expired,
+	// because Medium failed
+	CacheStale = "stale"
+	// CacheBypass is a post got from Medium because the cache was
+	// bypassed
+	CacheBypass = "bypass"
+)
+
 type ConvertMediumToMdOutput struct {
+	Markdown     string
+	PostId       string
+	Title        string
+	Subtitle     string
+	Author       string
+	PublishedAt  time.Time
+	UpdatedAt    time.Time
+	Tags         []string
+	CanonicalUrl string
+	ReadingTime  time.Duration
+	PreviewImage string
+	// CacheStatus is one of the cache statuses, empty if the service has
+	// no cache
+	CacheStatus string
+}
+
+type ConvertPostToMdOptions struct {
+	// Source is the platform of the post: medium, devto, hashnode or
+	// substack, or any other added to the service, detected from the URL
+	// if empty
+	Source string
+	// FrontMatter and Flavour are the front matter prepended to the
+	// Markdown, as in ConvertMediumToMdOptions
+	FrontMatter string
+	Flavour     string
+}
+
+type ConvertPostToMdOutput struct {
+	ConvertMediumToMdOutput
+	// Source is the platform the post was got from
+	Source string
+}
+
+type ExportMediumPostsOptions struct {
+	ExportMediumToMdOptions
+	// Parallelism is the number of posts exported concurrently, 2 by
+	// default
+	Parallelism int
+	// RateLimit is the maximum number of posts started per second, up to
+	// 1000, unlimited if zero
+	RateLimit float64
+	// Checkpoint has the posts exported by a previous run, which are
+	// skipped
+	Checkpoint MediumExportCheckpoint
+	// OnResult, if set, is called after each post with its result and the
+	// checkpoint to resume from, so it can be saved
+	OnResult func(result ExportMediumPostsResult, checkpoint MediumExportCheckpoint)
+}
+
+// MediumExportCheckpoint has the progress of a bulk export, to resume it
+// after an interruption.
+type MediumExportCheckpoint struct {
+	Source string `json:"source"`
+	// Done has the IDs of the posts exported
+	Done []string `json:"done"`
+}
+
+type ExportMediumPostsResult struct {
+	PostId string
+	Title  string
+	// Skipped is true if the post was exported by a previous run
+	Skipped bool
+	Export  ExportMediumToMdOutput
+	Err     error
+}
+
+type ExportMediumPostsOutput struct {
+	Source string
+	// Kind is user or publication
+	Kind string
+	Name string
+	// Results has the results of the posts in the order they are listed
+	// by Medium, without the ones not attempted due to cancellation
+	Results    []ExportMediumPostsResult
+	Exported   int
+	Failed     int
+	Skipped    int
+	Checkpoint MediumExportCheckpoint
+}
+
+type ConvertMediumOutput struct {
+	PostId string
+	Title  string
+	// Format is the format of the content: markdown, html, asciidoc, rst,
+	// org or text
+	Format        string
+	ContentType   string
+	FileExtension string
+	Content       string
+	CacheStatus   string
+}
+
+type ConvertHtmlToMdOutput struct {
 	Markdown string
-	PostId   string
+	Title    string
+	Author   string
+	// Url is the canonical URL of the page or, if it has none, the URL it
+	// was got from
+	Url string
+}
+
+type PublishMdToMediumOptions struct {
+	// Title is the title of the post, by default the title of the front
+	// matter or the level 1 heading at the start of the Markdown
+	Title string
+	// Tags are the tags of the post, up to 5, by default the ones of the
+	// front matter
+	Tags         []string
+	CanonicalUrl string
+	// PublishStatus is draft, public or unlisted, draft by default
+	PublishStatus string
+}
+
+type PublishMdToMediumOutput struct {
+	PostId        string
+	Title         string
+	Url           string
+	PublishStatus string
+	Tags          []string
+	// PublishedAt is zero for drafts
+	PublishedAt time.Time
+	// Content is the HTML sent to Medium
+	Content string
+}
+
+type ResolveMediumPostIdOutput struct {
+	Input  string
+	PostId string
+	// Url is the URL the input pointed to after following redirects
+	Url string
+}
+
+type ExportMediumToMdOptions struct {
+	ConvertMediumToMdOptions
+	// AssetsDir is the directory of the images, relative to the Markdown
+	// file and inside its directory, "images" by default
+	AssetsDir string
+	// Archive is the format of the archive with all the files: zip, tar
+	// or tar.gz, no archive is created if empty
+	Archive string
+	// Workers is the number of images downloaded concurrently, 4 by default
+	Workers int
+	// Retries is the number of times the download of an image is retried,
+	// zero disables retries
+	Retries int
+}
+
+type ExportedFile struct {
+	Path        string
+	ContentType string
+	Data        []byte
+}
+
+type ExportMediumToMdOutput struct {
+	ConvertMediumToMdOutput
+	// Files has the Markdown, as index.md, and the images
+	Files []ExportedFile
+	// FailedImages has the URLs of the images that could not be downloaded
+	// and were kept as links
+	FailedImages  []string
+	Archive       []byte
+	ArchiveFormat string
+}
+
+type UrlQueryParameter struct {
+	Name  string
+	Value string
+}
+
+type ParseUrlOutput struct {
+	Url      string
+	Scheme   string
+	Username string
+	Password string
+	// HasPassword tells an empty password from no password
+	HasPassword bool
+	// Host is the host without the port and, for IPv6, the brackets
+	Host string
+	Port string
+	// Path is decoded, RawPath has it as written
+	Path    string
+	RawPath string
+	// Query has the decoded parameters in the order they are written,
+	// RawQuery has them as written
+	Query    []UrlQueryParameter
+	RawQuery string
+	Fragment string
+	// IsAbsolute is true when the URL has a scheme
+	IsAbsolute bool
+}
+
+// Query edit operations.
+const (
+	// UrlQuerySet replaces the value of a parameter, removing its other
+	// values, or adds it if missing
+	UrlQuerySet = "set"
+	// UrlQueryAdd adds a value to a parameter, keeping the others
+	UrlQueryAdd = "add"
+	// UrlQueryDelete removes all the values of a parameter
+	UrlQueryDelete = "delete"
+)
+
+type UrlQueryEdit struct {
+	// Operation is set, add or delete
+	Operation string
+	Name      string
+	// Value is ignored when deleting
+	Value string
+}
+
+type EditUrlQueryOutput struct {
+	Url   string
+	Query []UrlQueryParameter
+}
+
+// URL components, each with its own characters to percent-encode.
+const (
+	// UrlComponentQuery is a name or value of a query parameter, encoded
+	// as in HTML forms, with spaces as +
+	UrlComponentQuery = "query"
+	// UrlComponentPath is a path, slashes are kept
+	UrlComponentPath = "path"
+	// UrlComponentSegment is a segment of a path, slashes are encoded
+	UrlComponentSegment  = "segment"
+	UrlComponentFragment = "fragment"
+	// UrlComponentUserinfo is a username or password
+	UrlComponentUserinfo = "userinfo"
+)
+
+type UrlComponentOutput struct {
+	Component string
+	Encoded   string
+	Decoded   string
+}
+
+type NormalizeUrlOutput struct {
+	Url string
+	// Host is the host in ASCII, with internationalized labels in
+	// punycode, and UnicodeHost the same host decoded
+	Host        string
+	UnicodeHost string
+	// Changed is true when the normalized URL is not the one given
+	Changed bool
+}
+
+type StripUrlTrackingOutput struct {
+	Url string
+	// Removed has the names of the parameters removed, in the order they
+	// were written
+	Removed []string
+}
+
+type ResolveUrlOutput struct {
+	Url string
+}
+
+type ParseCidrOutput struct {
+	// Version is 4 or 6
+	Version int
+	// Address is the address given, which can be any in the prefix
+	Address      string
+	PrefixLength int
+	// Prefix is the network address with the prefix length
+	Prefix   string
+	Netmask  string
+	Wildcard string
+	Network  string
+	// Broadcast is empty for IPv6, which has no broadcast address, and
+	// for IPv4 /31 and /32 prefixes, which have no room for one
+	Broadcast string
+	// FirstHost and LastHost are the range of usable hosts which, for
+	// IPv4, excludes the network and broadcast addresses unless the
+	// prefix is a /31 or /32
+	FirstHost string
+	LastHost  string
+	// Addresses and Hosts are decimal numbers as, for IPv6, they do not
+	// fit an integer
+	Addresses string
+	Hosts     string
+	// Scope is private, public, loopback, link-local, multicast or
+	// unspecified
+	Scope string
+}
+
+type SplitCidrOptions struct {
+	// Count is the number of subnets, rounded up to a power of 2 to
+	// compute their size
+	Count int
+	// PrefixLength is the prefix length of the subnets, used if Count is
+	// zero
+	PrefixLength int
+}
+
+type SplitCidrOutput struct {
+	PrefixLength int
+	Subnets      []string
+}
+
+type SummarizeCidrsOutput struct {
+	// Prefixes are the smallest list of prefixes covering the same
+	// addresses as the ones given, IPv4 first
+	Prefixes []string
+}
+
+type CompareCidrsOutput struct {
+	Equal bool
+	// Contains is true when the first prefix contains the second one and
+	// ContainedBy when the second contains the first
+	Contains    bool
+	ContainedBy bool
+	Overlaps    bool
+}
+
+type ConvertIpOutput struct {
+	Version int
+	Address string
+	// Expanded is the address with all the digits, for IPv6 all the
+	// groups with leading zeros
+	Expanded   string
+	Integer    string
+	Hex        string
+	ReverseDns string
+}
+
+type HttpHeader struct {
+	Name  string
+	Value string
+}
+
+// HTTP authentication schemes.
+const (
+	HttpAuthBasic  = "basic"
+	HttpAuthBearer = "bearer"
+)
+
+type HttpAuth struct {
+	// Type is basic or bearer, no authentication if empty
+	Type     string
+	Username string
+	Password string
+	Token    string
+}
+
+type HttpRequest struct {
+	// Method is GET by default
+	Method  string
+	Url     string
+	Headers []HttpHeader
+	Body    string
+	Auth    HttpAuth
+}
+
+type SendHttpRequestOptions struct {
+	// DisableRedirects returns the redirect responses instead of
+	// following them
+	DisableRedirects bool
+	// MaxRedirects is the number of redirects followed, 10 by default
+	MaxRedirects int
+	// Insecure skips the verification of the certificate of the server
+	Insecure bool
+}
+
+// HttpTimings is how long each phase of a request took. The phases of
+// connecting are zero when a connection is reused.
+type HttpTimings struct {
+	DnsLookup    time.Duration
+	Connect      time.Duration
+	TlsHandshake time.Duration
+	// TimeToFirstByte is from the request being sent to the first byte
+	// of the response, the time the server took to answer
+	TimeToFirstByte time.Duration
+	// Transfer is the time reading the body of the response
+	Transfer time.Duration
+	// Total is the time of all the requests, including the redirects
+	Total            time.Duration
+	ConnectionReused bool
+}
+
+type HttpRedirect struct {
+	StatusCode int
+	Url        string
+	Location   string
+}
+
+type HttpCookie struct {
+	Name     string
+	Value    string
+	Domain   string
+	Path     string
+	Expires  time.Time
+	MaxAge   int
+	Secure   bool
+	HttpOnly bool
+	// SameSite is lax, strict or none, empty if not set
+	SameSite string
+}
+
+type SendHttpRequestOutput struct {
+	// Url is the URL of the last request, after the redirects
+	Url        string
+	Proto      string
+	StatusCode int
+	Status     string
+	Headers    []HttpHeader
+	Body       string
+	// Truncated is true when the body was larger than the 10MB read
+	Truncated bool
+	Redirects []HttpRedirect
+	// Cookies are the ones set by all the responses, including the
+	// redirects
+	Cookies []HttpCookie
+	// Timings are the ones of the last request, but Total
+	Timings HttpTimings
+}
+
+// Formats to export and import HTTP requests.
+const (
+	HttpFormatCurl = "curl"
+	HttpFormatHar  = "har"
+)
+
+type ExportHttpRequestOutput struct {
+	Format  string
+	Content string
+}
+
+type ImportHttpRequestOutput struct {
+	Format string
+	// Requests has a request for cURL and all the entries of a HAR
+	Requests []HttpRequest
+}
+
+type InspectCertificatesOptions struct {
+	// Roots are the PEM certificates of the trusted roots the chain is
+	// validated against, the chain is not validated if empty
+	Roots string
+	// DnsName is the name, or IP address, the leaf certificate must be
+	// valid for, not checked if empty
+	DnsName string
+	// At is when the certificates must be valid, now by default
+	At time.Time
+}
+
+type CertificateName struct {
+	CommonName         string
+	Organization       []string
+	OrganizationalUnit []string
+	Locality           []string
+	Province           []string
+	Country            []string
+	// String is the whole name as in RFC 2253
+	String string
+}
+
+type CertificatePublicKey struct {
+	// Algorithm is RSA, ECDSA or Ed25519
+	Algorithm string
+	// Size is the size of the key in bits
+	Size int
+	// Curve is the curve of ECDSA keys
+	Curve string
+}
+
+type CertificateFingerprints struct {
+	Sha1   string
+	Sha256 string
+}
+
+type CertificateExtension struct {
+	Oid string
+	// Name is empty for the extensions not known
+	Name     string
+	Critical bool
+}
+
+type CertificateInfo struct {
+	Version      int
+	SerialNumber string
+	Subject      CertificateName
+	Issuer       CertificateName
+	// DnsNames, IpAddresses, EmailAddresses and Uris are the subject
+	// alternative names
+	DnsNames       []string
+	IpAddresses    []string
+	EmailAddresses []string
+	Uris           []string
+	NotBefore      datetime.FromUnixTimestampOutput
+	NotAfter       datetime.FromUnixTimestampOutput
+	Expired        bool
+	NotYetValid    bool
+	// DaysRemaining is the number of whole days until the certificate
+	// expires, negative if it expired
+	DaysRemaining          int
+	PublicKey              CertificatePublicKey
+	SignatureAlgorithm     string
+	IsCa                   bool
+	SelfSigned             bool
+	KeyUsage               []string
+	ExtKeyUsage            []string
+	Fingerprints           CertificateFingerprints
+	SubjectKeyId           string
+	AuthorityKeyId         string
+	OcspServers            []string
+	IssuingCertificateUrls []string
+	CrlDistributionPoints  []string
+	Extensions             []CertificateExtension
+}
+
+type CertificateRequestInfo struct {
+	Subject            CertificateName
+	DnsNames           []string
+	IpAddresses        []string
+	EmailAddresses     []string
+	Uris               []string
+	PublicKey          CertificatePublicKey
+	SignatureAlgorithm string
+	// SignatureValid is true when the request is signed by the key it
+	// has
+	SignatureValid bool
+	Extensions     []CertificateExtension
+}
+
+type CertificateChainValidation struct {
+	// Checked is false when there are no roots to validate against
+	Checked bool
+	Valid   bool
+	// Chains has the common names of the certificates of each chain
+	// found, from the leaf to the root
+	Chains [][]string
+	// Errors are the reasons the chain is not valid
+	Errors []string
+}
+
+type InspectCertificatesOutput struct {
+	// Certificates are in the order given, the first being the leaf of
+	// the chain
+	Certificates []CertificateInfo
+	Requests     []CertificateRequestInfo
+	Chain        CertificateChainValidation
+	// Skipped are the types of the PEM blocks that are neither
+	// certificates nor requests, like private keys
+	Skipped []string
+}
+
+type ParseEmailOptions struct {
+	// IncludeAttachments adds the content of each attachment, in base64
+	IncludeAttachments bool
+}
+
+type EmailHeader struct {
+	Name string
+	// Value has the encoded words decoded to UTF-8
+	Value string
+	// Raw is the value as in the message, unfolded
+	Raw string
+}
+
+type EmailAddress struct {
+	Name    string
+	Address string
+}
+
+// EmailHop is a server the message went through, from a Received
+// header.
+type EmailHop struct {
+	From string
+	// FromIp is the address of the sending server, as seen by the
+	// receiving one
+	FromIp string
+	By     string
+	With   string
+	Id     string
+	For    string
+	// Date is when the server received the message, zero if missing
+	Date datetime.FromUnixTimestampOutput
+	// Delay is the number of seconds since the previous hop or, for the
+	// first one, since the date of the message, zero if a date is
+	// missing
+	Delay int64
+}
+
+type EmailAuthenticationResult struct {
+	// AuthServId is the server that did the check
+	AuthServId string
+	// Method is the check, like spf, dkim or dmarc
+	Method string
+	// Result is the outcome, like pass, fail, softfail or none
+	Result string
+	Reason string
+	// Properties are the details of the check, like header.from or
+	// smtp.mailfrom
+	Properties map[string]string
+}
+
+type EmailAuthentication struct {
+	// Spf, Dkim and Dmarc are the results of the topmost
+	// Authentication-Results header, the one added by the server that
+	// received the message, empty if missing
+	Spf   string
+	Dkim  string
+	Dmarc string
+	// Results are the results of all the Authentication-Results
+	// headers, from the topmost
+	Results []EmailAuthenticationResult
+}
+
+type EmailPart struct {
+	ContentType      string
+	Charset          string
+	TransferEncoding string
+	Disposition      string
+	Filename         string
+	ContentId        string
+	// Size is the number of bytes of the decoded content, zero for
+	// multipart parts
+	Size  int
+	Parts []EmailPart
+}
+
+type EmailAttachment struct {
+	Filename    string
+	ContentType string
+	Size        int
+	Sha256      string
+	// Inline is true for the attachments shown in the body, like images
+	// referenced by their ContentId
+	Inline    bool
+	ContentId string
+	// Content is in base64, only if requested
+	Content string
+}
+
+type ParseEmailOutput struct {
+	// Headers are the headers of the message, in order
+	Headers   []EmailHeader
+	From      []EmailAddress
+	To        []EmailAddress
+	Cc        []EmailAddress
+	ReplyTo   []EmailAddress
+	Subject   string
+	MessageId string
+	// Date is zero if missing
+	Date datetime.FromUnixTimestampOutput
+	// Hops are the servers the message went through, from the first
+	Hops []EmailHop
+	// TotalDelay is the sum of the delays of the hops, in seconds
+	TotalDelay     int64
+	Authentication EmailAuthentication
+	// Body is the tree of parts of the message
+	Body EmailPart
+	// TextBody and HtmlBody are the first text and HTML parts that are
+	// not attachments, converted to UTF-8
+	TextBody    string
+	HtmlBody    string
+	Attachments []EmailAttachment
+}
+
+// The types of device of a User-Agent.
+const (
+	UserAgentDeviceDesktop = "desktop"
+	UserAgentDeviceMobile  = "mobile"
+	UserAgentDeviceTablet  = "tablet"
+	UserAgentDeviceTv      = "tv"
+	UserAgentDeviceConsole = "console"
+	UserAgentDeviceBot     = "bot"
+	UserAgentDeviceOther   = "other"
+)
+
+// UserAgentComponent is a browser, engine or operating system, with
+// its version.
+type UserAgentComponent struct {
+	// Family is the name, like Chrome, Blink or Windows, Other if unknown
+	Family     string
+	Major      string
+	Minor      string
+	Patch      string
+	PatchMinor string
+	// Version joins the parts of the version found with dots
+	Version string
+}
+
+type UserAgentDevice struct {
+	// Family is the name, like iPhone, Other if unknown
+	Family string
+	Brand  string
+	Model  string
+	// Type is one of the UserAgentDevice constants
+	Type string
+}
+
+type ParseUserAgentOutput struct {
+	Browser UserAgentComponent
+	Engine  UserAgentComponent
+	Os      UserAgentComponent
+	Device  UserAgentDevice
+	// IsBot is true for crawlers, like search engines and link previews
+	IsBot bool
 }
 
 type Interface interface {
+	ConvertHtmlToMd(input string) (ConvertHtmlToMdOutput, error)
+	ConvertHtmlToMdContext(ctx context.Context, input string) (ConvertHtmlToMdOutput, error)
+	CompareCidrs(first string, second string) (CompareCidrsOutput, error)
+	CompareCidrsContext(ctx context.Context, first string, second string) (CompareCidrsOutput, error)
+	ConvertIp(address string) (ConvertIpOutput, error)
+	ConvertIpContext(ctx context.Context, address string) (ConvertIpOutput, error)
+	ConvertMedium(postId string, format string) (ConvertMediumOutput, error)
+	ConvertMediumContext(ctx context.Context, postId string, format string) (ConvertMediumOutput, error)
+	ConvertPostToMd(input string, options ConvertPostToMdOptions) (ConvertPostToMdOutput, error)
+	ConvertPostToMdContext(ctx context.Context, input string, options ConvertPostToMdOptions) (ConvertPostToMdOutput, error)
 	ConvertMediumToMd(postId string) (ConvertMediumToMdOutput, error)
+	ConvertMediumToMdContext(ctx context.Context, postId string) (ConvertMediumToMdOutput, error)
+	ConvertMediumToMdWithOptions(postId string, options ConvertMediumToMdOptions) (ConvertMediumToMdOutput, error)
+	ConvertMediumToMdWithOptionsContext(ctx context.Context, postId string, options ConvertMediumToMdOptions) (ConvertMediumToMdOutput, error)
+	DecodeUrlComponent(value string, component string) (UrlComponentOutput, error)
+	DecodeUrlComponentContext(ctx context.Context, value string, component string) (UrlComponentOutput, error)
+	EditUrlQuery(rawUrl string, edits []UrlQueryEdit) (EditUrlQueryOutput, error)
+	EditUrlQueryContext(ctx context.Context, rawUrl string, edits []UrlQueryEdit) (EditUrlQueryOutput, error)
+	EncodeUrlComponent(value string, component string) (UrlComponentOutput, error)
+	EncodeUrlComponentContext(ctx context.Context, value string, component string) (UrlComponentOutput, error)
+	ExportHttpRequest(request HttpRequest, format string) (ExportHttpRequestOutput, error)
+	ExportHttpRequestContext(ctx context.Context, request HttpRequest, format string) (ExportHttpRequestOutput, error)
+	ExportMediumPosts(source string, options ExportMediumPostsOptions) (ExportMediumPostsOutput, error)
+	ExportMediumPostsContext(ctx context.Context, source string, options ExportMediumPostsOptions) (ExportMediumPostsOutput, error)
+	ExportMediumToMd(postId string, options ExportMediumToMdOptions) (ExportMediumToMdOutput, error)
+	ExportMediumToMdContext(ctx context.Context, postId string, options ExportMediumToMdOptions) (ExportMediumToMdOutput, error)
+	ImportHttpRequest(content string, format string) (ImportHttpRequestOutput, error)
+	ImportHttpRequestContext(ctx context.Context, content string, format string) (ImportHttpRequestOutput, error)
+	InspectCertificates(input string, options InspectCertificatesOptions) (InspectCertificatesOutput, error)
+	InspectCertificatesContext(ctx context.Context, input string, options InspectCertificatesOptions) (InspectCertificatesOutput, error)
+	NormalizeUrl(rawUrl string) (NormalizeUrlOutput, error)
+	NormalizeUrlContext(ctx context.Context, rawUrl string) (NormalizeUrlOutput, error)
+	ParseCidr(cidr string) (ParseCidrOutput, error)
+	ParseCidrContext(ctx context.Context, cidr string) (ParseCidrOutput, error)
+	ParseEmail(raw string, options ParseEmailOptions) (ParseEmailOutput, error)
+	ParseEmailContext(ctx context.Context, raw string, options ParseEmailOptions) (ParseEmailOutput, error)
+	ParseUrl(rawUrl string) (ParseUrlOutput, error)
+	ParseUrlContext(ctx context.Context, rawUrl string) (ParseUrlOutput, error)
+	ParseUserAgent(userAgent string) (ParseUserAgentOutput, error)
+	ParseUserAgentContext(ctx context.Context, userAgent string) (ParseUserAgentOutput, error)
+	PublishMdToMedium(markdown string, options PublishMdToMediumOptions) (PublishMdToMediumOutput, error)
+	PublishMdToMediumContext(ctx context.Context, markdown string, options PublishMdToMediumOptions) (PublishMdToMediumOutput, error)
+	ResolveMediumPostId(input string) (ResolveMediumPostIdOutput, error)
+	ResolveMediumPostIdContext(ctx context.Context, input string) (ResolveMediumPostIdOutput, error)
+	ResolveUrl(base string, reference string) (ResolveUrlOutput, error)
+	ResolveUrlContext(ctx context.Context, base string, reference string) (ResolveUrlOutput, error)
+	SendHttpRequest(request HttpRequest, options SendHttpRequestOptions) (SendHttpRequestOutput, error)
+	SendHttpRequestContext(ctx context.Context, request HttpRequest, options SendHttpRequestOptions) (SendHttpRequestOutput, error)
+	SplitCidr(cidr string, options SplitCidrOptions) (SplitCidrOutput, error)
+	SplitCidrContext(ctx context.Context, cidr string, options SplitCidrOptions) (SplitCidrOutput, error)
+	StripUrlTracking(rawUrl string) (StripUrlTrackingOutput, error)
+	StripUrlTrackingContext(ctx context.Context, rawUrl string) (StripUrlTrackingOutput, error)
+	SummarizeCidrs(cidrs []string) (SummarizeCidrsOutput, error)
+	SummarizeCidrsContext(ctx context.Context, cidrs []string) (SummarizeCidrsOutput, error)
 }
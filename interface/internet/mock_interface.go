@@ -2,13 +2,185 @@
 
 package internet
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // MockInterface is an autogenerated mock type for the Interface type
 type MockInterface struct {
 	mock.Mock
 }
 
+// CompareCidrs provides a mock function with given fields: first, second
+func (_m *MockInterface) CompareCidrs(first string, second string) (CompareCidrsOutput, error) {
+	ret := _m.Called(first, second)
+
+	var r0 CompareCidrsOutput
+	if rf, ok := ret.Get(0).(func(string, string) CompareCidrsOutput); ok {
+		r0 = rf(first, second)
+	} else {
+		r0 = ret.Get(0).(CompareCidrsOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(first, second)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompareCidrsContext provides a mock function with given fields: ctx, first, second
+func (_m *MockInterface) CompareCidrsContext(ctx context.Context, first string, second string) (CompareCidrsOutput, error) {
+	ret := _m.Called(ctx, first, second)
+
+	var r0 CompareCidrsOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) CompareCidrsOutput); ok {
+		r0 = rf(ctx, first, second)
+	} else {
+		r0 = ret.Get(0).(CompareCidrsOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, first, second)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConvertHtmlToMd provides a mock function with given fields: input
+func (_m *MockInterface) ConvertHtmlToMd(input string) (ConvertHtmlToMdOutput, error) {
+	ret := _m.Called(input)
+
+	var r0 ConvertHtmlToMdOutput
+	if rf, ok := ret.Get(0).(func(string) ConvertHtmlToMdOutput); ok {
+		r0 = rf(input)
+	} else {
+		r0 = ret.Get(0).(ConvertHtmlToMdOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConvertHtmlToMdContext provides a mock function with given fields: ctx, input
+func (_m *MockInterface) ConvertHtmlToMdContext(ctx context.Context, input string) (ConvertHtmlToMdOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 ConvertHtmlToMdOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string) ConvertHtmlToMdOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		r0 = ret.Get(0).(ConvertHtmlToMdOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConvertIp provides a mock function with given fields: address
+func (_m *MockInterface) ConvertIp(address string) (ConvertIpOutput, error) {
+	ret := _m.Called(address)
+
+	var r0 ConvertIpOutput
+	if rf, ok := ret.Get(0).(func(string) ConvertIpOutput); ok {
+		r0 = rf(address)
+	} else {
+		r0 = ret.Get(0).(ConvertIpOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConvertIpContext provides a mock function with given fields: ctx, address
+func (_m *MockInterface) ConvertIpContext(ctx context.Context, address string) (ConvertIpOutput, error) {
+	ret := _m.Called(ctx, address)
+
+	var r0 ConvertIpOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string) ConvertIpOutput); ok {
+		r0 = rf(ctx, address)
+	} else {
+		r0 = ret.Get(0).(ConvertIpOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConvertMedium provides a mock function with given fields: postId, format
+func (_m *MockInterface) ConvertMedium(postId string, format string) (ConvertMediumOutput, error) {
+	ret := _m.Called(postId, format)
+
+	var r0 ConvertMediumOutput
+	if rf, ok := ret.Get(0).(func(string, string) ConvertMediumOutput); ok {
+		r0 = rf(postId, format)
+	} else {
+		r0 = ret.Get(0).(ConvertMediumOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(postId, format)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConvertMediumContext provides a mock function with given fields: ctx, postId, format
+func (_m *MockInterface) ConvertMediumContext(ctx context.Context, postId string, format string) (ConvertMediumOutput, error) {
+	ret := _m.Called(ctx, postId, format)
+
+	var r0 ConvertMediumOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ConvertMediumOutput); ok {
+		r0 = rf(ctx, postId, format)
+	} else {
+		r0 = ret.Get(0).(ConvertMediumOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, postId, format)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ConvertMediumToMd provides a mock function with given fields: postId
 func (_m *MockInterface) ConvertMediumToMd(postId string) (ConvertMediumToMdOutput, error) {
 	ret := _m.Called(postId)
@@ -29,3 +201,948 @@ func (_m *MockInterface) ConvertMediumToMd(postId string) (ConvertMediumToMdOutp
 
 	return r0, r1
 }
+
+// ConvertMediumToMdContext provides a mock function with given fields: ctx, postId
+func (_m *MockInterface) ConvertMediumToMdContext(ctx context.Context, postId string) (ConvertMediumToMdOutput, error) {
+	ret := _m.Called(ctx, postId)
+
+	var r0 ConvertMediumToMdOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string) ConvertMediumToMdOutput); ok {
+		r0 = rf(ctx, postId)
+	} else {
+		r0 = ret.Get(0).(ConvertMediumToMdOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, postId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConvertMediumToMdWithOptions provides a mock function with given fields: postId, options
+func (_m *MockInterface) ConvertMediumToMdWithOptions(postId string, options ConvertMediumToMdOptions) (ConvertMediumToMdOutput, error) {
+	ret := _m.Called(postId, options)
+
+	var r0 ConvertMediumToMdOutput
+	if rf, ok := ret.Get(0).(func(string, ConvertMediumToMdOptions) ConvertMediumToMdOutput); ok {
+		r0 = rf(postId, options)
+	} else {
+		r0 = ret.Get(0).(ConvertMediumToMdOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, ConvertMediumToMdOptions) error); ok {
+		r1 = rf(postId, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConvertMediumToMdWithOptionsContext provides a mock function with given fields: ctx, postId, options
+func (_m *MockInterface) ConvertMediumToMdWithOptionsContext(ctx context.Context, postId string, options ConvertMediumToMdOptions) (ConvertMediumToMdOutput, error) {
+	ret := _m.Called(ctx, postId, options)
+
+	var r0 ConvertMediumToMdOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string, ConvertMediumToMdOptions) ConvertMediumToMdOutput); ok {
+		r0 = rf(ctx, postId, options)
+	} else {
+		r0 = ret.Get(0).(ConvertMediumToMdOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, ConvertMediumToMdOptions) error); ok {
+		r1 = rf(ctx, postId, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConvertPostToMd provides a mock function with given fields: input, options
+func (_m *MockInterface) ConvertPostToMd(input string, options ConvertPostToMdOptions) (ConvertPostToMdOutput, error) {
+	ret := _m.Called(input, options)
+
+	var r0 ConvertPostToMdOutput
+	if rf, ok := ret.Get(0).(func(string, ConvertPostToMdOptions) ConvertPostToMdOutput); ok {
+		r0 = rf(input, options)
+	} else {
+		r0 = ret.Get(0).(ConvertPostToMdOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, ConvertPostToMdOptions) error); ok {
+		r1 = rf(input, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConvertPostToMdContext provides a mock function with given fields: ctx, input, options
+func (_m *MockInterface) ConvertPostToMdContext(ctx context.Context, input string, options ConvertPostToMdOptions) (ConvertPostToMdOutput, error) {
+	ret := _m.Called(ctx, input, options)
+
+	var r0 ConvertPostToMdOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string, ConvertPostToMdOptions) ConvertPostToMdOutput); ok {
+		r0 = rf(ctx, input, options)
+	} else {
+		r0 = ret.Get(0).(ConvertPostToMdOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, ConvertPostToMdOptions) error); ok {
+		r1 = rf(ctx, input, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DecodeUrlComponent provides a mock function with given fields: value, component
+func (_m *MockInterface) DecodeUrlComponent(value string, component string) (UrlComponentOutput, error) {
+	ret := _m.Called(value, component)
+
+	var r0 UrlComponentOutput
+	if rf, ok := ret.Get(0).(func(string, string) UrlComponentOutput); ok {
+		r0 = rf(value, component)
+	} else {
+		r0 = ret.Get(0).(UrlComponentOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(value, component)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DecodeUrlComponentContext provides a mock function with given fields: ctx, value, component
+func (_m *MockInterface) DecodeUrlComponentContext(ctx context.Context, value string, component string) (UrlComponentOutput, error) {
+	ret := _m.Called(ctx, value, component)
+
+	var r0 UrlComponentOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) UrlComponentOutput); ok {
+		r0 = rf(ctx, value, component)
+	} else {
+		r0 = ret.Get(0).(UrlComponentOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, value, component)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EditUrlQuery provides a mock function with given fields: rawUrl, edits
+func (_m *MockInterface) EditUrlQuery(rawUrl string, edits []UrlQueryEdit) (EditUrlQueryOutput, error) {
+	ret := _m.Called(rawUrl, edits)
+
+	var r0 EditUrlQueryOutput
+	if rf, ok := ret.Get(0).(func(string, []UrlQueryEdit) EditUrlQueryOutput); ok {
+		r0 = rf(rawUrl, edits)
+	} else {
+		r0 = ret.Get(0).(EditUrlQueryOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []UrlQueryEdit) error); ok {
+		r1 = rf(rawUrl, edits)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EditUrlQueryContext provides a mock function with given fields: ctx, rawUrl, edits
+func (_m *MockInterface) EditUrlQueryContext(ctx context.Context, rawUrl string, edits []UrlQueryEdit) (EditUrlQueryOutput, error) {
+	ret := _m.Called(ctx, rawUrl, edits)
+
+	var r0 EditUrlQueryOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string, []UrlQueryEdit) EditUrlQueryOutput); ok {
+		r0 = rf(ctx, rawUrl, edits)
+	} else {
+		r0 = ret.Get(0).(EditUrlQueryOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, []UrlQueryEdit) error); ok {
+		r1 = rf(ctx, rawUrl, edits)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EncodeUrlComponent provides a mock function with given fields: value, component
+func (_m *MockInterface) EncodeUrlComponent(value string, component string) (UrlComponentOutput, error) {
+	ret := _m.Called(value, component)
+
+	var r0 UrlComponentOutput
+	if rf, ok := ret.Get(0).(func(string, string) UrlComponentOutput); ok {
+		r0 = rf(value, component)
+	} else {
+		r0 = ret.Get(0).(UrlComponentOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(value, component)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EncodeUrlComponentContext provides a mock function with given fields: ctx, value, component
+func (_m *MockInterface) EncodeUrlComponentContext(ctx context.Context, value string, component string) (UrlComponentOutput, error) {
+	ret := _m.Called(ctx, value, component)
+
+	var r0 UrlComponentOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) UrlComponentOutput); ok {
+		r0 = rf(ctx, value, component)
+	} else {
+		r0 = ret.Get(0).(UrlComponentOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, value, component)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportHttpRequest provides a mock function with given fields: request, format
+func (_m *MockInterface) ExportHttpRequest(request HttpRequest, format string) (ExportHttpRequestOutput, error) {
+	ret := _m.Called(request, format)
+
+	var r0 ExportHttpRequestOutput
+	if rf, ok := ret.Get(0).(func(HttpRequest, string) ExportHttpRequestOutput); ok {
+		r0 = rf(request, format)
+	} else {
+		r0 = ret.Get(0).(ExportHttpRequestOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(HttpRequest, string) error); ok {
+		r1 = rf(request, format)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportHttpRequestContext provides a mock function with given fields: ctx, request, format
+func (_m *MockInterface) ExportHttpRequestContext(ctx context.Context, request HttpRequest, format string) (ExportHttpRequestOutput, error) {
+	ret := _m.Called(ctx, request, format)
+
+	var r0 ExportHttpRequestOutput
+	if rf, ok := ret.Get(0).(func(context.Context, HttpRequest, string) ExportHttpRequestOutput); ok {
+		r0 = rf(ctx, request, format)
+	} else {
+		r0 = ret.Get(0).(ExportHttpRequestOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, HttpRequest, string) error); ok {
+		r1 = rf(ctx, request, format)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportMediumPosts provides a mock function with given fields: source, options
+func (_m *MockInterface) ExportMediumPosts(source string, options ExportMediumPostsOptions) (ExportMediumPostsOutput, error) {
+	ret := _m.Called(source, options)
+
+	var r0 ExportMediumPostsOutput
+	if rf, ok := ret.Get(0).(func(string, ExportMediumPostsOptions) ExportMediumPostsOutput); ok {
+		r0 = rf(source, options)
+	} else {
+		r0 = ret.Get(0).(ExportMediumPostsOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, ExportMediumPostsOptions) error); ok {
+		r1 = rf(source, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportMediumPostsContext provides a mock function with given fields: ctx, source, options
+func (_m *MockInterface) ExportMediumPostsContext(ctx context.Context, source string, options ExportMediumPostsOptions) (ExportMediumPostsOutput, error) {
+	ret := _m.Called(ctx, source, options)
+
+	var r0 ExportMediumPostsOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string, ExportMediumPostsOptions) ExportMediumPostsOutput); ok {
+		r0 = rf(ctx, source, options)
+	} else {
+		r0 = ret.Get(0).(ExportMediumPostsOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, ExportMediumPostsOptions) error); ok {
+		r1 = rf(ctx, source, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportMediumToMd provides a mock function with given fields: postId, options
+func (_m *MockInterface) ExportMediumToMd(postId string, options ExportMediumToMdOptions) (ExportMediumToMdOutput, error) {
+	ret := _m.Called(postId, options)
+
+	var r0 ExportMediumToMdOutput
+	if rf, ok := ret.Get(0).(func(string, ExportMediumToMdOptions) ExportMediumToMdOutput); ok {
+		r0 = rf(postId, options)
+	} else {
+		r0 = ret.Get(0).(ExportMediumToMdOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, ExportMediumToMdOptions) error); ok {
+		r1 = rf(postId, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportMediumToMdContext provides a mock function with given fields: ctx, postId, options
+func (_m *MockInterface) ExportMediumToMdContext(ctx context.Context, postId string, options ExportMediumToMdOptions) (ExportMediumToMdOutput, error) {
+	ret := _m.Called(ctx, postId, options)
+
+	var r0 ExportMediumToMdOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string, ExportMediumToMdOptions) ExportMediumToMdOutput); ok {
+		r0 = rf(ctx, postId, options)
+	} else {
+		r0 = ret.Get(0).(ExportMediumToMdOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, ExportMediumToMdOptions) error); ok {
+		r1 = rf(ctx, postId, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportHttpRequest provides a mock function with given fields: content, format
+func (_m *MockInterface) ImportHttpRequest(content string, format string) (ImportHttpRequestOutput, error) {
+	ret := _m.Called(content, format)
+
+	var r0 ImportHttpRequestOutput
+	if rf, ok := ret.Get(0).(func(string, string) ImportHttpRequestOutput); ok {
+		r0 = rf(content, format)
+	} else {
+		r0 = ret.Get(0).(ImportHttpRequestOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(content, format)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportHttpRequestContext provides a mock function with given fields: ctx, content, format
+func (_m *MockInterface) ImportHttpRequestContext(ctx context.Context, content string, format string) (ImportHttpRequestOutput, error) {
+	ret := _m.Called(ctx, content, format)
+
+	var r0 ImportHttpRequestOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ImportHttpRequestOutput); ok {
+		r0 = rf(ctx, content, format)
+	} else {
+		r0 = ret.Get(0).(ImportHttpRequestOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, content, format)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InspectCertificates provides a mock function with given fields: input, options
+func (_m *MockInterface) InspectCertificates(input string, options InspectCertificatesOptions) (InspectCertificatesOutput, error) {
+	ret := _m.Called(input, options)
+
+	var r0 InspectCertificatesOutput
+	if rf, ok := ret.Get(0).(func(string, InspectCertificatesOptions) InspectCertificatesOutput); ok {
+		r0 = rf(input, options)
+	} else {
+		r0 = ret.Get(0).(InspectCertificatesOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, InspectCertificatesOptions) error); ok {
+		r1 = rf(input, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InspectCertificatesContext provides a mock function with given fields: ctx, input, options
+func (_m *MockInterface) InspectCertificatesContext(ctx context.Context, input string, options InspectCertificatesOptions) (InspectCertificatesOutput, error) {
+	ret := _m.Called(ctx, input, options)
+
+	var r0 InspectCertificatesOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string, InspectCertificatesOptions) InspectCertificatesOutput); ok {
+		r0 = rf(ctx, input, options)
+	} else {
+		r0 = ret.Get(0).(InspectCertificatesOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, InspectCertificatesOptions) error); ok {
+		r1 = rf(ctx, input, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NormalizeUrl provides a mock function with given fields: rawUrl
+func (_m *MockInterface) NormalizeUrl(rawUrl string) (NormalizeUrlOutput, error) {
+	ret := _m.Called(rawUrl)
+
+	var r0 NormalizeUrlOutput
+	if rf, ok := ret.Get(0).(func(string) NormalizeUrlOutput); ok {
+		r0 = rf(rawUrl)
+	} else {
+		r0 = ret.Get(0).(NormalizeUrlOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(rawUrl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NormalizeUrlContext provides a mock function with given fields: ctx, rawUrl
+func (_m *MockInterface) NormalizeUrlContext(ctx context.Context, rawUrl string) (NormalizeUrlOutput, error) {
+	ret := _m.Called(ctx, rawUrl)
+
+	var r0 NormalizeUrlOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string) NormalizeUrlOutput); ok {
+		r0 = rf(ctx, rawUrl)
+	} else {
+		r0 = ret.Get(0).(NormalizeUrlOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, rawUrl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParseCidr provides a mock function with given fields: cidr
+func (_m *MockInterface) ParseCidr(cidr string) (ParseCidrOutput, error) {
+	ret := _m.Called(cidr)
+
+	var r0 ParseCidrOutput
+	if rf, ok := ret.Get(0).(func(string) ParseCidrOutput); ok {
+		r0 = rf(cidr)
+	} else {
+		r0 = ret.Get(0).(ParseCidrOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(cidr)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParseCidrContext provides a mock function with given fields: ctx, cidr
+func (_m *MockInterface) ParseCidrContext(ctx context.Context, cidr string) (ParseCidrOutput, error) {
+	ret := _m.Called(ctx, cidr)
+
+	var r0 ParseCidrOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string) ParseCidrOutput); ok {
+		r0 = rf(ctx, cidr)
+	} else {
+		r0 = ret.Get(0).(ParseCidrOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, cidr)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParseEmail provides a mock function with given fields: raw, options
+func (_m *MockInterface) ParseEmail(raw string, options ParseEmailOptions) (ParseEmailOutput, error) {
+	ret := _m.Called(raw, options)
+
+	var r0 ParseEmailOutput
+	if rf, ok := ret.Get(0).(func(string, ParseEmailOptions) ParseEmailOutput); ok {
+		r0 = rf(raw, options)
+	} else {
+		r0 = ret.Get(0).(ParseEmailOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, ParseEmailOptions) error); ok {
+		r1 = rf(raw, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParseEmailContext provides a mock function with given fields: ctx, raw, options
+func (_m *MockInterface) ParseEmailContext(ctx context.Context, raw string, options ParseEmailOptions) (ParseEmailOutput, error) {
+	ret := _m.Called(ctx, raw, options)
+
+	var r0 ParseEmailOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string, ParseEmailOptions) ParseEmailOutput); ok {
+		r0 = rf(ctx, raw, options)
+	} else {
+		r0 = ret.Get(0).(ParseEmailOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, ParseEmailOptions) error); ok {
+		r1 = rf(ctx, raw, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParseUrl provides a mock function with given fields: rawUrl
+func (_m *MockInterface) ParseUrl(rawUrl string) (ParseUrlOutput, error) {
+	ret := _m.Called(rawUrl)
+
+	var r0 ParseUrlOutput
+	if rf, ok := ret.Get(0).(func(string) ParseUrlOutput); ok {
+		r0 = rf(rawUrl)
+	} else {
+		r0 = ret.Get(0).(ParseUrlOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(rawUrl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParseUrlContext provides a mock function with given fields: ctx, rawUrl
+func (_m *MockInterface) ParseUrlContext(ctx context.Context, rawUrl string) (ParseUrlOutput, error) {
+	ret := _m.Called(ctx, rawUrl)
+
+	var r0 ParseUrlOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string) ParseUrlOutput); ok {
+		r0 = rf(ctx, rawUrl)
+	} else {
+		r0 = ret.Get(0).(ParseUrlOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, rawUrl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParseUserAgent provides a mock function with given fields: userAgent
+func (_m *MockInterface) ParseUserAgent(userAgent string) (ParseUserAgentOutput, error) {
+	ret := _m.Called(userAgent)
+
+	var r0 ParseUserAgentOutput
+	if rf, ok := ret.Get(0).(func(string) ParseUserAgentOutput); ok {
+		r0 = rf(userAgent)
+	} else {
+		r0 = ret.Get(0).(ParseUserAgentOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userAgent)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParseUserAgentContext provides a mock function with given fields: ctx, userAgent
+func (_m *MockInterface) ParseUserAgentContext(ctx context.Context, userAgent string) (ParseUserAgentOutput, error) {
+	ret := _m.Called(ctx, userAgent)
+
+	var r0 ParseUserAgentOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string) ParseUserAgentOutput); ok {
+		r0 = rf(ctx, userAgent)
+	} else {
+		r0 = ret.Get(0).(ParseUserAgentOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userAgent)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishMdToMedium provides a mock function with given fields: markdown, options
+func (_m *MockInterface) PublishMdToMedium(markdown string, options PublishMdToMediumOptions) (PublishMdToMediumOutput, error) {
+	ret := _m.Called(markdown, options)
+
+	var r0 PublishMdToMediumOutput
+	if rf, ok := ret.Get(0).(func(string, PublishMdToMediumOptions) PublishMdToMediumOutput); ok {
+		r0 = rf(markdown, options)
+	} else {
+		r0 = ret.Get(0).(PublishMdToMediumOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, PublishMdToMediumOptions) error); ok {
+		r1 = rf(markdown, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishMdToMediumContext provides a mock function with given fields: ctx, markdown, options
+func (_m *MockInterface) PublishMdToMediumContext(ctx context.Context, markdown string, options PublishMdToMediumOptions) (PublishMdToMediumOutput, error) {
+	ret := _m.Called(ctx, markdown, options)
+
+	var r0 PublishMdToMediumOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string, PublishMdToMediumOptions) PublishMdToMediumOutput); ok {
+		r0 = rf(ctx, markdown, options)
+	} else {
+		r0 = ret.Get(0).(PublishMdToMediumOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, PublishMdToMediumOptions) error); ok {
+		r1 = rf(ctx, markdown, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResolveMediumPostId provides a mock function with given fields: input
+func (_m *MockInterface) ResolveMediumPostId(input string) (ResolveMediumPostIdOutput, error) {
+	ret := _m.Called(input)
+
+	var r0 ResolveMediumPostIdOutput
+	if rf, ok := ret.Get(0).(func(string) ResolveMediumPostIdOutput); ok {
+		r0 = rf(input)
+	} else {
+		r0 = ret.Get(0).(ResolveMediumPostIdOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResolveMediumPostIdContext provides a mock function with given fields: ctx, input
+func (_m *MockInterface) ResolveMediumPostIdContext(ctx context.Context, input string) (ResolveMediumPostIdOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 ResolveMediumPostIdOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string) ResolveMediumPostIdOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		r0 = ret.Get(0).(ResolveMediumPostIdOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResolveUrl provides a mock function with given fields: base, reference
+func (_m *MockInterface) ResolveUrl(base string, reference string) (ResolveUrlOutput, error) {
+	ret := _m.Called(base, reference)
+
+	var r0 ResolveUrlOutput
+	if rf, ok := ret.Get(0).(func(string, string) ResolveUrlOutput); ok {
+		r0 = rf(base, reference)
+	} else {
+		r0 = ret.Get(0).(ResolveUrlOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(base, reference)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResolveUrlContext provides a mock function with given fields: ctx, base, reference
+func (_m *MockInterface) ResolveUrlContext(ctx context.Context, base string, reference string) (ResolveUrlOutput, error) {
+	ret := _m.Called(ctx, base, reference)
+
+	var r0 ResolveUrlOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ResolveUrlOutput); ok {
+		r0 = rf(ctx, base, reference)
+	} else {
+		r0 = ret.Get(0).(ResolveUrlOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, base, reference)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SendHttpRequest provides a mock function with given fields: request, options
+func (_m *MockInterface) SendHttpRequest(request HttpRequest, options SendHttpRequestOptions) (SendHttpRequestOutput, error) {
+	ret := _m.Called(request, options)
+
+	var r0 SendHttpRequestOutput
+	if rf, ok := ret.Get(0).(func(HttpRequest, SendHttpRequestOptions) SendHttpRequestOutput); ok {
+		r0 = rf(request, options)
+	} else {
+		r0 = ret.Get(0).(SendHttpRequestOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(HttpRequest, SendHttpRequestOptions) error); ok {
+		r1 = rf(request, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SendHttpRequestContext provides a mock function with given fields: ctx, request, options
+func (_m *MockInterface) SendHttpRequestContext(ctx context.Context, request HttpRequest, options SendHttpRequestOptions) (SendHttpRequestOutput, error) {
+	ret := _m.Called(ctx, request, options)
+
+	var r0 SendHttpRequestOutput
+	if rf, ok := ret.Get(0).(func(context.Context, HttpRequest, SendHttpRequestOptions) SendHttpRequestOutput); ok {
+		r0 = rf(ctx, request, options)
+	} else {
+		r0 = ret.Get(0).(SendHttpRequestOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, HttpRequest, SendHttpRequestOptions) error); ok {
+		r1 = rf(ctx, request, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SplitCidr provides a mock function with given fields: cidr, options
+func (_m *MockInterface) SplitCidr(cidr string, options SplitCidrOptions) (SplitCidrOutput, error) {
+	ret := _m.Called(cidr, options)
+
+	var r0 SplitCidrOutput
+	if rf, ok := ret.Get(0).(func(string, SplitCidrOptions) SplitCidrOutput); ok {
+		r0 = rf(cidr, options)
+	} else {
+		r0 = ret.Get(0).(SplitCidrOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, SplitCidrOptions) error); ok {
+		r1 = rf(cidr, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SplitCidrContext provides a mock function with given fields: ctx, cidr, options
+func (_m *MockInterface) SplitCidrContext(ctx context.Context, cidr string, options SplitCidrOptions) (SplitCidrOutput, error) {
+	ret := _m.Called(ctx, cidr, options)
+
+	var r0 SplitCidrOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string, SplitCidrOptions) SplitCidrOutput); ok {
+		r0 = rf(ctx, cidr, options)
+	} else {
+		r0 = ret.Get(0).(SplitCidrOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, SplitCidrOptions) error); ok {
+		r1 = rf(ctx, cidr, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StripUrlTracking provides a mock function with given fields: rawUrl
+func (_m *MockInterface) StripUrlTracking(rawUrl string) (StripUrlTrackingOutput, error) {
+	ret := _m.Called(rawUrl)
+
+	var r0 StripUrlTrackingOutput
+	if rf, ok := ret.Get(0).(func(string) StripUrlTrackingOutput); ok {
+		r0 = rf(rawUrl)
+	} else {
+		r0 = ret.Get(0).(StripUrlTrackingOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(rawUrl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StripUrlTrackingContext provides a mock function with given fields: ctx, rawUrl
+func (_m *MockInterface) StripUrlTrackingContext(ctx context.Context, rawUrl string) (StripUrlTrackingOutput, error) {
+	ret := _m.Called(ctx, rawUrl)
+
+	var r0 StripUrlTrackingOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string) StripUrlTrackingOutput); ok {
+		r0 = rf(ctx, rawUrl)
+	} else {
+		r0 = ret.Get(0).(StripUrlTrackingOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, rawUrl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SummarizeCidrs provides a mock function with given fields: cidrs
+func (_m *MockInterface) SummarizeCidrs(cidrs []string) (SummarizeCidrsOutput, error) {
+	ret := _m.Called(cidrs)
+
+	var r0 SummarizeCidrsOutput
+	if rf, ok := ret.Get(0).(func([]string) SummarizeCidrsOutput); ok {
+		r0 = rf(cidrs)
+	} else {
+		r0 = ret.Get(0).(SummarizeCidrsOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(cidrs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SummarizeCidrsContext provides a mock function with given fields: ctx, cidrs
+func (_m *MockInterface) SummarizeCidrsContext(ctx context.Context, cidrs []string) (SummarizeCidrsOutput, error) {
+	ret := _m.Called(ctx, cidrs)
+
+	var r0 SummarizeCidrsOutput
+	if rf, ok := ret.Get(0).(func(context.Context, []string) SummarizeCidrsOutput); ok {
+		r0 = rf(ctx, cidrs)
+	} else {
+		r0 = ret.Get(0).(SummarizeCidrsOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, cidrs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -16,6 +16,8 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 */
 package programming
 
+import "context"
+
 type UuidOutput struct {
 	UUID string
 }
@@ -27,5 +29,7 @@ type JwtDebuggerOutput struct {
 
 type Interface interface {
 	NewUuid() UuidOutput
+	NewUuidContext(ctx context.Context) (UuidOutput, error)
 	DebugJwt(tokenString string) (JwtDebuggerOutput, error)
+	DebugJwtContext(ctx context.Context, tokenString string) (JwtDebuggerOutput, error)
 }
@@ -2,7 +2,11 @@
 
 package programming
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // MockInterface is an autogenerated mock type for the Interface type
 type MockInterface struct {
@@ -30,6 +34,27 @@ func (_m *MockInterface) DebugJwt(tokenString string) (JwtDebuggerOutput, error)
 	return r0, r1
 }
 
+// DebugJwtContext provides a mock function with given fields: ctx, tokenString
+func (_m *MockInterface) DebugJwtContext(ctx context.Context, tokenString string) (JwtDebuggerOutput, error) {
+	ret := _m.Called(ctx, tokenString)
+
+	var r0 JwtDebuggerOutput
+	if rf, ok := ret.Get(0).(func(context.Context, string) JwtDebuggerOutput); ok {
+		r0 = rf(ctx, tokenString)
+	} else {
+		r0 = ret.Get(0).(JwtDebuggerOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenString)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUuid provides a mock function with given fields:
 func (_m *MockInterface) NewUuid() UuidOutput {
 	ret := _m.Called()
@@ -43,3 +68,24 @@ func (_m *MockInterface) NewUuid() UuidOutput {
 
 	return r0
 }
+
+// NewUuidContext provides a mock function with given fields: ctx
+func (_m *MockInterface) NewUuidContext(ctx context.Context) (UuidOutput, error) {
+	ret := _m.Called(ctx)
+
+	var r0 UuidOutput
+	if rf, ok := ret.Get(0).(func(context.Context) UuidOutput); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(UuidOutput)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -17,17 +17,27 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 package datetime
 
 import (
+	"context"
 	"time"
 
 	"github.com/renato0307/canivete-core/interface/datetime"
 )
 
 func (s *Service) FromUnitTimestamp(unixTime int64) datetime.FromUnixTimestampOutput {
+	output, _ := s.FromUnitTimestampContext(context.Background(), unixTime)
+	return output
+}
+
+func (s *Service) FromUnitTimestampContext(ctx context.Context, unixTime int64) (datetime.FromUnixTimestampOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return datetime.FromUnixTimestampOutput{}, err
+	}
+
 	t := time.Unix(unixTime, 0)
 	strDate := t.UTC().Format(time.UnixDate)
 
 	return datetime.FromUnixTimestampOutput{
 		UnixTimestamp: unixTime,
 		UtcTimestamp:  strDate,
-	}
+	}, nil
 }
@@ -17,6 +17,7 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 package datetime
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -30,3 +31,16 @@ func TestFromUnitTimestamp(t *testing.T) {
 	// assert
 	assert.Equal(t, output.UtcTimestamp, "Wed Dec  8 12:00:00 UTC 2021")
 }
+
+func TestFromUnitTimestampContextCanceled(t *testing.T) {
+	// arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// act
+	p := Service{}
+	_, err := p.FromUnitTimestampContext(ctx, 1638964800)
+
+	// assert
+	assert.Equal(t, context.Canceled, err)
+}
@@ -17,6 +17,7 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 package finance
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
@@ -39,7 +40,15 @@ import (
 // 	t = the time the money is invested or borrowed for
 // 	m = the regular contribution
 // 	y = regular contributions in the compounded period
+//
+// The output has the currency of the service, set with WithCurrency.
 func (s *Service) CalculateCompoundInterests(p, n, t, m, y, rInt float64) (finance.CompoundInterestsOutput, error) {
+	return s.CalculateCompoundInterestsContext(context.Background(), p, n, t, m, y, rInt)
+}
+
+// CalculateCompoundInterestsContext is like CalculateCompoundInterests
+// but stops calculating the history when the context is done.
+func (s *Service) CalculateCompoundInterestsContext(ctx context.Context, p, n, t, m, y, rInt float64) (finance.CompoundInterestsOutput, error) {
 	output := finance.CompoundInterestsOutput{
 		Total:   finance.CompoundInterestsDetailOutput{},
 		History: []finance.CompoundInterestsHistoryEntryOutput{},
@@ -49,12 +58,24 @@ func (s *Service) CalculateCompoundInterests(p, n, t, m, y, rInt float64) (finan
 		return output, errors.New("y must be bigger than zero")
 	}
 
+	if s.currency != "" {
+		c, err := lookupCurrency(s.currency)
+		if err != nil {
+			return output, err
+		}
+		output.Currency = c.code
+	}
+
 	r := rInt / 100
 
 	output.Total = calculateValues(p, n, t, m, y, r)
 
 	// history
 	for i := 1; i <= int(t); i++ {
+		if err := ctx.Err(); err != nil {
+			return output, err
+		}
+
 		historyEntry := finance.CompoundInterestsHistoryEntryOutput{}
 		historyEntry.Totals = calculateValues(p, n, float64(i), m, y, r)
 		historyEntry.Period = fmt.Sprint(i)
@@ -17,6 +17,7 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 package finance
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -42,6 +43,26 @@ func TestCalculateCompoundInterestWithRegularContributions(t *testing.T) {
 	assert.Equal(t, 23763.28, output.Total.FinalAmount)
 }
 
+func TestCalculateCompoundInterestWithCurrency(t *testing.T) {
+	// act
+	p := NewService(WithCurrency("eur"))
+	output, err := p.CalculateCompoundInterests(1000, 1, 10, 0, 0, 5)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "EUR", output.Currency)
+}
+
+func TestCalculateCompoundInterestWithUnknownCurrency(t *testing.T) {
+	// act
+	p := NewService(WithCurrency("XYZ"))
+	_, err := p.CalculateCompoundInterests(1000, 1, 10, 0, 0, 5)
+
+	// assert
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unknown currency")
+}
+
 func TestCompoundInterestsWithRegularContributionsInvalidValues(t *testing.T) {
 	// act
 	p := Service{}
@@ -51,3 +72,16 @@ func TestCompoundInterestsWithRegularContributionsInvalidValues(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "y must be bigger than zero")
 }
+
+func TestCalculateCompoundInterestsContextCanceled(t *testing.T) {
+	// arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// act
+	p := Service{}
+	_, err := p.CalculateCompoundInterestsContext(ctx, 1000, 1, 1000000, 0, 0, 5)
+
+	// assert
+	assert.Equal(t, context.Canceled, err)
+}
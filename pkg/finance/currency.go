@@ -0,0 +1,229 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package finance
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/renato0307/canivete-core/interface/finance"
+)
+
+type currency struct {
+	code       string
+	numeric    string
+	name       string
+	symbol     string
+	minorUnits int
+}
+
+// currencies contains the ISO 4217 metadata of the supported currencies,
+// which include all the currencies published by the European Central Bank.
+var currencies = map[string]currency{
+	"AED": {"AED", "784", "UAE Dirham", "د.إ", 2},
+	"ARS": {"ARS", "032", "Argentine Peso", "$", 2},
+	"AUD": {"AUD", "036", "Australian Dollar", "A$", 2},
+	"BGN": {"BGN", "975", "Bulgarian Lev", "лв", 2},
+	"BHD": {"BHD", "048", "Bahraini Dinar", "BD", 3},
+	"BRL": {"BRL", "986", "Brazilian Real", "R$", 2},
+	"CAD": {"CAD", "124", "Canadian Dollar", "CA$", 2},
+	"CHF": {"CHF", "756", "Swiss Franc", "CHF", 2},
+	"CLP": {"CLP", "152", "Chilean Peso", "$", 0},
+	"CNY": {"CNY", "156", "Yuan Renminbi", "¥", 2},
+	"CZK": {"CZK", "203", "Czech Koruna", "Kč", 2},
+	"DKK": {"DKK", "208", "Danish Krone", "kr.", 2},
+	"EUR": {"EUR", "978", "Euro", "€", 2},
+	"GBP": {"GBP", "826", "Pound Sterling", "£", 2},
+	"HKD": {"HKD", "344", "Hong Kong Dollar", "HK$", 2},
+	"HRK": {"HRK", "191", "Kuna", "kn", 2},
+	"HUF": {"HUF", "348", "Forint", "Ft", 2},
+	"IDR": {"IDR", "360", "Rupiah", "Rp", 2},
+	"ILS": {"ILS", "376", "New Israeli Sheqel", "₪", 2},
+	"INR": {"INR", "356", "Indian Rupee", "₹", 2},
+	"ISK": {"ISK", "352", "Iceland Krona", "kr", 0},
+	"JPY": {"JPY", "392", "Yen", "¥", 0},
+	"KRW": {"KRW", "410", "Won", "₩", 0},
+	"KWD": {"KWD", "414", "Kuwaiti Dinar", "KD", 3},
+	"MXN": {"MXN", "484", "Mexican Peso", "MX$", 2},
+	"MYR": {"MYR", "458", "Malaysian Ringgit", "RM", 2},
+	"NOK": {"NOK", "578", "Norwegian Krone", "kr", 2},
+	"NZD": {"NZD", "554", "New Zealand Dollar", "NZ$", 2},
+	"PHP": {"PHP", "608", "Philippine Peso", "₱", 2},
+	"PLN": {"PLN", "985", "Zloty", "zł", 2},
+	"RON": {"RON", "946", "Romanian Leu", "lei", 2},
+	"RUB": {"RUB", "643", "Russian Ruble", "₽", 2},
+	"SAR": {"SAR", "682", "Saudi Riyal", "SR", 2},
+	"SEK": {"SEK", "752", "Swedish Krona", "kr", 2},
+	"SGD": {"SGD", "702", "Singapore Dollar", "S$", 2},
+	"THB": {"THB", "764", "Baht", "฿", 2},
+	"TRY": {"TRY", "949", "Turkish Lira", "₺", 2},
+	"USD": {"USD", "840", "US Dollar", "$", 2},
+	"ZAR": {"ZAR", "710", "Rand", "R", 2},
+}
+
+func lookupCurrency(code string) (currency, error) {
+	c, ok := currencies[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return c, fmt.Errorf("unknown currency %q", code)
+	}
+
+	return c, nil
+}
+
+// GetCurrency returns the ISO 4217 metadata of a currency.
+func (s *Service) GetCurrency(code string) (finance.CurrencyOutput, error) {
+	return s.GetCurrencyContext(context.Background(), code)
+}
+
+func (s *Service) GetCurrencyContext(ctx context.Context, code string) (finance.CurrencyOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return finance.CurrencyOutput{}, err
+	}
+
+	c, err := lookupCurrency(code)
+	if err != nil {
+		return finance.CurrencyOutput{}, err
+	}
+
+	return finance.CurrencyOutput{
+		Code:       c.code,
+		Numeric:    c.numeric,
+		Name:       c.name,
+		Symbol:     c.symbol,
+		MinorUnits: c.minorUnits,
+	}, nil
+}
+
+// ConvertCurrency converts an amount between two currencies.
+//
+// The converted amount is rounded to the minor units of the target currency.
+func (s *Service) ConvertCurrency(amount float64, from, to string) (finance.ConvertCurrencyOutput, error) {
+	return s.ConvertCurrencyContext(context.Background(), amount, from, to)
+}
+
+func (s *Service) ConvertCurrencyContext(ctx context.Context, amount float64, from, to string) (finance.ConvertCurrencyOutput, error) {
+	output := finance.ConvertCurrencyOutput{Amount: amount}
+
+	converter, err := s.newConverter(ctx, from, to)
+	if err != nil {
+		return output, err
+	}
+
+	output.From = converter.from.code
+	output.To = converter.to.code
+	output.Rate = converter.rate
+	output.ConvertedAmount = converter.convert(amount)
+	output.RatesDate = converter.table.Date
+	output.RatesFetchedAt = converter.table.FetchedAt
+
+	return output, nil
+}
+
+// ConvertCompoundInterests converts all the amounts of a compound interests
+// calculation, including the history, between two currencies.
+//
+// If from is empty the currency of the input is used.
+func (s *Service) ConvertCompoundInterests(input finance.CompoundInterestsOutput, from, to string) (finance.CompoundInterestsOutput, error) {
+	return s.ConvertCompoundInterestsContext(context.Background(), input, from, to)
+}
+
+func (s *Service) ConvertCompoundInterestsContext(ctx context.Context, input finance.CompoundInterestsOutput, from, to string) (finance.CompoundInterestsOutput, error) {
+	output := finance.CompoundInterestsOutput{
+		Total:   finance.CompoundInterestsDetailOutput{},
+		History: []finance.CompoundInterestsHistoryEntryOutput{},
+	}
+
+	if from == "" {
+		from = input.Currency
+	}
+	if from == "" {
+		return output, fmt.Errorf("the currency of the input is unknown")
+	}
+
+	converter, err := s.newConverter(ctx, from, to)
+	if err != nil {
+		return output, err
+	}
+
+	output.Currency = converter.to.code
+	output.Total = converter.convertDetail(input.Total)
+	for _, entry := range input.History {
+		if err := ctx.Err(); err != nil {
+			return output, err
+		}
+
+		output.History = append(output.History, finance.CompoundInterestsHistoryEntryOutput{
+			Period: entry.Period,
+			Totals: converter.convertDetail(entry.Totals),
+		})
+	}
+
+	return output, nil
+}
+
+type converter struct {
+	from  currency
+	to    currency
+	rate  float64
+	table RateTable
+}
+
+func (s *Service) newConverter(ctx context.Context, from, to string) (converter, error) {
+	c := converter{}
+
+	var err error
+	c.from, err = lookupCurrency(from)
+	if err != nil {
+		return c, err
+	}
+	c.to, err = lookupCurrency(to)
+	if err != nil {
+		return c, err
+	}
+
+	c.table, err = s.rates().Rates(ctx)
+	if err != nil {
+		return c, fmt.Errorf("error getting exchange rates: %w", err)
+	}
+
+	c.rate, err = c.table.Rate(c.from.code, c.to.code)
+	if err != nil {
+		return c, err
+	}
+
+	return c, nil
+}
+
+func (c converter) convert(amount float64) float64 {
+	return roundMinorUnits(amount*c.rate, c.to.minorUnits)
+}
+
+func (c converter) convertDetail(detail finance.CompoundInterestsDetailOutput) finance.CompoundInterestsDetailOutput {
+	output := finance.CompoundInterestsDetailOutput{}
+	output.FinalAmount = c.convert(detail.FinalAmount)
+	output.TotalContributions = c.convert(detail.TotalContributions)
+	output.Interests = roundMinorUnits(output.FinalAmount-output.TotalContributions, c.to.minorUnits)
+
+	return output
+}
+
+func roundMinorUnits(value float64, minorUnits int) float64 {
+	factor := math.Pow(10, float64(minorUnits))
+	return math.Round(value*factor) / factor
+}
@@ -0,0 +1,110 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package finance
+
+import (
+	"testing"
+
+	"github.com/renato0307/canivete-core/interface/finance"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestService() *Service {
+	return NewService(WithRateProvider(CSVRateProvider{Path: "testdata/rates.csv"}))
+}
+
+func TestGetCurrency(t *testing.T) {
+	// act
+	s := Service{}
+	output, err := s.GetCurrency("jpy")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "JPY", output.Code)
+	assert.Equal(t, "392", output.Numeric)
+	assert.Equal(t, 0, output.MinorUnits)
+}
+
+func TestGetCurrencyUnknown(t *testing.T) {
+	// act
+	s := Service{}
+	_, err := s.GetCurrency("XYZ")
+
+	// assert
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unknown currency")
+}
+
+func TestConvertCurrency(t *testing.T) {
+	// act
+	s := newTestService()
+	output, err := s.ConvertCurrency(100, "EUR", "USD")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, 112.85, output.ConvertedAmount)
+	assert.Equal(t, 1.1285, output.Rate)
+	assert.Equal(t, "2021-12-17", output.RatesDate.Format("2006-01-02"))
+}
+
+func TestConvertCurrencyRoundsToMinorUnits(t *testing.T) {
+	// act
+	s := newTestService()
+	output, err := s.ConvertCurrency(100, "USD", "JPY")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, 11354.0, output.ConvertedAmount)
+}
+
+func TestConvertCurrencyWithoutRate(t *testing.T) {
+	// act
+	s := newTestService()
+	_, err := s.ConvertCurrency(100, "EUR", "CHF")
+
+	// assert
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "no exchange rate available for CHF")
+}
+
+func TestConvertCompoundInterests(t *testing.T) {
+	// arrange
+	s := NewService(WithRateProvider(CSVRateProvider{Path: "testdata/rates.csv"}), WithCurrency("EUR"))
+	input, _ := s.CalculateCompoundInterests(1000, 1, 2, 0, 0, 5)
+
+	// act
+	output, err := s.ConvertCompoundInterests(input, "", "GBP")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "GBP", output.Currency)
+	assert.Equal(t, 938.7, output.Total.FinalAmount)
+	assert.Equal(t, 851.43, output.Total.TotalContributions)
+	assert.Equal(t, 87.27, output.Total.Interests)
+	assert.Len(t, output.History, 2)
+	assert.Equal(t, 894.0, output.History[0].Totals.FinalAmount)
+}
+
+func TestConvertCompoundInterestsUnknownCurrency(t *testing.T) {
+	// act
+	s := newTestService()
+	_, err := s.ConvertCompoundInterests(finance.CompoundInterestsOutput{}, "", "GBP")
+
+	// assert
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "currency of the input is unknown")
+}
@@ -0,0 +1,464 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package finance
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/renato0307/canivete-core/interface/finance"
+)
+
+// table is the tabular representation of a finance result, used by
+// the exporters.
+type table struct {
+	columns []tableColumn
+	rows    [][]tableCell
+}
+
+type tableColumn struct {
+	key    string
+	header string
+}
+
+// tableCell is either a text or an amount.
+type tableCell struct {
+	text     string
+	amount   float64
+	isAmount bool
+}
+
+func textCell(text string) tableCell {
+	return tableCell{text: text}
+}
+
+func amountCell(amount float64) tableCell {
+	return tableCell{amount: amount, isAmount: true}
+}
+
+func compoundInterestsTable(input finance.CompoundInterestsOutput) table {
+	t := table{
+		columns: []tableColumn{
+			{"period", "Period"},
+			{"final_amount", "Final Amount"},
+			{"total_contributions", "Total Contributions"},
+			{"interests", "Interests"},
+		},
+	}
+
+	for _, entry := range input.History {
+		t.rows = append(t.rows, []tableCell{
+			textCell(entry.Period),
+			amountCell(entry.Totals.FinalAmount),
+			amountCell(entry.Totals.TotalContributions),
+			amountCell(entry.Totals.Interests),
+		})
+	}
+
+	return t
+}
+
+// selectColumns returns a table with only the columns with the given
+// keys, in the given order.
+func (t table) selectColumns(keys []string) (table, error) {
+	if len(keys) == 0 {
+		return t, nil
+	}
+
+	indexes := []int{}
+	selected := table{}
+	for _, key := range keys {
+		found := false
+		for i, column := range t.columns {
+			if column.key == strings.ToLower(strings.TrimSpace(key)) {
+				indexes = append(indexes, i)
+				selected.columns = append(selected.columns, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return selected, fmt.Errorf("unknown column %q", key)
+		}
+	}
+
+	for _, row := range t.rows {
+		selectedRow := []tableCell{}
+		for _, i := range indexes {
+			selectedRow = append(selectedRow, row[i])
+		}
+		selected.rows = append(selected.rows, selectedRow)
+	}
+
+	return selected, nil
+}
+
+type exporter struct {
+	contentType   string
+	fileExtension string
+	export        func(ctx context.Context, t table, f *formatter) ([]byte, error)
+}
+
+var exporters = map[string]exporter{
+	"csv":      {"text/csv", "csv", exportCSV(',')},
+	"tsv":      {"text/tab-separated-values", "tsv", exportCSV('\t')},
+	"markdown": {"text/markdown", "md", exportMarkdown},
+	"jsonl":    {"application/jsonl", "jsonl", exportJSONLines},
+	"xlsx":     {"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "xlsx", exportXLSX},
+}
+
+// ExportCompoundInterests exports the history of a compound interests
+// calculation to a tabular format.
+//
+// When a locale is given the amounts are formatted with the locale and
+// the currency of the input, except for xlsx where they are always
+// written as numbers so spreadsheets can use them in formulas.
+func (s *Service) ExportCompoundInterests(input finance.CompoundInterestsOutput, options finance.ExportOptions) (finance.ExportOutput, error) {
+	return s.ExportCompoundInterestsContext(context.Background(), input, options)
+}
+
+func (s *Service) ExportCompoundInterestsContext(ctx context.Context, input finance.CompoundInterestsOutput, options finance.ExportOptions) (finance.ExportOutput, error) {
+	return exportTable(ctx, compoundInterestsTable(input), input.Currency, options)
+}
+
+// ExportTable exports any tabular result, with the same formats and
+// options as ExportCompoundInterests.
+func (s *Service) ExportTable(input finance.Table, options finance.ExportOptions) (finance.ExportOutput, error) {
+	return s.ExportTableContext(context.Background(), input, options)
+}
+
+func (s *Service) ExportTableContext(ctx context.Context, input finance.Table, options finance.ExportOptions) (finance.ExportOutput, error) {
+	t, err := newTable(input)
+	if err != nil {
+		return finance.ExportOutput{}, err
+	}
+
+	return exportTable(ctx, t, input.Currency, options)
+}
+
+// newTable validates a table given to export, which must have unique
+// keys for all the columns, a cell per column in each row and finite
+// amounts.
+func newTable(input finance.Table) (table, error) {
+	t := table{}
+
+	keys := map[string]bool{}
+	for i, column := range input.Columns {
+		key := strings.ToLower(strings.TrimSpace(column.Key))
+		if key == "" {
+			return t, fmt.Errorf("column %d has no key", i+1)
+		}
+		if keys[key] {
+			return t, fmt.Errorf("column %d has the duplicate key %q", i+1, key)
+		}
+		keys[key] = true
+		t.columns = append(t.columns, tableColumn{key, column.Header})
+	}
+
+	for i, row := range input.Rows {
+		if len(row) != len(t.columns) {
+			return t, fmt.Errorf("row %d has %d cells but there are %d columns", i+1, len(row), len(t.columns))
+		}
+
+		cells := []tableCell{}
+		for j, cell := range row {
+			if cell.IsAmount && (math.IsNaN(cell.Amount) || math.IsInf(cell.Amount, 0)) {
+				return t, fmt.Errorf("row %d has the invalid amount %v in column %d", i+1, cell.Amount, j+1)
+			}
+			cells = append(cells, tableCell{text: cell.Text, amount: cell.Amount, isAmount: cell.IsAmount})
+		}
+		t.rows = append(t.rows, cells)
+	}
+
+	return t, nil
+}
+
+func exportTable(ctx context.Context, t table, currencyCode string, options finance.ExportOptions) (finance.ExportOutput, error) {
+	output := finance.ExportOutput{}
+
+	format := strings.ToLower(options.Format)
+	exp, ok := exporters[format]
+	if !ok {
+		return output, fmt.Errorf("unsupported export format %q", options.Format)
+	}
+
+	t, err := t.selectColumns(options.Columns)
+	if err != nil {
+		return output, err
+	}
+
+	var f *formatter
+	if options.Locale != "" {
+		localeFormatter, err := newFormatter(currencyCode, options.Locale)
+		if err != nil {
+			return output, err
+		}
+		f = &localeFormatter
+	}
+
+	data, err := exp.export(ctx, t, f)
+	if err != nil {
+		return output, fmt.Errorf("error exporting to %s: %w", format, err)
+	}
+
+	output.Format = format
+	output.ContentType = exp.contentType
+	output.FileExtension = exp.fileExtension
+	output.Data = data
+
+	return output, nil
+}
+
+// cellText returns the text of a cell, with amounts formatted by the
+// formatter or as raw numbers with two decimal places if it is nil.
+func cellText(cell tableCell, f *formatter) string {
+	if !cell.isAmount {
+		return cell.text
+	}
+	if f != nil {
+		return f.format(cell.amount)
+	}
+
+	return strconv.FormatFloat(cell.amount, 'f', 2, 64)
+}
+
+func exportCSV(separator rune) func(ctx context.Context, t table, f *formatter) ([]byte, error) {
+	return func(ctx context.Context, t table, f *formatter) ([]byte, error) {
+		var buffer bytes.Buffer
+		writer := csv.NewWriter(&buffer)
+		writer.Comma = separator
+
+		headers := []string{}
+		for _, column := range t.columns {
+			headers = append(headers, column.header)
+		}
+		if err := writer.Write(headers); err != nil {
+			return nil, err
+		}
+
+		for _, row := range t.rows {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			record := []string{}
+			for _, cell := range row {
+				record = append(record, cellText(cell, f))
+			}
+			if err := writer.Write(record); err != nil {
+				return nil, err
+			}
+		}
+
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return nil, err
+		}
+
+		return buffer.Bytes(), nil
+	}
+}
+
+func exportMarkdown(ctx context.Context, t table, f *formatter) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	headers := []string{}
+	alignments := []string{}
+	for i, column := range t.columns {
+		headers = append(headers, escapeMarkdownCell(column.header))
+		if len(t.rows) > 0 && t.rows[0][i].isAmount {
+			alignments = append(alignments, "---:")
+		} else {
+			alignments = append(alignments, "---")
+		}
+	}
+	buffer.WriteString(fmt.Sprintf("| %s |\n", strings.Join(headers, " | ")))
+	buffer.WriteString(fmt.Sprintf("| %s |\n", strings.Join(alignments, " | ")))
+
+	for _, row := range t.rows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		cells := []string{}
+		for _, cell := range row {
+			cells = append(cells, escapeMarkdownCell(cellText(cell, f)))
+		}
+		buffer.WriteString(fmt.Sprintf("| %s |\n", strings.Join(cells, " | ")))
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// markdownCellEscaper escapes the pipes and replaces the line breaks,
+// which would otherwise end the row, by HTML breaks.
+var markdownCellEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+func escapeMarkdownCell(text string) string {
+	return markdownCellEscaper.Replace(text)
+}
+
+// exportJSONLines writes one JSON object per row, keeping the order of
+// the columns. Amounts are numbers unless a formatter is given.
+func exportJSONLines(ctx context.Context, t table, f *formatter) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	for _, row := range t.rows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		buffer.WriteString("{")
+		for i, cell := range row {
+			if i > 0 {
+				buffer.WriteString(",")
+			}
+
+			key, _ := json.Marshal(t.columns[i].key)
+			var value []byte
+			var err error
+			if cell.isAmount && f == nil {
+				value, err = json.Marshal(cell.amount)
+			} else {
+				value, err = json.Marshal(cellText(cell, f))
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			buffer.Write(key)
+			buffer.WriteString(":")
+			buffer.Write(value)
+		}
+		buffer.WriteString("}\n")
+	}
+
+	return buffer.Bytes(), nil
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/></Types>`
+
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`
+
+	// the second cell format uses the built-in "#,##0.00" number format
+	// and the third one is bold, for the headers
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts><fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills><borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders><cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs><cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs></styleSheet>`
+)
+
+// exportXLSX writes an Office Open XML spreadsheet with a single sheet.
+func exportXLSX(ctx context.Context, t table, _ *formatter) ([]byte, error) {
+	var sheet bytes.Buffer
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	headers := []tableCell{}
+	for _, column := range t.columns {
+		headers = append(headers, textCell(column.header))
+	}
+	writeXLSXRow(&sheet, 1, headers, 2)
+	for i, row := range t.rows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		writeXLSXRow(&sheet, i+2, row, 0)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+		{"xl/worksheets/sheet1.xml", sheet.String()},
+	}
+
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	for _, file := range files {
+		writer, err := archive.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		_, err = writer.Write([]byte(file.content))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := archive.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func writeXLSXRow(sheet *bytes.Buffer, number int, cells []tableCell, textStyle int) {
+	sheet.WriteString(fmt.Sprintf(`<row r="%d">`, number))
+	for i, cell := range cells {
+		reference := fmt.Sprintf("%s%d", xlsxColumnName(i), number)
+		if cell.isAmount {
+			sheet.WriteString(fmt.Sprintf(`<c r="%s" s="1"><v>%s</v></c>`,
+				reference,
+				strconv.FormatFloat(cell.amount, 'f', -1, 64)))
+			continue
+		}
+
+		var text bytes.Buffer
+		xml.EscapeText(&text, []byte(cell.text))
+		sheet.WriteString(fmt.Sprintf(`<c r="%s" s="%d" t="inlineStr"><is><t>%s</t></is></c>`,
+			reference,
+			textStyle,
+			text.String()))
+	}
+	sheet.WriteString(`</row>`)
+}
+
+// xlsxColumnName converts a zero based column index to its name,
+// e.g. 0 is A, 25 is Z and 26 is AA.
+func xlsxColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+
+	return name
+}
@@ -0,0 +1,238 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package finance
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/renato0307/canivete-core/interface/finance"
+	"github.com/stretchr/testify/assert"
+)
+
+func newExportInput() finance.CompoundInterestsOutput {
+	s := NewService(WithCurrency("EUR"))
+	input, _ := s.CalculateCompoundInterests(1000, 1, 2, 100, 1, 5)
+
+	return input
+}
+
+func TestExportCompoundInterestsCSV(t *testing.T) {
+	// act
+	s := Service{}
+	output, err := s.ExportCompoundInterests(newExportInput(), finance.ExportOptions{Format: "csv"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "text/csv", output.ContentType)
+	assert.Equal(t, "csv", output.FileExtension)
+	assert.Equal(t,
+		"Period,Final Amount,Total Contributions,Interests\n"+
+			"1,1150.00,1100.00,50.00\n"+
+			"2,1307.50,1200.00,107.50\n",
+		string(output.Data))
+}
+
+func TestExportCompoundInterestsTSVWithLocale(t *testing.T) {
+	// act
+	s := Service{}
+	output, err := s.ExportCompoundInterests(newExportInput(), finance.ExportOptions{
+		Format:  "tsv",
+		Columns: []string{"period", "final_amount"},
+		Locale:  "pt-PT",
+	})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t,
+		"Period\tFinal Amount\n"+
+			"1\t1.150,00 €\n"+
+			"2\t1.307,50 €\n",
+		string(output.Data))
+}
+
+func TestExportCompoundInterestsMarkdown(t *testing.T) {
+	// act
+	s := Service{}
+	output, err := s.ExportCompoundInterests(newExportInput(), finance.ExportOptions{
+		Format:  "markdown",
+		Columns: []string{"period", "interests"},
+	})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t,
+		"| Period | Interests |\n"+
+			"| --- | ---: |\n"+
+			"| 1 | 50.00 |\n"+
+			"| 2 | 107.50 |\n",
+		string(output.Data))
+}
+
+func TestExportCompoundInterestsJSONLines(t *testing.T) {
+	// act
+	s := Service{}
+	output, err := s.ExportCompoundInterests(newExportInput(), finance.ExportOptions{
+		Format:  "jsonl",
+		Columns: []string{"interests", "period"},
+	})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t,
+		`{"interests":50,"period":"1"}`+"\n"+
+			`{"interests":107.5,"period":"2"}`+"\n",
+		string(output.Data))
+}
+
+func TestExportCompoundInterestsXLSX(t *testing.T) {
+	// act
+	s := Service{}
+	output, err := s.ExportCompoundInterests(newExportInput(), finance.ExportOptions{Format: "XLSX"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "xlsx", output.Format)
+
+	archive, err := zip.NewReader(bytes.NewReader(output.Data), int64(len(output.Data)))
+	assert.Nil(t, err)
+
+	names := []string{}
+	var sheet string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			reader, _ := file.Open()
+			data, _ := ioutil.ReadAll(reader)
+			sheet = string(data)
+		}
+	}
+	assert.Contains(t, names, "[Content_Types].xml")
+	assert.Contains(t, names, "xl/workbook.xml")
+	assert.Contains(t, sheet, `<c r="D3" s="1"><v>107.5</v></c>`)
+	assert.Contains(t, sheet, `<c r="B1" s="2" t="inlineStr"><is><t>Final Amount</t></is></c>`)
+}
+
+func TestExportCompoundInterestsInvalidOptions(t *testing.T) {
+	// act
+	s := Service{}
+	_, formatErr := s.ExportCompoundInterests(newExportInput(), finance.ExportOptions{Format: "pdf"})
+	_, columnErr := s.ExportCompoundInterests(newExportInput(), finance.ExportOptions{
+		Format:  "csv",
+		Columns: []string{"rate"},
+	})
+
+	// assert
+	assert.NotNil(t, formatErr)
+	assert.Contains(t, formatErr.Error(), "unsupported export format")
+	assert.NotNil(t, columnErr)
+	assert.Contains(t, columnErr.Error(), "unknown column")
+}
+
+func TestExportTable(t *testing.T) {
+	// arrange
+	input := finance.Table{
+		Currency: "USD",
+		Columns:  []finance.TableColumn{{Key: "Code", Header: "Code"}, {Key: "amount", Header: "Amount"}},
+		Rows: [][]finance.TableCell{
+			{{Text: "EUR"}, {Amount: 1234.5, IsAmount: true}},
+			{{Text: "GBP"}, {Amount: 99, IsAmount: true}},
+		},
+	}
+
+	// act
+	s := Service{}
+	output, err := s.ExportTable(input, finance.ExportOptions{Format: "csv", Columns: []string{"amount", "code"}, Locale: "en-US"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "text/csv", output.ContentType)
+	assert.Equal(t,
+		"Amount,Code\n"+
+			"\"$1,234.50\",EUR\n"+
+			"$99.00,GBP\n",
+		string(output.Data))
+}
+
+func TestExportTableInvalid(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input finance.Table
+		want  string
+	}{
+		{
+			name:  "column without key",
+			input: finance.Table{Columns: []finance.TableColumn{{Header: "Code"}}},
+			want:  "column 1 has no key",
+		},
+		{
+			name: "row without all the cells",
+			input: finance.Table{
+				Columns: []finance.TableColumn{{Key: "code"}, {Key: "amount"}},
+				Rows:    [][]finance.TableCell{{{Text: "EUR"}}},
+			},
+			want: "row 1 has 1 cells but there are 2 columns",
+		},
+		{
+			name:  "duplicate column keys",
+			input: finance.Table{Columns: []finance.TableColumn{{Key: "code"}, {Key: " Code "}}},
+			want:  `column 2 has the duplicate key "code"`,
+		},
+		{
+			name: "amount not a number",
+			input: finance.Table{
+				Columns: []finance.TableColumn{{Key: "code"}, {Key: "amount"}},
+				Rows:    [][]finance.TableCell{{{Text: "EUR"}, {Amount: math.NaN(), IsAmount: true}}},
+			},
+			want: "row 1 has the invalid amount NaN in column 2",
+		},
+		{
+			name: "infinite amount",
+			input: finance.Table{
+				Columns: []finance.TableColumn{{Key: "amount"}},
+				Rows:    [][]finance.TableCell{{{Amount: 1}}, {{Amount: math.Inf(1), IsAmount: true}}},
+			},
+			want: "row 2 has the invalid amount +Inf in column 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			s := Service{}
+			_, err := s.ExportTable(tc.input, finance.ExportOptions{Format: "csv"})
+
+			// assert
+			assert.EqualError(t, err, tc.want)
+		})
+	}
+}
+
+func TestEscapeMarkdownCell(t *testing.T) {
+	assert.Equal(t, `a \| b`, escapeMarkdownCell("a | b"))
+	assert.Equal(t, "first<br>second<br>third", escapeMarkdownCell("first\nsecond\r\nthird"))
+}
+
+func TestXlsxColumnName(t *testing.T) {
+	assert.Equal(t, "A", xlsxColumnName(0))
+	assert.Equal(t, "Z", xlsxColumnName(25))
+	assert.Equal(t, "AA", xlsxColumnName(26))
+	assert.Equal(t, "BA", xlsxColumnName(52))
+}
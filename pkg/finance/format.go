@@ -0,0 +1,285 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package finance
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/renato0307/canivete-core/interface/finance"
+)
+
+type negativeStyle int
+
+const (
+	// -€1.00 or -1,00 €
+	negativeLeadingMinus negativeStyle = iota
+	// € -1,00
+	negativeMinusAfterSymbol
+	// (€1.00)
+	negativeParentheses
+	// 1,00- €
+	negativeTrailingMinus
+)
+
+type locale struct {
+	tag              string
+	groupSeparator   string
+	decimalSeparator string
+	// groupSize is the size of the first group of digits, left of the
+	// decimal separator, and secondaryGroupSize the size of the others
+	groupSize          int
+	secondaryGroupSize int
+	symbolAfter        bool
+	symbolSpace        bool
+	negative           negativeStyle
+}
+
+var locales = map[string]locale{
+	"en-US": {"en-US", ",", ".", 3, 3, false, false, negativeLeadingMinus},
+	"en-GB": {"en-GB", ",", ".", 3, 3, false, false, negativeLeadingMinus},
+	"en-IN": {"en-IN", ",", ".", 3, 2, false, false, negativeLeadingMinus},
+	"pt-PT": {"pt-PT", ".", ",", 3, 3, true, true, negativeLeadingMinus},
+	"pt-BR": {"pt-BR", ".", ",", 3, 3, false, true, negativeLeadingMinus},
+	"de-DE": {"de-DE", ".", ",", 3, 3, true, true, negativeLeadingMinus},
+	"de-AT": {"de-AT", " ", ",", 3, 3, false, true, negativeMinusAfterSymbol},
+	"de-CH": {"de-CH", "’", ".", 3, 3, false, true, negativeMinusAfterSymbol},
+	"fr-FR": {"fr-FR", " ", ",", 3, 3, true, true, negativeLeadingMinus},
+	"es-ES": {"es-ES", ".", ",", 3, 3, true, true, negativeLeadingMinus},
+	"it-IT": {"it-IT", ".", ",", 3, 3, true, true, negativeLeadingMinus},
+	"nl-NL": {"nl-NL", ".", ",", 3, 3, false, true, negativeMinusAfterSymbol},
+	"ja-JP": {"ja-JP", ",", ".", 3, 3, false, false, negativeLeadingMinus},
+	"da-DK": {"da-DK", ".", ",", 3, 3, true, true, negativeTrailingMinus},
+	// accounting style, used in financial statements
+	"en-US-accounting": {"en-US-accounting", ",", ".", 3, 3, false, false, negativeParentheses},
+}
+
+// defaultLocales maps languages to their most common locale.
+var defaultLocales = map[string]string{
+	"en": "en-US",
+	"pt": "pt-PT",
+	"de": "de-DE",
+	"fr": "fr-FR",
+	"es": "es-ES",
+	"it": "it-IT",
+	"nl": "nl-NL",
+	"ja": "ja-JP",
+	"da": "da-DK",
+}
+
+// lookupLocale finds a locale by its tag, accepting both "pt-PT" and
+// "pt_pt" forms, or just the language.
+func lookupLocale(tag string) (locale, error) {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"), "-")
+	parts[0] = strings.ToLower(parts[0])
+	if len(parts) > 1 {
+		parts[1] = strings.ToUpper(parts[1])
+	}
+	for i := 2; i < len(parts); i++ {
+		parts[i] = strings.ToLower(parts[i])
+	}
+
+	normalized := strings.Join(parts, "-")
+	if len(parts) == 1 {
+		normalized = defaultLocales[normalized]
+	}
+
+	l, ok := locales[normalized]
+	if !ok {
+		return l, fmt.Errorf("unsupported locale %q", tag)
+	}
+
+	return l, nil
+}
+
+// FormatAmount formats an amount according to the conventions of a
+// locale, e.g. 1234.56 EUR is formatted as "1.234,56 €" in pt-PT.
+//
+// If currency is empty the amount is formatted as a plain number with
+// two decimal places.
+func (s *Service) FormatAmount(amount float64, currency, locale string) (finance.FormatAmountOutput, error) {
+	return s.FormatAmountContext(context.Background(), amount, currency, locale)
+}
+
+func (s *Service) FormatAmountContext(ctx context.Context, amount float64, currency, locale string) (finance.FormatAmountOutput, error) {
+	output := finance.FormatAmountOutput{Amount: amount}
+
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
+	f, err := newFormatter(currency, locale)
+	if err != nil {
+		return output, err
+	}
+
+	output.Currency = f.currency.code
+	output.Locale = f.locale.tag
+	output.Formatted = f.format(amount)
+
+	return output, nil
+}
+
+// FormatCompoundInterests formats all the amounts of a compound interests
+// calculation, including the history, using the currency of the input.
+func (s *Service) FormatCompoundInterests(input finance.CompoundInterestsOutput, locale string) (finance.FormattedCompoundInterestsOutput, error) {
+	return s.FormatCompoundInterestsContext(context.Background(), input, locale)
+}
+
+func (s *Service) FormatCompoundInterestsContext(ctx context.Context, input finance.CompoundInterestsOutput, locale string) (finance.FormattedCompoundInterestsOutput, error) {
+	output := finance.FormattedCompoundInterestsOutput{
+		Total:   finance.FormattedCompoundInterestsDetailOutput{},
+		History: []finance.FormattedCompoundInterestsHistoryEntryOutput{},
+	}
+
+	f, err := newFormatter(input.Currency, locale)
+	if err != nil {
+		return output, err
+	}
+
+	output.Locale = f.locale.tag
+	output.Currency = f.currency.code
+	output.Total = f.formatDetail(input.Total)
+	for _, entry := range input.History {
+		if err := ctx.Err(); err != nil {
+			return output, err
+		}
+
+		output.History = append(output.History, finance.FormattedCompoundInterestsHistoryEntryOutput{
+			Period: entry.Period,
+			Totals: f.formatDetail(entry.Totals),
+		})
+	}
+
+	return output, nil
+}
+
+type formatter struct {
+	locale   locale
+	currency currency
+}
+
+func newFormatter(currencyCode, localeTag string) (formatter, error) {
+	f := formatter{currency: currency{minorUnits: 2}}
+
+	var err error
+	f.locale, err = lookupLocale(localeTag)
+	if err != nil {
+		return f, err
+	}
+
+	if currencyCode != "" {
+		f.currency, err = lookupCurrency(currencyCode)
+		if err != nil {
+			return f, err
+		}
+	}
+
+	return f, nil
+}
+
+func (f formatter) formatDetail(detail finance.CompoundInterestsDetailOutput) finance.FormattedCompoundInterestsDetailOutput {
+	return finance.FormattedCompoundInterestsDetailOutput{
+		FinalAmount:        f.format(detail.FinalAmount),
+		TotalContributions: f.format(detail.TotalContributions),
+		Interests:          f.format(detail.Interests),
+	}
+}
+
+func (f formatter) format(amount float64) string {
+	number := f.formatNumber(math.Abs(amount))
+	negative := amount < 0 && strings.Trim(number, "0"+f.locale.decimalSeparator+f.locale.groupSeparator) != ""
+
+	symbol := f.currency.symbol
+	if symbol == "" {
+		if negative {
+			if f.locale.negative == negativeParentheses {
+				return "(" + number + ")"
+			}
+			if f.locale.negative == negativeTrailingMinus {
+				return number + "-"
+			}
+			return "-" + number
+		}
+		return number
+	}
+
+	space := ""
+	if f.locale.symbolSpace {
+		space = " "
+	}
+
+	if !negative {
+		if f.locale.symbolAfter {
+			return number + space + symbol
+		}
+		return symbol + space + number
+	}
+
+	switch f.locale.negative {
+	case negativeParentheses:
+		if f.locale.symbolAfter {
+			return "(" + number + space + symbol + ")"
+		}
+		return "(" + symbol + space + number + ")"
+	case negativeTrailingMinus:
+		if f.locale.symbolAfter {
+			return number + "-" + space + symbol
+		}
+		return symbol + space + number + "-"
+	case negativeMinusAfterSymbol:
+		if f.locale.symbolAfter {
+			return "-" + number + space + symbol
+		}
+		return symbol + space + "-" + number
+	default:
+		if f.locale.symbolAfter {
+			return "-" + number + space + symbol
+		}
+		return "-" + symbol + space + number
+	}
+}
+
+// formatNumber formats a positive number with the separators of the
+// locale and the minor units of the currency.
+func (f formatter) formatNumber(value float64) string {
+	digits := strconv.FormatFloat(value, 'f', f.currency.minorUnits, 64)
+
+	integer, fraction := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		integer, fraction = digits[:i], digits[i+1:]
+	}
+
+	groups := []string{}
+	size := f.locale.groupSize
+	for len(integer) > size {
+		groups = append([]string{integer[len(integer)-size:]}, groups...)
+		integer = integer[:len(integer)-size]
+		size = f.locale.secondaryGroupSize
+	}
+	groups = append([]string{integer}, groups...)
+
+	number := strings.Join(groups, f.locale.groupSeparator)
+	if fraction != "" {
+		number += f.locale.decimalSeparator + fraction
+	}
+
+	return number
+}
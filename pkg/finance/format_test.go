@@ -0,0 +1,86 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package finance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		locale   string
+		expected string
+	}{
+		{1234.56, "EUR", "pt-PT", "1.234,56 €"},
+		{1234.56, "EUR", "de_de", "1.234,56 €"},
+		{-1234.56, "EUR", "de-DE", "-1.234,56 €"},
+		{1234.56, "USD", "en-US", "$1,234.56"},
+		{-1234.56, "USD", "en-US", "-$1,234.56"},
+		{-1234.56, "USD", "en-US-accounting", "($1,234.56)"},
+		{1234.56, "BRL", "pt-BR", "R$ 1.234,56"},
+		{-1234.56, "EUR", "nl-NL", "€ -1.234,56"},
+		{1234567.891, "CHF", "de-CH", "CHF 1’234’567.89"},
+		{1234567.5, "JPY", "ja", "¥1,234,568"},
+		{12345678.9, "INR", "en-IN", "₹1,23,45,678.90"},
+		{-5, "DKK", "da-DK", "5,00- kr."},
+		{1234.5, "", "fr-FR", "1 234,50"},
+		{-0.001, "EUR", "pt-PT", "0,00 €"},
+	}
+
+	for _, test := range tests {
+		// act
+		s := Service{}
+		output, err := s.FormatAmount(test.amount, test.currency, test.locale)
+
+		// assert
+		assert.Nil(t, err)
+		assert.Equal(t, test.expected, output.Formatted)
+	}
+}
+
+func TestFormatAmountUnsupportedLocale(t *testing.T) {
+	// act
+	s := Service{}
+	_, err := s.FormatAmount(1, "EUR", "xx-YY")
+
+	// assert
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unsupported locale")
+}
+
+func TestFormatCompoundInterests(t *testing.T) {
+	// arrange
+	s := NewService(WithCurrency("EUR"))
+	input, _ := s.CalculateCompoundInterests(1000, 1, 2, 100, 1, 5)
+
+	// act
+	output, err := s.FormatCompoundInterests(input, "pt")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "pt-PT", output.Locale)
+	assert.Equal(t, "EUR", output.Currency)
+	assert.Equal(t, "1.307,50 €", output.Total.FinalAmount)
+	assert.Equal(t, "1.200,00 €", output.Total.TotalContributions)
+	assert.Len(t, output.History, 2)
+	assert.Equal(t, "1", output.History[0].Period)
+	assert.Equal(t, "1.150,00 €", output.History[0].Totals.FinalAmount)
+}
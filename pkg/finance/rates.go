@@ -0,0 +1,293 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package finance
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const ecbDailyRatesUrl = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// RateProvider provides exchange rates.
+type RateProvider interface {
+	// Rates returns the current exchange rates.
+	Rates(ctx context.Context) (RateTable, error)
+}
+
+// RateTable holds exchange rates relative to a base currency.
+//
+// Each rate is the amount of the currency that one unit of the base
+// currency buys.
+type RateTable struct {
+	Base      string
+	Rates     map[string]float64
+	Date      time.Time
+	FetchedAt time.Time
+}
+
+// Rate returns the rate to convert from one currency to another,
+// crossing through the base currency when needed.
+func (t RateTable) Rate(from, to string) (float64, error) {
+	fromRate, err := t.baseRate(from)
+	if err != nil {
+		return 0, err
+	}
+
+	toRate, err := t.baseRate(to)
+	if err != nil {
+		return 0, err
+	}
+
+	return toRate / fromRate, nil
+}
+
+func (t RateTable) baseRate(code string) (float64, error) {
+	if code == t.Base {
+		return 1, nil
+	}
+
+	rate, ok := t.Rates[code]
+	if !ok || rate <= 0 {
+		return 0, fmt.Errorf("no exchange rate available for %s", code)
+	}
+
+	return rate, nil
+}
+
+// CSVRateProvider reads exchange rates from a CSV file.
+//
+// The file must have a header with the columns "currency" and "rate",
+// and optionally "date" in the YYYY-MM-DD format. Rates are relative
+// to Base, which defaults to EUR.
+type CSVRateProvider struct {
+	Path string
+	Base string
+}
+
+func (p CSVRateProvider) Rates(ctx context.Context) (RateTable, error) {
+	if err := ctx.Err(); err != nil {
+		return RateTable{}, err
+	}
+
+	file, err := os.Open(p.Path)
+	if err != nil {
+		return RateTable{}, fmt.Errorf("error opening rates file: %s", err.Error())
+	}
+	defer file.Close()
+
+	base := p.Base
+	if base == "" {
+		base = "EUR"
+	}
+
+	return parseCSVRates(file, strings.ToUpper(base))
+}
+
+func parseCSVRates(r io.Reader, base string) (RateTable, error) {
+	table := RateTable{
+		Base:      base,
+		Rates:     map[string]float64{},
+		FetchedAt: time.Now().UTC(),
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return table, fmt.Errorf("error reading rates file: %s", err.Error())
+	}
+	if len(records) == 0 {
+		return table, fmt.Errorf("rates file is empty")
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	currencyColumn, hasCurrency := columns["currency"]
+	rateColumn, hasRate := columns["rate"]
+	dateColumn, hasDate := columns["date"]
+	if !hasCurrency || !hasRate {
+		return table, fmt.Errorf("rates file must have the currency and rate columns")
+	}
+
+	for line, record := range records[1:] {
+		code := strings.ToUpper(strings.TrimSpace(record[currencyColumn]))
+		rate, err := strconv.ParseFloat(strings.TrimSpace(record[rateColumn]), 64)
+		if err != nil {
+			return table, fmt.Errorf("invalid rate for %s on line %d", code, line+2)
+		}
+		table.Rates[code] = rate
+
+		if hasDate && table.Date.IsZero() {
+			table.Date, err = time.Parse("2006-01-02", strings.TrimSpace(record[dateColumn]))
+			if err != nil {
+				return table, fmt.Errorf("invalid date on line %d", line+2)
+			}
+		}
+	}
+
+	return table, nil
+}
+
+// ECBRateProvider gets the daily reference rates published by the
+// European Central Bank.
+//
+// The zero value uses http.DefaultClient and the official ECB endpoint.
+type ECBRateProvider struct {
+	Client *http.Client
+	Url    string
+}
+
+type ecbEnvelope struct {
+	Cube struct {
+		Cube []struct {
+			Time string `xml:"time,attr"`
+			Cube []struct {
+				Currency string `xml:"currency,attr"`
+				Rate     string `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+func (p ECBRateProvider) Rates(ctx context.Context) (RateTable, error) {
+	table := RateTable{Base: "EUR", Rates: map[string]float64{}}
+
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: time.Second * 10}
+	}
+
+	url := p.Url
+	if url == "" {
+		url = ecbDailyRatesUrl
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return table, fmt.Errorf("error creating request: %s", err.Error())
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return table, fmt.Errorf("error executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return table, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return table, fmt.Errorf("error reading response: %s", err.Error())
+	}
+
+	envelope := ecbEnvelope{}
+	err = xml.Unmarshal(body, &envelope)
+	if err != nil {
+		return table, fmt.Errorf("error un-marshalling ecb response: %s", err.Error())
+	}
+	if len(envelope.Cube.Cube) == 0 {
+		return table, fmt.Errorf("ecb response does not contain rates")
+	}
+
+	// the daily file has a single cube, the historical ones start
+	// with the most recent date
+	day := envelope.Cube.Cube[0]
+	table.Date, err = time.Parse("2006-01-02", day.Time)
+	if err != nil {
+		return table, fmt.Errorf("invalid date in ecb response: %s", day.Time)
+	}
+
+	for _, cube := range day.Cube {
+		rate, err := strconv.ParseFloat(cube.Rate, 64)
+		if err != nil {
+			return table, fmt.Errorf("invalid rate for %s in ecb response", cube.Currency)
+		}
+		table.Rates[cube.Currency] = rate
+	}
+	table.FetchedAt = time.Now().UTC()
+
+	return table, nil
+}
+
+// CachedRateProvider caches the rates returned by another provider
+// for the duration of the TTL.
+type CachedRateProvider struct {
+	Provider RateProvider
+	TTL      time.Duration
+
+	mu    sync.Mutex
+	table *RateTable
+	now   func() time.Time
+}
+
+func (p *CachedRateProvider) Rates(ctx context.Context) (RateTable, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now
+	if p.now != nil {
+		now = p.now
+	}
+
+	if p.table != nil && now().Before(p.table.FetchedAt.Add(p.TTL)) {
+		return *p.table, nil
+	}
+
+	table, err := p.Provider.Rates(ctx)
+	if err != nil {
+		return table, err
+	}
+	if table.FetchedAt.IsZero() {
+		table.FetchedAt = now().UTC()
+	}
+	p.table = &table
+
+	return table, nil
+}
+
+var (
+	defaultRateProviderOnce     sync.Once
+	defaultRateProviderInstance RateProvider
+)
+
+func defaultRateProvider() RateProvider {
+	defaultRateProviderOnce.Do(func() {
+		defaultRateProviderInstance = &CachedRateProvider{
+			Provider: ECBRateProvider{},
+			TTL:      time.Hour,
+		}
+	})
+
+	return defaultRateProviderInstance
+}
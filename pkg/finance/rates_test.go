@@ -0,0 +1,160 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package finance
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubRateProvider struct {
+	table RateTable
+	calls int
+}
+
+func (p *stubRateProvider) Rates(ctx context.Context) (RateTable, error) {
+	p.calls++
+	return p.table, nil
+}
+
+func newECBServer(t *testing.T) *httptest.Server {
+	data, err := ioutil.ReadFile("testdata/eurofxref-daily.xml")
+	assert.Nil(t, err)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		w.Write(data)
+	}))
+}
+
+func TestRateTableCrossRate(t *testing.T) {
+	// arrange
+	table := RateTable{Base: "EUR", Rates: map[string]float64{"USD": 1.2, "GBP": 0.8}}
+
+	// act
+	rate, err := table.Rate("USD", "GBP")
+
+	// assert
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.6667, rate, 0.0001)
+}
+
+func TestRateTableUnknownCurrency(t *testing.T) {
+	// arrange
+	table := RateTable{Base: "EUR", Rates: map[string]float64{"USD": 1.2}}
+
+	// act
+	_, err := table.Rate("EUR", "GBP")
+
+	// assert
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "GBP")
+}
+
+func TestCSVRateProvider(t *testing.T) {
+	// act
+	p := CSVRateProvider{Path: "testdata/rates.csv"}
+	table, err := p.Rates(context.Background())
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "EUR", table.Base)
+	assert.Equal(t, 1.1285, table.Rates["USD"])
+	assert.Equal(t, "2021-12-17", table.Date.Format("2006-01-02"))
+}
+
+func TestCSVRateProviderMissingFile(t *testing.T) {
+	// act
+	p := CSVRateProvider{Path: "testdata/missing.csv"}
+	_, err := p.Rates(context.Background())
+
+	// assert
+	assert.NotNil(t, err)
+}
+
+func TestECBRateProvider(t *testing.T) {
+	// arrange
+	server := newECBServer(t)
+	defer server.Close()
+
+	// act
+	p := ECBRateProvider{Client: server.Client(), Url: server.URL}
+	table, err := p.Rates(context.Background())
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "EUR", table.Base)
+	assert.Equal(t, 1.0404, table.Rates["CHF"])
+	assert.Equal(t, "2021-12-17", table.Date.Format("2006-01-02"))
+	assert.False(t, table.FetchedAt.IsZero())
+}
+
+func TestECBRateProviderServerError(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// act
+	p := ECBRateProvider{Client: server.Client(), Url: server.URL}
+	_, err := p.Rates(context.Background())
+
+	// assert
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "503")
+}
+
+func TestECBRateProviderContextCanceled(t *testing.T) {
+	// arrange
+	server := newECBServer(t)
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// act
+	p := ECBRateProvider{Client: server.Client(), Url: server.URL}
+	_, err := p.Rates(ctx)
+
+	// assert
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestCachedRateProvider(t *testing.T) {
+	// arrange
+	now := time.Date(2021, 12, 17, 16, 0, 0, 0, time.UTC)
+	stub := &stubRateProvider{table: RateTable{Base: "EUR", Rates: map[string]float64{"USD": 1.1}}}
+	p := &CachedRateProvider{Provider: stub, TTL: time.Hour, now: func() time.Time { return now }}
+
+	// act
+	first, _ := p.Rates(context.Background())
+	now = now.Add(30 * time.Minute)
+	p.Rates(context.Background())
+	now = now.Add(time.Hour)
+	p.Rates(context.Background())
+
+	// assert
+	assert.Equal(t, 2, stub.calls)
+	assert.Equal(t, time.Date(2021, 12, 17, 16, 0, 0, 0, time.UTC), first.FetchedAt)
+}
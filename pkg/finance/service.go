@@ -17,4 +17,46 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 package finance
 
 type Service struct {
+	rateProvider RateProvider
+	currency     string
+}
+
+// Option configures a Service created with NewService.
+type Option func(*Service)
+
+// WithRateProvider sets the provider used to get exchange rates.
+func WithRateProvider(provider RateProvider) Option {
+	return func(s *Service) {
+		s.rateProvider = provider
+	}
+}
+
+// WithCurrency sets the ISO 4217 code of the currency of the amounts of
+// the calculations, set on their outputs for conversions and formatting.
+func WithCurrency(code string) Option {
+	return func(s *Service) {
+		s.currency = code
+	}
+}
+
+// NewService creates a new finance service.
+//
+// A Service created without options, or declared as a zero value,
+// uses the European Central Bank reference rates, cached for one hour,
+// and calculates amounts without a currency.
+func NewService(opts ...Option) *Service {
+	s := &Service{}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *Service) rates() RateProvider {
+	if s.rateProvider == nil {
+		return defaultRateProvider()
+	}
+
+	return s.rateProvider
 }
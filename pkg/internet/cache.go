@@ -0,0 +1,245 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CachedPost is the response of Medium to the query of a post, as kept
+// in a PostCache.
+type CachedPost struct {
+	PostId string `json:"postId"`
+	// Hash is the SHA-256 of the data, in hexadecimal
+	Hash string `json:"hash"`
+	Data []byte `json:"-"`
+	// ETag and LastModified are the validators sent by Medium, used to
+	// revalidate the post
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	FetchedAt    time.Time `json:"fetchedAt"`
+}
+
+// PostCache stores the Medium posts, so they are not requested on every
+// conversion.
+type PostCache interface {
+	// Get returns the post cached, if any
+	Get(postId string) (CachedPost, bool, error)
+	// Put adds or replaces a post
+	Put(post CachedPost) error
+}
+
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// MemoryPostCache is a PostCache in memory, keeping the posts used most
+// recently up to its capacity.
+type MemoryPostCache struct {
+	capacity int
+	ttl      time.Duration
+	mutex    sync.Mutex
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+// NewMemoryPostCache creates a cache holding up to capacity posts, which
+// are dropped ttl after being fetched. A zero ttl keeps them until they
+// are the least recently used of a full cache.
+func NewMemoryPostCache(capacity int, ttl time.Duration) *MemoryPostCache {
+	return &MemoryPostCache{
+		capacity: capacity,
+		ttl:      ttl,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+func (c *MemoryPostCache) Get(postId string) (CachedPost, bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[postId]
+	if !ok {
+		return CachedPost{}, false, nil
+	}
+
+	post := element.Value.(CachedPost)
+	if c.ttl > 0 && time.Since(post.FetchedAt) > c.ttl {
+		c.order.Remove(element)
+		delete(c.entries, postId)
+		return CachedPost{}, false, nil
+	}
+
+	c.order.MoveToFront(element)
+	return post, true, nil
+}
+
+func (c *MemoryPostCache) Put(post CachedPost) error {
+	if c.capacity <= 0 {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[post.PostId]; ok {
+		element.Value = post
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	c.entries[post.PostId] = c.order.PushFront(post)
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(CachedPost).PostId)
+	}
+
+	return nil
+}
+
+// Len returns the number of posts in the cache.
+func (c *MemoryPostCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
+
+// DiskPostCache is a PostCache in a directory. The data of each post is
+// stored in a file named after its ID and hash, <id>-<hash>.json, and
+// its metadata in <id>.json, so a post is only rewritten when it
+// changes.
+type DiskPostCache struct {
+	dir string
+}
+
+// NewDiskPostCache creates a cache in dir, which is created if it does
+// not exist.
+func NewDiskPostCache(dir string) (*DiskPostCache, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cache directory: %w", err)
+	}
+
+	return &DiskPostCache{dir: dir}, nil
+}
+
+func (c *DiskPostCache) Get(postId string) (CachedPost, bool, error) {
+	post := CachedPost{}
+	if !mediumPostId.MatchString(postId) {
+		return post, false, fmt.Errorf("invalid post ID %q", postId)
+	}
+
+	metadata, err := ioutil.ReadFile(c.metadataPath(postId))
+	if os.IsNotExist(err) {
+		return post, false, nil
+	}
+	if err != nil {
+		return post, false, fmt.Errorf("error reading cached post: %w", err)
+	}
+
+	err = json.Unmarshal(metadata, &post)
+	if err != nil || post.PostId != postId {
+		return CachedPost{}, false, fmt.Errorf("invalid cached post %s", postId)
+	}
+
+	post.Data, err = ioutil.ReadFile(c.dataPath(postId, post.Hash))
+	if err != nil {
+		return CachedPost{}, false, fmt.Errorf("error reading cached post: %w", err)
+	}
+	if contentHash(post.Data) != post.Hash {
+		return CachedPost{}, false, fmt.Errorf("cached post %s is corrupted", postId)
+	}
+
+	return post, true, nil
+}
+
+func (c *DiskPostCache) Put(post CachedPost) error {
+	if !mediumPostId.MatchString(post.PostId) {
+		return fmt.Errorf("invalid post ID %q", post.PostId)
+	}
+
+	post.Hash = contentHash(post.Data)
+	dataPath := c.dataPath(post.PostId, post.Hash)
+	if _, err := os.Stat(dataPath); os.IsNotExist(err) {
+		err = writeFileAtomic(dataPath, post.Data)
+		if err != nil {
+			return fmt.Errorf("error caching post: %w", err)
+		}
+	}
+
+	metadata, err := json.Marshal(post)
+	if err != nil {
+		return fmt.Errorf("error caching post: %w", err)
+	}
+
+	err = writeFileAtomic(c.metadataPath(post.PostId), metadata)
+	if err != nil {
+		return fmt.Errorf("error caching post: %w", err)
+	}
+
+	// the previous versions are not needed anymore
+	previous, _ := filepath.Glob(filepath.Join(c.dir, post.PostId+"-*.json"))
+	for _, file := range previous {
+		if file != dataPath {
+			os.Remove(file)
+		}
+	}
+
+	return nil
+}
+
+func (c *DiskPostCache) metadataPath(postId string) string {
+	return filepath.Join(c.dir, postId+".json")
+}
+
+func (c *DiskPostCache) dataPath(postId, hash string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%s-%s.json", postId, hash))
+}
+
+// writeFileAtomic writes a file through a temporary file, so readers
+// never see it partially written.
+func writeFileAtomic(path string, data []byte) error {
+	file, err := ioutil.TempFile(filepath.Dir(path), "."+strings.TrimSuffix(filepath.Base(path), ".json")+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
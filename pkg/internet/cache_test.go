@@ -0,0 +1,141 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryPostCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	// arrange
+	c := NewMemoryPostCache(2, 0)
+	c.Put(CachedPost{PostId: "f744fbff033e", FetchedAt: time.Now()})
+	c.Put(CachedPost{PostId: "a2371a1c11b7", FetchedAt: time.Now()})
+
+	// act
+	_, firstOk, _ := c.Get("f744fbff033e")
+	c.Put(CachedPost{PostId: "5d3c2b1a0f9e", FetchedAt: time.Now()})
+	_, evictedOk, _ := c.Get("a2371a1c11b7")
+	_, keptOk, _ := c.Get("f744fbff033e")
+
+	// assert
+	assert.True(t, firstOk)
+	assert.False(t, evictedOk)
+	assert.True(t, keptOk)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestMemoryPostCacheReplaces(t *testing.T) {
+	// arrange
+	c := NewMemoryPostCache(2, 0)
+	c.Put(CachedPost{PostId: "f744fbff033e", Hash: "old"})
+
+	// act
+	c.Put(CachedPost{PostId: "f744fbff033e", Hash: "new"})
+	post, ok, err := c.Get("f744fbff033e")
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "new", post.Hash)
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestMemoryPostCacheExpires(t *testing.T) {
+	// arrange
+	c := NewMemoryPostCache(2, time.Minute)
+	c.Put(CachedPost{PostId: "f744fbff033e", FetchedAt: time.Now().Add(-2 * time.Minute)})
+	c.Put(CachedPost{PostId: "a2371a1c11b7", FetchedAt: time.Now()})
+
+	// act
+	_, expiredOk, _ := c.Get("f744fbff033e")
+	_, freshOk, _ := c.Get("a2371a1c11b7")
+
+	// assert
+	assert.False(t, expiredOk)
+	assert.True(t, freshOk)
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestDiskPostCache(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	c, err := NewDiskPostCache(filepath.Join(dir, "posts"))
+	assert.Nil(t, err)
+	fetchedAt := time.Date(2021, 12, 15, 13, 0, 0, 0, time.UTC)
+
+	// act
+	putErr := c.Put(CachedPost{PostId: "f744fbff033e", Data: []byte(`{"v":1}`), ETag: `"1"`, FetchedAt: fetchedAt})
+	secondPutErr := c.Put(CachedPost{PostId: "f744fbff033e", Data: []byte(`{"v":2}`), ETag: `"2"`, FetchedAt: fetchedAt})
+	post, ok, getErr := c.Get("f744fbff033e")
+	_, missingOk, missingErr := c.Get("a2371a1c11b7")
+	files, _ := filepath.Glob(filepath.Join(dir, "posts", "*"))
+
+	// assert
+	assert.Nil(t, putErr)
+	assert.Nil(t, secondPutErr)
+	assert.Nil(t, getErr)
+	assert.True(t, ok)
+	assert.Equal(t, []byte(`{"v":2}`), post.Data)
+	assert.Equal(t, contentHash([]byte(`{"v":2}`)), post.Hash)
+	assert.Equal(t, `"2"`, post.ETag)
+	assert.True(t, fetchedAt.Equal(post.FetchedAt))
+	assert.False(t, missingOk)
+	assert.Nil(t, missingErr)
+	assert.ElementsMatch(t, []string{
+		filepath.Join(dir, "posts", "f744fbff033e.json"),
+		filepath.Join(dir, "posts", "f744fbff033e-"+post.Hash+".json"),
+	}, files)
+}
+
+func TestDiskPostCacheDetectsCorruption(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	c, _ := NewDiskPostCache(dir)
+	c.Put(CachedPost{PostId: "f744fbff033e", Data: []byte(`{"v":1}`)})
+	ioutil.WriteFile(filepath.Join(dir, "f744fbff033e-"+contentHash([]byte(`{"v":1}`))+".json"), []byte(`{"v":9}`), 0644)
+
+	// act
+	_, ok, err := c.Get("f744fbff033e")
+
+	// assert
+	assert.False(t, ok)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "corrupted")
+}
+
+func TestDiskPostCacheRejectsInvalidIds(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	c, _ := NewDiskPostCache(dir)
+
+	// act
+	putErr := c.Put(CachedPost{PostId: "../f744fbff033e", Data: []byte("{}")})
+	_, _, getErr := c.Get("../../etc/passwd")
+	_, statErr := os.Stat(filepath.Join(filepath.Dir(dir), "f744fbff033e.json"))
+
+	// assert
+	assert.NotNil(t, putErr)
+	assert.NotNil(t, getErr)
+	assert.True(t, os.IsNotExist(statErr))
+}
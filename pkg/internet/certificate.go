@@ -0,0 +1,375 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+// InspectCertificates describes the X.509 certificates and requests in
+// PEM, or a DER certificate or request, as is or in base64, and
+// validates the chain of certificates if there are roots to trust.
+func (s *Service) InspectCertificates(input string, options internet.InspectCertificatesOptions) (internet.InspectCertificatesOutput, error) {
+	return s.InspectCertificatesContext(context.Background(), input, options)
+}
+
+func (s *Service) InspectCertificatesContext(ctx context.Context, input string, options internet.InspectCertificatesOptions) (internet.InspectCertificatesOutput, error) {
+	output := internet.InspectCertificatesOutput{}
+
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
+	parsed, err := parseCertificates(input)
+	if err != nil {
+		return output, err
+	}
+	if len(parsed.certificates) == 0 && len(parsed.requests) == 0 {
+		return output, &internet.InvalidCertificateError{Reason: "no certificates or requests found"}
+	}
+
+	at := options.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	output.Certificates = []internet.CertificateInfo{}
+	for _, certificate := range parsed.certificates {
+		info, err := s.certificateInfo(ctx, certificate, at)
+		if err != nil {
+			return output, err
+		}
+		output.Certificates = append(output.Certificates, info)
+	}
+
+	output.Requests = []internet.CertificateRequestInfo{}
+	for _, request := range parsed.requests {
+		output.Requests = append(output.Requests, certificateRequestInfo(request))
+	}
+
+	if options.Roots != "" && len(parsed.certificates) > 0 {
+		roots, err := parseCertificates(options.Roots)
+		if err != nil {
+			return output, err
+		}
+		if len(roots.certificates) == 0 {
+			return output, &internet.InvalidCertificateError{Reason: "no root certificates found"}
+		}
+		output.Chain, err = s.validateChain(ctx, parsed.certificates, roots.certificates, options.DnsName, at)
+		if err != nil {
+			return output, err
+		}
+	}
+	output.Skipped = parsed.skipped
+
+	return output, nil
+}
+
+// parsedCertificates are the certificates and requests of an input.
+type parsedCertificates struct {
+	certificates []*x509.Certificate
+	requests     []*x509.CertificateRequest
+	// skipped are the types of the other PEM blocks
+	skipped []string
+}
+
+// parseCertificates parses the PEM blocks of the input or, if it has
+// none, the input as DER, in base64 or not.
+func parseCertificates(input string) (parsedCertificates, error) {
+	parsed := parsedCertificates{skipped: []string{}}
+
+	rest := []byte(input)
+	for index := 1; ; index++ {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		switch block.Type {
+		case "CERTIFICATE", "TRUSTED CERTIFICATE", "X509 CERTIFICATE":
+			certificate, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return parsed, &internet.InvalidCertificateError{Reason: fmt.Sprintf("pem block %d: %s", index, err.Error())}
+			}
+			parsed.certificates = append(parsed.certificates, certificate)
+		case "CERTIFICATE REQUEST", "NEW CERTIFICATE REQUEST":
+			request, err := x509.ParseCertificateRequest(block.Bytes)
+			if err != nil {
+				return parsed, &internet.InvalidCertificateError{Reason: fmt.Sprintf("pem block %d: %s", index, err.Error())}
+			}
+			parsed.requests = append(parsed.requests, request)
+		default:
+			parsed.skipped = append(parsed.skipped, block.Type)
+		}
+	}
+	if len(parsed.certificates) > 0 || len(parsed.requests) > 0 || len(parsed.skipped) > 0 {
+		return parsed, nil
+	}
+
+	der := []byte(input)
+	if decoded, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(input), "")); err == nil {
+		der = decoded
+	}
+
+	certificates, err := x509.ParseCertificates(der)
+	if err == nil && len(certificates) > 0 {
+		parsed.certificates = certificates
+		return parsed, nil
+	}
+	request, err := x509.ParseCertificateRequest(der)
+	if err == nil {
+		parsed.requests = append(parsed.requests, request)
+		return parsed, nil
+	}
+
+	return parsed, &internet.InvalidCertificateError{Reason: "the input is neither pem nor der"}
+}
+
+func (s *Service) certificateInfo(ctx context.Context, certificate *x509.Certificate, at time.Time) (internet.CertificateInfo, error) {
+	notBefore, err := s.datetime().FromUnitTimestampContext(ctx, certificate.NotBefore.Unix())
+	if err != nil {
+		return internet.CertificateInfo{}, err
+	}
+	notAfter, err := s.datetime().FromUnitTimestampContext(ctx, certificate.NotAfter.Unix())
+	if err != nil {
+		return internet.CertificateInfo{}, err
+	}
+
+	sha1Sum := sha1.Sum(certificate.Raw)
+	sha256Sum := sha256.Sum256(certificate.Raw)
+
+	info := internet.CertificateInfo{
+		Version:                certificate.Version,
+		SerialNumber:           colonHex(certificate.SerialNumber.Bytes()),
+		Subject:                certificateName(certificate.Subject),
+		Issuer:                 certificateName(certificate.Issuer),
+		DnsNames:               nonNil(certificate.DNSNames),
+		IpAddresses:            []string{},
+		EmailAddresses:         nonNil(certificate.EmailAddresses),
+		Uris:                   []string{},
+		NotBefore:              notBefore,
+		NotAfter:               notAfter,
+		Expired:                at.After(certificate.NotAfter),
+		NotYetValid:            at.Before(certificate.NotBefore),
+		DaysRemaining:          daysBetween(at, certificate.NotAfter),
+		PublicKey:              certificatePublicKey(certificate.PublicKey),
+		SignatureAlgorithm:     certificate.SignatureAlgorithm.String(),
+		IsCa:                   certificate.BasicConstraintsValid && certificate.IsCA,
+		SelfSigned:             isSelfSigned(certificate),
+		KeyUsage:               keyUsageNames(certificate.KeyUsage),
+		ExtKeyUsage:            extKeyUsageNames(certificate.ExtKeyUsage),
+		Fingerprints:           internet.CertificateFingerprints{Sha1: colonHex(sha1Sum[:]), Sha256: colonHex(sha256Sum[:])},
+		SubjectKeyId:           colonHex(certificate.SubjectKeyId),
+		AuthorityKeyId:         colonHex(certificate.AuthorityKeyId),
+		OcspServers:            nonNil(certificate.OCSPServer),
+		IssuingCertificateUrls: nonNil(certificate.IssuingCertificateURL),
+		CrlDistributionPoints:  nonNil(certificate.CRLDistributionPoints),
+		Extensions:             certificateExtensions(certificate.Extensions),
+	}
+	for _, ip := range certificate.IPAddresses {
+		info.IpAddresses = append(info.IpAddresses, ip.String())
+	}
+	for _, uri := range certificate.URIs {
+		info.Uris = append(info.Uris, uri.String())
+	}
+
+	return info, nil
+}
+
+func certificateRequestInfo(request *x509.CertificateRequest) internet.CertificateRequestInfo {
+	info := internet.CertificateRequestInfo{
+		Subject:            certificateName(request.Subject),
+		DnsNames:           nonNil(request.DNSNames),
+		IpAddresses:        []string{},
+		EmailAddresses:     nonNil(request.EmailAddresses),
+		Uris:               []string{},
+		PublicKey:          certificatePublicKey(request.PublicKey),
+		SignatureAlgorithm: request.SignatureAlgorithm.String(),
+		SignatureValid:     request.CheckSignature() == nil,
+		Extensions:         certificateExtensions(request.Extensions),
+	}
+	for _, ip := range request.IPAddresses {
+		info.IpAddresses = append(info.IpAddresses, ip.String())
+	}
+	for _, uri := range request.URIs {
+		info.Uris = append(info.Uris, uri.String())
+	}
+
+	return info
+}
+
+func certificateName(name pkix.Name) internet.CertificateName {
+	return internet.CertificateName{
+		CommonName:         name.CommonName,
+		Organization:       nonNil(name.Organization),
+		OrganizationalUnit: nonNil(name.OrganizationalUnit),
+		Locality:           nonNil(name.Locality),
+		Province:           nonNil(name.Province),
+		Country:            nonNil(name.Country),
+		String:             name.String(),
+	}
+}
+
+func certificatePublicKey(key interface{}) internet.CertificatePublicKey {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return internet.CertificatePublicKey{Algorithm: "RSA", Size: k.N.BitLen()}
+	case *ecdsa.PublicKey:
+		return internet.CertificatePublicKey{Algorithm: "ECDSA", Size: k.Curve.Params().BitSize, Curve: k.Curve.Params().Name}
+	case ed25519.PublicKey:
+		return internet.CertificatePublicKey{Algorithm: "Ed25519", Size: 256}
+	}
+
+	return internet.CertificatePublicKey{Algorithm: "unknown"}
+}
+
+// isSelfSigned tells if a certificate is issued by itself, having the
+// same subject and issuer and being signed by its own key, even when
+// it is not a CA.
+func isSelfSigned(certificate *x509.Certificate) bool {
+	return bytes.Equal(certificate.RawSubject, certificate.RawIssuer) &&
+		certificate.CheckSignature(certificate.SignatureAlgorithm, certificate.RawTBSCertificate, certificate.Signature) == nil
+}
+
+// daysBetween returns the number of whole days from start to end,
+// negative if end is before start.
+func daysBetween(start, end time.Time) int {
+	return int(end.Sub(start) / (24 * time.Hour))
+}
+
+// colonHex returns bytes in hexadecimal separated by colons, as in
+// AB:CD:EF.
+func colonHex(data []byte) string {
+	hex := make([]string, len(data))
+	for i, b := range data {
+		hex[i] = fmt.Sprintf("%02X", b)
+	}
+
+	return strings.Join(hex, ":")
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+
+	return values
+}
+
+// keyUsages are the names of the key usages, as in RFC 5280.
+var keyUsages = []struct {
+	usage x509.KeyUsage
+	name  string
+}{
+	{x509.KeyUsageDigitalSignature, "digitalSignature"},
+	{x509.KeyUsageContentCommitment, "contentCommitment"},
+	{x509.KeyUsageKeyEncipherment, "keyEncipherment"},
+	{x509.KeyUsageDataEncipherment, "dataEncipherment"},
+	{x509.KeyUsageKeyAgreement, "keyAgreement"},
+	{x509.KeyUsageCertSign, "keyCertSign"},
+	{x509.KeyUsageCRLSign, "cRLSign"},
+	{x509.KeyUsageEncipherOnly, "encipherOnly"},
+	{x509.KeyUsageDecipherOnly, "decipherOnly"},
+}
+
+func keyUsageNames(usage x509.KeyUsage) []string {
+	names := []string{}
+	for _, u := range keyUsages {
+		if usage&u.usage != 0 {
+			names = append(names, u.name)
+		}
+	}
+
+	return names
+}
+
+// extKeyUsages are the names of the extended key usages, as in OpenSSL.
+var extKeyUsages = map[x509.ExtKeyUsage]string{
+	x509.ExtKeyUsageAny:                            "any",
+	x509.ExtKeyUsageServerAuth:                     "serverAuth",
+	x509.ExtKeyUsageClientAuth:                     "clientAuth",
+	x509.ExtKeyUsageCodeSigning:                    "codeSigning",
+	x509.ExtKeyUsageEmailProtection:                "emailProtection",
+	x509.ExtKeyUsageIPSECEndSystem:                 "ipsecEndSystem",
+	x509.ExtKeyUsageIPSECTunnel:                    "ipsecTunnel",
+	x509.ExtKeyUsageIPSECUser:                      "ipsecUser",
+	x509.ExtKeyUsageTimeStamping:                   "timeStamping",
+	x509.ExtKeyUsageOCSPSigning:                    "OCSPSigning",
+	x509.ExtKeyUsageMicrosoftServerGatedCrypto:     "msSGC",
+	x509.ExtKeyUsageNetscapeServerGatedCrypto:      "nsSGC",
+	x509.ExtKeyUsageMicrosoftCommercialCodeSigning: "msCodeCom",
+	x509.ExtKeyUsageMicrosoftKernelCodeSigning:     "msKernelCode",
+}
+
+func extKeyUsageNames(usages []x509.ExtKeyUsage) []string {
+	names := []string{}
+	for _, usage := range usages {
+		if name, ok := extKeyUsages[usage]; ok {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// extensionNames are the names of the common extensions of certificates.
+var extensionNames = map[string]string{
+	"2.5.29.14":               "subjectKeyIdentifier",
+	"2.5.29.15":               "keyUsage",
+	"2.5.29.17":               "subjectAltName",
+	"2.5.29.18":               "issuerAltName",
+	"2.5.29.19":               "basicConstraints",
+	"2.5.29.30":               "nameConstraints",
+	"2.5.29.31":               "cRLDistributionPoints",
+	"2.5.29.32":               "certificatePolicies",
+	"2.5.29.35":               "authorityKeyIdentifier",
+	"2.5.29.37":               "extKeyUsage",
+	"1.3.6.1.5.5.7.1.1":       "authorityInfoAccess",
+	"1.3.6.1.5.5.7.1.24":      "tlsFeature",
+	"1.3.6.1.4.1.11129.2.4.2": "signedCertificateTimestampList",
+	"1.3.6.1.4.1.11129.2.4.3": "precertificatePoison",
+}
+
+func certificateExtensions(extensions []pkix.Extension) []internet.CertificateExtension {
+	output := []internet.CertificateExtension{}
+	for _, extension := range extensions {
+		oid := extension.Id.String()
+		output = append(output, internet.CertificateExtension{
+			Oid:      oid,
+			Name:     extensionNames[oid],
+			Critical: extension.Critical,
+		})
+	}
+
+	return output
+}
@@ -0,0 +1,194 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+// validateChain validates a chain of certificates, the first being the
+// leaf and the others the intermediates, against the roots. Besides the
+// problems found by x509, it reports all the certificates not valid at
+// the time and the ones out of order, as servers must send the chain in
+// order.
+func (s *Service) validateChain(ctx context.Context, certificates []*x509.Certificate, roots []*x509.Certificate, dnsName string, at time.Time) (internet.CertificateChainValidation, error) {
+	validation := internet.CertificateChainValidation{
+		Checked: true,
+		Chains:  [][]string{},
+		Errors:  []string{},
+	}
+
+	for _, certificate := range certificates {
+		reason, err := s.validityError(ctx, certificate, at)
+		if err != nil {
+			return validation, err
+		}
+		if reason != "" {
+			validation.Errors = append(validation.Errors, reason)
+		}
+	}
+
+	for i := 1; i < len(certificates); i++ {
+		if certificates[i-1].CheckSignatureFrom(certificates[i]) != nil {
+			validation.Errors = append(validation.Errors, fmt.Sprintf("certificate %d, %q, is not the issuer of certificate %d, %q, the chain is out of order or has extra certificates",
+				i+1, certificateDisplayName(certificates[i]), i, certificateDisplayName(certificates[i-1])))
+		}
+	}
+
+	leaf := certificates[0]
+	if dnsName != "" {
+		if err := leaf.VerifyHostname(dnsName); err != nil {
+			validation.Errors = append(validation.Errors, hostnameErrorReason(leaf, dnsName))
+		}
+	}
+
+	rootPool := x509.NewCertPool()
+	for _, root := range roots {
+		rootPool.AddCert(root)
+	}
+	intermediatePool := x509.NewCertPool()
+	for _, intermediate := range certificates[1:] {
+		intermediatePool.AddCert(intermediate)
+	}
+
+	chains, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         rootPool,
+		Intermediates: intermediatePool,
+		CurrentTime:   at,
+		// the usage of the certificate is not known
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		if reason := chainErrorReason(err, append(append([]*x509.Certificate{}, certificates...), roots...)); reason != "" {
+			validation.Errors = append(validation.Errors, reason)
+		}
+	}
+	for _, chain := range chains {
+		names := []string{}
+		for _, certificate := range chain {
+			names = append(names, certificateDisplayName(certificate))
+		}
+		validation.Chains = append(validation.Chains, names)
+	}
+
+	validation.Valid = len(validation.Errors) == 0
+
+	return validation, nil
+}
+
+// validityError returns why a certificate is not valid at a time, empty
+// if it is.
+func (s *Service) validityError(ctx context.Context, certificate *x509.Certificate, at time.Time) (string, error) {
+	switch {
+	case at.After(certificate.NotAfter):
+		notAfter, err := s.datetime().FromUnitTimestampContext(ctx, certificate.NotAfter.Unix())
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("certificate %q expired on %s", certificateDisplayName(certificate), notAfter.UtcTimestamp), nil
+	case at.Before(certificate.NotBefore):
+		notBefore, err := s.datetime().FromUnitTimestampContext(ctx, certificate.NotBefore.Unix())
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("certificate %q is not valid until %s", certificateDisplayName(certificate), notBefore.UtcTimestamp), nil
+	}
+
+	return "", nil
+}
+
+func hostnameErrorReason(leaf *x509.Certificate, dnsName string) string {
+	names := append([]string{}, leaf.DNSNames...)
+	for _, ip := range leaf.IPAddresses {
+		names = append(names, ip.String())
+	}
+	if len(names) == 0 {
+		return fmt.Sprintf("certificate %q has no subject alternative names, so it is not valid for %q", certificateDisplayName(leaf), dnsName)
+	}
+
+	return fmt.Sprintf("certificate %q is valid for %s, not %q", certificateDisplayName(leaf), strings.Join(names, ", "), dnsName)
+}
+
+// chainErrorReason explains an error of x509 validating a chain, empty
+// for the errors already reported, like the expired certificates.
+// known are all the certificates given, to tell a missing issuer from
+// one that did not sign the certificate.
+func chainErrorReason(err error, known []*x509.Certificate) string {
+	var unknownAuthority x509.UnknownAuthorityError
+	if errors.As(err, &unknownAuthority) && unknownAuthority.Cert != nil {
+		certificate := unknownAuthority.Cert
+		for _, candidate := range known {
+			if bytes.Equal(candidate.RawSubject, certificate.RawIssuer) && candidate != certificate {
+				return fmt.Sprintf("certificate %q is not signed by %q, although it is its issuer by name",
+					certificateDisplayName(certificate), certificateDisplayName(candidate))
+			}
+		}
+		if bytes.Equal(certificate.RawSubject, certificate.RawIssuer) {
+			return fmt.Sprintf("certificate %q is self-signed and not one of the roots", certificateDisplayName(certificate))
+		}
+		return fmt.Sprintf("the issuer %q of certificate %q is missing, add the intermediate certificate or its root",
+			issuerDisplayName(certificate), certificateDisplayName(certificate))
+	}
+
+	var invalid x509.CertificateInvalidError
+	if errors.As(err, &invalid) {
+		name := certificateDisplayName(invalid.Cert)
+		switch invalid.Reason {
+		case x509.Expired:
+			return ""
+		case x509.NotAuthorizedToSign:
+			return fmt.Sprintf("certificate %q is not a CA, so it can not sign other certificates", name)
+		case x509.TooManyIntermediates:
+			return fmt.Sprintf("the chain is longer than the path length allowed by certificate %q", name)
+		case x509.CANotAuthorizedForThisName, x509.CANotAuthorizedForExtKeyUsage:
+			return fmt.Sprintf("certificate %q is not authorized by its issuer: %s", name, invalid.Detail)
+		case x509.IncompatibleUsage:
+			return fmt.Sprintf("certificate %q is not allowed for the usage of its chain", name)
+		}
+	}
+
+	return err.Error()
+}
+
+// certificateDisplayName returns the common name of a certificate or,
+// if it has none, its whole subject.
+func certificateDisplayName(certificate *x509.Certificate) string {
+	if certificate == nil {
+		return ""
+	}
+	if certificate.Subject.CommonName != "" {
+		return certificate.Subject.CommonName
+	}
+
+	return certificate.Subject.String()
+}
+
+func issuerDisplayName(certificate *x509.Certificate) string {
+	if certificate.Issuer.CommonName != "" {
+		return certificate.Issuer.CommonName
+	}
+
+	return certificate.Issuer.String()
+}
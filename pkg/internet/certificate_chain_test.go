@@ -0,0 +1,145 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspectCertificatesChain(t *testing.T) {
+	leaf := readTestCertificate(t, "leaf.pem")
+	intermediate := readTestCertificate(t, "intermediate.pem")
+	root := readTestCertificate(t, "root.pem")
+	valid := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		input    string
+		roots    string
+		dnsName  string
+		at       time.Time
+		expected internet.CertificateChainValidation
+	}{
+		{
+			name:    "valid",
+			input:   readTestCertificate(t, "chain.pem"),
+			roots:   root,
+			dnsName: "api.canivete.dev",
+			at:      valid,
+			expected: internet.CertificateChainValidation{
+				Checked: true,
+				Valid:   true,
+				Chains:  [][]string{{"api.canivete.dev", "Canivete Intermediate CA", "Canivete Root CA"}},
+				Errors:  []string{},
+			},
+		},
+		{
+			name:  "missing intermediate",
+			input: leaf,
+			roots: root,
+			at:    valid,
+			expected: internet.CertificateChainValidation{
+				Checked: true,
+				Chains:  [][]string{},
+				Errors:  []string{`the issuer "Canivete Intermediate CA" of certificate "api.canivete.dev" is missing, add the intermediate certificate or its root`},
+			},
+		},
+		{
+			name:  "expired",
+			input: readTestCertificate(t, "chain.pem"),
+			roots: root,
+			at:    time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC),
+			expected: internet.CertificateChainValidation{
+				Checked: true,
+				Chains:  [][]string{},
+				Errors:  []string{`certificate "api.canivete.dev" expired on Sat Dec 31 00:00:00 UTC 2022`},
+			},
+		},
+		{
+			name:    "wrong hostname",
+			input:   readTestCertificate(t, "chain.pem"),
+			roots:   root,
+			dnsName: "canivete.dev",
+			at:      valid,
+			expected: internet.CertificateChainValidation{
+				Checked: true,
+				Chains:  [][]string{{"api.canivete.dev", "Canivete Intermediate CA", "Canivete Root CA"}},
+				Errors:  []string{`certificate "api.canivete.dev" is valid for api.canivete.dev, www.canivete.dev, 192.0.2.10, 2001:db8::10, not "canivete.dev"`},
+			},
+		},
+		{
+			name:  "out of order",
+			input: intermediate + leaf,
+			roots: root,
+			at:    valid,
+			expected: internet.CertificateChainValidation{
+				Checked: true,
+				Chains:  [][]string{{"Canivete Intermediate CA", "Canivete Root CA"}},
+				Errors: []string{
+					`certificate 2, "api.canivete.dev", is not the issuer of certificate 1, "Canivete Intermediate CA", the chain is out of order or has extra certificates`,
+				},
+			},
+		},
+		{
+			name:  "self-signed",
+			input: readTestCertificate(t, "selfsigned.pem"),
+			roots: root,
+			at:    time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC),
+			expected: internet.CertificateChainValidation{
+				Checked: true,
+				Chains:  [][]string{},
+				Errors:  []string{`certificate "localhost" is self-signed and not one of the roots`},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			options := internet.InspectCertificatesOptions{Roots: tc.roots, DnsName: tc.dnsName, At: tc.at}
+
+			// act
+			output, err := NewService().InspectCertificates(tc.input, options)
+
+			// assert
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, output.Chain)
+		})
+	}
+}
+
+func TestInspectCertificatesChainNotChecked(t *testing.T) {
+	// act
+	output, err := NewService().InspectCertificates(readTestCertificate(t, "chain.pem"), internet.InspectCertificatesOptions{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.False(t, output.Chain.Checked)
+	assert.Len(t, output.Certificates, 2)
+}
+
+func TestInspectCertificatesChainInvalidRoots(t *testing.T) {
+	// act
+	_, err := NewService().InspectCertificates(readTestCertificate(t, "chain.pem"), internet.InspectCertificatesOptions{Roots: readTestCertificate(t, "request.csr")})
+
+	// assert
+	assert.Equal(t, &internet.InvalidCertificateError{Reason: "no root certificates found"}, err)
+}
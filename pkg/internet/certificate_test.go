@@ -0,0 +1,274 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/datetime"
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+// readTestCertificate reads a file of testdata/tls.
+func readTestCertificate(t *testing.T, name string) string {
+	data, err := ioutil.ReadFile("testdata/tls/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestInspectCertificates(t *testing.T) {
+	// arrange
+	input := readTestCertificate(t, "leaf.pem")
+	options := internet.InspectCertificatesOptions{At: time.Date(2022, 12, 1, 12, 0, 0, 0, time.UTC)}
+
+	// act
+	output, err := NewService().InspectCertificates(input, options)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Len(t, output.Certificates, 1)
+	assert.Empty(t, output.Requests)
+	assert.Empty(t, output.Skipped)
+	assert.False(t, output.Chain.Checked)
+	assert.Equal(t, internet.CertificateInfo{
+		Version:      3,
+		SerialNumber: "0A:1B:2C:3D:4E:5F",
+		Subject: internet.CertificateName{
+			CommonName:         "api.canivete.dev",
+			Organization:       []string{"Canivete"},
+			OrganizationalUnit: []string{"Platform"},
+			Locality:           []string{"Lisbon"},
+			Province:           []string{},
+			Country:            []string{"PT"},
+			String:             "CN=api.canivete.dev,OU=Platform,O=Canivete,L=Lisbon,C=PT",
+		},
+		Issuer: internet.CertificateName{
+			CommonName:         "Canivete Intermediate CA",
+			Organization:       []string{"Canivete"},
+			OrganizationalUnit: []string{},
+			Locality:           []string{},
+			Province:           []string{},
+			Country:            []string{},
+			String:             "CN=Canivete Intermediate CA,O=Canivete",
+		},
+		DnsNames:           []string{"api.canivete.dev", "www.canivete.dev"},
+		IpAddresses:        []string{"192.0.2.10", "2001:db8::10"},
+		EmailAddresses:     []string{"ops@canivete.dev"},
+		Uris:               []string{"spiffe://canivete/api"},
+		NotBefore:          datetime.FromUnixTimestampOutput{UnixTimestamp: 1640995200, UtcTimestamp: "Sat Jan  1 00:00:00 UTC 2022"},
+		NotAfter:           datetime.FromUnixTimestampOutput{UnixTimestamp: 1672444800, UtcTimestamp: "Sat Dec 31 00:00:00 UTC 2022"},
+		DaysRemaining:      29,
+		PublicKey:          internet.CertificatePublicKey{Algorithm: "ECDSA", Size: 256, Curve: "P-256"},
+		SignatureAlgorithm: "ECDSA-SHA384",
+		KeyUsage:           []string{"digitalSignature"},
+		ExtKeyUsage:        []string{"serverAuth", "clientAuth"},
+		Fingerprints: internet.CertificateFingerprints{
+			Sha1:   "C2:25:70:80:4F:99:1B:90:D6:97:85:5C:5D:78:B1:1E:F1:2B:A2:BF",
+			Sha256: "A6:66:7D:82:90:91:62:22:BF:87:2C:50:AA:C3:9C:F3:36:F4:C6:70:9B:64:6F:D1:81:A9:37:BC:F8:93:F4:F4",
+		},
+		AuthorityKeyId:         "2D:A6:19:D1:1C:27:36:0C:22:B8:40:2A:73:92:75:E3:1A:DC:D2:9E",
+		OcspServers:            []string{"http://ocsp.canivete.dev"},
+		IssuingCertificateUrls: []string{"http://pki.canivete.dev/intermediate.crt"},
+		CrlDistributionPoints:  []string{"http://pki.canivete.dev/intermediate.crl"},
+		Extensions: []internet.CertificateExtension{
+			{Oid: "2.5.29.15", Name: "keyUsage", Critical: true},
+			{Oid: "2.5.29.37", Name: "extKeyUsage"},
+			{Oid: "2.5.29.19", Name: "basicConstraints", Critical: true},
+			{Oid: "2.5.29.35", Name: "authorityKeyIdentifier"},
+			{Oid: "1.3.6.1.5.5.7.1.1", Name: "authorityInfoAccess"},
+			{Oid: "2.5.29.17", Name: "subjectAltName"},
+			{Oid: "2.5.29.31", Name: "cRLDistributionPoints"},
+		},
+	}, output.Certificates[0])
+}
+
+func TestInspectCertificatesKinds(t *testing.T) {
+	testCases := []struct {
+		name      string
+		file      string
+		algorithm string
+		size      int
+		isCa      bool
+		self      bool
+		expired   bool
+	}{
+		{"rsa root", "root.pem", "RSA", 2048, true, true, false},
+		{"ecdsa intermediate", "intermediate.pem", "ECDSA", 384, true, false, false},
+		{"ed25519 expired", "selfsigned.pem", "Ed25519", 256, false, true, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			output, err := NewService().InspectCertificates(readTestCertificate(t, tc.file),
+				internet.InspectCertificatesOptions{At: time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)})
+
+			// assert
+			assert.Nil(t, err)
+			info := output.Certificates[0]
+			assert.Equal(t, tc.algorithm, info.PublicKey.Algorithm)
+			assert.Equal(t, tc.size, info.PublicKey.Size)
+			assert.Equal(t, tc.isCa, info.IsCa)
+			assert.Equal(t, tc.self, info.SelfSigned)
+			assert.Equal(t, tc.expired, info.Expired)
+		})
+	}
+}
+
+func TestInspectCertificatesValidity(t *testing.T) {
+	testCases := []struct {
+		name        string
+		at          time.Time
+		expired     bool
+		notYetValid bool
+		days        int
+	}{
+		{"valid", time.Date(2022, 12, 30, 0, 0, 0, 0, time.UTC), false, false, 1},
+		{"last day", time.Date(2022, 12, 30, 12, 0, 0, 0, time.UTC), false, false, 0},
+		{"expired", time.Date(2023, 1, 30, 0, 0, 0, 0, time.UTC), true, false, -30},
+		{"not yet valid", time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC), false, true, 395},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			output, err := NewService().InspectCertificates(readTestCertificate(t, "leaf.pem"), internet.InspectCertificatesOptions{At: tc.at})
+
+			// assert
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expired, output.Certificates[0].Expired)
+			assert.Equal(t, tc.notYetValid, output.Certificates[0].NotYetValid)
+			assert.Equal(t, tc.days, output.Certificates[0].DaysRemaining)
+		})
+	}
+}
+
+func TestInspectCertificatesFormats(t *testing.T) {
+	der := readTestCertificate(t, "leaf.der")
+	testCases := []struct {
+		name  string
+		input string
+	}{
+		{"der", der},
+		{"base64", base64.StdEncoding.EncodeToString([]byte(der))},
+		{"base64 lines", base64.StdEncoding.EncodeToString([]byte(der))[:64] + "\n" + base64.StdEncoding.EncodeToString([]byte(der))[64:]},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			output, err := NewService().InspectCertificates(tc.input, internet.InspectCertificatesOptions{})
+
+			// assert
+			assert.Nil(t, err)
+			assert.Len(t, output.Certificates, 1)
+			assert.Equal(t, "api.canivete.dev", output.Certificates[0].Subject.CommonName)
+		})
+	}
+}
+
+func TestInspectCertificatesRequest(t *testing.T) {
+	// act
+	output, err := NewService().InspectCertificates(readTestCertificate(t, "request.csr"), internet.InspectCertificatesOptions{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Empty(t, output.Certificates)
+	assert.Equal(t, []internet.CertificateRequestInfo{{
+		Subject: internet.CertificateName{
+			CommonName:         "new.canivete.dev",
+			Organization:       []string{"Canivete"},
+			OrganizationalUnit: []string{},
+			Locality:           []string{},
+			Province:           []string{},
+			Country:            []string{},
+			String:             "CN=new.canivete.dev,O=Canivete",
+		},
+		DnsNames:           []string{"new.canivete.dev"},
+		IpAddresses:        []string{},
+		EmailAddresses:     []string{"ops@canivete.dev"},
+		Uris:               []string{},
+		PublicKey:          internet.CertificatePublicKey{Algorithm: "RSA", Size: 2048},
+		SignatureAlgorithm: "SHA256-RSA",
+		SignatureValid:     true,
+		Extensions:         []internet.CertificateExtension{{Oid: "2.5.29.17", Name: "subjectAltName"}},
+	}}, output.Requests)
+}
+
+func TestInspectCertificatesSkipped(t *testing.T) {
+	// act
+	output, err := NewService().InspectCertificates(readTestCertificate(t, "selfsigned-with-key.pem"), internet.InspectCertificatesOptions{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Len(t, output.Certificates, 1)
+	assert.Equal(t, []string{"PRIVATE KEY"}, output.Skipped)
+}
+
+func TestInspectCertificatesInvalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		reason string
+	}{
+		{"not a certificate", "hello", "the input is neither pem nor der"},
+		{"only a key", readTestCertificate(t, "selfsigned-with-key.pem")[:170] + "-----END PRIVATE KEY-----\n", "no certificates or requests found"},
+		{"bad block", "-----BEGIN CERTIFICATE-----\naGVsbG8=\n-----END CERTIFICATE-----\n", "pem block 1: x509: malformed certificate"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			_, err := NewService().InspectCertificates(tc.input, internet.InspectCertificatesOptions{})
+
+			// assert
+			var invalid *internet.InvalidCertificateError
+			assert.True(t, errors.As(err, &invalid))
+			assert.Equal(t, tc.reason, invalid.Reason)
+		})
+	}
+}
+
+// fixedDatetime renders every date as the same text.
+type fixedDatetime struct{}
+
+func (fixedDatetime) FromUnitTimestamp(unixTime int64) datetime.FromUnixTimestampOutput {
+	return datetime.FromUnixTimestampOutput{UnixTimestamp: unixTime, UtcTimestamp: "fixed"}
+}
+
+func (d fixedDatetime) FromUnitTimestampContext(ctx context.Context, unixTime int64) (datetime.FromUnixTimestampOutput, error) {
+	return d.FromUnitTimestamp(unixTime), nil
+}
+
+func TestInspectCertificatesWithDatetime(t *testing.T) {
+	// act
+	output, err := NewService(WithDatetime(fixedDatetime{})).InspectCertificates(readTestCertificate(t, "leaf.pem"), internet.InspectCertificatesOptions{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "fixed", output.Certificates[0].NotBefore.UtcTimestamp)
+	assert.Equal(t, "fixed", output.Certificates[0].NotAfter.UtcTimestamp)
+}
@@ -0,0 +1,261 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"sort"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+// maxSubnets is the maximum number of subnets SplitCidr lists.
+const maxSubnets = 65536
+
+// ParseCidr describes an IPv4 or IPv6 prefix, or a single address if
+// without a prefix length: its network, range of hosts, masks and size.
+func (s *Service) ParseCidr(cidr string) (internet.ParseCidrOutput, error) {
+	return s.ParseCidrContext(context.Background(), cidr)
+}
+
+func (s *Service) ParseCidrContext(ctx context.Context, cidr string) (internet.ParseCidrOutput, error) {
+	output := internet.ParseCidrOutput{}
+
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
+	prefix, err := parseIpPrefix(cidr)
+	if err != nil {
+		return output, err
+	}
+
+	network, last := prefix.network(), prefix.last()
+	first, hostsLast, hosts := network, last, prefix.size()
+	// /31 and /32 prefixes have no network nor broadcast addresses
+	hasBroadcast := prefix.bits == 32 && prefix.bits-prefix.length > 1
+	if hasBroadcast {
+		// the network and broadcast addresses are not hosts
+		first = new(big.Int).Add(network, big.NewInt(1))
+		hostsLast = new(big.Int).Sub(last, big.NewInt(1))
+		hosts = new(big.Int).Sub(hosts, big.NewInt(2))
+	}
+
+	output = internet.ParseCidrOutput{
+		Version:      ipVersion(prefix.bits),
+		Address:      formatIp(prefix.address, prefix.bits),
+		PrefixLength: prefix.length,
+		Prefix:       prefix.String(),
+		Netmask:      formatIp(prefix.mask(), prefix.bits),
+		Wildcard:     formatIp(prefix.hostmask(), prefix.bits),
+		Network:      formatIp(network, prefix.bits),
+		FirstHost:    formatIp(first, prefix.bits),
+		LastHost:     formatIp(hostsLast, prefix.bits),
+		Addresses:    prefix.size().String(),
+		Hosts:        hosts.String(),
+		Scope:        ipScope(ipBytes(prefix.address, prefix.bits)),
+	}
+	if hasBroadcast {
+		output.Broadcast = formatIp(last, prefix.bits)
+	}
+
+	return output, nil
+}
+
+// SplitCidr splits a prefix into subnets, either a number of them, as
+// large as possible, or all the ones with a prefix length.
+func (s *Service) SplitCidr(cidr string, options internet.SplitCidrOptions) (internet.SplitCidrOutput, error) {
+	return s.SplitCidrContext(context.Background(), cidr, options)
+}
+
+func (s *Service) SplitCidrContext(ctx context.Context, cidr string, options internet.SplitCidrOptions) (internet.SplitCidrOutput, error) {
+	output := internet.SplitCidrOutput{}
+
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
+	prefix, err := parseIpPrefix(cidr)
+	if err != nil {
+		return output, err
+	}
+
+	length, count := options.PrefixLength, options.Count
+	switch {
+	case options.Count < 0:
+		return output, fmt.Errorf("the number of subnets must be positive")
+	case options.Count > 0:
+		// the smallest power of 2 with count subnets, without overflowing
+		length = prefix.length + bits.Len(uint(count-1))
+		if length > prefix.bits {
+			return output, fmt.Errorf("%s can not be split in %d subnets", prefix, count)
+		}
+	case options.PrefixLength < prefix.length || options.PrefixLength > prefix.bits:
+		return output, fmt.Errorf("the prefix length of the subnets of %s must be between %d and %d", prefix, prefix.length, prefix.bits)
+	default:
+		if length-prefix.length > 16 {
+			return output, fmt.Errorf("%s has more than %d subnets of /%d", prefix, maxSubnets, length)
+		}
+		count = 1 << uint(length-prefix.length)
+	}
+	if count > maxSubnets {
+		return output, fmt.Errorf("can not list more than %d subnets", maxSubnets)
+	}
+
+	subnets := make([]string, count)
+	subnet := ipPrefix{address: prefix.network(), bits: prefix.bits, length: length}
+	for i := range subnets {
+		subnets[i] = subnet.String()
+		subnet.address = new(big.Int).Add(subnet.address, subnet.size())
+	}
+
+	output = internet.SplitCidrOutput{
+		PrefixLength: length,
+		Subnets:      subnets,
+	}
+
+	return output, nil
+}
+
+// SummarizeCidrs aggregates prefixes into the smallest list of prefixes
+// covering the same addresses, removing the ones contained in others and
+// merging the adjacent ones.
+func (s *Service) SummarizeCidrs(cidrs []string) (internet.SummarizeCidrsOutput, error) {
+	return s.SummarizeCidrsContext(context.Background(), cidrs)
+}
+
+func (s *Service) SummarizeCidrsContext(ctx context.Context, cidrs []string) (internet.SummarizeCidrsOutput, error) {
+	output := internet.SummarizeCidrsOutput{}
+
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
+	ranges := []ipRange{}
+	for _, cidr := range cidrs {
+		prefix, err := parseIpPrefix(cidr)
+		if err != nil {
+			return output, err
+		}
+		ranges = append(ranges, ipRange{first: prefix.network(), last: prefix.last(), bits: prefix.bits})
+	}
+
+	output.Prefixes = []string{}
+	for _, r := range mergeIpRanges(ranges) {
+		for _, prefix := range r.prefixes() {
+			output.Prefixes = append(output.Prefixes, prefix.String())
+		}
+	}
+
+	return output, nil
+}
+
+// CompareCidrs tells if two prefixes are equal, one contains the other
+// or they overlap. Prefixes of different versions never overlap.
+func (s *Service) CompareCidrs(first string, second string) (internet.CompareCidrsOutput, error) {
+	return s.CompareCidrsContext(context.Background(), first, second)
+}
+
+func (s *Service) CompareCidrsContext(ctx context.Context, first string, second string) (internet.CompareCidrsOutput, error) {
+	output := internet.CompareCidrsOutput{}
+
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
+	firstPrefix, err := parseIpPrefix(first)
+	if err != nil {
+		return output, err
+	}
+	secondPrefix, err := parseIpPrefix(second)
+	if err != nil {
+		return output, err
+	}
+
+	contains, containedBy := firstPrefix.contains(secondPrefix), secondPrefix.contains(firstPrefix)
+	output = internet.CompareCidrsOutput{
+		Equal:       contains && containedBy,
+		Contains:    contains,
+		ContainedBy: containedBy,
+		// prefixes overlap only if one contains the other
+		Overlaps: contains || containedBy,
+	}
+
+	return output, nil
+}
+
+// ipRange is a range of addresses, from first to last.
+type ipRange struct {
+	first *big.Int
+	last  *big.Int
+	bits  int
+}
+
+// mergeIpRanges sorts ranges, IPv4 first, and merges the ones that
+// overlap or are adjacent.
+func mergeIpRanges(ranges []ipRange) []ipRange {
+	sort.Slice(ranges, func(i, j int) bool {
+		if ranges[i].bits != ranges[j].bits {
+			return ranges[i].bits < ranges[j].bits
+		}
+		return ranges[i].first.Cmp(ranges[j].first) < 0
+	})
+
+	merged := []ipRange{}
+	for _, r := range ranges {
+		if len(merged) > 0 {
+			previous := &merged[len(merged)-1]
+			next := new(big.Int).Add(previous.last, big.NewInt(1))
+			if previous.bits == r.bits && r.first.Cmp(next) <= 0 {
+				if r.last.Cmp(previous.last) > 0 {
+					previous.last = r.last
+				}
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+
+	return merged
+}
+
+// prefixes returns the smallest list of prefixes covering the range,
+// taking each time the largest prefix starting at the first address not
+// covered yet that does not go past the last one.
+func (r ipRange) prefixes() []ipPrefix {
+	prefixes := []ipPrefix{}
+	first := new(big.Int).Set(r.first)
+	for first.Cmp(r.last) <= 0 {
+		hostBits := r.bits
+		if first.Sign() != 0 {
+			hostBits = int(first.TrailingZeroBits())
+		}
+		remaining := new(big.Int).Sub(r.last, first)
+		if fits := remaining.Add(remaining, big.NewInt(1)).BitLen() - 1; fits < hostBits {
+			hostBits = fits
+		}
+
+		prefix := ipPrefix{address: new(big.Int).Set(first), bits: r.bits, length: r.bits - hostBits}
+		prefixes = append(prefixes, prefix)
+		first.Add(first, prefix.size())
+	}
+
+	return prefixes
+}
@@ -0,0 +1,269 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCidr(t *testing.T) {
+	testCases := []struct {
+		cidr string
+		want internet.ParseCidrOutput
+	}{
+		{"10.1.2.3/22", internet.ParseCidrOutput{
+			Version:      4,
+			Address:      "10.1.2.3",
+			PrefixLength: 22,
+			Prefix:       "10.1.0.0/22",
+			Netmask:      "255.255.252.0",
+			Wildcard:     "0.0.3.255",
+			Network:      "10.1.0.0",
+			Broadcast:    "10.1.3.255",
+			FirstHost:    "10.1.0.1",
+			LastHost:     "10.1.3.254",
+			Addresses:    "1024",
+			Hosts:        "1022",
+			Scope:        "private",
+		}},
+		{"192.0.2.10/31", internet.ParseCidrOutput{
+			Version:      4,
+			Address:      "192.0.2.10",
+			PrefixLength: 31,
+			Prefix:       "192.0.2.10/31",
+			Netmask:      "255.255.255.254",
+			Wildcard:     "0.0.0.1",
+			Network:      "192.0.2.10",
+			FirstHost:    "192.0.2.10",
+			LastHost:     "192.0.2.11",
+			Addresses:    "2",
+			Hosts:        "2",
+			Scope:        "public",
+		}},
+		{"8.8.8.8", internet.ParseCidrOutput{
+			Version:      4,
+			Address:      "8.8.8.8",
+			PrefixLength: 32,
+			Prefix:       "8.8.8.8/32",
+			Netmask:      "255.255.255.255",
+			Wildcard:     "0.0.0.0",
+			Network:      "8.8.8.8",
+			FirstHost:    "8.8.8.8",
+			LastHost:     "8.8.8.8",
+			Addresses:    "1",
+			Hosts:        "1",
+			Scope:        "public",
+		}},
+		{"fd00:1:2::abc/48", internet.ParseCidrOutput{
+			Version:      6,
+			Address:      "fd00:1:2::abc",
+			PrefixLength: 48,
+			Prefix:       "fd00:1:2::/48",
+			Netmask:      "ffff:ffff:ffff::",
+			Wildcard:     "::ffff:ffff:ffff:ffff:ffff",
+			Network:      "fd00:1:2::",
+			FirstHost:    "fd00:1:2::",
+			LastHost:     "fd00:1:2:ffff:ffff:ffff:ffff:ffff",
+			Addresses:    "1208925819614629174706176",
+			Hosts:        "1208925819614629174706176",
+			Scope:        "private",
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.cidr, func(t *testing.T) {
+			// act
+			output, err := NewService().ParseCidr(tc.cidr)
+
+			// assert
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, output)
+		})
+	}
+}
+
+func TestParseCidrScope(t *testing.T) {
+	testCases := map[string]string{
+		"127.0.0.1":   "loopback",
+		"::1":         "loopback",
+		"0.0.0.0/0":   "unspecified",
+		"169.254.1.1": "link-local",
+		"fe80::1/64":  "link-local",
+		"224.0.0.251": "link-local",
+		"239.1.1.1":   "multicast",
+		"172.16.5.4":  "private",
+		"2001:db8::1": "public",
+	}
+
+	for cidr, want := range testCases {
+		t.Run(cidr, func(t *testing.T) {
+			// act
+			output, err := NewService().ParseCidr(cidr)
+
+			// assert
+			assert.Nil(t, err)
+			assert.Equal(t, want, output.Scope)
+		})
+	}
+}
+
+func TestParseCidrInvalid(t *testing.T) {
+	testCases := []struct {
+		cidr   string
+		reason string
+	}{
+		{"10.0.0.0/33", "the prefix length must be between 0 and 32"},
+		{"10.0.0.0/x", "the prefix length must be between 0 and 32"},
+		{"::/129", "the prefix length must be between 0 and 128"},
+		{"10.0.0.256/8", "not an ip address"},
+		{"vpc", "not an ip address"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.cidr, func(t *testing.T) {
+			// act
+			_, err := NewService().ParseCidr(tc.cidr)
+
+			// assert
+			var invalid *internet.InvalidIpError
+			assert.True(t, errors.As(err, &invalid))
+			assert.Equal(t, tc.reason, invalid.Reason)
+		})
+	}
+}
+
+func TestSplitCidr(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cidr    string
+		options internet.SplitCidrOptions
+		want    internet.SplitCidrOutput
+	}{
+		{"count power of 2", "10.0.0.0/16", internet.SplitCidrOptions{Count: 4}, internet.SplitCidrOutput{
+			PrefixLength: 18,
+			Subnets:      []string{"10.0.0.0/18", "10.0.64.0/18", "10.0.128.0/18", "10.0.192.0/18"},
+		}},
+		{"count rounded up", "10.0.5.7/24", internet.SplitCidrOptions{Count: 3}, internet.SplitCidrOutput{
+			PrefixLength: 26,
+			Subnets:      []string{"10.0.5.0/26", "10.0.5.64/26", "10.0.5.128/26"},
+		}},
+		{"prefix length", "10.0.0.0/22", internet.SplitCidrOptions{PrefixLength: 24}, internet.SplitCidrOutput{
+			PrefixLength: 24,
+			Subnets:      []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/24", "10.0.3.0/24"},
+		}},
+		{"ipv6", "2001:db8::/32", internet.SplitCidrOptions{PrefixLength: 34}, internet.SplitCidrOutput{
+			PrefixLength: 34,
+			Subnets:      []string{"2001:db8::/34", "2001:db8:4000::/34", "2001:db8:8000::/34", "2001:db8:c000::/34"},
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			output, err := NewService().SplitCidr(tc.cidr, tc.options)
+
+			// assert
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, output)
+		})
+	}
+}
+
+func TestSplitCidrInvalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cidr    string
+		options internet.SplitCidrOptions
+		want    string
+	}{
+		{"no options", "10.0.0.0/24", internet.SplitCidrOptions{}, "the prefix length of the subnets of 10.0.0.0/24 must be between 24 and 32"},
+		{"larger subnets", "10.0.0.0/24", internet.SplitCidrOptions{PrefixLength: 16}, "the prefix length of the subnets of 10.0.0.0/24 must be between 24 and 32"},
+		{"too many", "10.0.0.0/30", internet.SplitCidrOptions{Count: 5}, "10.0.0.0/30 can not be split in 5 subnets"},
+		{"too many to list", "2001:db8::/32", internet.SplitCidrOptions{PrefixLength: 64}, "2001:db8::/32 has more than 65536 subnets of /64"},
+		{"negative count", "10.0.0.0/24", internet.SplitCidrOptions{Count: -1}, "the number of subnets must be positive"},
+		{"huge count", "10.0.0.0/8", internet.SplitCidrOptions{Count: math.MaxInt64}, "10.0.0.0/8 can not be split in 9223372036854775807 subnets"},
+		{"huge count to list", "2001:db8::/32", internet.SplitCidrOptions{Count: math.MaxInt64}, "can not list more than 65536 subnets"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			_, err := NewService().SplitCidr(tc.cidr, tc.options)
+
+			// assert
+			assert.EqualError(t, err, tc.want)
+		})
+	}
+}
+
+func TestSummarizeCidrs(t *testing.T) {
+	testCases := []struct {
+		name  string
+		cidrs []string
+		want  []string
+	}{
+		{"adjacent", []string{"10.0.1.0/24", "10.0.0.0/24", "10.0.2.0/23"}, []string{"10.0.0.0/22"}},
+		{"contained", []string{"10.0.0.0/8", "10.1.0.0/16", "10.1.2.3"}, []string{"10.0.0.0/8"}},
+		{"not aligned", []string{"10.0.1.0/24", "10.0.2.0/24"}, []string{"10.0.1.0/24", "10.0.2.0/24"}},
+		{"addresses", []string{"192.0.2.1", "192.0.2.2", "192.0.2.3", "192.0.2.0"}, []string{"192.0.2.0/30"}},
+		{"uneven range", []string{"192.0.2.1", "192.0.2.2/31", "192.0.2.4/30"}, []string{"192.0.2.1/32", "192.0.2.2/31", "192.0.2.4/30"}},
+		{"mixed versions", []string{"2001:db8:1::/48", "10.0.0.0/24", "2001:db8::/48"}, []string{"10.0.0.0/24", "2001:db8::/47"}},
+		{"everything", []string{"0.0.0.0/1", "128.0.0.0/1"}, []string{"0.0.0.0/0"}},
+		{"none", []string{}, []string{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			output, err := NewService().SummarizeCidrs(tc.cidrs)
+
+			// assert
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, output.Prefixes)
+		})
+	}
+}
+
+func TestCompareCidrs(t *testing.T) {
+	testCases := []struct {
+		first  string
+		second string
+		want   internet.CompareCidrsOutput
+	}{
+		{"10.0.0.0/8", "10.1.0.0/16", internet.CompareCidrsOutput{Contains: true, Overlaps: true}},
+		{"10.1.0.0/16", "10.0.0.0/8", internet.CompareCidrsOutput{ContainedBy: true, Overlaps: true}},
+		{"10.0.0.1/24", "10.0.0.0/24", internet.CompareCidrsOutput{Equal: true, Contains: true, ContainedBy: true, Overlaps: true}},
+		{"10.0.0.0/24", "10.0.1.0/24", internet.CompareCidrsOutput{}},
+		{"::/0", "10.0.0.0/8", internet.CompareCidrsOutput{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.first+" "+tc.second, func(t *testing.T) {
+			// act
+			output, err := NewService().CompareCidrs(tc.first, tc.second)
+
+			// assert
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, output)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import "strings"
+
+// document is the format independent model of an article. Sources, like
+// Medium, are parsed into a document which is then rendered in any of the
+// documentFormats.
+type document struct {
+	title  string
+	author string
+	blocks []block
+}
+
+type blockKind int
+
+const (
+	blockHeading blockKind = iota
+	blockParagraph
+	blockImage
+	blockCode
+	blockList
+	blockQuote
+	blockRule
+	blockEmbed
+	blockTable
+)
+
+type block struct {
+	kind blockKind
+	// level is the level of a heading, 1 being the title of the document
+	level int
+	// inlines is the content of headings, paragraphs and quotes, which
+	// can span several lines
+	inlines []inline
+	// items has the content of each item of a list
+	items   [][]inline
+	ordered bool
+	// depths has the nesting depth of each item of a list, 0 for the
+	// items of the list itself, and itemsOrdered if the list of each item
+	// is ordered. Lists without them are not nested.
+	depths       []int
+	itemsOrdered []bool
+	// rows has the content of each cell of a table, the first row being
+	// the header
+	rows [][][]inline
+	// code and lang are the content and language of a code block
+	code string
+	lang string
+	// src is the URL of an image or embedded media and alt the
+	// alternative text of an image
+	src string
+	alt string
+	// href is the link of an image or the URL of the embedded media
+	href string
+	// title, width and height describe the embedded media
+	title  string
+	width  int
+	height int
+}
+
+type inlineKind int
+
+const (
+	inlineText inlineKind = iota
+	inlineStrong
+	inlineEmphasis
+	inlineCode
+	inlineLink
+)
+
+type inline struct {
+	kind     inlineKind
+	text     string
+	href     string
+	children []inline
+}
+
+func textInline(text string) inline {
+	return inline{kind: inlineText, text: text}
+}
+
+func linkInline(text, href string) inline {
+	return inline{kind: inlineLink, href: href, children: []inline{textInline(text)}}
+}
+
+// plainText returns the text of inlines without any markup.
+func plainText(inlines []inline) string {
+	var builder strings.Builder
+	for _, node := range inlines {
+		if node.kind == inlineText {
+			builder.WriteString(node.text)
+			continue
+		}
+		builder.WriteString(plainText(node.children))
+	}
+
+	return builder.String()
+}
+
+// tableColumns returns the number of columns of a table, the number of
+// cells of its longest row.
+func tableColumns(rows [][][]inline) int {
+	columns := 0
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+
+	return columns
+}
+
+// tableCells returns the cells of a row rendered with render, adding
+// empty cells up to columns.
+func tableCells(row [][]inline, columns int, render func([]inline) string) []string {
+	cells := make([]string, columns)
+	for i, cell := range row {
+		cells[i] = render(cell)
+	}
+
+	return cells
+}
+
+// documentRenderer renders a document in a specific format.
+type documentRenderer interface {
+	render(d document) string
+}
+
+type documentFormat struct {
+	contentType   string
+	fileExtension string
+	renderer      func(s *Service) documentRenderer
+}
+
+var documentFormats = map[string]documentFormat{
+	"markdown": {"text/markdown", "md", func(s *Service) documentRenderer { return markdownRenderer{embedFallback: s.render.embedFallback} }},
+	"html":     {"text/html", "html", func(s *Service) documentRenderer { return htmlRenderer{} }},
+	"asciidoc": {"text/asciidoc", "adoc", func(s *Service) documentRenderer { return asciidocRenderer{} }},
+	"rst":      {"text/x-rst", "rst", func(s *Service) documentRenderer { return rstRenderer{} }},
+	"org":      {"text/org", "org", func(s *Service) documentRenderer { return orgRenderer{} }},
+	"text":     {"text/plain", "txt", func(s *Service) documentRenderer { return textRenderer{} }},
+}
+
+// delimit wraps content with the opening and closing delimiters of an
+// inline markup. Leading and trailing spaces are kept outside, as most
+// markup languages do not allow them next to the delimiters, and the
+// delimiters are dropped when there is nothing but spaces to wrap.
+func delimit(opening, closing, content string) string {
+	trimmed := strings.TrimSpace(content)
+	if trimmed == "" {
+		return content
+	}
+
+	start := strings.Index(content, trimmed)
+	return content[:start] + opening + trimmed + closing + content[start+len(trimmed):]
+}
+
+// fence returns a delimiter made of at least min repetitions of char
+// that does not appear in content.
+func fence(char string, min int, content string) string {
+	delimiter := strings.Repeat(char, min)
+	for strings.Contains(content, delimiter) {
+		delimiter += char
+	}
+
+	return delimiter
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sampleDocument has one block of each kind, to test the renderers.
+func sampleDocument() document {
+	return document{
+		title:  "Go tips",
+		author: "Renato Torres",
+		blocks: []block{
+			{kind: blockHeading, level: 2, inlines: []inline{textInline("Small things")}},
+			{kind: blockParagraph, inlines: []inline{
+				textInline("Use "),
+				{kind: inlineStrong, children: []inline{textInline("small ")}},
+				{kind: inlineCode, children: []inline{textInline("interfaces")}},
+				textInline(" and read "),
+				{kind: inlineLink, href: "https://go.dev/doc/effective_go", children: []inline{
+					textInline("Effective "),
+					{kind: inlineEmphasis, children: []inline{textInline("Go")}},
+				}},
+				textInline("."),
+			}},
+			{kind: blockImage, src: "https://example.com/gopher.png", alt: "The gopher"},
+			{kind: blockCode, lang: "go", code: "type Service struct {\n}"},
+			{kind: blockList, items: [][]inline{{textInline("Testable")}, {textInline("Clear")}}},
+			{kind: blockList, ordered: true, items: [][]inline{{textInline("Define")}, {textInline("Implement")}}},
+			{kind: blockQuote, inlines: []inline{textInline("Simplicity is complicated.")}},
+			{kind: blockRule},
+			{kind: blockEmbed, src: "https://speakerdeck.com/player/4f2a", href: "https://speakerdeck.com/renato0307/go", title: "Slides"},
+			{kind: blockTable, rows: [][][]inline{
+				{{textInline("Operator")}, {textInline("Meaning")}},
+				{{{kind: inlineCode, children: []inline{textInline("|")}}}, {textInline("Bitwise or")}},
+				{{{kind: inlineCode, children: []inline{textInline("&&")}}}, {textInline("Logical and")}},
+			}},
+		},
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	// act
+	output := plainText(sampleDocument().blocks[1].inlines)
+
+	// assert
+	assert.Equal(t, "Use small interfaces and read Effective Go.", output)
+}
+
+func TestTableCells(t *testing.T) {
+	// arrange
+	rows := [][][]inline{{{textInline("a")}, {textInline("b")}}, {{textInline("c")}}}
+
+	// act
+	columns := tableColumns(rows)
+	cells := tableCells(rows[1], columns, plainText)
+
+	// assert
+	assert.Equal(t, 2, columns)
+	assert.Equal(t, []string{"c", ""}, cells)
+}
+
+func TestDelimit(t *testing.T) {
+	assert.Equal(t, " **bold** ", delimit("**", "**", " bold "))
+	assert.Equal(t, "  ", delimit("**", "**", "  "))
+	assert.Equal(t, "", delimit("*", "*", ""))
+}
+
+func TestFence(t *testing.T) {
+	assert.Equal(t, "```", fence("`", 3, "no backticks"))
+	assert.Equal(t, "````", fence("`", 3, "```go\n```"))
+	assert.Equal(t, "-----", fence("-", 4, "a\n----\nb"))
+}
@@ -0,0 +1,406 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+// maxEmailDepth is how deep the parts of a message can be nested.
+const maxEmailDepth = 32
+
+// ParseEmail parses an RFC 5322 message and its MIME parts, decoding
+// its headers, the servers it went through, the results of its
+// authentication and its attachments.
+func (s *Service) ParseEmail(raw string, options internet.ParseEmailOptions) (internet.ParseEmailOutput, error) {
+	return s.ParseEmailContext(context.Background(), raw, options)
+}
+
+func (s *Service) ParseEmailContext(ctx context.Context, raw string, options internet.ParseEmailOptions) (internet.ParseEmailOutput, error) {
+	output := internet.ParseEmailOutput{}
+
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
+	headers, body, err := readEmailHeaders([]byte(raw))
+	if err != nil {
+		return output, err
+	}
+	if len(headers) == 0 {
+		return output, &internet.InvalidEmailError{Reason: "the message has no headers"}
+	}
+	output.Headers = headers
+
+	header := emailHeaderMap(headers)
+	output.From = emailAddresses(header.Get("From"))
+	output.To = emailAddresses(header.Get("To"))
+	output.Cc = emailAddresses(header.Get("Cc"))
+	output.ReplyTo = emailAddresses(header.Get("Reply-To"))
+	output.Subject = decodeEmailHeader(header.Get("Subject"))
+	output.MessageId = strings.Trim(strings.TrimSpace(header.Get("Message-Id")), "<>")
+
+	date, err := parseEmailDate(header.Get("Date"))
+	if err == nil {
+		output.Date, err = s.datetime().FromUnitTimestampContext(ctx, date.Unix())
+		if err != nil {
+			return output, err
+		}
+	}
+
+	output.Hops, output.TotalDelay, err = s.emailHops(ctx, header.Values("Received"), date)
+	if err != nil {
+		return output, err
+	}
+	output.Authentication = emailAuthentication(header.Values("Authentication-Results"))
+
+	parts := emailParts{options: options, attachments: []internet.EmailAttachment{}}
+	output.Body, err = parts.parse(ctx, header, body, 0)
+	if err != nil {
+		return output, err
+	}
+	output.TextBody = parts.text
+	output.HtmlBody = parts.html
+	output.Attachments = parts.attachments
+
+	return output, nil
+}
+
+// readEmailHeaders splits a message, or a part of it, into its headers,
+// unfolded and in order, and its body.
+func readEmailHeaders(message []byte) ([]internet.EmailHeader, []byte, error) {
+	headers := []internet.EmailHeader{}
+
+	rest := message
+	// skips the separator line of the messages saved in mbox files
+	if bytes.HasPrefix(rest, []byte("From ")) {
+		rest = nil
+		if end := bytes.IndexByte(message, '\n'); end >= 0 {
+			rest = message[end+1:]
+		}
+	}
+
+	for number := 1; len(rest) > 0; number++ {
+		line := rest
+		rest = nil
+		if end := bytes.IndexByte(line, '\n'); end >= 0 {
+			line, rest = line[:end], line[end+1:]
+		}
+		line = bytes.TrimSuffix(line, []byte("\r"))
+
+		if len(line) == 0 {
+			break
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if len(headers) == 0 {
+				return nil, nil, &internet.InvalidEmailError{Reason: "the first line is a continuation of a header"}
+			}
+			headers[len(headers)-1].Raw += string(line)
+			continue
+		}
+
+		colon := bytes.IndexByte(line, ':')
+		if colon <= 0 {
+			return nil, nil, &internet.InvalidEmailError{Reason: fmt.Sprintf("line %d is not a header: %q", number, line)}
+		}
+		headers = append(headers, internet.EmailHeader{
+			Name: string(bytes.TrimRight(line[:colon], " \t")),
+			Raw:  string(bytes.TrimLeft(line[colon+1:], " \t")),
+		})
+	}
+
+	for i := range headers {
+		headers[i].Raw = strings.TrimSpace(headers[i].Raw)
+		headers[i].Value = decodeEmailHeader(headers[i].Raw)
+	}
+
+	return headers, rest, nil
+}
+
+// emailHeaderMap indexes the raw values of the headers by their
+// canonical name.
+func emailHeaderMap(headers []internet.EmailHeader) textproto.MIMEHeader {
+	header := textproto.MIMEHeader{}
+	for _, h := range headers {
+		header.Add(h.Name, h.Raw)
+	}
+
+	return header
+}
+
+// emailWordDecoder decodes RFC 2047 encoded words, in the charsets
+// decodeCharset supports.
+var emailWordDecoder = &mime.WordDecoder{
+	CharsetReader: func(charset string, input io.Reader) (io.Reader, error) {
+		data, err := ioutil.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+		text, err := decodeCharset(charset, data)
+		if err != nil {
+			return nil, err
+		}
+		return strings.NewReader(text), nil
+	},
+}
+
+// decodeEmailHeader decodes the encoded words of a header, returning it
+// as is if they can not be decoded.
+func decodeEmailHeader(value string) string {
+	decoded, err := emailWordDecoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+
+	return decoded
+}
+
+// emailAddresses parses a list of addresses. A list that is not valid,
+// as some senders write, is returned as a single name.
+func emailAddresses(value string) []internet.EmailAddress {
+	addresses := []internet.EmailAddress{}
+	if strings.TrimSpace(value) == "" {
+		return addresses
+	}
+
+	parser := mail.AddressParser{WordDecoder: emailWordDecoder}
+	list, err := parser.ParseList(value)
+	if err != nil {
+		return append(addresses, internet.EmailAddress{Name: decodeEmailHeader(value)})
+	}
+	for _, address := range list {
+		addresses = append(addresses, internet.EmailAddress{Name: address.Name, Address: address.Address})
+	}
+
+	return addresses
+}
+
+// emailDateComment matches the comment some servers add after a date,
+// like (UTC) or (Pacific Standard Time).
+var emailDateComment = regexp.MustCompile(`\s*\([^()]*\)\s*$`)
+
+// parseEmailDate parses an RFC 5322 date, ignoring its comment.
+func parseEmailDate(value string) (time.Time, error) {
+	value = emailDateComment.ReplaceAllString(strings.TrimSpace(value), "")
+	return mail.ParseDate(value)
+}
+
+// emailParts walks the parts of a message, collecting its bodies and
+// attachments.
+type emailParts struct {
+	options     internet.ParseEmailOptions
+	text        string
+	textFound   bool
+	html        string
+	htmlFound   bool
+	attachments []internet.EmailAttachment
+}
+
+// parse describes a part, and the parts in it, at a depth of nesting.
+func (p *emailParts) parse(ctx context.Context, header textproto.MIMEHeader, body []byte, depth int) (internet.EmailPart, error) {
+	if err := ctx.Err(); err != nil {
+		return internet.EmailPart{}, err
+	}
+	if depth > maxEmailDepth {
+		return internet.EmailPart{}, &internet.InvalidEmailError{Reason: fmt.Sprintf("the parts are nested more than %d levels", maxEmailDepth)}
+	}
+
+	// a missing or invalid content type means plain text, RFC 2045
+	// section 5.2
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if mediaType == "" || (err != nil && err != mime.ErrInvalidMediaParameter) {
+		mediaType, params = "text/plain", map[string]string{"charset": "us-ascii"}
+	}
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+
+	part := internet.EmailPart{
+		ContentType:      mediaType,
+		Charset:          params["charset"],
+		TransferEncoding: strings.ToLower(strings.TrimSpace(header.Get("Content-Transfer-Encoding"))),
+		Disposition:      disposition,
+		Filename:         decodeEmailHeader(firstNonEmpty(dispositionParams["filename"], params["name"])),
+		ContentId:        strings.Trim(strings.TrimSpace(header.Get("Content-Id")), "<>"),
+		Parts:            []internet.EmailPart{},
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		return p.parseMultipart(ctx, part, params["boundary"], body, depth)
+	}
+
+	content, err := decodeTransferEncoding(part.TransferEncoding, body)
+	if err != nil {
+		return part, &internet.InvalidEmailError{Reason: fmt.Sprintf("the %s part: %v", mediaType, err)}
+	}
+	part.Size = len(content)
+
+	switch {
+	case mediaType == "message/rfc822" && disposition != "attachment":
+		headers, innerBody, err := readEmailHeaders(content)
+		if err != nil {
+			return part, err
+		}
+		inner, err := p.parse(ctx, emailHeaderMap(headers), innerBody, depth+1)
+		if err != nil {
+			return part, err
+		}
+		part.Parts = append(part.Parts, inner)
+	case (mediaType == "text/plain" || mediaType == "text/html") && part.Filename == "" && disposition != "attachment":
+		p.addBody(mediaType, part.Charset, content)
+	default:
+		p.addAttachment(part, content)
+	}
+
+	return part, nil
+}
+
+// parseMultipart describes a multipart part and the parts in it.
+func (p *emailParts) parseMultipart(ctx context.Context, part internet.EmailPart, boundary string, body []byte, depth int) (internet.EmailPart, error) {
+	if boundary == "" {
+		return part, &internet.InvalidEmailError{Reason: fmt.Sprintf("the %s part has no boundary", part.ContentType)}
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		raw, err := reader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return part, &internet.InvalidEmailError{Reason: fmt.Sprintf("the %s part: %v", part.ContentType, err)}
+		}
+		content, err := ioutil.ReadAll(raw)
+		if err != nil {
+			return part, &internet.InvalidEmailError{Reason: fmt.Sprintf("the %s part: %v", part.ContentType, err)}
+		}
+
+		child, err := p.parse(ctx, raw.Header, content, depth+1)
+		if err != nil {
+			return part, err
+		}
+		part.Parts = append(part.Parts, child)
+	}
+
+	return part, nil
+}
+
+// addBody keeps the first text and the first HTML body, in UTF-8 when
+// their charset is supported.
+func (p *emailParts) addBody(mediaType string, charset string, content []byte) {
+	text, err := decodeCharset(charset, content)
+	if err != nil {
+		text = string(content)
+	}
+
+	if mediaType == "text/html" {
+		if !p.htmlFound {
+			p.html, p.htmlFound = text, true
+		}
+		return
+	}
+	if !p.textFound {
+		p.text, p.textFound = text, true
+	}
+}
+
+// addAttachment adds a part that is neither a body nor a container of
+// other parts as an attachment.
+func (p *emailParts) addAttachment(part internet.EmailPart, content []byte) {
+	sum := sha256.Sum256(content)
+	attachment := internet.EmailAttachment{
+		Filename:    part.Filename,
+		ContentType: part.ContentType,
+		Size:        len(content),
+		Sha256:      hex.EncodeToString(sum[:]),
+		Inline:      part.Disposition == "inline" || (part.Disposition == "" && part.ContentId != ""),
+		ContentId:   part.ContentId,
+	}
+	if p.options.IncludeAttachments {
+		attachment.Content = base64.StdEncoding.EncodeToString(content)
+	}
+
+	p.attachments = append(p.attachments, attachment)
+}
+
+// decodeTransferEncoding decodes the content of a part. Base64 is
+// accepted without padding, as some senders write it.
+func decodeTransferEncoding(encoding string, content []byte) ([]byte, error) {
+	switch encoding {
+	case "base64":
+		data := bytes.Map(func(r rune) rune {
+			if r == ' ' || r == '\t' || r == '\r' || r == '\n' {
+				return -1
+			}
+			return r
+		}, content)
+		data = bytes.TrimRight(data, "=")
+		decoded := make([]byte, base64.RawStdEncoding.DecodedLen(len(data)))
+		n, err := base64.RawStdEncoding.Decode(decoded, data)
+		return decoded[:n], err
+	case "quoted-printable":
+		return ioutil.ReadAll(quotedprintable.NewReader(bytes.NewReader(content)))
+	}
+
+	return content, nil
+}
+
+// windows1252 are the characters windows-1252 has in place of the
+// control characters 0x80 to 0x9f of iso-8859-1, zero where it has
+// none.
+var windows1252 = [32]rune{
+	0x20ac, 0, 0x201a, 0x0192, 0x201e, 0x2026, 0x2020, 0x2021,
+	0x02c6, 0x2030, 0x0160, 0x2039, 0x0152, 0, 0x017d, 0,
+	0, 0x2018, 0x2019, 0x201c, 0x201d, 0x2022, 0x2013, 0x2014,
+	0x02dc, 0x2122, 0x0161, 0x203a, 0x0153, 0, 0x017e, 0x0178,
+}
+
+// decodeCharset converts text to UTF-8 from the charsets most messages
+// use: UTF-8, US-ASCII, ISO-8859-1 and Windows-1252.
+func decodeCharset(charset string, data []byte) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		return string(data), nil
+	case "iso-8859-1", "iso8859-1", "latin1", "latin-1", "windows-1252", "cp1252":
+		windows := strings.Contains(charset, "1252")
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+			if windows && b >= 0x80 && b <= 0x9f && windows1252[b-0x80] != 0 {
+				runes[i] = windows1252[b-0x80]
+			}
+		}
+		return string(runes), nil
+	}
+
+	return "", fmt.Errorf("unsupported charset %q", charset)
+}
@@ -0,0 +1,171 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"strings"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+// emailAuthentication summarizes the Authentication-Results headers,
+// RFC 8601, from the topmost. Only the topmost is trusted for the
+// summary, as the others can be added by anyone on the way.
+func emailAuthentication(values []string) internet.EmailAuthentication {
+	authentication := internet.EmailAuthentication{Results: []internet.EmailAuthenticationResult{}}
+
+	for i, value := range values {
+		results := parseAuthenticationResults(value)
+		if i == 0 {
+			for _, result := range results {
+				switch {
+				case result.Method == "spf" && authentication.Spf == "":
+					authentication.Spf = result.Result
+				case result.Method == "dkim" && authentication.Dkim == "":
+					authentication.Dkim = result.Result
+				case result.Method == "dmarc" && authentication.Dmarc == "":
+					authentication.Dmarc = result.Result
+				}
+			}
+		}
+		authentication.Results = append(authentication.Results, results...)
+	}
+
+	return authentication
+}
+
+// parseAuthenticationResults parses an Authentication-Results header,
+// made of the server that did the checks and the results of each one,
+// separated by semicolons.
+func parseAuthenticationResults(value string) []internet.EmailAuthenticationResult {
+	results := []internet.EmailAuthenticationResult{}
+
+	statements := splitAuthenticationResults(value)
+	if len(statements) == 0 || len(statements[0]) == 0 {
+		return results
+	}
+	authServId := statements[0][0]
+
+	for _, statement := range statements[1:] {
+		if len(statement) == 0 || !strings.Contains(statement[0], "=") {
+			// none, when there are no results
+			continue
+		}
+
+		method, result := splitAuthenticationProperty(statement[0])
+		if slash := strings.Index(method, "/"); slash >= 0 {
+			method = method[:slash]
+		}
+		parsed := internet.EmailAuthenticationResult{
+			AuthServId: authServId,
+			Method:     strings.ToLower(method),
+			Result:     strings.ToLower(result),
+			Properties: map[string]string{},
+		}
+		for _, property := range statement[1:] {
+			key, value := splitAuthenticationProperty(property)
+			if strings.EqualFold(key, "reason") {
+				parsed.Reason = value
+				continue
+			}
+			if value != "" {
+				parsed.Properties[strings.ToLower(key)] = value
+			}
+		}
+
+		results = append(results, parsed)
+	}
+
+	return results
+}
+
+// splitAuthenticationProperty splits a key=value pair.
+func splitAuthenticationProperty(property string) (string, string) {
+	equal := strings.Index(property, "=")
+	if equal < 0 {
+		return property, ""
+	}
+
+	return property[:equal], property[equal+1:]
+}
+
+// splitAuthenticationResults splits an Authentication-Results header
+// into statements, separated by semicolons, of words. Comments are
+// dropped, quoted strings are unquoted and spaces around = are ignored.
+func splitAuthenticationResults(value string) [][]string {
+	statements := [][]string{}
+	words := []string{}
+	word := strings.Builder{}
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	space := false
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			space = true
+			continue
+		case c == '(':
+			for depth := 0; i < len(value); i++ {
+				if value[i] == '\\' {
+					i++
+				} else if value[i] == '(' {
+					depth++
+				} else if value[i] == ')' {
+					depth--
+					if depth == 0 {
+						break
+					}
+				}
+			}
+			space = true
+			continue
+		}
+
+		// a space ends a word, unless it is around the = of a property
+		current := word.String()
+		if space && c != '=' && !strings.HasSuffix(current, "=") {
+			flush()
+		}
+		space = false
+
+		switch c {
+		case ';':
+			flush()
+			statements = append(statements, words)
+			words = []string{}
+		case '"':
+			for i++; i < len(value) && value[i] != '"'; i++ {
+				if value[i] == '\\' && i+1 < len(value) {
+					i++
+				}
+				word.WriteByte(value[i])
+			}
+		default:
+			word.WriteByte(c)
+		}
+	}
+	flush()
+	statements = append(statements, words)
+
+	return statements
+}
@@ -0,0 +1,138 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"testing"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEmailAuthentication(t *testing.T) {
+	// act
+	output, err := NewService().ParseEmail(readTestEmail(t, "invoice.eml"), internet.ParseEmailOptions{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, internet.EmailAuthentication{
+		Spf:   "pass",
+		Dkim:  "pass",
+		Dmarc: "pass",
+		Results: []internet.EmailAuthenticationResult{
+			{
+				AuthServId: "mx.example.com",
+				Method:     "dkim",
+				Result:     "pass",
+				Properties: map[string]string{"header.i": "@canivete.dev", "header.s": "s1", "header.b": "AbCdEf12"},
+			},
+			{
+				AuthServId: "mx.example.com",
+				Method:     "spf",
+				Result:     "pass",
+				Properties: map[string]string{"smtp.mailfrom": "bounces@news.canivete.dev"},
+			},
+			{
+				AuthServId: "mx.example.com",
+				Method:     "dmarc",
+				Result:     "pass",
+				Properties: map[string]string{"header.from": "canivete.dev"},
+			},
+			{
+				AuthServId: "mail.canivete.dev",
+				Method:     "spf",
+				Result:     "none",
+				Properties: map[string]string{"smtp.helo": "app.internal"},
+			},
+		},
+	}, output.Authentication)
+}
+
+func TestParseAuthenticationResults(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected []internet.EmailAuthenticationResult
+	}{
+		{
+			name:     "none",
+			value:    "mx.canivete.dev 1; none",
+			expected: []internet.EmailAuthenticationResult{},
+		},
+		{
+			name:     "empty",
+			value:    "",
+			expected: []internet.EmailAuthenticationResult{},
+		},
+		{
+			name:  "reason and version",
+			value: `mx.canivete.dev; dkim/1=FAIL reason="signature \"verification\" failed" header.d=canivete.dev (the key was rotated)`,
+			expected: []internet.EmailAuthenticationResult{{
+				AuthServId: "mx.canivete.dev",
+				Method:     "dkim",
+				Result:     "fail",
+				Reason:     `signature "verification" failed`,
+				Properties: map[string]string{"header.d": "canivete.dev"},
+			}},
+		},
+		{
+			name:  "spaces around equals",
+			value: "mx.canivete.dev;\r\n\tspf = softfail smtp.mailfrom = ops@canivete.dev ; dmarc=fail (p=NONE (nested)) header.from=canivete.dev",
+			expected: []internet.EmailAuthenticationResult{
+				{
+					AuthServId: "mx.canivete.dev",
+					Method:     "spf",
+					Result:     "softfail",
+					Properties: map[string]string{"smtp.mailfrom": "ops@canivete.dev"},
+				},
+				{
+					AuthServId: "mx.canivete.dev",
+					Method:     "dmarc",
+					Result:     "fail",
+					Properties: map[string]string{"header.from": "canivete.dev"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			results := parseAuthenticationResults(tc.value)
+
+			// assert
+			assert.Equal(t, tc.expected, results)
+		})
+	}
+}
+
+func TestEmailAuthenticationTopmost(t *testing.T) {
+	// arrange
+	values := []string{
+		"mx.canivete.dev; spf=fail smtp.mailfrom=x@canivete.dev",
+		"attacker.example; spf=pass; dkim=pass; dmarc=pass",
+	}
+
+	// act
+	authentication := emailAuthentication(values)
+
+	// assert
+	assert.Equal(t, "fail", authentication.Spf)
+	assert.Equal(t, "", authentication.Dkim)
+	assert.Equal(t, "", authentication.Dmarc)
+	assert.Len(t, authentication.Results, 4)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+// receivedClauses are the clauses of a Received header, RFC 5321
+// section 4.4.
+var receivedClauses = map[string]bool{"from": true, "by": true, "via": true, "with": true, "id": true, "for": true}
+
+// receivedIp matches the address of the sending server, which most
+// servers write in brackets, like [192.0.2.1] or [IPv6:2001:db8::1].
+var receivedIp = regexp.MustCompile(`\[(?i:IPv6:)?([0-9A-Fa-f:.]+)\]`)
+
+// emailHops describes the Received headers, from the topmost, as the
+// hops of the message from the first server, with the delay of each one
+// since the date of the message or the previous hop.
+func (s *Service) emailHops(ctx context.Context, received []string, sent time.Time) ([]internet.EmailHop, int64, error) {
+	hops := []internet.EmailHop{}
+	total := int64(0)
+
+	previous := sent
+	for i := len(received) - 1; i >= 0; i-- {
+		hop, date := parseReceived(received[i])
+		if !date.IsZero() {
+			var err error
+			hop.Date, err = s.datetime().FromUnitTimestampContext(ctx, date.Unix())
+			if err != nil {
+				return nil, 0, err
+			}
+			if !previous.IsZero() {
+				hop.Delay = date.Unix() - previous.Unix()
+				total += hop.Delay
+			}
+		}
+		previous = date
+
+		hops = append(hops, hop)
+	}
+
+	return hops, total, nil
+}
+
+// parseReceived parses the clauses of a Received header and the date
+// after them, zero if missing or invalid.
+func parseReceived(value string) (internet.EmailHop, time.Time) {
+	date := time.Time{}
+	if semicolon := strings.LastIndex(value, ";"); semicolon >= 0 {
+		if parsed, err := parseEmailDate(value[semicolon+1:]); err == nil {
+			date = parsed
+		}
+		value = value[:semicolon]
+	}
+
+	clauses := map[string][]string{}
+	clause := ""
+	for _, word := range receivedWords(value) {
+		if receivedClauses[strings.ToLower(word)] {
+			clause = strings.ToLower(word)
+			continue
+		}
+		// the comments of the id and for clauses are not part of them,
+		// like the TLS version some servers write last
+		comment := strings.HasPrefix(word, "(")
+		if clause != "" && !(comment && (clause == "id" || clause == "for")) {
+			clauses[clause] = append(clauses[clause], word)
+		}
+	}
+
+	hop := internet.EmailHop{
+		From: strings.Join(clauses["from"], " "),
+		By:   strings.Join(clauses["by"], " "),
+		With: strings.Join(clauses["with"], " "),
+		Id:   strings.Join(clauses["id"], " "),
+		For:  strings.Trim(strings.Join(clauses["for"], " "), "<>"),
+	}
+	for _, match := range receivedIp.FindAllStringSubmatch(hop.From, -1) {
+		if net.ParseIP(match[1]) != nil {
+			hop.FromIp = match[1]
+			break
+		}
+	}
+
+	return hop, date
+}
+
+// receivedWords splits the clauses of a Received header into words,
+// each comment, nested or not, being a single word.
+func receivedWords(value string) []string {
+	words := []string{}
+	word := strings.Builder{}
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	depth := 0
+	for _, r := range value {
+		switch {
+		case r == '(':
+			if depth == 0 {
+				flush()
+			}
+			depth++
+			word.WriteRune(r)
+		case r == ')' && depth > 0:
+			depth--
+			word.WriteRune(r)
+			if depth == 0 {
+				flush()
+			}
+		case depth == 0 && (r == ' ' || r == '\t' || r == '\r' || r == '\n'):
+			flush()
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+
+	return words
+}
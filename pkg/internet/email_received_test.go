@@ -0,0 +1,130 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"testing"
+
+	"github.com/renato0307/canivete-core/interface/datetime"
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEmailHops(t *testing.T) {
+	// act
+	output, err := NewService().ParseEmail(readTestEmail(t, "invoice.eml"), internet.ParseEmailOptions{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, []internet.EmailHop{
+		{
+			From:   "app.internal (app.internal [IPv6:2001:db8::25])",
+			FromIp: "2001:db8::25",
+			By:     "mail.canivete.dev (Postfix)",
+			With:   "ESMTP",
+			Id:     "4K7Q2L0abc",
+			For:    "support@example.com",
+			Date:   datetime.FromUnixTimestampOutput{UnixTimestamp: 1646128798, UtcTimestamp: "Tue Mar  1 09:59:58 UTC 2022"},
+			Delay:  3,
+		},
+		{
+			From:   "mail.canivete.dev (mail.canivete.dev [192.0.2.25])",
+			FromIp: "192.0.2.25",
+			By:     "mx.example.com",
+			With:   "ESMTPS",
+			Id:     "5a7b9c11d2",
+			For:    "support@example.com",
+			Date:   datetime.FromUnixTimestampOutput{UnixTimestamp: 1646128805, UtcTimestamp: "Tue Mar  1 10:00:05 UTC 2022"},
+			Delay:  7,
+		},
+		{
+			By:    "2002:a05:6402:1234",
+			With:  "SMTP",
+			Id:    "x12csp123456ede",
+			Date:  datetime.FromUnixTimestampOutput{UnixTimestamp: 1646128807, UtcTimestamp: "Tue Mar  1 10:00:07 UTC 2022"},
+			Delay: 2,
+		},
+	}, output.Hops)
+	assert.Equal(t, int64(12), output.TotalDelay)
+}
+
+func TestParseReceived(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected internet.EmailHop
+		date     int64
+	}{
+		{
+			name:     "no date",
+			value:    "from [192.0.2.7] by mx.canivete.dev via HTTP",
+			expected: internet.EmailHop{From: "[192.0.2.7]", FromIp: "192.0.2.7", By: "mx.canivete.dev"},
+		},
+		{
+			name:     "invalid date",
+			value:    "from a.canivete.dev by b.canivete.dev; yesterday",
+			expected: internet.EmailHop{From: "a.canivete.dev", By: "b.canivete.dev"},
+		},
+		{
+			name:     "nested comments",
+			value:    "from a (helo (x) [not an ip]) by b (envelope-from <x@y>) with LMTP; Tue, 1 Mar 2022 10:00:00 +0100",
+			expected: internet.EmailHop{From: "a (helo (x) [not an ip])", By: "b (envelope-from <x@y>)", With: "LMTP"},
+			date:     1646125200,
+		},
+		{
+			name:     "uppercase clauses",
+			value:    "FROM a BY b ID 1 (queued) FOR <c@d>; Tue, 1 Mar 2022 10:00:00 GMT",
+			expected: internet.EmailHop{From: "a", By: "b", Id: "1", For: "c@d"},
+			date:     1646128800,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			hop, date := parseReceived(tc.value)
+
+			// assert
+			assert.Equal(t, tc.expected, hop)
+			if tc.date == 0 {
+				assert.True(t, date.IsZero())
+			} else {
+				assert.Equal(t, tc.date, date.Unix())
+			}
+		})
+	}
+}
+
+func TestParseEmailHopsMissingDates(t *testing.T) {
+	// arrange
+	raw := "Received: by c; Tue, 1 Mar 2022 10:00:30 +0000\n" +
+		"Received: by b\n" +
+		"Received: by a; Tue, 1 Mar 2022 10:00:10 +0000\n" +
+		"\nHi"
+
+	// act
+	output, err := NewService().ParseEmail(raw, internet.ParseEmailOptions{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Len(t, output.Hops, 3)
+	assert.Equal(t, int64(0), output.Hops[0].Delay)
+	assert.Equal(t, int64(0), output.Hops[1].Delay)
+	assert.Equal(t, datetime.FromUnixTimestampOutput{}, output.Hops[1].Date)
+	assert.Equal(t, int64(0), output.Hops[2].Delay)
+	assert.Equal(t, int64(0), output.TotalDelay)
+}
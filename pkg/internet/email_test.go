@@ -0,0 +1,253 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/renato0307/canivete-core/interface/datetime"
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+// readTestEmail reads a message of testdata/email.
+func readTestEmail(t *testing.T, name string) string {
+	data, err := ioutil.ReadFile("testdata/email/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestParseEmail(t *testing.T) {
+	// arrange
+	raw := readTestEmail(t, "invoice.eml")
+
+	// act
+	output, err := NewService().ParseEmail(raw, internet.ParseEmailOptions{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Len(t, output.Headers, 17)
+	assert.Equal(t, internet.EmailHeader{
+		Name:  "Subject",
+		Value: "Fatura de março ✔",
+		Raw:   "=?UTF-8?Q?Fatura_de_mar=C3=A7o?= =?UTF-8?Q?_=E2=9C=94?=",
+	}, output.Headers[12])
+	assert.Equal(t, internet.EmailHeader{
+		Name:  "DKIM-Signature",
+		Value: "v=1; a=rsa-sha256; d=canivete.dev; s=s1;\th=from:to:subject:date; b=AbCdEf12",
+		Raw:   "v=1; a=rsa-sha256; d=canivete.dev; s=s1;\th=from:to:subject:date; b=AbCdEf12",
+	}, output.Headers[7])
+	assert.Equal(t, []internet.EmailAddress{{Name: "João Silva", Address: "joao@canivete.dev"}}, output.From)
+	assert.Equal(t, []internet.EmailAddress{
+		{Name: "Support", Address: "support@example.com"},
+		{Name: "José", Address: "jose@example.com"},
+	}, output.To)
+	assert.Equal(t, []internet.EmailAddress{{Address: "ops@canivete.dev"}}, output.Cc)
+	assert.Equal(t, []internet.EmailAddress{{Name: "Canivete Support", Address: "help@canivete.dev"}}, output.ReplyTo)
+	assert.Equal(t, "Fatura de março ✔", output.Subject)
+	assert.Equal(t, "20220301095955.1234@canivete.dev", output.MessageId)
+	assert.Equal(t, datetime.FromUnixTimestampOutput{UnixTimestamp: 1646128795, UtcTimestamp: "Tue Mar  1 09:59:55 UTC 2022"}, output.Date)
+	assert.Equal(t, "Olá, segue a fatura de março. A fatura está em anexo e tem o valor de 12,30 €.", output.TextBody)
+	assert.Equal(t, `<p>Olá, segue a fatura de março.</p><img src="cid:logo@canivete">`, output.HtmlBody)
+	assert.Equal(t, []internet.EmailAttachment{
+		{
+			ContentType: "image/png",
+			Size:        70,
+			Sha256:      "1b55ca3505acd725c7cd9b2076328f041c09eea437f2f044f5103588ada96693",
+			Inline:      true,
+			ContentId:   "logo@canivete",
+		},
+		{
+			Filename:    "fatura março.pdf",
+			ContentType: "application/pdf",
+			Size:        77,
+			Sha256:      "56c2ac043c28d3543275a6f50b593a6beb0b6888ed8159ac02e3e7f4a32ccb26",
+		},
+	}, output.Attachments)
+}
+
+func TestParseEmailParts(t *testing.T) {
+	// act
+	output, err := NewService().ParseEmail(readTestEmail(t, "invoice.eml"), internet.ParseEmailOptions{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, internet.EmailPart{
+		ContentType: "multipart/mixed",
+		Parts: []internet.EmailPart{
+			{
+				ContentType: "multipart/alternative",
+				Parts: []internet.EmailPart{
+					{ContentType: "text/plain", Charset: "utf-8", TransferEncoding: "quoted-printable", Size: 83, Parts: []internet.EmailPart{}},
+					{
+						ContentType: "multipart/related",
+						Parts: []internet.EmailPart{
+							{ContentType: "text/html", Charset: "iso-8859-1", TransferEncoding: "base64", Size: 65, Parts: []internet.EmailPart{}},
+							{ContentType: "image/png", TransferEncoding: "base64", ContentId: "logo@canivete", Size: 70, Parts: []internet.EmailPart{}},
+						},
+					},
+				},
+			},
+			{
+				ContentType:      "application/pdf",
+				TransferEncoding: "base64",
+				Disposition:      "attachment",
+				Filename:         "fatura março.pdf",
+				Size:             77,
+				Parts:            []internet.EmailPart{},
+			},
+		},
+	}, output.Body)
+}
+
+func TestParseEmailIncludeAttachments(t *testing.T) {
+	// act
+	output, err := NewService().ParseEmail(readTestEmail(t, "invoice.eml"), internet.ParseEmailOptions{IncludeAttachments: true})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "JVBERi0xLjQKMSAwIG9iaiA8PCAvVHlwZSAvQ2F0YWxvZyA+PiBlbmRvYmoKdHJhaWxlciA8PCAvUm9vdCAxIDAgUiA+PgolJUVPRgo=", output.Attachments[1].Content)
+}
+
+func TestParseEmailCrlf(t *testing.T) {
+	// arrange
+	raw := strings.ReplaceAll(readTestEmail(t, "invoice.eml"), "\n", "\r\n")
+
+	// act
+	output, err := NewService().ParseEmail(raw, internet.ParseEmailOptions{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "Fatura de março ✔", output.Subject)
+	assert.Len(t, output.Attachments, 2)
+	assert.Equal(t, "Olá, segue a fatura de março. A fatura está em anexo e tem o valor de 12,30 €.", output.TextBody)
+}
+
+func TestParseEmailSimple(t *testing.T) {
+	testCases := []struct {
+		name     string
+		raw      string
+		text     string
+		html     string
+		subject  string
+		from     []internet.EmailAddress
+		attached []string
+	}{
+		{
+			name:    "no content type",
+			raw:     "From: ops@canivete.dev\nSubject: Hello\n\nHello world\n",
+			text:    "Hello world\n",
+			subject: "Hello",
+			from:    []internet.EmailAddress{{Address: "ops@canivete.dev"}},
+		},
+		{
+			name:    "mbox separator",
+			raw:     "From ops@canivete.dev Tue Mar  1 09:59:55 2022\nFrom: ops@canivete.dev\n\nHello\n",
+			text:    "Hello\n",
+			subject: "",
+			from:    []internet.EmailAddress{{Address: "ops@canivete.dev"}},
+		},
+		{
+			name:    "windows-1252",
+			raw:     "From: ops@canivete.dev\nSubject: =?windows-1252?Q?=93quoted=94?=\nContent-Type: text/plain; charset=windows-1252\n\nCost: 5\x80\n",
+			text:    "Cost: 5€\n",
+			subject: "“quoted”",
+			from:    []internet.EmailAddress{{Address: "ops@canivete.dev"}},
+		},
+		{
+			name:    "unsupported charset",
+			raw:     "From: ops@canivete.dev\nSubject: =?koi8-r?B?8NLJ18XU?=\nContent-Type: text/html; charset=koi8-r\n\n<p>Hi</p>",
+			html:    "<p>Hi</p>",
+			subject: "=?koi8-r?B?8NLJ18XU?=",
+			from:    []internet.EmailAddress{{Address: "ops@canivete.dev"}},
+		},
+		{
+			name: "invalid from",
+			raw:  "From: Canivete, Ops <ops@canivete.dev>\n\nHi",
+			text: "Hi",
+			from: []internet.EmailAddress{{Name: "Canivete, Ops <ops@canivete.dev>"}},
+		},
+		{
+			name:     "base64 without padding",
+			raw:      "From: ops@canivete.dev\nContent-Type: text/csv; name=report.csv\nContent-Transfer-Encoding: base64\n\nYSxi\nCjEsMgo\n",
+			from:     []internet.EmailAddress{{Address: "ops@canivete.dev"}},
+			attached: []string{"report.csv"},
+		},
+		{
+			name:     "forwarded message",
+			raw:      "From: ops@canivete.dev\nContent-Type: multipart/mixed; boundary=b\n\n--b\nContent-Type: text/plain\n\nSee below\n--b\nContent-Type: message/rfc822\n\nFrom: joao@canivete.dev\nSubject: Original\nContent-Type: multipart/mixed; boundary=c\n\n--c\nContent-Type: text/plain\n\nOriginal text\n--c\nContent-Type: application/zip\nContent-Disposition: attachment; filename=\"=?UTF-8?Q?c=C3=B3digo.zip?=\"\n\nPK\n--c--\n--b--\n",
+			text:     "See below",
+			from:     []internet.EmailAddress{{Address: "ops@canivete.dev"}},
+			attached: []string{"código.zip"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			output, err := NewService().ParseEmail(tc.raw, internet.ParseEmailOptions{})
+
+			// assert
+			assert.Nil(t, err)
+			assert.Equal(t, tc.text, output.TextBody)
+			assert.Equal(t, tc.html, output.HtmlBody)
+			assert.Equal(t, tc.subject, output.Subject)
+			assert.Equal(t, tc.from, output.From)
+			attached := []string{}
+			for _, attachment := range output.Attachments {
+				attached = append(attached, attachment.Filename)
+			}
+			assert.Equal(t, append([]string{}, tc.attached...), attached)
+		})
+	}
+}
+
+func TestParseEmailInvalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		raw    string
+		reason string
+	}{
+		{"empty", "", "the message has no headers"},
+		{"not a message", "hello world", `line 1 is not a header: "hello world"`},
+		{"continuation first", " Subject: hello\n\nbody", "the first line is a continuation of a header"},
+		{"no boundary", "Content-Type: multipart/mixed\n\nbody", "the multipart/mixed part has no boundary"},
+		{"bad base64", "Content-Type: image/png\nContent-Transfer-Encoding: base64\n\n*not base64*", "the image/png part: illegal base64 data at input byte 0"},
+		{"truncated", "Content-Type: multipart/mixed; boundary=b\n\n--b\nContent-Type: text/plain\n\nHello", "the multipart/mixed part: unexpected EOF"},
+		{"too deep", strings.Repeat("Content-Type: message/rfc822\n\n", 40) + "Hi", "the parts are nested more than 32 levels"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			_, err := NewService().ParseEmail(tc.raw, internet.ParseEmailOptions{})
+
+			// assert
+			var invalid *internet.InvalidEmailError
+			assert.True(t, errors.As(err, &invalid))
+			if invalid != nil {
+				assert.Equal(t, tc.reason, invalid.Reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+type frontMatterField struct {
+	key   string
+	value interface{}
+}
+
+// frontMatterFlavours has, for each static site generator, the front
+// matter formats it supports and the fields of a post in its own names.
+var frontMatterFlavours = map[string]struct {
+	formats []string
+	fields  func(output internet.ConvertMediumToMdOutput) []frontMatterField
+}{
+	"hugo": {
+		formats: []string{"yaml", "toml"},
+		fields: func(o internet.ConvertMediumToMdOutput) []frontMatterField {
+			return []frontMatterField{
+				{"title", o.Title},
+				{"description", o.Subtitle},
+				{"author", o.Author},
+				{"date", o.PublishedAt},
+				{"lastmod", o.UpdatedAt},
+				{"tags", o.Tags},
+				{"canonicalURL", o.CanonicalUrl},
+				{"images", nonEmptyList(o.PreviewImage)},
+			}
+		},
+	},
+	"jekyll": {
+		formats: []string{"yaml"},
+		fields: func(o internet.ConvertMediumToMdOutput) []frontMatterField {
+			return []frontMatterField{
+				{"layout", "post"},
+				{"title", o.Title},
+				{"subtitle", o.Subtitle},
+				{"author", o.Author},
+				{"date", o.PublishedAt},
+				{"last_modified_at", o.UpdatedAt},
+				{"tags", o.Tags},
+				{"canonical_url", o.CanonicalUrl},
+				{"image", o.PreviewImage},
+				{"reading_time", int(o.ReadingTime.Round(time.Minute).Minutes())},
+			}
+		},
+	},
+	"astro": {
+		formats: []string{"yaml"},
+		fields: func(o internet.ConvertMediumToMdOutput) []frontMatterField {
+			return []frontMatterField{
+				{"title", o.Title},
+				{"description", o.Subtitle},
+				{"author", o.Author},
+				{"pubDate", o.PublishedAt},
+				{"updatedDate", o.UpdatedAt},
+				{"tags", o.Tags},
+				{"canonicalURL", o.CanonicalUrl},
+				{"heroImage", o.PreviewImage},
+				{"minutesRead", int(o.ReadingTime.Round(time.Minute).Minutes())},
+			}
+		},
+	},
+}
+
+func validateFrontMatterOptions(options internet.ConvertMediumToMdOptions) error {
+	format := strings.ToLower(options.FrontMatter)
+	if format == "" {
+		return nil
+	}
+
+	flavourName := frontMatterFlavour(options)
+	flavour, ok := frontMatterFlavours[flavourName]
+	if !ok {
+		return fmt.Errorf("unsupported front matter flavour %q", options.Flavour)
+	}
+
+	for _, supported := range flavour.formats {
+		if format == supported {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s does not support %s front matter", flavourName, format)
+}
+
+func frontMatterFlavour(options internet.ConvertMediumToMdOptions) string {
+	if options.Flavour == "" {
+		return "hugo"
+	}
+
+	return strings.ToLower(options.Flavour)
+}
+
+// frontMatter returns the front matter for a post, or an empty string if
+// none was requested. The options must have been validated.
+func frontMatter(output internet.ConvertMediumToMdOutput, options internet.ConvertMediumToMdOptions) string {
+	format := strings.ToLower(options.FrontMatter)
+	if format == "" {
+		return ""
+	}
+
+	fields := frontMatterFlavours[frontMatterFlavour(options)].fields(output)
+
+	delimiter, separator := "---", ": "
+	if format == "toml" {
+		delimiter, separator = "+++", " = "
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString(delimiter + "\n")
+	for _, field := range fields {
+		value, ok := frontMatterValue(field.value)
+		if !ok {
+			continue
+		}
+		buffer.WriteString(field.key + separator + value + "\n")
+	}
+	buffer.WriteString(delimiter + "\n\n")
+
+	return buffer.String()
+}
+
+// frontMatterValue formats a value with the syntax shared by YAML and
+// TOML, returning false for empty values, which are omitted.
+func frontMatterValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return quoteFrontMatter(v), v != ""
+	case int:
+		return strconv.Itoa(v), v != 0
+	case time.Time:
+		return v.Format(time.RFC3339), !v.IsZero()
+	case []string:
+		items := []string{}
+		for _, item := range v {
+			items = append(items, quoteFrontMatter(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]", len(v) > 0
+	}
+
+	return "", false
+}
+
+// quoteFrontMatter quotes a string using the escapes that are valid
+// both in YAML and TOML double quoted strings.
+func quoteFrontMatter(value string) string {
+	var buffer bytes.Buffer
+	buffer.WriteString(`"`)
+	for _, r := range value {
+		switch {
+		case r == '"' || r == '\\':
+			buffer.WriteRune('\\')
+			buffer.WriteRune(r)
+		case r == '\n':
+			buffer.WriteString(`\n`)
+		case r == '\t':
+			buffer.WriteString(`\t`)
+		case unicode.IsControl(r):
+			buffer.WriteString(fmt.Sprintf(`\u%04X`, r))
+		default:
+			buffer.WriteRune(r)
+		}
+	}
+	buffer.WriteString(`"`)
+
+	return buffer.String()
+}
+
+func nonEmptyList(values ...string) []string {
+	list := []string{}
+	for _, value := range values {
+		if value != "" {
+			list = append(list, value)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,119 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const defaultGitHubApiEndpoint = "https://api.github.com"
+
+// Gist is a GitHub gist.
+type Gist struct {
+	Id    string
+	Files []GistFile
+}
+
+// GistFile is a file of a GitHub gist.
+type GistFile struct {
+	Filename string
+	Language string
+	Content  string
+}
+
+// GistClient gets gists from GitHub.
+type GistClient interface {
+	Gist(ctx context.Context, id string) (Gist, error)
+}
+
+type gitHubGistClient struct {
+	client   *http.Client
+	endpoint string
+}
+
+// NewGitHubGistClient creates a GistClient using the GitHub REST API
+// available at endpoint, or the public one if endpoint is empty.
+func NewGitHubGistClient(client *http.Client, endpoint string) GistClient {
+	if endpoint == "" {
+		endpoint = defaultGitHubApiEndpoint
+	}
+
+	return &gitHubGistClient{client: client, endpoint: strings.TrimRight(endpoint, "/")}
+}
+
+type gitHubGistResponse struct {
+	Id    string `json:"id"`
+	Files map[string]struct {
+		Filename  string `json:"filename"`
+		Language  string `json:"language"`
+		Content   string `json:"content"`
+		Truncated bool   `json:"truncated"`
+	} `json:"files"`
+}
+
+func (c *gitHubGistClient) Gist(ctx context.Context, id string) (Gist, error) {
+	gist := Gist{}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.endpoint+"/gists/"+id, nil)
+	if err != nil {
+		return gist, fmt.Errorf("error creating request: %s", err.Error())
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return gist, fmt.Errorf("error executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return gist, fmt.Errorf("unexpected status code %d getting gist %s", resp.StatusCode, id)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return gist, fmt.Errorf("error reading response: %s", err.Error())
+	}
+
+	response := gitHubGistResponse{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return gist, fmt.Errorf("error un-marshalling gist response: %s", err.Error())
+	}
+
+	gist.Id = response.Id
+	for _, file := range response.Files {
+		gist.Files = append(gist.Files, GistFile{
+			Filename: file.Filename,
+			Language: file.Language,
+			Content:  file.Content,
+		})
+	}
+
+	// the API returns the files as a map, sort them like GitHub does
+	sort.Slice(gist.Files, func(i, j int) bool {
+		return gist.Files[i].Filename < gist.Files[j].Filename
+	})
+
+	return gist, nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitHubGistClient(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/gists/0d8e", r.URL.Path)
+		w.Write([]byte(`{
+			"id": "0d8e",
+			"files": {
+				"main.go": {"filename": "main.go", "language": "Go", "content": "package main"},
+				"go.mod": {"filename": "go.mod", "language": "Go Module", "content": "module x"}
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	// act
+	c := NewGitHubGistClient(server.Client(), server.URL)
+	gist, err := c.Gist(context.Background(), "0d8e")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "0d8e", gist.Id)
+	assert.Len(t, gist.Files, 2)
+	assert.Equal(t, "go.mod", gist.Files[0].Filename)
+	assert.Equal(t, "package main", gist.Files[1].Content)
+}
+
+func TestGitHubGistClientNotFound(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	// act
+	c := NewGitHubGistClient(server.Client(), server.URL)
+	_, err := c.Gist(context.Background(), "0d8e")
+
+	// assert
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
@@ -0,0 +1,150 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	graphQLName = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+	graphQLType = regexp.MustCompile(`^(?:[_A-Za-z][_0-9A-Za-z]*|\[[_A-Za-z][_0-9A-Za-z]*!?\])!?$`)
+)
+
+// graphQLQuery is a GraphQL query operation. Values are never written in
+// the query text: they are passed as variables, which the arguments of
+// the fields refer to, so they can not change the query.
+type graphQLQuery struct {
+	operation string
+	variables []graphQLVariable
+	fields    []graphQLField
+}
+
+type graphQLVariable struct {
+	name  string
+	typ   string
+	value interface{}
+}
+
+type graphQLField struct {
+	name      string
+	arguments []graphQLArgument
+	fields    []graphQLField
+}
+
+type graphQLArgument struct {
+	name     string
+	variable string
+}
+
+// graphQLRequest is the body of a GraphQL request sent over HTTP.
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// gqlField returns a field selecting the sub fields, if any.
+func gqlField(name string, fields ...graphQLField) graphQLField {
+	return graphQLField{name: name, fields: fields}
+}
+
+// withArgument returns the field with an argument set to the value of a
+// variable of the query.
+func (f graphQLField) withArgument(name, variable string) graphQLField {
+	f.arguments = append(append([]graphQLArgument{}, f.arguments...), graphQLArgument{name, variable})
+	return f
+}
+
+// request builds the request of the query, checking the names and types
+// are valid and the arguments refer to variables of the query.
+func (q graphQLQuery) request() (graphQLRequest, error) {
+	request := graphQLRequest{OperationName: q.operation, Variables: map[string]interface{}{}}
+
+	if q.operation != "" && !graphQLName.MatchString(q.operation) {
+		return request, fmt.Errorf("invalid graphql operation name %q", q.operation)
+	}
+
+	var builder strings.Builder
+	builder.WriteString("query")
+	if q.operation != "" {
+		builder.WriteString(" " + q.operation)
+	}
+
+	definitions := []string{}
+	for _, variable := range q.variables {
+		if !graphQLName.MatchString(variable.name) {
+			return request, fmt.Errorf("invalid graphql variable name %q", variable.name)
+		}
+		if !graphQLType.MatchString(variable.typ) {
+			return request, fmt.Errorf("invalid graphql type %q of variable %s", variable.typ, variable.name)
+		}
+
+		definitions = append(definitions, fmt.Sprintf("$%s: %s", variable.name, variable.typ))
+		request.Variables[variable.name] = variable.value
+	}
+	if len(definitions) > 0 {
+		builder.WriteString("(" + strings.Join(definitions, ", ") + ")")
+	}
+
+	err := writeGraphQLFields(&builder, q.fields, request.Variables, 0)
+	if err != nil {
+		return request, err
+	}
+
+	request.Query = builder.String()
+	return request, nil
+}
+
+func writeGraphQLFields(builder *strings.Builder, fields []graphQLField, variables map[string]interface{}, depth int) error {
+	builder.WriteString(" {\n")
+	for _, field := range fields {
+		if !graphQLName.MatchString(field.name) {
+			return fmt.Errorf("invalid graphql field name %q", field.name)
+		}
+
+		builder.WriteString(strings.Repeat("  ", depth+1) + field.name)
+
+		arguments := []string{}
+		for _, argument := range field.arguments {
+			if !graphQLName.MatchString(argument.name) {
+				return fmt.Errorf("invalid graphql argument name %q", argument.name)
+			}
+			if _, ok := variables[argument.variable]; !ok {
+				return fmt.Errorf("undefined graphql variable %q", argument.variable)
+			}
+
+			arguments = append(arguments, fmt.Sprintf("%s: $%s", argument.name, argument.variable))
+		}
+		if len(arguments) > 0 {
+			builder.WriteString("(" + strings.Join(arguments, ", ") + ")")
+		}
+
+		if len(field.fields) > 0 {
+			err := writeGraphQLFields(builder, field.fields, variables, depth+1)
+			if err != nil {
+				return err
+			}
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString(strings.Repeat("  ", depth) + "}")
+
+	return nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphQLQueryRequest(t *testing.T) {
+	// arrange
+	query := graphQLQuery{
+		operation: "PostQuery",
+		variables: []graphQLVariable{{name: "id", typ: "ID!", value: `x") { id } q: post(id: "y`}},
+		fields: []graphQLField{
+			gqlField("post", gqlField("id"), gqlField("creator", gqlField("name"))).withArgument("id", "id"),
+		},
+	}
+
+	// act
+	request, err := query.request()
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "query PostQuery($id: ID!) {\n"+
+		"  post(id: $id) {\n"+
+		"    id\n"+
+		"    creator {\n"+
+		"      name\n"+
+		"    }\n"+
+		"  }\n"+
+		"}", request.Query)
+	assert.Equal(t, "PostQuery", request.OperationName)
+	assert.Equal(t, map[string]interface{}{"id": `x") { id } q: post(id: "y`}, request.Variables)
+}
+
+func TestGraphQLQueryRequestInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		query graphQLQuery
+	}{
+		{"operation", graphQLQuery{operation: "Post Query"}},
+		{"variable name", graphQLQuery{variables: []graphQLVariable{{name: "id) { x }", typ: "ID"}}}},
+		{"variable type", graphQLQuery{variables: []graphQLVariable{{name: "id", typ: "ID = \"x\""}}}},
+		{"field", graphQLQuery{fields: []graphQLField{gqlField("post { id }")}}},
+		{"argument", graphQLQuery{
+			variables: []graphQLVariable{{name: "id", typ: "ID"}},
+			fields:    []graphQLField{gqlField("post").withArgument("id: \"x\", y", "id")},
+		}},
+		{"undefined variable", graphQLQuery{fields: []graphQLField{gqlField("post").withArgument("id", "id")}}},
+	}
+
+	for _, test := range tests {
+		// act
+		_, err := test.query.request()
+
+		// assert
+		assert.NotNil(t, err, test.name)
+	}
+}
+
+func TestMediumPostQuery(t *testing.T) {
+	// act
+	request, err := mediumPostQuery("f744fbff033e")
+	data, jsonErr := json.Marshal(request)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, jsonErr)
+	assert.Contains(t, request.Query, "query PostQuery($id: ID!) {\n  post(id: $id) {\n")
+	assert.NotContains(t, request.Query, "f744fbff033e")
+	assert.Contains(t, string(data), `"variables":{"id":"f744fbff033e"}`)
+}
+
+func TestMediumPostQueryRejectsInjection(t *testing.T) {
+	ids := []string{
+		`f744fbff033e") { id } evil: user(username: "x`,
+		`f744fbff033e"`,
+		"f744fbff033e\n",
+		"f744fbff033e }",
+		"$id",
+		"F744FBFF033E",
+		"",
+	}
+
+	for _, id := range ids {
+		// act
+		_, err := mediumPostQuery(id)
+
+		// assert
+		invalid := &internet.InvalidMediumInputError{}
+		assert.True(t, errors.As(err, &invalid), id)
+	}
+}
+
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestGetPostDataRejectsInjection(t *testing.T) {
+	// arrange
+	requested := false
+	server := newMediumServer(t)
+	defer server.Close()
+	client := server.Client()
+	transport := client.Transport
+	client.Transport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		requested = true
+		return transport.RoundTrip(r)
+	})
+
+	// act
+	s := NewService(WithHTTPClient(client), WithMediumEndpoint(server.URL))
+	_, _, err := s.getPostData(context.Background(), `f744fbff033e") { id } evil: post(id: "a2371a1c11b7`, false)
+
+	// assert
+	invalid := &internet.InvalidMediumInputError{}
+	assert.True(t, errors.As(err, &invalid))
+	assert.False(t, requested)
+}
@@ -0,0 +1,334 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"encoding/xml"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// htmlNode is an element or a text of an HTML document.
+type htmlNode struct {
+	// tag is the name of an element in lower case, empty for texts
+	tag      string
+	attrs    map[string]string
+	text     string
+	parent   *htmlNode
+	children []*htmlNode
+}
+
+// htmlVoidElements never have content nor an end tag.
+var htmlVoidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+// htmlImpliedEnds has the elements whose start tag closes an open element
+// of the same kind, like a new paragraph, list item or table row, with
+// everything left open inside it. The open element is only searched up
+// to the scope of the new one: a new cell does not close the cell of an
+// outer table.
+var htmlImpliedEnds = map[string]struct {
+	closes []string
+	scope  []string
+}{
+	"p":  {[]string{"p"}, htmlBlockScope},
+	"li": {[]string{"li"}, []string{"ul", "ol", "table"}},
+	"dt": {[]string{"dt", "dd"}, []string{"dl", "table"}},
+	"dd": {[]string{"dt", "dd"}, []string{"dl", "table"}},
+	"tr": {[]string{"tr"}, []string{"table", "thead", "tbody", "tfoot"}},
+	"td": {[]string{"td", "th"}, []string{"tr", "table"}},
+	"th": {[]string{"td", "th"}, []string{"tr", "table"}},
+}
+
+// htmlBlockScope are the elements a paragraph can not be closed through.
+var htmlBlockScope = []string{
+	"article", "aside", "blockquote", "body", "dd", "div", "dt", "figure", "footer",
+	"header", "li", "main", "nav", "section", "table", "td", "th",
+}
+
+var (
+	// the content of these elements is not parsed as markup
+	htmlRawElements = []*regexp.Regexp{
+		regexp.MustCompile(`(?is)<script\b.*?</script\s*>`),
+		regexp.MustCompile(`(?is)<style\b.*?</style\s*>`),
+		regexp.MustCompile(`(?is)<noscript\b.*?</noscript\s*>`),
+		regexp.MustCompile(`(?is)<template\b.*?</template\s*>`),
+		regexp.MustCompile(`(?is)<svg\b.*?</svg\s*>`),
+	}
+	htmlComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+	// a < that does not start a tag is text
+	htmlStrayLessThan = regexp.MustCompile(`<([^A-Za-z/!?]|$)`)
+)
+
+// parseHTML parses an HTML document with the XML decoder in its lenient
+// mode, with the HTML entities. The elements are closed as browsers do
+// in the common cases: void elements, implied ends of paragraphs, list
+// items and cells, and end tags closing the elements left open inside.
+// Whatever could be parsed is returned if the document is not valid.
+func parseHTML(document string) *htmlNode {
+	document = strings.ToValidUTF8(document, "�")
+	document = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\r' && r != '\t' {
+			return -1
+		}
+		return r
+	}, document)
+	for _, raw := range htmlRawElements {
+		document = raw.ReplaceAllString(document, "")
+	}
+	document = htmlComment.ReplaceAllString(document, "")
+	document = htmlStrayLessThan.ReplaceAllString(document, "&lt;$1")
+	document = quoteAttributes(document)
+
+	decoder := newHTMLDecoder(document)
+	root := &htmlNode{tag: "#document"}
+	current := root
+	offset := 0
+	for {
+		start := decoder.InputOffset()
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// the decoder stops at its first error, so the parsing goes on
+			// with a new decoder after the end of the bad token, or the
+			// rest of the document is kept as text
+			rest := document[offset+int(start):]
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				current.children = append(current.children, &htmlNode{text: html.UnescapeString(rest), parent: current})
+				break
+			}
+			offset += int(start) + end + 1
+			decoder = newHTMLDecoder(document[offset:])
+			continue
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			tag := strings.ToLower(t.Name.Local)
+			if implied, ok := htmlImpliedEnds[tag]; ok {
+				current = closeImplied(current, implied.closes, implied.scope)
+			}
+
+			node := &htmlNode{tag: tag, attrs: map[string]string{}, parent: current}
+			for _, attr := range t.Attr {
+				node.attrs[strings.ToLower(attr.Name.Local)] = attr.Value
+			}
+			current.children = append(current.children, node)
+			if !htmlVoidElements[tag] {
+				current = node
+			}
+		case xml.EndElement:
+			tag := strings.ToLower(t.Name.Local)
+			for open := current; open != root; open = open.parent {
+				if open.tag == tag {
+					current = open.parent
+					break
+				}
+			}
+		case xml.CharData:
+			current.children = append(current.children, &htmlNode{text: string(t), parent: current})
+		}
+	}
+
+	return root
+}
+
+// closeImplied returns the element to add a new element to, after closing
+// the nearest open element with one of the tags in closes, unless there
+// is an element with a tag in scope before it.
+func closeImplied(current *htmlNode, closes, scope []string) *htmlNode {
+	for open := current; open.parent != nil; open = open.parent {
+		if containsString(closes, open.tag) {
+			return open.parent
+		}
+		if containsString(scope, open.tag) {
+			break
+		}
+	}
+
+	return current
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newHTMLDecoder creates a lenient XML decoder that knows the HTML
+// entities and ignores the declared charsets.
+func newHTMLDecoder(document string) *xml.Decoder {
+	decoder := xml.NewDecoder(strings.NewReader(document))
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	return decoder
+}
+
+// quoteAttributes quotes the values of the attributes of the start tags,
+// as the XML decoder only accepts names as unquoted values. An unquoted
+// value starts at the = after the attribute name and ends at a space or
+// at the end of the tag.
+func quoteAttributes(document string) string {
+	var builder strings.Builder
+	builder.Grow(len(document))
+
+	inTag, quote, unquoted := false, byte(0), false
+	for i := 0; i < len(document); i++ {
+		c := document[i]
+		switch {
+		case unquoted && (c == '>' || c == ' ' || c == '\t' || c == '\n' || c == '\r'):
+			builder.WriteByte('"')
+			unquoted = false
+			inTag = c != '>'
+		case unquoted && c == '"':
+			builder.WriteString("&quot;")
+			continue
+		case unquoted && c == '<':
+			builder.WriteString("&lt;")
+			continue
+		case unquoted:
+			// an = inside an unquoted value, like in a query string, is
+			// part of the value
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case inTag && (c == '"' || c == '\''):
+			quote = c
+		case inTag && c == '>':
+			inTag = false
+		case inTag && c == '=':
+			builder.WriteByte(c)
+			for i+1 < len(document) && (document[i+1] == ' ' || document[i+1] == '\t') {
+				i++
+			}
+			if i+1 < len(document) && document[i+1] != '"' && document[i+1] != '\'' && document[i+1] != '>' {
+				builder.WriteByte('"')
+				unquoted = true
+			}
+			continue
+		case !inTag && c == '<' && i+1 < len(document) && isAsciiLetter(document[i+1]):
+			inTag = true
+		}
+		builder.WriteByte(c)
+	}
+	if unquoted {
+		builder.WriteByte('"')
+	}
+
+	return builder.String()
+}
+
+func isAsciiLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func (n *htmlNode) attr(name string) string {
+	return n.attrs[name]
+}
+
+// find returns the first element, in document order, for which match
+// returns true.
+func (n *htmlNode) find(match func(*htmlNode) bool) *htmlNode {
+	for _, child := range n.children {
+		if child.tag == "" {
+			continue
+		}
+		if match(child) {
+			return child
+		}
+		if found := child.find(match); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// findAll returns all the elements, in document order, for which match
+// returns true.
+func (n *htmlNode) findAll(match func(*htmlNode) bool) []*htmlNode {
+	found := []*htmlNode{}
+	for _, child := range n.children {
+		if child.tag == "" {
+			continue
+		}
+		if match(child) {
+			found = append(found, child)
+		}
+		found = append(found, child.findAll(match)...)
+	}
+
+	return found
+}
+
+func byTag(tags ...string) func(*htmlNode) bool {
+	return func(n *htmlNode) bool {
+		for _, tag := range tags {
+			if n.tag == tag {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// textContent returns all the text inside the node, with the spaces
+// collapsed.
+func (n *htmlNode) textContent() string {
+	var builder strings.Builder
+	n.writeText(&builder)
+
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+func (n *htmlNode) writeText(builder *strings.Builder) {
+	if n.tag == "" {
+		builder.WriteString(n.text)
+		return
+	}
+	for _, child := range n.children {
+		child.writeText(builder)
+	}
+	if n.tag != "#document" && !htmlInlineElements[n.tag] {
+		builder.WriteString(" ")
+	}
+}
+
+// htmlInlineElements are the elements that do not break the text.
+var htmlInlineElements = map[string]bool{
+	"a": true, "abbr": true, "b": true, "bdi": true, "bdo": true, "cite": true, "code": true,
+	"data": true, "del": true, "dfn": true, "em": true, "i": true, "ins": true, "kbd": true,
+	"mark": true, "q": true, "s": true, "samp": true, "small": true, "span": true, "strike": true,
+	"strong": true, "sub": true, "sup": true, "time": true, "tt": true, "u": true, "var": true,
+}
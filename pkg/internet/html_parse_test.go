@@ -0,0 +1,166 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHTML(t *testing.T) {
+	testCases := []struct {
+		name string
+		html string
+		tags []string
+		text string
+	}{
+		{
+			name: "implied ends",
+			html: "<ul><li>one<li>two</ul><p>a<p>b",
+			tags: []string{"ul", "li", "li", "p", "p"},
+			text: "one two a b",
+		},
+		{
+			name: "void elements",
+			html: "<p>a<br>b<img src=x.png>c</p>",
+			tags: []string{"p", "br", "img"},
+			text: "a b c",
+		},
+		{
+			name: "unclosed and stray end tags",
+			html: "<div><p><b>bold</div></span><p>after",
+			tags: []string{"div", "p", "b", "p"},
+			text: "bold after",
+		},
+		{
+			name: "entities and less than signs",
+			html: "<p>a &lt; b &amp;&amp; c < d &mdash; &copy;</p>",
+			tags: []string{"p"},
+			text: "a < b && c < d — ©",
+		},
+		{
+			name: "scripts, styles and comments",
+			html: "<script>if (a<b) {}</script><style>p{}</style><!-- <p>hidden</p> --><p>shown</p>",
+			tags: []string{"p"},
+			text: "shown",
+		},
+		{
+			name: "attributes without quotes nor values",
+			html: "<input disabled><a href=/x class=link>x</a>",
+			tags: []string{"input", "a"},
+			text: "x",
+		},
+		{
+			name: "unquoted query strings",
+			html: "<article><p>One <a href=/x?a=1&b=2>link</a> here.</p><p>Two</p></article>",
+			tags: []string{"article", "p", "a", "p"},
+			text: "One link here. Two",
+		},
+		{
+			name: "bad tags are skipped",
+			html: "<p>One <a \"b\">link</a></p><p>Two</p>",
+			tags: []string{"p", "p"},
+			text: "One link Two",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			root := parseHTML(tc.html)
+
+			// assert
+			tags := []string{}
+			for _, n := range root.findAll(func(*htmlNode) bool { return true }) {
+				tags = append(tags, n.tag)
+			}
+			assert.Equal(t, tc.tags, tags)
+			assert.Equal(t, tc.text, root.textContent())
+		})
+	}
+}
+
+func TestParseHTMLAttributes(t *testing.T) {
+	// act
+	root := parseHTML(`<A HREF="/x?a=1&amp;b=2" Class=link title='a > b' data-id=a"b>x</A>`)
+
+	// assert
+	link := root.find(byTag("a"))
+	assert.NotNil(t, link)
+	assert.Equal(t, "/x?a=1&b=2", link.attr("href"))
+	assert.Equal(t, "link", link.attr("class"))
+	assert.Equal(t, "a > b", link.attr("title"))
+	assert.Equal(t, `a"b`, link.attr("data-id"))
+	assert.Equal(t, "x", link.textContent())
+}
+
+func TestParseHTMLUnquotedQueryString(t *testing.T) {
+	// act
+	root := parseHTML(`<a href=/x?a=1&b=2&c==3 title=a<b>x</a>`)
+
+	// assert
+	link := root.find(byTag("a"))
+	assert.NotNil(t, link)
+	assert.Equal(t, "/x?a=1&b=2&c==3", link.attr("href"))
+	assert.Equal(t, "a<b", link.attr("title"))
+	assert.Equal(t, "x", link.textContent())
+}
+
+// htmlOutline writes the elements of a node as tag(children), and the
+// texts trimmed.
+func htmlOutline(n *htmlNode) string {
+	parts := []string{}
+	for _, child := range n.children {
+		if child.tag == "" {
+			if text := strings.TrimSpace(child.text); text != "" {
+				parts = append(parts, text)
+			}
+			continue
+		}
+		parts = append(parts, child.tag+"("+htmlOutline(child)+")")
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func TestParseHTMLImpliedEnds(t *testing.T) {
+	testCases := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"unclosed cells and rows", "<table><tr><td>a<td>b<tr><td>c<th>d</table>", "table(tr(td(a) td(b)) tr(td(c) th(d)))"},
+		{"unclosed cells with inline elements", "<table><tr><td><b>a<tr><td>b</table>", "table(tr(td(b(a))) tr(td(b)))"},
+		{"nested table", "<table><tr><td><table><tr><td>a</table><td>b</table>", "table(tr(td(table(tr(td(a)))) td(b)))"},
+		{"nested list", "<ul><li>a<ul><li>b<li>c</ul><li>d</ul>", "ul(li(a ul(li(b) li(c))) li(d))"},
+		{"paragraphs in inline elements", "<p><b>a<p>b", "p(b(a)) p(b)"},
+		{"paragraphs in blocks", "<p>a<div><p>b</div>", "p(a div(p(b)))"},
+		{"definitions", "<dl><dt>a<dd>b<dt>c</dl>", "dl(dt(a) dd(b) dt(c))"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			root := parseHTML(tc.html)
+
+			// assert
+			assert.Equal(t, tc.want, htmlOutline(root))
+		})
+	}
+}
@@ -0,0 +1,242 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// The extraction of the main content of a page follows the heuristics
+// of Arc90's Readability: the elements that are unlikely to be content
+// are removed, the parents of the paragraphs are scored by the amount of
+// text they have and the best one, together with its related siblings,
+// is the content.
+
+var (
+	unlikelyCandidates = regexp.MustCompile(`(?i)banner|breadcrumb|combx|comment|community|cookie|disqus|extra|footer|gdpr|header|legends|menu|newsletter|pager|pagination|popup|related|remark|replies|rss|share|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|supplemental`)
+	maybeCandidate     = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positiveHints      = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|post|text|blog|story`)
+	negativeHints      = regexp.MustCompile(`(?i)-ad-|banner|combx|comment|com-|contact|foot|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+)
+
+// htmlBoilerplateElements are never part of the content.
+var htmlBoilerplateElements = map[string]bool{
+	"aside": true, "button": true, "canvas": true, "dialog": true, "embed": true, "footer": true,
+	"form": true, "header": true, "iframe": true, "input": true, "menu": true, "nav": true,
+	"object": true, "select": true, "textarea": true,
+}
+
+// extractArticle returns the element with the main content of a page,
+// which may be a new element grouping the best candidate and its
+// siblings. The page is modified.
+func extractArticle(root *htmlNode) *htmlNode {
+	body := root.find(byTag("body"))
+	if body == nil {
+		body = root
+	}
+	pruneUnlikely(body)
+
+	scores := map[*htmlNode]float64{}
+	candidates := []*htmlNode{}
+	addScore := func(n *htmlNode, score float64) {
+		if n == nil || n.tag == "#document" {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			scores[n] = initialScore(n)
+			candidates = append(candidates, n)
+		}
+		scores[n] += score
+	}
+
+	for _, paragraph := range body.findAll(byTag("p", "pre", "td", "blockquote")) {
+		text := paragraph.textContent()
+		if len(text) < 25 {
+			continue
+		}
+
+		// a point for the paragraph, one for each comma and one for each
+		// 100 characters, up to 3
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text)/100), 3)
+		addScore(paragraph.parent, score)
+		if paragraph.parent != nil {
+			addScore(paragraph.parent.parent, score/2)
+		}
+	}
+
+	var top *htmlNode
+	best := 0.0
+	for _, candidate := range candidates {
+		score := scores[candidate] * (1 - linkDensity(candidate))
+		scores[candidate] = score
+		if top == nil || score > best {
+			top, best = candidate, score
+		}
+	}
+	if top == nil || top.parent == nil {
+		cleanConditionally(body)
+		return body
+	}
+
+	content := &htmlNode{tag: "div", attrs: map[string]string{}}
+	threshold := math.Max(10, best*0.2)
+	for _, sibling := range top.parent.children {
+		if sibling == top || isRelatedSibling(sibling, top, scores, threshold) {
+			content.children = append(content.children, sibling)
+		}
+	}
+	cleanConditionally(content)
+
+	return content
+}
+
+// pruneUnlikely removes the boilerplate, hidden elements and the
+// elements whose class or ID hint that they are not content.
+func pruneUnlikely(n *htmlNode) {
+	children := []*htmlNode{}
+	for _, child := range n.children {
+		if child.tag != "" && isUnlikely(child) {
+			continue
+		}
+		pruneUnlikely(child)
+		children = append(children, child)
+	}
+	n.children = children
+}
+
+func isUnlikely(n *htmlNode) bool {
+	if htmlBoilerplateElements[n.tag] || isHidden(n) {
+		return true
+	}
+
+	switch n.tag {
+	case "body", "article", "main", "a", "table", "tbody", "tr", "td", "th", "pre", "code", "img", "figure":
+		return false
+	}
+
+	hints := n.attr("class") + " " + n.attr("id")
+	return unlikelyCandidates.MatchString(hints) && !maybeCandidate.MatchString(hints)
+}
+
+func isHidden(n *htmlNode) bool {
+	_, hidden := n.attrs["hidden"]
+	style := strings.ReplaceAll(strings.ToLower(n.attr("style")), " ", "")
+
+	return hidden || n.attr("aria-hidden") == "true" ||
+		strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden")
+}
+
+// initialScore is the score of a candidate before counting its
+// paragraphs, by the kind of element and its class and ID.
+func initialScore(n *htmlNode) float64 {
+	score := classWeight(n)
+	switch n.tag {
+	case "article", "div", "main", "section":
+		score += 5
+	case "blockquote", "pre", "td":
+		score += 3
+	case "address", "dd", "dl", "dt", "form", "li", "ol", "ul":
+		score -= 3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score -= 5
+	}
+
+	return score
+}
+
+// classWeight adds 25 points when the class or the ID hint the element
+// is content and subtracts 25 when they hint it is not.
+func classWeight(n *htmlNode) float64 {
+	weight := 0.0
+	for _, hint := range []string{n.attr("class"), n.attr("id")} {
+		if hint == "" {
+			continue
+		}
+		if negativeHints.MatchString(hint) {
+			weight -= 25
+		}
+		if positiveHints.MatchString(hint) {
+			weight += 25
+		}
+	}
+
+	return weight
+}
+
+// linkDensity returns the fraction of the text of the element that is in
+// links.
+func linkDensity(n *htmlNode) float64 {
+	length := len(n.textContent())
+	if length == 0 {
+		return 0
+	}
+
+	links := 0
+	for _, link := range n.findAll(byTag("a")) {
+		links += len(link.textContent())
+	}
+
+	return float64(links) / float64(length)
+}
+
+// isRelatedSibling tells if a sibling of the best candidate is also
+// content, when it has a good score or is a paragraph with few links.
+func isRelatedSibling(sibling, top *htmlNode, scores map[*htmlNode]float64, threshold float64) bool {
+	if sibling.tag == "" {
+		return false
+	}
+
+	bonus := 0.0
+	if class := sibling.attr("class"); class != "" && class == top.attr("class") {
+		bonus = threshold * 0.2
+	}
+	if score, ok := scores[sibling]; ok && score+bonus >= threshold {
+		return true
+	}
+
+	if sibling.tag != "p" {
+		return false
+	}
+	text := sibling.textContent()
+	density := linkDensity(sibling)
+
+	return (len(text) > 80 && density < 0.25) ||
+		(len(text) > 0 && density == 0 && strings.Contains(text, ". "))
+}
+
+// cleanConditionally removes the containers of the content that look
+// like boilerplate: the ones hinting they are not content and the ones
+// mostly made of links.
+func cleanConditionally(n *htmlNode) {
+	children := []*htmlNode{}
+	for _, child := range n.children {
+		switch child.tag {
+		case "div", "section", "table", "ul", "ol":
+			if classWeight(child) < 0 {
+				continue
+			}
+			if (child.tag == "div" || child.tag == "section") && linkDensity(child) > 0.5 {
+				continue
+			}
+		}
+		cleanConditionally(child)
+		children = append(children, child)
+	}
+	n.children = children
+}
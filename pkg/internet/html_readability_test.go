@@ -0,0 +1,69 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractArticle(t *testing.T) {
+	// arrange
+	root := parseHTML(`<body>
+		<nav><a href="/">Home</a></nav>
+		<div class="sidebar"><p>Subscribe to the newsletter, it is free, weekly, and short.</p></div>
+		<div class="links"><p><a href="/a">A first link to another page</a>, <a href="/b">another link</a>.</p></div>
+		<div class="post-body">
+			<p>The first paragraph of the article, long enough, with commas, to be content.</p>
+			<p>The second paragraph of the article, also long enough to be content.</p>
+		</div>
+		<p>A paragraph next to the article. It has no links, so it is kept.</p>
+		<footer><p>Copyright, all rights reserved, no matter how long this text is.</p></footer>
+	</body>`)
+
+	// act
+	article := extractArticle(root)
+
+	// assert
+	assert.Equal(t, "The first paragraph of the article, long enough, with commas, to be content. "+
+		"The second paragraph of the article, also long enough to be content. "+
+		"A paragraph next to the article. It has no links, so it is kept.",
+		article.textContent())
+}
+
+func TestExtractArticleWithoutParagraphs(t *testing.T) {
+	// arrange
+	root := parseHTML(`<body><header>Site</header><div>Just text</div></body>`)
+
+	// act
+	article := extractArticle(root)
+
+	// assert
+	assert.Equal(t, "Just text", article.textContent())
+}
+
+func TestLinkDensity(t *testing.T) {
+	// arrange
+	root := parseHTML(`<div><a href="/">12345</a>67890</div>`)
+
+	// act
+	density := linkDensity(root.find(byTag("div")))
+
+	// assert
+	assert.Equal(t, 0.5, density)
+}
@@ -0,0 +1,572 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+// maxHtmlSize is the maximum size of a page read to be converted.
+const maxHtmlSize = 10 << 20
+
+// ConvertHtmlToMd converts the main content of a web page to GitHub
+// flavoured Markdown. The input is either the URL of the page or its
+// HTML.
+func (s *Service) ConvertHtmlToMd(input string) (internet.ConvertHtmlToMdOutput, error) {
+	return s.ConvertHtmlToMdContext(context.Background(), input)
+}
+
+func (s *Service) ConvertHtmlToMdContext(ctx context.Context, input string) (internet.ConvertHtmlToMdOutput, error) {
+	output := internet.ConvertHtmlToMdOutput{}
+
+	page := strings.TrimSpace(input)
+	var pageUrl *url.URL
+	if !strings.HasPrefix(page, "<") {
+		u, err := url.Parse(page)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return output, &internet.InvalidHtmlInputError{Input: input, Reason: "neither an http(s) URL nor HTML"}
+		}
+
+		page, pageUrl, err = s.getPage(ctx, u)
+		if err != nil {
+			return output, err
+		}
+	}
+
+	root := parseHTML(page)
+	metadata := htmlMetadata(root, pageUrl)
+	article := extractArticle(root)
+
+	converter := htmlConverter{base: metadata.base, title: metadata.title}
+	d := document{
+		title:  metadata.title,
+		author: metadata.author,
+		blocks: converter.convert(article),
+	}
+
+	output.Title = metadata.title
+	output.Author = metadata.author
+	output.Url = metadata.url
+	output.Markdown = markdownRenderer{embedFallback: s.render.embedFallback}.render(d)
+
+	return output, nil
+}
+
+// getPage gets a page, returning its content and its URL after following
+// redirects.
+func (s *Service) getPage(ctx context.Context, u *url.URL) (string, *url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("error creating request: %s", err.Error())
+	}
+	req.Header.Set("User-Agent", s.userAgentHeader())
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := s.httpClient().Do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("error getting page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", nil, fmt.Errorf("error getting page: %s", resp.Status)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType != "" && !strings.Contains(contentType, "html") {
+		return "", nil, &internet.InvalidHtmlInputError{Input: u.String(), Reason: fmt.Sprintf("the content type is %s", contentType)}
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxHtmlSize))
+	if err != nil {
+		return "", nil, fmt.Errorf("error reading response: %s", err.Error())
+	}
+
+	return string(body), resp.Request.URL, nil
+}
+
+// pageMetadata has the metadata of a page.
+type pageMetadata struct {
+	title  string
+	author string
+	// url is the canonical URL of the page
+	url string
+	// base is the URL the links are relative to
+	base *url.URL
+}
+
+// htmlMetadata gets the metadata of a page from its Open Graph and
+// standard meta tags, falling back to its title and headings.
+func htmlMetadata(root *htmlNode, pageUrl *url.URL) pageMetadata {
+	metadata := pageMetadata{base: pageUrl}
+
+	meta := map[string]string{}
+	for _, n := range root.findAll(byTag("meta")) {
+		key := strings.ToLower(firstNonEmpty(n.attr("property"), n.attr("name")))
+		if _, ok := meta[key]; !ok && key != "" {
+			meta[key] = strings.TrimSpace(n.attr("content"))
+		}
+	}
+
+	if base := root.find(byTag("base")); base != nil {
+		metadata.base = resolveUrl(metadata.base, base.attr("href"))
+	}
+
+	metadata.title = firstNonEmpty(meta["og:title"], meta["twitter:title"])
+	if metadata.title == "" {
+		if h1 := root.findAll(byTag("h1")); len(h1) == 1 {
+			metadata.title = h1[0].textContent()
+		} else if title := root.find(byTag("title")); title != nil {
+			metadata.title = title.textContent()
+		}
+	}
+
+	metadata.author = meta["author"]
+	if author := meta["article:author"]; metadata.author == "" && !strings.Contains(author, "://") {
+		metadata.author = author
+	}
+	if metadata.author == "" {
+		byline := root.find(func(n *htmlNode) bool {
+			return strings.Contains(n.attr("rel"), "author") || n.attr("itemprop") == "author"
+		})
+		if byline != nil {
+			metadata.author = byline.textContent()
+		}
+	}
+
+	canonical := root.find(func(n *htmlNode) bool {
+		return n.tag == "link" && strings.EqualFold(n.attr("rel"), "canonical")
+	})
+	if canonical != nil {
+		if u := resolveUrl(metadata.base, canonical.attr("href")); u != nil {
+			metadata.url = u.String()
+		}
+	}
+	if metadata.url == "" {
+		if u := resolveUrl(metadata.base, meta["og:url"]); u != nil {
+			metadata.url = u.String()
+		}
+	}
+	if metadata.url == "" && pageUrl != nil {
+		metadata.url = pageUrl.String()
+	}
+	if metadata.base == nil && metadata.url != "" {
+		metadata.base, _ = url.Parse(metadata.url)
+	}
+
+	return metadata
+}
+
+// resolveUrl resolves a reference against base, if not nil. It returns
+// nil if the reference is empty or invalid.
+func resolveUrl(base *url.URL, reference string) *url.URL {
+	reference = strings.TrimSpace(reference)
+	if reference == "" {
+		return nil
+	}
+
+	u, err := url.Parse(reference)
+	if err != nil {
+		return nil
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+
+	return u
+}
+
+var htmlHeadings = map[string]int{"h1": 2, "h2": 2, "h3": 3, "h4": 4, "h5": 5, "h6": 6}
+
+// htmlCodeLanguage matches the classes with the language of code: the
+// ones of Prism and highlight.js, language-go and lang-go, and the ones of
+// Rouge, highlight go.
+var htmlCodeLanguage = regexp.MustCompile(`(?:^|\s)(?:(?:lang|language)-|highlight\s+)([\w+#.-]+)`)
+
+// htmlConverter converts the content of a page to the blocks of a
+// document. Text and inline elements are gathered into paragraphs, which
+// end at the start of any block element.
+type htmlConverter struct {
+	base  *url.URL
+	title string
+
+	blocks    []block
+	paragraph []inline
+	// images are the images found in the current paragraph, which are
+	// added after it
+	images []block
+	// space is true when the text gathered ends with a space, so the next
+	// spaces are collapsed
+	space bool
+	// href is the link of the images in the current link
+	href string
+}
+
+func (c *htmlConverter) convert(n *htmlNode) []block {
+	c.space = true
+	c.container(n)
+	c.flush()
+
+	return c.blocks
+}
+
+// flush ends the current paragraph.
+func (c *htmlConverter) flush() {
+	if inlines := trimInlines(c.paragraph); len(inlines) > 0 {
+		c.blocks = append(c.blocks, block{kind: blockParagraph, inlines: inlines})
+	}
+	c.blocks = append(c.blocks, c.images...)
+	c.paragraph = nil
+	c.images = nil
+	c.space = true
+}
+
+// container converts the children of an element.
+func (c *htmlConverter) container(n *htmlNode) {
+	for _, child := range n.children {
+		if child.tag == "" || htmlInlineElements[child.tag] || child.tag == "br" || child.tag == "img" {
+			c.paragraph = append(c.paragraph, c.inline(child)...)
+			continue
+		}
+
+		c.flush()
+		c.block(child)
+	}
+}
+
+func (c *htmlConverter) block(n *htmlNode) {
+	if level, ok := htmlHeadings[n.tag]; ok {
+		inlines := c.inlines(n)
+		if n.tag == "h1" && strings.EqualFold(plainText(inlines), c.title) {
+			c.images = nil
+			return
+		}
+		if len(inlines) > 0 {
+			c.blocks = append(c.blocks, block{kind: blockHeading, level: level, inlines: inlines})
+		}
+		c.flush()
+		return
+	}
+
+	switch n.tag {
+	case "p", "dt", "dd", "figcaption", "address", "summary":
+		c.container(n)
+	case "pre":
+		code := n
+		if found := n.find(byTag("code")); found != nil {
+			code = found
+		}
+		lang := htmlCodeLanguage.FindStringSubmatch(code.attr("class") + " " + n.attr("class"))
+		b := block{kind: blockCode, code: strings.Trim(rawText(n), "\n")}
+		if lang != nil {
+			b.lang = lang[1]
+		}
+		c.blocks = append(c.blocks, b)
+	case "ul", "ol":
+		list := block{kind: blockList, ordered: n.tag == "ol"}
+		c.listItems(n, 0, &list)
+		if len(list.items) > 0 {
+			c.blocks = append(c.blocks, list)
+		}
+	case "blockquote":
+		if inlines := c.inlines(n); len(inlines) > 0 {
+			c.blocks = append(c.blocks, block{kind: blockQuote, inlines: inlines})
+		}
+	case "hr":
+		c.blocks = append(c.blocks, block{kind: blockRule})
+	case "table":
+		c.table(n)
+	case "figure":
+		c.figure(n)
+	case "head", "title", "meta", "link", "base":
+	default:
+		c.container(n)
+	}
+	c.flush()
+}
+
+// listItems adds the items of a list at a depth to a list block, the
+// items of its nested lists following the item they are in, one level
+// deeper.
+func (c *htmlConverter) listItems(n *htmlNode, depth int, list *block) {
+	for _, li := range listElements(n) {
+		item := &htmlNode{tag: "li"}
+		nested := []*htmlNode{}
+		for _, child := range li.children {
+			if child.tag == "ul" || child.tag == "ol" {
+				nested = append(nested, child)
+				continue
+			}
+			item.children = append(item.children, child)
+		}
+
+		if inlines := c.inlines(item); len(inlines) > 0 {
+			list.items = append(list.items, inlines)
+			list.depths = append(list.depths, depth)
+			list.itemsOrdered = append(list.itemsOrdered, n.tag == "ol")
+		}
+		for _, inner := range nested {
+			c.listItems(inner, depth+1, list)
+		}
+	}
+}
+
+// listElements returns the items of a list, which may be inside other
+// elements, but not the ones of the lists inside it.
+func listElements(n *htmlNode) []*htmlNode {
+	items := []*htmlNode{}
+	for _, child := range n.children {
+		switch child.tag {
+		case "li":
+			items = append(items, child)
+		case "", "ul", "ol":
+		default:
+			items = append(items, listElements(child)...)
+		}
+	}
+
+	return items
+}
+
+// table converts a table, its first row being the header. Tables with a
+// single column, or with tables inside, are used for the layout and their
+// cells are converted as containers.
+func (c *htmlConverter) table(n *htmlNode) {
+	rows := [][][]inline{}
+	layout := n.find(byTag("table")) != nil
+	for _, tr := range n.findAll(byTag("tr")) {
+		row := [][]inline{}
+		for _, cell := range tr.children {
+			if cell.tag == "td" || cell.tag == "th" {
+				row = append(row, c.inlines(cell))
+			}
+		}
+		if len(row) < 2 {
+			layout = true
+		}
+		rows = append(rows, row)
+	}
+
+	if layout || len(rows) == 0 {
+		c.images = nil
+		for _, cell := range n.findAll(byTag("td", "th")) {
+			c.container(cell)
+			c.flush()
+		}
+		return
+	}
+
+	c.blocks = append(c.blocks, block{kind: blockTable, rows: rows})
+}
+
+// figure converts the images of a figure, their caption being the
+// alternative text of the ones without it.
+func (c *htmlConverter) figure(n *htmlNode) {
+	caption := ""
+	if figcaption := n.find(byTag("figcaption")); figcaption != nil {
+		caption = figcaption.textContent()
+	}
+
+	for _, child := range n.children {
+		if child.tag == "figcaption" {
+			continue
+		}
+		if child.tag == "" || htmlInlineElements[child.tag] || child.tag == "img" {
+			c.paragraph = append(c.paragraph, c.inline(child)...)
+			continue
+		}
+		c.flush()
+		c.block(child)
+	}
+
+	images := len(c.images)
+	for i := range c.images {
+		if c.images[i].alt == "" {
+			c.images[i].alt = caption
+			caption = ""
+		}
+	}
+	c.flush()
+
+	if caption != "" && images > 0 {
+		c.blocks = append(c.blocks, block{kind: blockParagraph, inlines: []inline{{kind: inlineEmphasis, children: []inline{textInline(caption)}}}})
+	}
+}
+
+// inlines returns the inlines of an element converted as a paragraph,
+// its block elements ending lines.
+func (c *htmlConverter) inlines(n *htmlNode) []inline {
+	c.space = true
+	inlines := c.inlineChildren(n)
+	c.space = true
+
+	return trimInlines(inlines)
+}
+
+func (c *htmlConverter) inlineChildren(n *htmlNode) []inline {
+	inlines := []inline{}
+	for _, child := range n.children {
+		if child.tag != "" && !htmlInlineElements[child.tag] && child.tag != "br" && child.tag != "img" {
+			inlines = append(trimInlinesRight(inlines, " "), c.inlineChildren(child)...)
+			inlines = append(trimInlinesRight(inlines, " "), textInline("\n"))
+			c.space = true
+			continue
+		}
+		inlines = append(inlines, c.inline(child)...)
+	}
+
+	return inlines
+}
+
+var htmlSpaces = regexp.MustCompile(`\s+`)
+
+func (c *htmlConverter) inline(n *htmlNode) []inline {
+	switch n.tag {
+	case "":
+		text := htmlSpaces.ReplaceAllString(n.text, " ")
+		if c.space {
+			text = strings.TrimLeft(text, " ")
+		}
+		if text == "" {
+			return nil
+		}
+		c.space = strings.HasSuffix(text, " ")
+		return []inline{textInline(text)}
+	case "br":
+		c.space = true
+		return []inline{textInline("\n")}
+	case "img":
+		c.image(n)
+		return nil
+	case "strong", "b":
+		return c.wrap(inlineStrong, "", n)
+	case "em", "i", "cite", "dfn", "var":
+		return c.wrap(inlineEmphasis, "", n)
+	case "code", "kbd", "samp", "tt":
+		text := strings.Join(strings.Fields(rawText(n)), " ")
+		if text == "" {
+			return nil
+		}
+		c.space = false
+		return []inline{{kind: inlineCode, children: []inline{textInline(text)}}}
+	case "a":
+		href := n.attr("href")
+		u := resolveUrl(c.base, href)
+		if u == nil || strings.HasPrefix(href, "#") || (u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "mailto") {
+			return c.inlineChildren(n)
+		}
+		c.href = u.String()
+		inlines := c.wrap(inlineLink, u.String(), n)
+		c.href = ""
+		return inlines
+	}
+
+	return c.inlineChildren(n)
+}
+
+// wrap returns the children of an element wrapped in an inline of the
+// kind, nothing if they are empty.
+func (c *htmlConverter) wrap(kind inlineKind, href string, n *htmlNode) []inline {
+	children := c.inlineChildren(n)
+	if strings.TrimSpace(plainText(children)) == "" {
+		return children
+	}
+
+	return []inline{{kind: kind, href: href, children: children}}
+}
+
+// image adds an image to the current paragraph. The lazy loaded images
+// have their URL in a data attribute.
+func (c *htmlConverter) image(n *htmlNode) {
+	src := firstNonEmpty(n.attr("data-src"), n.attr("src"))
+	if src == "" || strings.HasPrefix(src, "data:") {
+		return
+	}
+
+	u := resolveUrl(c.base, src)
+	if u == nil {
+		return
+	}
+	c.images = append(c.images, block{kind: blockImage, src: u.String(), alt: strings.TrimSpace(n.attr("alt")), href: c.href})
+}
+
+// rawText returns the text of an element keeping its spaces, as in
+// preformatted text.
+func rawText(n *htmlNode) string {
+	if n.tag == "" {
+		return n.text
+	}
+	if n.tag == "br" {
+		return "\n"
+	}
+
+	var builder strings.Builder
+	for _, child := range n.children {
+		builder.WriteString(rawText(child))
+	}
+
+	return builder.String()
+}
+
+// trimInlines removes the spaces and line breaks at the start and at the
+// end of inlines and the empty texts left.
+func trimInlines(inlines []inline) []inline {
+	return trimInlinesRight(trimInlinesLeft(inlines, " \n"), " \n")
+}
+
+func trimInlinesLeft(inlines []inline, cutset string) []inline {
+	for len(inlines) > 0 {
+		first := &inlines[0]
+		if first.kind == inlineText {
+			first.text = strings.TrimLeft(first.text, cutset)
+		} else {
+			first.children = trimInlinesLeft(first.children, cutset)
+		}
+		if plainText(inlines[:1]) != "" {
+			break
+		}
+		inlines = inlines[1:]
+	}
+
+	return inlines
+}
+
+func trimInlinesRight(inlines []inline, cutset string) []inline {
+	for len(inlines) > 0 {
+		last := &inlines[len(inlines)-1]
+		if last.kind == inlineText {
+			last.text = strings.TrimRight(last.text, cutset)
+		} else {
+			last.children = trimInlinesRight(last.children, cutset)
+		}
+		if plainText(inlines[len(inlines)-1:]) != "" {
+			break
+		}
+		inlines = inlines[:len(inlines)-1]
+	}
+
+	return inlines
+}
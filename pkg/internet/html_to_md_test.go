@@ -0,0 +1,195 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+// newPageServer starts a server answering with the page recorded in
+// testdata/html.
+func newPageServer(t *testing.T, name string) *httptest.Server {
+	page, err := ioutil.ReadFile("testdata/html/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page)
+	}))
+}
+
+func TestConvertHtmlToMd(t *testing.T) {
+	// arrange
+	server := newPageServer(t, "article.html")
+	defer server.Close()
+
+	// act
+	s := NewService()
+	output, err := s.ConvertHtmlToMd(server.URL + "/posts/testing-http-clients/")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "Testing HTTP clients in Go", output.Title)
+	assert.Equal(t, "Renato Torres", output.Author)
+	assert.Equal(t, server.URL+"/posts/testing-http-clients/", output.Url)
+	assert.Equal(t, "# Testing HTTP clients in Go\nBy Renato Torres\n"+
+		"\nPublished on 15 Dec 2021\n"+
+		"\nMost services call other services, and testing that code without hitting the network is easier than it looks. "+
+		"The standard library has everything we need, in the `net/http/httptest` package.\n"+
+		"\n## Starting a test server\n"+
+		"\nAn *httptest.Server* listens on a random port of the loopback interface and answers with the handler we give it. "+
+		"Point the client to its [URL](https://pkg.go.dev/net/http/httptest#Server.URL), and the requests never leave the machine.\n"+
+		"\n```go\nserver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {\n"+
+		"\tw.Write([]byte(`{\"ok\": true}`))\n}))\ndefer server.Close()\n```\n"+
+		"\n![The client talks to the test server]("+server.URL+"/images/httptest.png)\n"+
+		"\n## What to check\n"+
+		"\nThe handler is also a good place to check the requests, for example:\n"+
+		"\n- the method and the path,\n- the headers, like **User-Agent**,\n- the body.\n"+
+		"\nErrors deserve their own cases, and a table keeps them readable:\n"+
+		"\n| Status | Expected error |\n| --- | --- |\n| 404 | not found |\n| 429 | rate limited |\n"+
+		"\n> A little copying is better than a little dependency.\n"+
+		"\nTimeouts are the last thing to test — a handler that sleeps longer than the client waits is enough. "+
+		"See the next section and the [post about contexts]("+server.URL+"/posts/contexts/).\n"+
+		"\n---\n"+
+		"\n1. Start the server.\n2. Configure the client.\n",
+		output.Markdown)
+}
+
+func TestConvertHtmlToMdFromHtml(t *testing.T) {
+	// arrange
+	page := `<html><head><title>Notes</title></head><body>
+		<div id="content"><h1>Notes</h1>
+		<p>Line one<br>line two, with <a href="notes.html"><img src="a.png" alt="A"></a> an image.
+		<p>Cells with pipes, commas, and more text to be scored as content:
+		<table><tr><th>a|b<th>c</table>
+		</div></body></html>`
+
+	// act
+	s := NewService()
+	output, err := s.ConvertHtmlToMd(page)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "Notes", output.Title)
+	assert.Equal(t, "", output.Url)
+	assert.Equal(t, "# Notes\n"+
+		"\nLine one\nline two, with an image.\n"+
+		"\n[![A](a.png)](notes.html)\n"+
+		"\nCells with pipes, commas, and more text to be scored as content:\n"+
+		"\n| a\\|b | c |\n| --- | --- |\n",
+		output.Markdown)
+}
+
+func TestConvertHtmlToMdNestedLists(t *testing.T) {
+	// arrange
+	page := `<html><head><title>Steps</title></head><body><article><h1>Steps</h1>
+		<p>The steps to follow, with the details of each one of them:</p>
+		<ol><li>Install<ul><li>on Linux<li>on macOS<ol><li>with brew</ol></ul>
+		<li>Configure<div><li>the token</div></ol>
+		</article></body></html>`
+
+	// act
+	s := NewService()
+	output, err := s.ConvertHtmlToMd(page)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "# Steps\n"+
+		"\nThe steps to follow, with the details of each one of them:\n"+
+		"\n1. Install\n   - on Linux\n   - on macOS\n     1. with brew\n2. Configure\n3. the token\n",
+		output.Markdown)
+}
+
+func TestConvertHtmlToMdWithInvalidInput(t *testing.T) {
+	testCases := []string{
+		"",
+		"not a page",
+		"ftp://example.com/page.html",
+		"https://",
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc, func(t *testing.T) {
+			// act
+			s := NewService()
+			_, err := s.ConvertHtmlToMd(tc)
+
+			// assert
+			var invalid *internet.InvalidHtmlInputError
+			assert.True(t, errors.As(err, &invalid))
+		})
+	}
+}
+
+func TestConvertHtmlToMdNotHtml(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	// act
+	s := NewService()
+	_, err := s.ConvertHtmlToMd(server.URL)
+
+	// assert
+	var invalid *internet.InvalidHtmlInputError
+	assert.True(t, errors.As(err, &invalid))
+	assert.Contains(t, err.Error(), "application/json")
+}
+
+func TestConvertHtmlToMdNotFound(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	// act
+	s := NewService()
+	_, err := s.ConvertHtmlToMd(server.URL + "/missing")
+
+	// assert
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "404 Not Found")
+}
+
+func TestConvertHtmlToMdSendsUserAgent(t *testing.T) {
+	// arrange
+	userAgent := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(`<p>Hello</p>`))
+	}))
+	defer server.Close()
+
+	// act
+	s := NewService(WithUserAgent("canivete-test/1.0"))
+	_, err := s.ConvertHtmlToMd(server.URL)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "canivete-test/1.0", userAgent)
+}
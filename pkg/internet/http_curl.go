@@ -0,0 +1,386 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+// curlCommand returns the cURL command line sending the request, an
+// option per line after the first one.
+func curlCommand(request internet.HttpRequest) (string, error) {
+	method := strings.ToUpper(request.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	args := []string{}
+	switch {
+	case method == "HEAD":
+		args = append(args, "--head")
+	case method == "GET" && request.Body == "":
+	case method == "POST" && request.Body != "":
+		// cURL posts the data by default
+	default:
+		args = append(args, "--request "+shellQuote(method))
+	}
+	args = append(args, shellQuote(request.Url))
+
+	for _, header := range request.Headers {
+		args = append(args, "--header "+shellQuote(header.Name+": "+header.Value))
+	}
+
+	switch strings.ToLower(request.Auth.Type) {
+	case "":
+	case internet.HttpAuthBasic:
+		args = append(args, "--user "+shellQuote(request.Auth.Username+":"+request.Auth.Password))
+	case internet.HttpAuthBearer:
+		args = append(args, "--oauth2-bearer "+shellQuote(request.Auth.Token))
+	default:
+		return "", fmt.Errorf("unsupported authentication %q, use basic or bearer", request.Auth.Type)
+	}
+
+	if request.Body != "" {
+		args = append(args, "--data-raw "+shellQuote(request.Body))
+	}
+
+	return "curl " + strings.Join(args, " \\\n  "), nil
+}
+
+// shellQuote quotes a word for POSIX shells, between single quotes if it
+// has any character other than letters, digits and a few safe ones.
+func shellQuote(word string) string {
+	if word != "" && strings.Trim(word, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:@%+=,") == "" {
+		return word
+	}
+
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+// curlFlags are the options of cURL without an argument which do not
+// change the request.
+var curlFlags = map[string]bool{
+	"-s": true, "--silent": true, "-S": true, "--show-error": true,
+	"-v": true, "--verbose": true, "-i": true, "--include": true,
+	"-L": true, "--location": true, "-k": true, "--insecure": true,
+	"--compressed": true, "-f": true, "--fail": true, "-#": true,
+	"--http1.1": true, "--http2": true, "--globoff": true, "-g": true,
+	"-N": true, "--no-buffer": true,
+}
+
+// curlIgnoredOptions are the options of cURL with an argument which do
+// not change the request.
+var curlIgnoredOptions = map[string]bool{
+	"-o": true, "--output": true, "-m": true, "--max-time": true,
+	"--connect-timeout": true, "-w": true, "--write-out": true,
+	"--retry": true, "-x": true, "--proxy": true, "--cacert": true,
+	"--max-redirs": true,
+}
+
+// curlRequest parses a cURL command line, as copied from the developer
+// tools of browsers, into a request. Reading files, as in --data @file,
+// is not supported.
+func curlRequest(command string) (internet.HttpRequest, error) {
+	request := internet.HttpRequest{}
+
+	words, err := shellWords(command)
+	if err != nil {
+		return request, err
+	}
+	if len(words) == 0 || words[0] != "curl" {
+		return request, fmt.Errorf("not a curl command")
+	}
+
+	data := []string{}
+	head, get := false, false
+	for i := 1; i < len(words); i++ {
+		word := words[i]
+		if !strings.HasPrefix(word, "-") || word == "-" {
+			request.Url = word
+			continue
+		}
+
+		if !strings.HasPrefix(word, "--") && len(word) > 2 {
+			// short options can be combined, -sSL, or have their argument
+			// attached, -XPOST
+			if curlShortFlags(word) {
+				continue
+			}
+			words = append(words[:i+1], append([]string{word[2:]}, words[i+1:]...)...)
+			word = word[:2]
+		}
+
+		if curlFlags[word] {
+			continue
+		}
+		if word == "-I" || word == "--head" {
+			head = true
+			continue
+		}
+		if word == "-G" || word == "--get" {
+			get = true
+			continue
+		}
+
+		if i+1 >= len(words) {
+			return request, fmt.Errorf("the curl option %s needs an argument", word)
+		}
+		i++
+		value := words[i]
+
+		switch word {
+		case "-X", "--request":
+			request.Method = strings.ToUpper(value)
+		case "--url":
+			request.Url = value
+		case "-H", "--header":
+			name, headerValue := value, ""
+			if colon := strings.IndexByte(value, ':'); colon >= 0 {
+				name, headerValue = value[:colon], strings.TrimSpace(value[colon+1:])
+			}
+			request.Headers = append(request.Headers, internet.HttpHeader{Name: strings.TrimSpace(name), Value: headerValue})
+		case "-d", "--data", "--data-ascii", "--data-binary", "--data-raw":
+			if strings.HasPrefix(value, "@") && word != "--data-raw" {
+				return request, fmt.Errorf("reading the data from a file is not supported")
+			}
+			if word == "-d" || word == "--data" || word == "--data-ascii" {
+				value = strings.NewReplacer("\r", "", "\n", "").Replace(value)
+			}
+			data = append(data, value)
+		case "--data-urlencode":
+			data = append(data, curlUrlEncode(value))
+		case "--json":
+			data = append(data, value)
+			request.Headers = append(request.Headers,
+				internet.HttpHeader{Name: "Content-Type", Value: "application/json"},
+				internet.HttpHeader{Name: "Accept", Value: "application/json"})
+		case "-u", "--user":
+			username, password := value, ""
+			if colon := strings.IndexByte(value, ':'); colon >= 0 {
+				username, password = value[:colon], value[colon+1:]
+			}
+			request.Auth = internet.HttpAuth{Type: internet.HttpAuthBasic, Username: username, Password: password}
+		case "--oauth2-bearer":
+			request.Auth = internet.HttpAuth{Type: internet.HttpAuthBearer, Token: value}
+		case "-A", "--user-agent":
+			request.Headers = append(request.Headers, internet.HttpHeader{Name: "User-Agent", Value: value})
+		case "-e", "--referer":
+			request.Headers = append(request.Headers, internet.HttpHeader{Name: "Referer", Value: value})
+		case "-b", "--cookie":
+			if !strings.Contains(value, "=") {
+				return request, fmt.Errorf("reading the cookies from a file is not supported")
+			}
+			request.Headers = append(request.Headers, internet.HttpHeader{Name: "Cookie", Value: value})
+		default:
+			if !curlIgnoredOptions[word] {
+				return request, fmt.Errorf("unsupported curl option %s", word)
+			}
+		}
+	}
+
+	if request.Url == "" {
+		return request, fmt.Errorf("the curl command has no url")
+	}
+
+	body := strings.Join(data, "&")
+	switch {
+	case head:
+		request.Method = "HEAD"
+	case get && body != "":
+		separator := "?"
+		if strings.Contains(request.Url, "?") {
+			separator = "&"
+		}
+		request.Url += separator + body
+	case len(data) > 0:
+		request.Body = body
+		if request.Method == "" {
+			request.Method = "POST"
+		}
+		if !hasHeader(request.Headers, "Content-Type") {
+			request.Headers = append(request.Headers, internet.HttpHeader{Name: "Content-Type", Value: "application/x-www-form-urlencoded"})
+		}
+	}
+	if request.Method == "" {
+		request.Method = "GET"
+	}
+
+	return request, nil
+}
+
+// curlShortFlags tells if a word is made of short options without
+// arguments, like -sSL.
+func curlShortFlags(word string) bool {
+	for _, c := range word[1:] {
+		if !curlFlags["-"+string(c)] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// curlUrlEncode encodes the data of --data-urlencode: content, =content,
+// name=content or name@file, the last not supported.
+func curlUrlEncode(value string) string {
+	if i := strings.IndexByte(value, '='); i >= 0 {
+		if i == 0 {
+			return url.QueryEscape(value[1:])
+		}
+		return value[:i] + "=" + url.QueryEscape(value[i+1:])
+	}
+
+	return url.QueryEscape(value)
+}
+
+func hasHeader(headers []internet.HttpHeader, name string) bool {
+	for _, header := range headers {
+		if strings.EqualFold(header.Name, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// shellWords splits a command line into words as POSIX shells do, with
+// single and double quotes, backslash escapes, line continuations and
+// the $'...' quotes of bash.
+func shellWords(command string) ([]string, error) {
+	words := []string{}
+	var word strings.Builder
+	inWord := false
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '\\':
+			if i+1 < len(command) && command[i+1] == '\n' {
+				i++
+				continue
+			}
+			if i+2 < len(command) && command[i+1] == '\r' && command[i+2] == '\n' {
+				i += 2
+				continue
+			}
+			inWord = true
+			if i+1 < len(command) {
+				i++
+				word.WriteByte(command[i])
+			}
+		case c == '\'':
+			end := strings.IndexByte(command[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			word.WriteString(command[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '"':
+			end := i + 1
+			for ; end < len(command) && command[end] != '"'; end++ {
+				if command[end] == '\\' && end+1 < len(command) && strings.IndexByte("\"\\$`\n", command[end+1]) >= 0 {
+					end++
+					if command[end] != '\n' {
+						word.WriteByte(command[end])
+					}
+					continue
+				}
+				word.WriteByte(command[end])
+			}
+			if end >= len(command) {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+			i = end
+			inWord = true
+		case c == '$' && i+1 < len(command) && command[i+1] == '\'':
+			text, end, err := ansiCQuote(command, i+2)
+			if err != nil {
+				return nil, err
+			}
+			word.WriteString(text)
+			i = end
+			inWord = true
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
+
+// ansiCQuote decodes the text of a $'...' quote starting at start,
+// returning it with the position of the closing quote.
+func ansiCQuote(command string, start int) (string, int, error) {
+	escapes := map[byte]string{'n': "\n", 't': "\t", 'r': "\r", '\\': "\\", '\'': "'", '"': "\"", '0': "\x00", 'a': "\a", 'b': "\b", 'e': "\x1b", 'f': "\f", 'v': "\v"}
+
+	var text strings.Builder
+	for i := start; i < len(command); i++ {
+		c := command[i]
+		if c == '\'' {
+			return text.String(), i, nil
+		}
+		if c != '\\' || i+1 >= len(command) {
+			text.WriteByte(c)
+			continue
+		}
+
+		i++
+		if escaped, ok := escapes[command[i]]; ok {
+			text.WriteString(escaped)
+			continue
+		}
+		if (command[i] == 'x' || command[i] == 'u') && i+2 < len(command) {
+			digits := 2
+			if command[i] == 'u' {
+				digits = 4
+			}
+			end := i + 1
+			for end < len(command) && end < i+1+digits && isHex(command[end]) {
+				end++
+			}
+			if value, err := strconv.ParseUint(command[i+1:end], 16, 32); err == nil {
+				if command[i] == 'x' {
+					text.WriteByte(byte(value))
+				} else {
+					text.WriteRune(rune(value))
+				}
+				i = end - 1
+				continue
+			}
+		}
+		text.WriteByte('\\')
+		text.WriteByte(command[i])
+	}
+
+	return "", 0, fmt.Errorf("unterminated $' quote")
+}
@@ -0,0 +1,213 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"testing"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurlCommand(t *testing.T) {
+	testCases := []struct {
+		name    string
+		request internet.HttpRequest
+		want    string
+	}{
+		{
+			"get",
+			internet.HttpRequest{Url: "https://go.dev/doc/"},
+			"curl https://go.dev/doc/",
+		},
+		{
+			"post",
+			internet.HttpRequest{
+				Method:  "POST",
+				Url:     "https://api.example.com/users?active=true&sort=name",
+				Headers: []internet.HttpHeader{{Name: "Content-Type", Value: "application/json"}},
+				Body:    `{"name":"O'Brien"}`,
+				Auth:    internet.HttpAuth{Type: "basic", Username: "renato", Password: "s3cret"},
+			},
+			"curl 'https://api.example.com/users?active=true&sort=name' \\\n" +
+				"  --header 'Content-Type: application/json' \\\n" +
+				"  --user renato:s3cret \\\n" +
+				`  --data-raw '{"name":"O'\''Brien"}'`,
+		},
+		{
+			"delete",
+			internet.HttpRequest{Method: "delete", Url: "https://api.example.com/users/1", Auth: internet.HttpAuth{Type: "bearer", Token: "token"}},
+			"curl --request DELETE \\\n  https://api.example.com/users/1 \\\n  --oauth2-bearer token",
+		},
+		{
+			"head",
+			internet.HttpRequest{Method: "HEAD", Url: "https://go.dev"},
+			"curl --head \\\n  https://go.dev",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			command, err := curlCommand(tc.request)
+
+			// assert
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, command)
+		})
+	}
+}
+
+func TestCurlRequest(t *testing.T) {
+	testCases := []struct {
+		name    string
+		command string
+		want    internet.HttpRequest
+	}{
+		{
+			"get",
+			"curl -sSL https://go.dev/doc/",
+			internet.HttpRequest{Method: "GET", Url: "https://go.dev/doc/"},
+		},
+		{
+			"copied from a browser",
+			"curl 'https://api.example.com/users' \\\n" +
+				"  -H 'accept: application/json' \\\n" +
+				"  -H 'content-type: application/json' \\\n" +
+				"  -H $'cookie: a=1; b=\\'2\\'' \\\n" +
+				"  --data-raw $'{\"name\":\"O\\'Brien\\u00e9\"}' \\\n" +
+				"  --compressed",
+			internet.HttpRequest{
+				Method: "POST",
+				Url:    "https://api.example.com/users",
+				Headers: []internet.HttpHeader{
+					{Name: "accept", Value: "application/json"},
+					{Name: "content-type", Value: "application/json"},
+					{Name: "cookie", Value: "a=1; b='2'"},
+				},
+				Body: `{"name":"O'Briené"}`,
+			},
+		},
+		{
+			"form",
+			`curl -XPUT "https://example.com/form" -d "a=1" -d 'b=2' --data-urlencode "c=x y&z" -u "renato:s3cret"`,
+			internet.HttpRequest{
+				Method:  "PUT",
+				Url:     "https://example.com/form",
+				Headers: []internet.HttpHeader{{Name: "Content-Type", Value: "application/x-www-form-urlencoded"}},
+				Body:    "a=1&b=2&c=x+y%26z",
+				Auth:    internet.HttpAuth{Type: "basic", Username: "renato", Password: "s3cret"},
+			},
+		},
+		{
+			"get with data",
+			"curl -G https://example.com/search?lang=go -d q=tips -A canivete -e https://go.dev -b session=abc",
+			internet.HttpRequest{
+				Method: "GET",
+				Url:    "https://example.com/search?lang=go&q=tips",
+				Headers: []internet.HttpHeader{
+					{Name: "User-Agent", Value: "canivete"},
+					{Name: "Referer", Value: "https://go.dev"},
+					{Name: "Cookie", Value: "session=abc"},
+				},
+			},
+		},
+		{
+			"json",
+			`curl --json '{"a":1}' --url https://example.com --oauth2-bearer token -o out.json`,
+			internet.HttpRequest{
+				Method: "POST",
+				Url:    "https://example.com",
+				Headers: []internet.HttpHeader{
+					{Name: "Content-Type", Value: "application/json"},
+					{Name: "Accept", Value: "application/json"},
+				},
+				Body: `{"a":1}`,
+				Auth: internet.HttpAuth{Type: "bearer", Token: "token"},
+			},
+		},
+		{
+			"head",
+			"curl -I https://go.dev",
+			internet.HttpRequest{Method: "HEAD", Url: "https://go.dev"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			request, err := curlRequest(tc.command)
+
+			// assert
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, request)
+		})
+	}
+}
+
+func TestCurlRequestRoundTrip(t *testing.T) {
+	// arrange
+	request := internet.HttpRequest{
+		Method:  "PATCH",
+		Url:     "https://api.example.com/users/1?fields=name,email",
+		Headers: []internet.HttpHeader{{Name: "Content-Type", Value: "text/plain"}, {Name: "X-Note", Value: `it's "quoted" $HOME`}},
+		Body:    "line 1\nline 2\\n",
+		Auth:    internet.HttpAuth{Type: "basic", Username: "renato", Password: "p:ss"},
+	}
+
+	// act
+	command, err := curlCommand(request)
+	parsed, parseErr := curlRequest(command)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, parseErr)
+	assert.Equal(t, request, parsed)
+}
+
+func TestCurlRequestInvalid(t *testing.T) {
+	testCases := []struct {
+		command string
+		want    string
+	}{
+		{"wget https://go.dev", "not a curl command"},
+		{"curl -X", "the curl option -X needs an argument"},
+		{"curl --proxy-user a:b https://go.dev", "unsupported curl option --proxy-user"},
+		{"curl -d @body.json https://go.dev", "reading the data from a file is not supported"},
+		{"curl -H 'Accept: */*", "unterminated single quote"},
+		{"curl -s", "the curl command has no url"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.command, func(t *testing.T) {
+			// act
+			_, err := curlRequest(tc.command)
+
+			// assert
+			assert.EqualError(t, err, tc.want)
+		})
+	}
+}
+
+func TestShellWords(t *testing.T) {
+	// act
+	words, err := shellWords(`a\ b 'c d'"e \"f\" \$g" $'h\ti\x21' \` + "\n" + `j`)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a b", `c de "f" $g`, "h\ti!", "j"}, words)
+}
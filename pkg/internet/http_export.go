@@ -0,0 +1,126 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+// ExportHttpRequest writes a request as a cURL command line or as HAR.
+func (s *Service) ExportHttpRequest(request internet.HttpRequest, format string) (internet.ExportHttpRequestOutput, error) {
+	return s.ExportHttpRequestContext(context.Background(), request, format)
+}
+
+func (s *Service) ExportHttpRequestContext(ctx context.Context, request internet.HttpRequest, format string) (internet.ExportHttpRequestOutput, error) {
+	output := internet.ExportHttpRequestOutput{}
+
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
+	format = strings.ToLower(format)
+	var content string
+	var err error
+	switch format {
+	case internet.HttpFormatCurl:
+		content, err = curlCommand(request)
+	case internet.HttpFormatHar:
+		content, err = harDocument(request, time.Now())
+	default:
+		return output, unsupportedHttpFormat(format)
+	}
+	if err != nil {
+		return output, err
+	}
+
+	output = internet.ExportHttpRequestOutput{
+		Format:  format,
+		Content: content,
+	}
+
+	return output, nil
+}
+
+// ImportHttpRequest reads the requests of a cURL command line or of a
+// HAR. The format is detected if empty.
+func (s *Service) ImportHttpRequest(content string, format string) (internet.ImportHttpRequestOutput, error) {
+	return s.ImportHttpRequestContext(context.Background(), content, format)
+}
+
+func (s *Service) ImportHttpRequestContext(ctx context.Context, content string, format string) (internet.ImportHttpRequestOutput, error) {
+	output := internet.ImportHttpRequestOutput{}
+
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
+	format = strings.ToLower(format)
+	if format == "" {
+		format = detectHttpFormat(content)
+	}
+
+	requests := []internet.HttpRequest{}
+	switch format {
+	case internet.HttpFormatCurl:
+		request, err := curlRequest(content)
+		if err != nil {
+			return output, fmt.Errorf("error parsing curl command: %w", err)
+		}
+		requests = append(requests, request)
+	case internet.HttpFormatHar:
+		var err error
+		requests, err = harRequests(content)
+		if err != nil {
+			return output, err
+		}
+	default:
+		return output, unsupportedHttpFormat(format)
+	}
+
+	output = internet.ImportHttpRequestOutput{
+		Format:   format,
+		Requests: requests,
+	}
+
+	return output, nil
+}
+
+// detectHttpFormat returns the format of a content to import, empty if
+// not known.
+func detectHttpFormat(content string) string {
+	if strings.HasPrefix(strings.TrimSpace(content), "{") {
+		return internet.HttpFormatHar
+	}
+	if fields := strings.Fields(content); len(fields) > 0 && fields[0] == "curl" {
+		return internet.HttpFormatCurl
+	}
+
+	return ""
+}
+
+func unsupportedHttpFormat(format string) error {
+	if format == "" {
+		return fmt.Errorf("unknown format, use curl or har")
+	}
+
+	return fmt.Errorf("unsupported format %q, use curl or har", format)
+}
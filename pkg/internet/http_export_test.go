@@ -0,0 +1,86 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"testing"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportHttpRequest(t *testing.T) {
+	testCases := []struct {
+		format string
+		want   string
+	}{
+		{"curl", "curl"},
+		{"HAR", "har"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			// arrange
+			s := NewService()
+			request := internet.HttpRequest{Method: "POST", Url: "https://example.com/?q=1", Body: "a=1"}
+
+			// act
+			exported, exportErr := s.ExportHttpRequest(request, tc.format)
+			imported, importErr := s.ImportHttpRequest(exported.Content, "")
+
+			// assert
+			assert.Nil(t, exportErr)
+			assert.Nil(t, importErr)
+			assert.Equal(t, tc.want, exported.Format)
+			assert.Equal(t, tc.want, imported.Format)
+			assert.Len(t, imported.Requests, 1)
+			assert.Equal(t, "POST", imported.Requests[0].Method)
+			assert.Equal(t, "https://example.com/?q=1", imported.Requests[0].Url)
+			assert.Equal(t, "a=1", imported.Requests[0].Body)
+		})
+	}
+}
+
+func TestImportHttpRequestInvalid(t *testing.T) {
+	testCases := []struct {
+		content string
+		format  string
+		want    string
+	}{
+		{"GET / HTTP/1.1", "", "unknown format, use curl or har"},
+		{"curl https://go.dev", "postman", `unsupported format "postman", use curl or har`},
+		{"curl -X", "curl", "error parsing curl command: the curl option -X needs an argument"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.content, func(t *testing.T) {
+			// act
+			_, err := NewService().ImportHttpRequest(tc.content, tc.format)
+
+			// assert
+			assert.EqualError(t, err, tc.want)
+		})
+	}
+}
+
+func TestExportHttpRequestUnsupported(t *testing.T) {
+	// act
+	_, err := NewService().ExportHttpRequest(internet.HttpRequest{Url: "https://go.dev"}, "wget")
+
+	// assert
+	assert.EqualError(t, err, `unsupported format "wget", use curl or har`)
+}
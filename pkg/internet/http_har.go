@@ -0,0 +1,233 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+// harVersion is the version of the HAR format, as in
+// http://www.softwareishard.com/blog/har-12-spec/.
+const harVersion = "1.2"
+
+type har struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	Url         string         `json:"url"`
+	HttpVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string         `json:"mimeType"`
+	Text     string         `json:"text"`
+	Params   []harNameValue `json:"params,omitempty"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HttpVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// harDocument returns the HAR of a request not sent yet, its response
+// being empty. The authentication is written as a header, as HAR has no
+// other way to have it.
+func harDocument(request internet.HttpRequest, now time.Time) (string, error) {
+	method := strings.ToUpper(request.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	u, err := parseUrl(request.Url)
+	if err != nil {
+		return "", err
+	}
+	parameters, err := parseQuery(request.Url, u.RawQuery)
+	if err != nil {
+		return "", err
+	}
+
+	harRequest := harRequest{
+		Method:      method,
+		Url:         request.Url,
+		HttpVersion: "HTTP/1.1",
+		Cookies:     []harNameValue{},
+		Headers:     []harNameValue{},
+		QueryString: []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    len(request.Body),
+	}
+	for _, header := range request.Headers {
+		harRequest.Headers = append(harRequest.Headers, harNameValue{Name: header.Name, Value: header.Value})
+	}
+	for _, parameter := range parameters {
+		harRequest.QueryString = append(harRequest.QueryString, harNameValue{Name: parameter.name, Value: parameter.value})
+	}
+
+	switch strings.ToLower(request.Auth.Type) {
+	case "":
+	case internet.HttpAuthBasic:
+		credentials := base64.StdEncoding.EncodeToString([]byte(request.Auth.Username + ":" + request.Auth.Password))
+		harRequest.Headers = append(harRequest.Headers, harNameValue{Name: "Authorization", Value: "Basic " + credentials})
+	case internet.HttpAuthBearer:
+		harRequest.Headers = append(harRequest.Headers, harNameValue{Name: "Authorization", Value: "Bearer " + request.Auth.Token})
+	default:
+		return "", fmt.Errorf("unsupported authentication %q, use basic or bearer", request.Auth.Type)
+	}
+
+	if request.Body != "" {
+		harRequest.PostData = &harPostData{MimeType: headerValue(request.Headers, "Content-Type"), Text: request.Body}
+	}
+
+	document := har{Log: harLog{
+		Version: harVersion,
+		Creator: harCreator{Name: defaultUserAgent, Version: harVersion},
+		Entries: []harEntry{{
+			StartedDateTime: now.UTC().Format(time.RFC3339Nano),
+			Request:         harRequest,
+			Response: harResponse{
+				Cookies:     []harNameValue{},
+				Headers:     []harNameValue{},
+				HeadersSize: -1,
+				BodySize:    -1,
+			},
+		}},
+	}}
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error marshling har: %s", err.Error())
+	}
+
+	return string(data), nil
+}
+
+// harRequests returns the requests of all the entries of a HAR, like the
+// ones saved by the developer tools of browsers.
+func harRequests(content string) ([]internet.HttpRequest, error) {
+	document := har{}
+	err := json.Unmarshal([]byte(content), &document)
+	if err != nil {
+		return nil, fmt.Errorf("error un-marshalling har: %s", err.Error())
+	}
+
+	requests := []internet.HttpRequest{}
+	for i, entry := range document.Log.Entries {
+		if entry.Request.Url == "" {
+			return nil, fmt.Errorf("the entry %d of the har has no url", i)
+		}
+
+		request := internet.HttpRequest{
+			Method:  strings.ToUpper(entry.Request.Method),
+			Url:     entry.Request.Url,
+			Headers: []internet.HttpHeader{},
+		}
+		for _, header := range entry.Request.Headers {
+			// the pseudo-headers of HTTP/2, like :authority, are not
+			// headers of the request
+			if strings.HasPrefix(header.Name, ":") {
+				continue
+			}
+			request.Headers = append(request.Headers, internet.HttpHeader{Name: header.Name, Value: header.Value})
+		}
+
+		if postData := entry.Request.PostData; postData != nil {
+			request.Body = postData.Text
+			if request.Body == "" && len(postData.Params) > 0 {
+				values := []string{}
+				for _, param := range postData.Params {
+					values = append(values, url.QueryEscape(param.Name)+"="+url.QueryEscape(param.Value))
+				}
+				request.Body = strings.Join(values, "&")
+			}
+		}
+
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+func headerValue(headers []internet.HttpHeader, name string) string {
+	for _, header := range headers {
+		if strings.EqualFold(header.Name, name) {
+			return header.Value
+		}
+	}
+
+	return ""
+}
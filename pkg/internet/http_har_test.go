@@ -0,0 +1,112 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHarDocument(t *testing.T) {
+	// arrange
+	request := internet.HttpRequest{
+		Method:  "post",
+		Url:     "https://api.example.com/users?active=true",
+		Headers: []internet.HttpHeader{{Name: "Content-Type", Value: "application/json"}},
+		Body:    `{"name":"gopher"}`,
+		Auth:    internet.HttpAuth{Type: "bearer", Token: "token"},
+	}
+
+	// act
+	document, err := harDocument(request, time.Date(2021, 12, 15, 13, 0, 0, 0, time.UTC))
+
+	// assert
+	assert.Nil(t, err)
+	parsed := har{}
+	assert.Nil(t, json.Unmarshal([]byte(document), &parsed))
+	assert.Equal(t, "1.2", parsed.Log.Version)
+	assert.Len(t, parsed.Log.Entries, 1)
+	entry := parsed.Log.Entries[0]
+	assert.Equal(t, "2021-12-15T13:00:00Z", entry.StartedDateTime)
+	assert.Equal(t, harRequest{
+		Method:      "POST",
+		Url:         "https://api.example.com/users?active=true",
+		HttpVersion: "HTTP/1.1",
+		Cookies:     []harNameValue{},
+		Headers: []harNameValue{
+			{Name: "Content-Type", Value: "application/json"},
+			{Name: "Authorization", Value: "Bearer token"},
+		},
+		QueryString: []harNameValue{{Name: "active", Value: "true"}},
+		PostData:    &harPostData{MimeType: "application/json", Text: `{"name":"gopher"}`},
+		HeadersSize: -1,
+		BodySize:    17,
+	}, entry.Request)
+}
+
+func TestHarRequests(t *testing.T) {
+	// arrange
+	content, err := ioutil.ReadFile("testdata/har/browser.har")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// act
+	requests, err := harRequests(string(content))
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, []internet.HttpRequest{
+		{
+			Method:  "GET",
+			Url:     "https://dev.to/api/articles?username=renato0307",
+			Headers: []internet.HttpHeader{{Name: "accept", Value: "application/json"}},
+		},
+		{
+			Method:  "POST",
+			Url:     "https://example.com/login",
+			Headers: []internet.HttpHeader{{Name: "Content-Type", Value: "application/x-www-form-urlencoded"}},
+			Body:    "user=renato&password=s3cret%26",
+		},
+	}, requests)
+}
+
+func TestHarRequestsInvalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"not json", "{log", "error un-marshalling har: invalid character 'l' looking for beginning of object key string"},
+		{"no url", `{"log":{"entries":[{"request":{"method":"GET"}}]}}`, "the entry 0 of the har has no url"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			_, err := harRequests(tc.content)
+
+			// assert
+			assert.EqualError(t, err, tc.want)
+		})
+	}
+}
@@ -0,0 +1,331 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptrace"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+const (
+	defaultMaxRedirects = 10
+	// maxHttpBodySize is the size of the body of a response read
+	maxHttpBodySize = 10 << 20
+)
+
+// SendHttpRequest sends a request, following the redirects, and returns
+// the response with how long each phase of the request took.
+func (s *Service) SendHttpRequest(request internet.HttpRequest, options internet.SendHttpRequestOptions) (internet.SendHttpRequestOutput, error) {
+	return s.SendHttpRequestContext(context.Background(), request, options)
+}
+
+func (s *Service) SendHttpRequestContext(ctx context.Context, request internet.HttpRequest, options internet.SendHttpRequestOptions) (internet.SendHttpRequestOutput, error) {
+	output := internet.SendHttpRequestOutput{}
+
+	req, err := newHttpRequest(ctx, request)
+	if err != nil {
+		return output, err
+	}
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", s.userAgentHeader())
+	}
+
+	client, err := s.requestClient(options)
+	if err != nil {
+		return output, err
+	}
+
+	redirects := []internet.HttpRedirect{}
+	cookies := []internet.HttpCookie{}
+	maxRedirects := options.MaxRedirects
+	if maxRedirects == 0 {
+		maxRedirects = defaultMaxRedirects
+	}
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if options.DisableRedirects {
+			return http.ErrUseLastResponse
+		}
+		if len(via) > maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+
+		redirects = append(redirects, internet.HttpRedirect{
+			StatusCode: req.Response.StatusCode,
+			Url:        via[len(via)-1].URL.String(),
+			Location:   req.Response.Header.Get("Location"),
+		})
+		cookies = append(cookies, httpCookies(req.Response.Cookies())...)
+		return nil
+	}
+
+	trace := &httpTrace{}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace.clientTrace()))
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return output, fmt.Errorf("error executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyStart := time.Now()
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxHttpBodySize+1))
+	if err != nil {
+		return output, fmt.Errorf("error reading response: %s", err.Error())
+	}
+	end := time.Now()
+
+	output = internet.SendHttpRequestOutput{
+		Url:        resp.Request.URL.String(),
+		Proto:      resp.Proto,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Headers:    httpHeaders(resp.Header),
+		Body:       string(body),
+		Redirects:  redirects,
+		Cookies:    append(cookies, httpCookies(resp.Cookies())...),
+		Timings:    trace.timings(),
+	}
+	if len(body) > maxHttpBodySize {
+		output.Body = output.Body[:maxHttpBodySize]
+		output.Truncated = true
+	}
+	output.Timings.Transfer = end.Sub(bodyStart)
+	output.Timings.Total = end.Sub(start)
+
+	return output, nil
+}
+
+// newHttpRequest creates the request to send, with its headers and
+// authentication.
+func newHttpRequest(ctx context.Context, request internet.HttpRequest) (*http.Request, error) {
+	u, err := parseUrl(request.Url)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, &internet.InvalidUrlError{Url: request.Url, Reason: "only http and https urls are supported"}
+	}
+
+	method := strings.ToUpper(request.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var body io.Reader
+	if request.Body != "" {
+		body = strings.NewReader(request.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %s", err.Error())
+	}
+
+	for _, header := range request.Headers {
+		if strings.EqualFold(header.Name, "Host") {
+			req.Host = header.Value
+			continue
+		}
+		req.Header.Add(header.Name, header.Value)
+	}
+
+	switch strings.ToLower(request.Auth.Type) {
+	case "":
+	case internet.HttpAuthBasic:
+		req.SetBasicAuth(request.Auth.Username, request.Auth.Password)
+	case internet.HttpAuthBearer:
+		req.Header.Set("Authorization", "Bearer "+request.Auth.Token)
+	default:
+		return nil, fmt.Errorf("unsupported authentication %q, use basic or bearer", request.Auth.Type)
+	}
+
+	return req, nil
+}
+
+// requestClient returns a copy of the HTTP client of the service to
+// send a request, with its own cookie jar to keep the cookies set by the
+// redirects.
+func (s *Service) requestClient(options internet.SendHttpRequestOptions) (*http.Client, error) {
+	client := *s.httpClient()
+
+	if client.Jar == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating cookie jar: %s", err.Error())
+		}
+		client.Jar = jar
+	}
+
+	if options.Insecure {
+		transport := client.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		httpTransport, ok := transport.(*http.Transport)
+		if !ok {
+			return nil, fmt.Errorf("insecure requests are not supported with a custom transport")
+		}
+		httpTransport = httpTransport.Clone()
+		if httpTransport.TLSClientConfig == nil {
+			httpTransport.TLSClientConfig = &tls.Config{}
+		}
+		httpTransport.TLSClientConfig.InsecureSkipVerify = true
+		client.Transport = httpTransport
+	}
+
+	return &client, nil
+}
+
+// httpHeaders returns the headers sorted by name, a value each.
+func httpHeaders(header http.Header) []internet.HttpHeader {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	headers := []internet.HttpHeader{}
+	for _, name := range names {
+		for _, value := range header[name] {
+			headers = append(headers, internet.HttpHeader{Name: name, Value: value})
+		}
+	}
+
+	return headers
+}
+
+var sameSiteNames = map[http.SameSite]string{
+	http.SameSiteLaxMode:    "lax",
+	http.SameSiteStrictMode: "strict",
+	http.SameSiteNoneMode:   "none",
+}
+
+func httpCookies(cookies []*http.Cookie) []internet.HttpCookie {
+	output := []internet.HttpCookie{}
+	for _, c := range cookies {
+		output = append(output, internet.HttpCookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Expires:  c.Expires,
+			MaxAge:   c.MaxAge,
+			Secure:   c.Secure,
+			HttpOnly: c.HttpOnly,
+			SameSite: sameSiteNames[c.SameSite],
+		})
+	}
+
+	return output
+}
+
+// httpTrace records when each phase of a request started and ended. As
+// the phases of each redirect overwrite the previous ones, the times are
+// the ones of the last request.
+type httpTrace struct {
+	mutex  sync.Mutex
+	phases httpPhases
+}
+
+type httpPhases struct {
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	reused       bool
+}
+
+func (t *httpTrace) record(field *time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	*field = time.Now()
+}
+
+func (t *httpTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GetConn: func(hostPort string) {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			t.phases = httpPhases{}
+		},
+		DNSStart: func(httptrace.DNSStartInfo) { t.record(&t.phases.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.record(&t.phases.dnsDone) },
+		ConnectStart: func(network, addr string) {
+			// with several addresses, the connection is the first one
+			// tried
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			if t.phases.connectStart.IsZero() {
+				t.phases.connectStart = time.Now()
+			}
+		},
+		ConnectDone: func(network, addr string, err error) {
+			if err == nil {
+				t.record(&t.phases.connectDone)
+			}
+		},
+		TLSHandshakeStart: func() { t.record(&t.phases.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { t.record(&t.phases.tlsDone) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			t.phases.reused = info.Reused
+		},
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.record(&t.phases.wroteRequest) },
+		GotFirstResponseByte: func() { t.record(&t.phases.firstByte) },
+	}
+}
+
+func (t *httpTrace) timings() internet.HttpTimings {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	p := t.phases
+	return internet.HttpTimings{
+		DnsLookup:        between(p.dnsStart, p.dnsDone),
+		Connect:          between(p.connectStart, p.connectDone),
+		TlsHandshake:     between(p.tlsStart, p.tlsDone),
+		TimeToFirstByte:  between(p.wroteRequest, p.firstByte),
+		ConnectionReused: p.reused,
+	}
+}
+
+// between returns the time from start to end, zero if any of them did
+// not happen.
+func between(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+
+	return end.Sub(start)
+}
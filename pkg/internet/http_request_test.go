@@ -0,0 +1,263 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+// echoedRequest is what the server of newEchoServer answers with.
+type echoedRequest struct {
+	Method  string
+	Host    string
+	Headers http.Header
+	Body    string
+}
+
+// newEchoServer starts a server answering /echo with the request it got,
+// as JSON, and /login with a redirect to it setting a cookie.
+func newEchoServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Add("X-Values", "a")
+		w.Header().Add("X-Values", "b")
+		json.NewEncoder(w).Encode(echoedRequest{Method: r.Method, Host: r.Host, Headers: r.Header, Body: string(body)})
+	})
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/", HttpOnly: true, SameSite: http.SameSiteLaxMode})
+		http.Redirect(w, r, "/echo", http.StatusFound)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusMovedPermanently)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestSendHttpRequest(t *testing.T) {
+	// arrange
+	server := newEchoServer(t)
+	defer server.Close()
+	request := internet.HttpRequest{
+		Method: "post",
+		Url:    server.URL + "/echo",
+		Headers: []internet.HttpHeader{
+			{Name: "Content-Type", Value: "application/json"},
+			{Name: "X-Tags", Value: "go"},
+			{Name: "X-Tags", Value: "http"},
+			{Name: "Host", Value: "api.example.com"},
+		},
+		Body: `{"name":"gopher"}`,
+		Auth: internet.HttpAuth{Type: "basic", Username: "renato", Password: "secret"},
+	}
+
+	// act
+	output, err := NewService(WithUserAgent("test")).SendHttpRequest(request, internet.SendHttpRequestOptions{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, server.URL+"/echo", output.Url)
+	assert.Equal(t, "HTTP/1.1", output.Proto)
+	assert.Equal(t, 200, output.StatusCode)
+	assert.Equal(t, "200 OK", output.Status)
+	assert.Contains(t, output.Headers, internet.HttpHeader{Name: "Content-Type", Value: "application/json"})
+	assert.Contains(t, output.Headers, internet.HttpHeader{Name: "X-Values", Value: "a"})
+	assert.Contains(t, output.Headers, internet.HttpHeader{Name: "X-Values", Value: "b"})
+	assert.Empty(t, output.Redirects)
+	assert.Empty(t, output.Cookies)
+	assert.False(t, output.Truncated)
+
+	echoed := echoedRequest{}
+	assert.Nil(t, json.Unmarshal([]byte(output.Body), &echoed))
+	assert.Equal(t, "POST", echoed.Method)
+	assert.Equal(t, "api.example.com", echoed.Host)
+	assert.Equal(t, []string{"go", "http"}, echoed.Headers["X-Tags"])
+	assert.Equal(t, "Basic cmVuYXRvOnNlY3JldA==", echoed.Headers.Get("Authorization"))
+	assert.Equal(t, "test", echoed.Headers.Get("User-Agent"))
+	assert.Equal(t, `{"name":"gopher"}`, echoed.Body)
+
+	assert.False(t, output.Timings.ConnectionReused)
+	assert.True(t, output.Timings.Connect > 0)
+	assert.Equal(t, time.Duration(0), output.Timings.TlsHandshake)
+	assert.True(t, output.Timings.TimeToFirstByte > 0)
+	assert.True(t, output.Timings.Total >= output.Timings.TimeToFirstByte)
+}
+
+func TestSendHttpRequestBearer(t *testing.T) {
+	// arrange
+	server := newEchoServer(t)
+	defer server.Close()
+	request := internet.HttpRequest{
+		Url:     server.URL + "/echo",
+		Headers: []internet.HttpHeader{{Name: "User-Agent", Value: "curl/7.79.1"}},
+		Auth:    internet.HttpAuth{Type: "Bearer", Token: "token"},
+	}
+
+	// act
+	output, err := NewService().SendHttpRequest(request, internet.SendHttpRequestOptions{})
+
+	// assert
+	assert.Nil(t, err)
+	echoed := echoedRequest{}
+	assert.Nil(t, json.Unmarshal([]byte(output.Body), &echoed))
+	assert.Equal(t, "GET", echoed.Method)
+	assert.Equal(t, "Bearer token", echoed.Headers.Get("Authorization"))
+	assert.Equal(t, "curl/7.79.1", echoed.Headers.Get("User-Agent"))
+}
+
+func TestSendHttpRequestRedirects(t *testing.T) {
+	// arrange
+	server := newEchoServer(t)
+	defer server.Close()
+
+	// act
+	output, err := NewService().SendHttpRequest(internet.HttpRequest{Url: server.URL + "/login"}, internet.SendHttpRequestOptions{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, server.URL+"/echo", output.Url)
+	assert.Equal(t, 200, output.StatusCode)
+	assert.Equal(t, []internet.HttpRedirect{{StatusCode: 302, Url: server.URL + "/login", Location: "/echo"}}, output.Redirects)
+	assert.Equal(t, []internet.HttpCookie{{Name: "session", Value: "abc", Path: "/", HttpOnly: true, SameSite: "lax"}}, output.Cookies)
+
+	echoed := echoedRequest{}
+	assert.Nil(t, json.Unmarshal([]byte(output.Body), &echoed))
+	assert.Equal(t, "session=abc", echoed.Headers.Get("Cookie"))
+	assert.True(t, output.Timings.ConnectionReused)
+}
+
+func TestSendHttpRequestDisableRedirects(t *testing.T) {
+	// arrange
+	server := newEchoServer(t)
+	defer server.Close()
+
+	// act
+	output, err := NewService().SendHttpRequest(internet.HttpRequest{Url: server.URL + "/login"},
+		internet.SendHttpRequestOptions{DisableRedirects: true})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, server.URL+"/login", output.Url)
+	assert.Equal(t, 302, output.StatusCode)
+	assert.Contains(t, output.Headers, internet.HttpHeader{Name: "Location", Value: "/echo"})
+	assert.Empty(t, output.Redirects)
+	assert.Len(t, output.Cookies, 1)
+}
+
+func TestSendHttpRequestTooManyRedirects(t *testing.T) {
+	// arrange
+	server := newEchoServer(t)
+	defer server.Close()
+
+	// act
+	_, err := NewService().SendHttpRequest(internet.HttpRequest{Url: server.URL + "/loop"},
+		internet.SendHttpRequestOptions{MaxRedirects: 3})
+
+	// assert
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "stopped after 3 redirects")
+}
+
+func TestSendHttpRequestTls(t *testing.T) {
+	// arrange
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("secure"))
+	}))
+	// the request verifying the certificate fails the handshake
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+	request := internet.HttpRequest{Url: server.URL}
+
+	// act
+	_, verifyErr := NewService().SendHttpRequest(request, internet.SendHttpRequestOptions{})
+	output, err := NewService().SendHttpRequest(request, internet.SendHttpRequestOptions{Insecure: true})
+
+	// assert
+	assert.NotNil(t, verifyErr)
+	assert.Nil(t, err)
+	assert.Equal(t, "secure", output.Body)
+	assert.True(t, output.Timings.TlsHandshake > 0)
+}
+
+func TestSendHttpRequestTruncated(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("a", maxHttpBodySize+10)))
+	}))
+	defer server.Close()
+
+	// act
+	output, err := NewService().SendHttpRequest(internet.HttpRequest{Url: server.URL}, internet.SendHttpRequestOptions{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, output.Truncated)
+	assert.Len(t, output.Body, maxHttpBodySize)
+}
+
+func TestSendHttpRequestInvalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		request internet.HttpRequest
+		want    string
+	}{
+		{"scheme", internet.HttpRequest{Url: "ftp://example.com"}, `invalid url "ftp://example.com": only http and https urls are supported`},
+		{"url", internet.HttpRequest{Url: "http://exa mple.com"}, `invalid url "http://exa mple.com": invalid character " " in host name`},
+		{"auth", internet.HttpRequest{Url: "http://example.com", Auth: internet.HttpAuth{Type: "digest"}}, `unsupported authentication "digest", use basic or bearer`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			_, err := NewService().SendHttpRequest(tc.request, internet.SendHttpRequestOptions{})
+
+			// assert
+			assert.EqualError(t, err, tc.want)
+		})
+	}
+}
+
+func TestSendHttpRequestInsecureCustomTransport(t *testing.T) {
+	// arrange
+	s := NewService(WithTransport(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, errors.New("unexpected request")
+	})))
+
+	// act
+	_, err := s.SendHttpRequest(internet.HttpRequest{Url: "https://example.com"}, internet.SendHttpRequestOptions{Insecure: true})
+
+	// assert
+	assert.EqualError(t, err, "insecure requests are not supported with a custom transport")
+}
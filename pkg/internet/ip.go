@@ -0,0 +1,306 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+// ipPrefix is an IPv4 or IPv6 address with a prefix length, the address
+// as an integer to do arithmetic on both versions.
+type ipPrefix struct {
+	address *big.Int
+	// bits is the size of the address, 32 or 128
+	bits   int
+	length int
+}
+
+// parseIpPrefix parses an address with or without a prefix length, being
+// a single address prefix if without.
+func parseIpPrefix(input string) (ipPrefix, error) {
+	prefix := ipPrefix{}
+
+	address, length := strings.TrimSpace(input), ""
+	if i := strings.IndexByte(address, '/'); i >= 0 {
+		address, length = address[:i], address[i+1:]
+	}
+
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return prefix, &internet.InvalidIpError{Input: input, Reason: "not an ip address"}
+	}
+
+	prefix.bits = 128
+	if !strings.Contains(address, ":") {
+		prefix.bits = 32
+		ip = ip.To4()
+	}
+	prefix.address = new(big.Int).SetBytes(ip)
+	prefix.length = prefix.bits
+
+	if length != "" {
+		var err error
+		prefix.length, err = strconv.Atoi(length)
+		if err != nil || prefix.length < 0 || prefix.length > prefix.bits {
+			return prefix, &internet.InvalidIpError{
+				Input:  input,
+				Reason: fmt.Sprintf("the prefix length must be between 0 and %d", prefix.bits),
+			}
+		}
+	}
+
+	return prefix, nil
+}
+
+// size returns the number of addresses in the prefix.
+func (p ipPrefix) size() *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(p.bits-p.length))
+}
+
+// network returns the first address of the prefix.
+func (p ipPrefix) network() *big.Int {
+	hostBits := uint(p.bits - p.length)
+	return new(big.Int).Lsh(new(big.Int).Rsh(p.address, hostBits), hostBits)
+}
+
+// last returns the last address of the prefix.
+func (p ipPrefix) last() *big.Int {
+	last := new(big.Int).Add(p.network(), p.size())
+	return last.Sub(last, big.NewInt(1))
+}
+
+// mask returns the netmask of the prefix.
+func (p ipPrefix) mask() *big.Int {
+	return new(big.Int).Xor(ipMax(p.bits), p.hostmask())
+}
+
+// hostmask returns the inverse of the netmask, the wildcard of ACLs.
+func (p ipPrefix) hostmask() *big.Int {
+	return new(big.Int).Sub(p.size(), big.NewInt(1))
+}
+
+// contains tells if other is a prefix inside p, or p itself.
+func (p ipPrefix) contains(other ipPrefix) bool {
+	if p.bits != other.bits || p.length > other.length {
+		return false
+	}
+
+	hostBits := uint(p.bits - p.length)
+	return new(big.Int).Rsh(other.address, hostBits).Cmp(new(big.Int).Rsh(p.address, hostBits)) == 0
+}
+
+// String returns the prefix in CIDR notation, with its network address.
+func (p ipPrefix) String() string {
+	return fmt.Sprintf("%s/%d", formatIp(p.network(), p.bits), p.length)
+}
+
+// ipMax returns the largest address with bits.
+func ipMax(bits int) *big.Int {
+	max := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	return max.Sub(max, big.NewInt(1))
+}
+
+// ipBytes returns an address as the bytes of a net.IP.
+func ipBytes(address *big.Int, bits int) net.IP {
+	ip := make(net.IP, bits/8)
+	return address.FillBytes(ip)
+}
+
+// formatIp returns the usual text of an address, the dotted decimal for
+// IPv4 and the compressed hexadecimal groups for IPv6.
+func formatIp(address *big.Int, bits int) string {
+	ip := ipBytes(address, bits)
+	if bits == 128 && ip.To4() != nil {
+		// net.IP formats IPv4-mapped addresses as IPv4 ones
+		return "::ffff:" + ip.To4().String()
+	}
+
+	return ip.String()
+}
+
+// expandIp returns an address with all its digits, which for IPv6 are
+// the 8 groups of 4 hexadecimal digits.
+func expandIp(address *big.Int, bits int) string {
+	if bits == 32 {
+		return formatIp(address, bits)
+	}
+
+	hex := fmt.Sprintf("%032x", address)
+	groups := make([]string, 8)
+	for i := range groups {
+		groups[i] = hex[i*4 : i*4+4]
+	}
+
+	return strings.Join(groups, ":")
+}
+
+// reverseDns returns the name of an address in the reverse DNS zones,
+// in-addr.arpa for IPv4 and ip6.arpa, nibble by nibble, for IPv6.
+func reverseDns(address *big.Int, bits int) string {
+	labels := []string{}
+	if bits == 32 {
+		for _, b := range ipBytes(address, bits) {
+			labels = append([]string{strconv.Itoa(int(b))}, labels...)
+		}
+		return strings.Join(labels, ".") + ".in-addr.arpa"
+	}
+
+	for _, nibble := range fmt.Sprintf("%032x", address) {
+		labels = append([]string{string(nibble)}, labels...)
+	}
+
+	return strings.Join(labels, ".") + ".ip6.arpa"
+}
+
+// ipScope returns the kind of network an address belongs to.
+func ipScope(ip net.IP) string {
+	switch {
+	case ip.IsUnspecified():
+		return "unspecified"
+	case ip.IsLoopback():
+		return "loopback"
+	case ip.IsLinkLocalUnicast(), ip.IsLinkLocalMulticast():
+		return "link-local"
+	case ip.IsMulticast():
+		return "multicast"
+	case ip.IsPrivate():
+		return "private"
+	}
+
+	return "public"
+}
+
+// ConvertIp converts an address between its forms: dotted decimal or
+// hexadecimal groups, integer, hexadecimal and reverse DNS name. Any of
+// them is accepted as input.
+func (s *Service) ConvertIp(address string) (internet.ConvertIpOutput, error) {
+	return s.ConvertIpContext(context.Background(), address)
+}
+
+func (s *Service) ConvertIpContext(ctx context.Context, address string) (internet.ConvertIpOutput, error) {
+	output := internet.ConvertIpOutput{}
+
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
+	value, bits, err := parseIpForm(address)
+	if err != nil {
+		return output, err
+	}
+
+	output = internet.ConvertIpOutput{
+		Version:    ipVersion(bits),
+		Address:    formatIp(value, bits),
+		Expanded:   expandIp(value, bits),
+		Integer:    value.String(),
+		Hex:        fmt.Sprintf("0x%0*x", bits/4, value),
+		ReverseDns: reverseDns(value, bits),
+	}
+
+	return output, nil
+}
+
+func ipVersion(bits int) int {
+	if bits == 32 {
+		return 4
+	}
+
+	return 6
+}
+
+// parseIpForm parses an address in any of the forms of ConvertIp. An
+// integer is IPv4 if it fits 32 bits, and so is an hexadecimal with up to
+// 8 digits.
+func parseIpForm(input string) (*big.Int, int, error) {
+	address := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(input), "."))
+
+	switch {
+	case strings.HasSuffix(address, ".in-addr.arpa"), strings.HasSuffix(address, ".ip6.arpa"):
+		return parseReverseDns(input, address)
+	case strings.HasPrefix(address, "0x"):
+		value, ok := new(big.Int).SetString(address[2:], 16)
+		if !ok || strings.HasPrefix(address[2:], "-") || strings.HasPrefix(address[2:], "+") {
+			return nil, 0, &internet.InvalidIpError{Input: input, Reason: "not an hexadecimal number"}
+		}
+		if len(address) <= 10 {
+			return value, 32, nil
+		}
+		return ipInteger(input, value, 128)
+	case address != "" && strings.Trim(address, "0123456789") == "":
+		value, _ := new(big.Int).SetString(address, 10)
+		if value.Cmp(ipMax(32)) <= 0 {
+			return value, 32, nil
+		}
+		return ipInteger(input, value, 128)
+	}
+
+	prefix, err := parseIpPrefix(input)
+	if err != nil {
+		return nil, 0, err
+	}
+	if prefix.length != prefix.bits {
+		return nil, 0, &internet.InvalidIpError{Input: input, Reason: "a prefix is not an address"}
+	}
+
+	return prefix.address, prefix.bits, nil
+}
+
+func ipInteger(input string, value *big.Int, bits int) (*big.Int, int, error) {
+	if value.Cmp(ipMax(bits)) > 0 {
+		return nil, 0, &internet.InvalidIpError{Input: input, Reason: fmt.Sprintf("the number does not fit %d bits", bits)}
+	}
+
+	return value, bits, nil
+}
+
+// parseReverseDns parses the name of an address in the reverse DNS
+// zones, which must have all its labels.
+func parseReverseDns(input string, name string) (*big.Int, int, error) {
+	bits, labelCount, base := 32, 4, 10
+	if strings.HasSuffix(name, ".ip6.arpa") {
+		bits, labelCount, base = 128, 32, 16
+	}
+
+	labels := strings.Split(name, ".")
+	labels = labels[:len(labels)-2]
+	if len(labels) != labelCount {
+		return nil, 0, &internet.InvalidIpError{
+			Input:  input,
+			Reason: fmt.Sprintf("a reverse dns name needs %d labels, got %d", labelCount, len(labels)),
+		}
+	}
+
+	value := new(big.Int)
+	for i := len(labels) - 1; i >= 0; i-- {
+		digit, err := strconv.ParseUint(labels[i], base, 8)
+		if err != nil || (base == 16 && len(labels[i]) != 1) {
+			return nil, 0, &internet.InvalidIpError{Input: input, Reason: fmt.Sprintf("invalid label %q", labels[i])}
+		}
+		value.Lsh(value, uint(bits/labelCount))
+		value.Or(value, new(big.Int).SetUint64(digit))
+	}
+
+	return value, bits, nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertIp(t *testing.T) {
+	v4 := internet.ConvertIpOutput{
+		Version:    4,
+		Address:    "10.1.2.3",
+		Expanded:   "10.1.2.3",
+		Integer:    "167838211",
+		Hex:        "0x0a010203",
+		ReverseDns: "3.2.1.10.in-addr.arpa",
+	}
+	v6 := internet.ConvertIpOutput{
+		Version:    6,
+		Address:    "2001:db8::1",
+		Expanded:   "2001:0db8:0000:0000:0000:0000:0000:0001",
+		Integer:    "42540766411282592856903984951653826561",
+		Hex:        "0x20010db8000000000000000000000001",
+		ReverseDns: "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa",
+	}
+
+	testCases := []struct {
+		input string
+		want  internet.ConvertIpOutput
+	}{
+		{"10.1.2.3", v4},
+		{"167838211", v4},
+		{"0x0A010203", v4},
+		{"3.2.1.10.in-addr.arpa.", v4},
+		{"2001:DB8:0::1", v6},
+		{"42540766411282592856903984951653826561", v6},
+		{"0x20010db8000000000000000000000001", v6},
+		{"1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.B.D.0.1.0.0.2.IP6.ARPA", v6},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			// act
+			output, err := NewService().ConvertIp(tc.input)
+
+			// assert
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, output)
+		})
+	}
+}
+
+func TestConvertIpMapped(t *testing.T) {
+	// act
+	output, err := NewService().ConvertIp("::ffff:192.0.2.1")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, 6, output.Version)
+	assert.Equal(t, "::ffff:192.0.2.1", output.Address)
+	assert.Equal(t, "0x00000000000000000000ffffc0000201", output.Hex)
+}
+
+func TestConvertIpInvalid(t *testing.T) {
+	testCases := []struct {
+		input  string
+		reason string
+	}{
+		{"10.1.2", "not an ip address"},
+		{"10.0.0.0/8", "a prefix is not an address"},
+		{"0xzz", "not an hexadecimal number"},
+		{"340282366920938463463374607431768211456", "the number does not fit 128 bits"},
+		{"2.1.10.in-addr.arpa", "a reverse dns name needs 4 labels, got 3"},
+		{"256.2.1.10.in-addr.arpa", `invalid label "256"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			// act
+			_, err := NewService().ConvertIp(tc.input)
+
+			// assert
+			var invalid *internet.InvalidIpError
+			assert.True(t, errors.As(err, &invalid))
+			assert.Equal(t, tc.input, invalid.Input)
+			assert.Equal(t, tc.reason, invalid.Reason)
+		})
+	}
+}
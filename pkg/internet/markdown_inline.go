@@ -0,0 +1,466 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	mdAutolink     = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9+.-]{1,31}:[^\s<>]*)>`)
+	mdEmailLink    = regexp.MustCompile("^<([A-Za-z0-9.!#$%&'*+/=?^_`{|}~-]+@[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?(?:\\.[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?)*)>")
+	mdEntity       = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[A-Za-z][A-Za-z0-9]{1,31});`)
+	mdBareLink     = regexp.MustCompile(`^(?:https?://|www\.)[^\s<]*`)
+	mdLinkTitle    = regexp.MustCompile(`^[ \t\n]+(?:"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'|\((?:[^)\\]|\\.)*\))`)
+	mdHtmlInline   = regexp.MustCompile(`^<(?:/?[A-Za-z][A-Za-z0-9-]*(?:\s[^<>]*)?/?|!--(?:[^-]|-[^-])*--)>`)
+	mdTrailingLink = regexp.MustCompile(`[?!.,:*_~]+$`)
+)
+
+// mdDelimiter is a run of the characters of emphasis or strikethrough,
+// which may open or close them.
+type mdDelimiter struct {
+	char     rune
+	count    int
+	original int
+	canOpen  bool
+	canClose bool
+}
+
+// mdNode is either an inline or a delimiter not yet matched.
+type mdNode struct {
+	delimiter *mdDelimiter
+	inline    inline
+}
+
+// inlines parses the inline content of a block. Inline images, which
+// documents do not have, are replaced by their alternative text.
+func (p markdownParser) inlines(text string) []inline {
+	runes := []rune(strings.TrimSpace(text))
+	nodes := []mdNode{}
+
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			nodes = append(nodes, mdNode{inline: textInline(literal.String())})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		rest := string(runes[i:])
+		switch {
+		case c == '\\' && i+1 < len(runes) && runes[i+1] == '\n':
+			literal.WriteString("\n")
+			i += 2
+		case c == '\\' && i+1 < len(runes) && isAsciiPunct(runes[i+1]):
+			literal.WriteRune(runes[i+1])
+			i += 2
+		case c == '`':
+			count := runLength(runes, i)
+			end := closingBackticks(runes, i+count, count)
+			if end < 0 {
+				literal.WriteString(strings.Repeat("`", count))
+				i += count
+				continue
+			}
+			flush()
+			nodes = append(nodes, mdNode{inline: inline{kind: inlineCode, children: []inline{textInline(codeSpan(runes[i+count : end]))}}})
+			i = end + count
+		case c == '!' && i+1 < len(runes) && runes[i+1] == '[':
+			link, end, ok := p.link(runes, i+1)
+			if !ok {
+				literal.WriteRune(c)
+				i++
+				continue
+			}
+			literal.WriteString(plainText(link.children))
+			i = end
+		case c == '[':
+			link, end, ok := p.link(runes, i)
+			if !ok {
+				literal.WriteRune(c)
+				i++
+				continue
+			}
+			flush()
+			nodes = append(nodes, mdNode{inline: link})
+			i = end
+		case c == '<':
+			if m := mdAutolink.FindStringSubmatch(rest); m != nil {
+				flush()
+				nodes = append(nodes, mdNode{inline: linkInline(m[1], m[1])})
+				i += len([]rune(m[0]))
+			} else if m := mdEmailLink.FindStringSubmatch(rest); m != nil {
+				flush()
+				nodes = append(nodes, mdNode{inline: linkInline(m[1], "mailto:"+m[1])})
+				i += len([]rune(m[0]))
+			} else if m := mdHtmlInline.FindString(rest); m != "" {
+				i += len([]rune(m))
+			} else {
+				literal.WriteRune(c)
+				i++
+			}
+		case c == '*' || c == '_' || c == '~':
+			count := runLength(runes, i)
+			flush()
+			nodes = append(nodes, mdNode{delimiter: newDelimiter(runes, i, count)})
+			i += count
+		case c == '&':
+			if m := mdEntity.FindString(rest); m != "" {
+				literal.WriteString(html.UnescapeString(m))
+				i += len([]rune(m))
+				continue
+			}
+			literal.WriteRune(c)
+			i++
+		case (c == 'h' || c == 'w') && (i == 0 || isLinkBoundary(runes[i-1])) && mdBareLink.MatchString(rest):
+			text := bareLink(mdBareLink.FindString(rest))
+			if !strings.Contains(text, ".") {
+				literal.WriteRune(c)
+				i++
+				continue
+			}
+			href := text
+			if strings.HasPrefix(text, "www.") {
+				href = "http://" + text
+			}
+			flush()
+			nodes = append(nodes, mdNode{inline: linkInline(text, href)})
+			i += len([]rune(text))
+		case c == '\n':
+			// two spaces at the end of a line are a hard line break,
+			// otherwise it is a soft one, rendered as a space
+			line := strings.TrimRight(literal.String(), " ")
+			hard := len(literal.String())-len(line) >= 2
+			literal.Reset()
+			literal.WriteString(line)
+			if hard {
+				literal.WriteString("\n")
+			} else {
+				literal.WriteString(" ")
+			}
+			i++
+			for i < len(runes) && runes[i] == ' ' {
+				i++
+			}
+		default:
+			literal.WriteRune(c)
+			i++
+		}
+	}
+	flush()
+
+	return mdInlines(processEmphasis(nodes))
+}
+
+// link parses a link starting at the opening bracket, either inline,
+// [text](href "title"), or a reference, [text][label], [text][] or
+// [text]. It returns the link and where it ends.
+func (p markdownParser) link(runes []rune, open int) (inline, int, bool) {
+	closing := closingBracket(runes, open)
+	if closing < 0 {
+		return inline{}, 0, false
+	}
+	text := string(runes[open+1 : closing])
+	end := closing + 1
+
+	href := ""
+	switch {
+	case end < len(runes) && runes[end] == '(':
+		destination, after, ok := linkDestination(runes, end+1)
+		if !ok {
+			return inline{}, 0, false
+		}
+		href, end = destination, after
+	case end < len(runes) && runes[end] == '[':
+		labelEnd := closingBracket(runes, end)
+		if labelEnd < 0 {
+			return inline{}, 0, false
+		}
+		label := string(runes[end+1 : labelEnd])
+		if label == "" {
+			label = text
+		}
+		reference, ok := p.references[normalizeReference(label)]
+		if !ok {
+			return inline{}, 0, false
+		}
+		href, end = reference.href, labelEnd+1
+	default:
+		reference, ok := p.references[normalizeReference(text)]
+		if !ok {
+			return inline{}, 0, false
+		}
+		href = reference.href
+	}
+
+	return inline{kind: inlineLink, href: href, children: p.inlines(text)}, end, true
+}
+
+// closingBracket returns the index of the bracket closing the one at
+// open, skipping escaped brackets and code spans, or -1.
+func closingBracket(runes []rune, open int) int {
+	depth := 0
+	for i := open; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			i++
+		case '`':
+			count := runLength(runes, i)
+			if end := closingBackticks(runes, i+count, count); end >= 0 {
+				i = end + count - 1
+			} else {
+				i += count - 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// linkDestination parses the destination and the optional title of an
+// inline link, after its opening parenthesis, returning the destination
+// and the index after the closing parenthesis.
+func linkDestination(runes []rune, start int) (string, int, bool) {
+	i := start
+	for i < len(runes) && unicode.IsSpace(runes[i]) {
+		i++
+	}
+
+	var destination strings.Builder
+	if i < len(runes) && runes[i] == '<' {
+		i++
+		for ; i < len(runes) && runes[i] != '>'; i++ {
+			if runes[i] == '\n' {
+				return "", 0, false
+			}
+			destination.WriteRune(runes[i])
+		}
+		if i >= len(runes) {
+			return "", 0, false
+		}
+		i++
+	} else {
+		depth := 0
+		for ; i < len(runes) && !unicode.IsSpace(runes[i]); i++ {
+			c := runes[i]
+			if c == '\\' && i+1 < len(runes) && isAsciiPunct(runes[i+1]) {
+				destination.WriteRune(runes[i+1])
+				i++
+				continue
+			}
+			if c == '(' {
+				depth++
+			}
+			if c == ')' {
+				if depth == 0 {
+					break
+				}
+				depth--
+			}
+			destination.WriteRune(c)
+		}
+	}
+
+	if title := mdLinkTitle.FindString(string(runes[i:])); title != "" {
+		i += len([]rune(title))
+	}
+	for i < len(runes) && unicode.IsSpace(runes[i]) {
+		i++
+	}
+	if i >= len(runes) || runes[i] != ')' {
+		return "", 0, false
+	}
+
+	return html.UnescapeString(destination.String()), i + 1, true
+}
+
+func runLength(runes []rune, start int) int {
+	count := 0
+	for start+count < len(runes) && runes[start+count] == runes[start] {
+		count++
+	}
+
+	return count
+}
+
+// closingBackticks returns the start of the first run of exactly count
+// backticks from start, or -1.
+func closingBackticks(runes []rune, start, count int) int {
+	for i := start; i < len(runes); {
+		if runes[i] != '`' {
+			i++
+			continue
+		}
+		length := runLength(runes, i)
+		if length == count {
+			return i
+		}
+		i += length
+	}
+
+	return -1
+}
+
+// codeSpan returns the content of a code span, with the line endings as
+// spaces and, if padded with spaces on both sides, one of them removed.
+func codeSpan(runes []rune) string {
+	code := strings.ReplaceAll(string(runes), "\n", " ")
+	if len(code) >= 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+		code = code[1 : len(code)-1]
+	}
+
+	return code
+}
+
+// bareLink trims the trailing punctuation of an autolink without angle
+// brackets, and the closing parentheses without an opening one.
+func bareLink(link string) string {
+	link = mdTrailingLink.ReplaceAllString(link, "")
+	for strings.HasSuffix(link, ")") && strings.Count(link, ")") > strings.Count(link, "(") {
+		link = mdTrailingLink.ReplaceAllString(link[:len(link)-1], "")
+	}
+
+	return link
+}
+
+func isLinkBoundary(r rune) bool {
+	return unicode.IsSpace(r) || r == '*' || r == '_' || r == '~' || r == '('
+}
+
+func isAsciiPunct(r rune) bool {
+	return r < unicode.MaxASCII && unicode.IsPunct(r) || strings.ContainsRune("$+<=>^`|~", r)
+}
+
+func isMdPunct(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// newDelimiter creates the delimiter for the run of count characters at
+// start, which can open or close depending on the characters around it.
+func newDelimiter(runes []rune, start, count int) *mdDelimiter {
+	before, after := ' ', ' '
+	if start > 0 {
+		before = runes[start-1]
+	}
+	if start+count < len(runes) {
+		after = runes[start+count]
+	}
+
+	leftFlanking := !unicode.IsSpace(after) && (!isMdPunct(after) || unicode.IsSpace(before) || isMdPunct(before))
+	rightFlanking := !unicode.IsSpace(before) && (!isMdPunct(before) || unicode.IsSpace(after) || isMdPunct(after))
+
+	d := &mdDelimiter{char: runes[start], count: count, original: count, canOpen: leftFlanking, canClose: rightFlanking}
+	if d.char == '_' {
+		// an underscore does not emphasise inside words
+		d.canOpen = leftFlanking && (!rightFlanking || isMdPunct(before))
+		d.canClose = rightFlanking && (!leftFlanking || isMdPunct(after))
+	}
+
+	return d
+}
+
+// processEmphasis matches the delimiters, from the first closer, with
+// the nearest opener, as in CommonMark. Strikethrough, which documents do
+// not have, is dropped keeping its content.
+func processEmphasis(nodes []mdNode) []mdNode {
+	for closer := 0; closer < len(nodes); closer++ {
+		c := nodes[closer].delimiter
+		if c == nil || !c.canClose {
+			continue
+		}
+
+		for c.count > 0 {
+			opener := -1
+			for j := closer - 1; j >= 0; j-- {
+				o := nodes[j].delimiter
+				if o == nil || o.char != c.char || !o.canOpen || o.count == 0 {
+					continue
+				}
+				if c.char == '~' && o.count != c.count {
+					continue
+				}
+				if c.char != '~' && (o.canClose || c.canOpen) && (o.original+c.original)%3 == 0 &&
+					(o.original%3 != 0 || c.original%3 != 0) {
+					continue
+				}
+				opener = j
+				break
+			}
+			if opener < 0 {
+				break
+			}
+
+			o := nodes[opener].delimiter
+			children := mdInlines(nodes[opener+1 : closer])
+			wrapped := []mdNode{}
+			switch {
+			case c.char == '~':
+				o.count, c.count = 0, 0
+				for _, child := range children {
+					wrapped = append(wrapped, mdNode{inline: child})
+				}
+			case o.count >= 2 && c.count >= 2:
+				o.count, c.count = o.count-2, c.count-2
+				wrapped = append(wrapped, mdNode{inline: inline{kind: inlineStrong, children: children}})
+			default:
+				o.count, c.count = o.count-1, c.count-1
+				wrapped = append(wrapped, mdNode{inline: inline{kind: inlineEmphasis, children: children}})
+			}
+
+			rest := append(wrapped, nodes[closer:]...)
+			nodes = append(nodes[:opener+1], rest...)
+			closer = opener + 1 + len(wrapped)
+		}
+	}
+
+	return nodes
+}
+
+// mdInlines returns the inlines of nodes, the delimiters not matched
+// being text, merging the consecutive texts.
+func mdInlines(nodes []mdNode) []inline {
+	inlines := []inline{}
+	for _, node := range nodes {
+		current := node.inline
+		if node.delimiter != nil {
+			if node.delimiter.count == 0 {
+				continue
+			}
+			current = textInline(strings.Repeat(string(node.delimiter.char), node.delimiter.count))
+		}
+
+		if last := len(inlines) - 1; last >= 0 && current.kind == inlineText && inlines[last].kind == inlineText {
+			inlines[last].text += current.text
+			continue
+		}
+		inlines = append(inlines, current)
+	}
+
+	return inlines
+}
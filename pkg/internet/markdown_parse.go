@@ -0,0 +1,502 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"regexp"
+	"strings"
+)
+
+// The Markdown parser follows CommonMark with the GitHub extensions for
+// tables, task lists, strikethrough and autolinks, covering the syntax
+// used in posts. Lists are not nested in documents, so the items of
+// nested lists follow the item they are in, and raw HTML is dropped.
+
+var (
+	mdFence          = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})\\s*([^`\\s]*)[^`]*$")
+	mdAtxHeading     = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))??(?:[ \t]+#+)?[ \t]*$`)
+	mdSetextHeading  = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	mdThematicBreak  = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	mdBlockquote     = regexp.MustCompile(`^ {0,3}> ?`)
+	mdListItem       = regexp.MustCompile(`^( {0,3})([-+*]|\d{1,9}[.)])([ \t]{1,4}|$)(.*)$`)
+	mdTaskItem       = regexp.MustCompile(`^\[([ xX])\][ \t]+`)
+	mdTableDelimiter = regexp.MustCompile(`^ {0,3}\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	mdHtmlBlock      = regexp.MustCompile(`^ {0,3}<(?:!--|/?[A-Za-z][A-Za-z0-9-]*(?:[ \t/>]|$))`)
+	mdReference      = regexp.MustCompile(`^ {0,3}\[([^\]]+)\]:[ \t]*<?([^\s>]+)>?(?:[ \t]+(?:"[^"]*"|'[^']*'|\([^)]*\)))?[ \t]*$`)
+	mdImageParagraph = regexp.MustCompile(`^(?:\[)?!\[([^\]]*)\]\([ \t]*<?([^\s>)]+)>?(?:[ \t]+"[^"]*")?[ \t]*\)(?:\]\([ \t]*<?([^\s>)]+)>?[ \t]*\))?$`)
+)
+
+// markdownReference is the destination of a link reference definition.
+type markdownReference struct {
+	href string
+}
+
+type markdownParser struct {
+	references map[string]markdownReference
+}
+
+// parseMarkdown parses a Markdown document. A level 1 heading at its
+// start is the title.
+func parseMarkdown(source string) document {
+	source = strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(source)
+	lines := strings.Split(source, "\n")
+	for i, line := range lines {
+		lines[i] = expandIndentTabs(line)
+	}
+
+	p := markdownParser{references: map[string]markdownReference{}}
+	lines = p.collectReferences(lines)
+	blocks := p.blocks(lines)
+
+	d := document{}
+	if len(blocks) > 0 && blocks[0].kind == blockHeading && blocks[0].level == 1 {
+		d.title = plainText(blocks[0].inlines)
+		blocks = blocks[1:]
+	}
+	d.blocks = blocks
+
+	return d
+}
+
+// expandIndentTabs replaces the tabs of the indentation of a line with
+// spaces, up to the next multiple of 4 columns.
+func expandIndentTabs(line string) string {
+	var builder strings.Builder
+	column := 0
+	for i, c := range line {
+		switch c {
+		case ' ':
+			builder.WriteByte(' ')
+			column++
+		case '\t':
+			spaces := 4 - column%4
+			builder.WriteString(strings.Repeat(" ", spaces))
+			column += spaces
+		default:
+			builder.WriteString(line[i:])
+			return builder.String()
+		}
+	}
+
+	return builder.String()
+}
+
+// collectReferences removes the link reference definitions, outside
+// code blocks, keeping their destinations.
+func (p markdownParser) collectReferences(lines []string) []string {
+	kept := []string{}
+	fence := ""
+	for _, line := range lines {
+		if m := mdFence.FindStringSubmatch(line); m != nil {
+			if fence == "" {
+				fence = m[2]
+			} else if strings.HasPrefix(m[2], fence[:1]) && len(m[2]) >= len(fence) && m[3] == "" {
+				fence = ""
+			}
+		}
+
+		if m := mdReference.FindStringSubmatch(line); m != nil && fence == "" {
+			label := normalizeReference(m[1])
+			if _, ok := p.references[label]; !ok {
+				p.references[label] = markdownReference{href: m[2]}
+			}
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	return kept
+}
+
+func normalizeReference(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), " "))
+}
+
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// startsBlock tells if a line starts a block that interrupts a
+// paragraph.
+func startsBlock(line string) bool {
+	return mdFence.MatchString(line) || mdAtxHeading.MatchString(line) || mdThematicBreak.MatchString(line) ||
+		mdBlockquote.MatchString(line) || mdListItem.MatchString(line)
+}
+
+func (p markdownParser) blocks(lines []string) []block {
+	blocks := []block{}
+	paragraph := []string{}
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, p.paragraph(strings.Join(paragraph, "\n")))
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		if isBlank(line) {
+			flush()
+			i++
+			continue
+		}
+
+		if m := mdFence.FindStringSubmatch(line); m != nil {
+			flush()
+			b, end := fencedCode(lines, i, m)
+			blocks = append(blocks, b)
+			i = end
+			continue
+		}
+
+		if len(paragraph) == 0 && indentation(line) >= 4 {
+			b, end := indentedCode(lines, i)
+			blocks = append(blocks, b)
+			i = end
+			continue
+		}
+
+		if m := mdAtxHeading.FindStringSubmatch(line); m != nil {
+			flush()
+			blocks = append(blocks, block{kind: blockHeading, level: len(m[1]), inlines: p.inlines(m[2])})
+			i++
+			continue
+		}
+
+		if m := mdSetextHeading.FindStringSubmatch(line); m != nil && len(paragraph) > 0 {
+			level := 1
+			if m[1][0] == '-' {
+				level = 2
+			}
+			blocks = append(blocks, block{kind: blockHeading, level: level, inlines: p.inlines(strings.Join(paragraph, "\n"))})
+			paragraph = nil
+			i++
+			continue
+		}
+
+		if mdThematicBreak.MatchString(line) {
+			flush()
+			blocks = append(blocks, block{kind: blockRule})
+			i++
+			continue
+		}
+
+		if mdBlockquote.MatchString(line) {
+			flush()
+			b, end := p.blockquote(lines, i)
+			blocks = append(blocks, b)
+			i = end
+			continue
+		}
+
+		if mdListItem.MatchString(line) {
+			flush()
+			b, end := p.list(lines, i)
+			blocks = append(blocks, b)
+			i = end
+			continue
+		}
+
+		if i+1 < len(lines) && strings.Contains(line, "|") && mdTableDelimiter.MatchString(lines[i+1]) {
+			flush()
+			b, end := p.table(lines, i)
+			blocks = append(blocks, b)
+			i = end
+			continue
+		}
+
+		if len(paragraph) == 0 && mdHtmlBlock.MatchString(line) {
+			i = htmlBlockEnd(lines, i)
+			continue
+		}
+
+		paragraph = append(paragraph, strings.TrimLeft(line, " "))
+		i++
+	}
+	flush()
+
+	return blocks
+}
+
+// paragraph parses the text of a paragraph, which is an image if it has
+// nothing else.
+func (p markdownParser) paragraph(text string) block {
+	text = strings.TrimSpace(text)
+	if m := mdImageParagraph.FindStringSubmatch(text); m != nil && (m[3] == "" || strings.HasPrefix(text, "[")) {
+		return block{kind: blockImage, alt: m[1], src: m[2], href: m[3]}
+	}
+
+	return block{kind: blockParagraph, inlines: p.inlines(text)}
+}
+
+func fencedCode(lines []string, start int, opening []string) (block, int) {
+	indent, fence, lang := len(opening[1]), opening[2], opening[3]
+
+	code := []string{}
+	i := start + 1
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if indentation(line) < 4 && strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			i++
+			break
+		}
+
+		// the indentation of the fence is removed from the lines
+		remove := indentation(line)
+		if remove > indent {
+			remove = indent
+		}
+		code = append(code, line[remove:])
+	}
+
+	return block{kind: blockCode, code: strings.Join(code, "\n"), lang: lang}, i
+}
+
+func indentedCode(lines []string, start int) (block, int) {
+	code := []string{}
+	i := start
+	for ; i < len(lines) && (isBlank(lines[i]) || indentation(lines[i]) >= 4); i++ {
+		if isBlank(lines[i]) {
+			code = append(code, "")
+			continue
+		}
+		code = append(code, lines[i][4:])
+	}
+
+	for len(code) > 0 && code[len(code)-1] == "" {
+		code = code[:len(code)-1]
+	}
+
+	return block{kind: blockCode, code: strings.Join(code, "\n")}, i
+}
+
+// blockquote parses a block quote, including the lazy continuation
+// lines of its paragraphs. Its blocks are joined in a single quote.
+func (p markdownParser) blockquote(lines []string, start int) (block, int) {
+	inner := []string{}
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if loc := mdBlockquote.FindStringIndex(line); loc != nil {
+			inner = append(inner, line[loc[1]:])
+			continue
+		}
+		if isBlank(line) || startsBlock(line) || len(inner) == 0 || isBlank(inner[len(inner)-1]) {
+			break
+		}
+		inner = append(inner, line)
+	}
+
+	return block{kind: blockQuote, inlines: joinBlocks(p.blocks(inner))}, i
+}
+
+// list parses the consecutive items of a list with the same kind of
+// marker.
+func (p markdownParser) list(lines []string, start int) (block, int) {
+	first := mdListItem.FindStringSubmatch(lines[start])
+	ordered := isDigit(first[2][0])
+	marker := first[2][len(first[2])-1:]
+
+	b := block{kind: blockList, ordered: ordered}
+	i := start
+	for i < len(lines) {
+		m := mdListItem.FindStringSubmatch(lines[i])
+		if m == nil || mdThematicBreak.MatchString(lines[i]) || isDigit(m[2][0]) != ordered || m[2][len(m[2])-1:] != marker {
+			break
+		}
+
+		contentIndent := len(m[1]) + len(m[2]) + len(m[3])
+		if m[3] == "" {
+			contentIndent++
+		}
+
+		item := []string{m[4]}
+		i++
+		for i < len(lines) {
+			line := lines[i]
+			if isBlank(line) {
+				next := i + 1
+				for next < len(lines) && isBlank(lines[next]) {
+					next++
+				}
+				if next < len(lines) && indentation(lines[next]) >= contentIndent {
+					item = append(item, "")
+					i++
+					continue
+				}
+				break
+			}
+			if indentation(line) >= contentIndent {
+				item = append(item, line[contentIndent:])
+				i++
+				continue
+			}
+			if startsBlock(line) || isBlank(item[len(item)-1]) {
+				break
+			}
+			item = append(item, strings.TrimLeft(line, " "))
+			i++
+		}
+
+		b.items = append(b.items, p.listItem(item)...)
+
+		// a blank line between the items does not end the list
+		if i < len(lines) && isBlank(lines[i]) {
+			next := i + 1
+			for next < len(lines) && isBlank(lines[next]) {
+				next++
+			}
+			if next < len(lines) && mdListItem.MatchString(lines[next]) {
+				i = next
+			}
+		}
+	}
+
+	return b, i
+}
+
+// listItem parses the lines of an item, returning its content followed
+// by the items of its nested lists. Task list items are prefixed with a
+// ballot box.
+func (p markdownParser) listItem(lines []string) [][]inline {
+	if m := mdTaskItem.FindStringSubmatch(lines[0]); m != nil {
+		box := "☐ "
+		if m[1] != " " {
+			box = "☑ "
+		}
+		lines[0] = box + lines[0][len(m[0]):]
+	}
+
+	content := []block{}
+	nested := [][]inline{}
+	for _, b := range p.blocks(lines) {
+		if b.kind == blockList {
+			nested = append(nested, b.items...)
+			continue
+		}
+		content = append(content, b)
+	}
+
+	items := [][]inline{}
+	if inlines := joinBlocks(content); len(inlines) > 0 {
+		items = append(items, inlines)
+	}
+
+	return append(items, nested...)
+}
+
+// table parses a table, its first line being the header.
+func (p markdownParser) table(lines []string, start int) (block, int) {
+	b := block{kind: blockTable}
+	b.rows = append(b.rows, p.tableRow(lines[start]))
+
+	i := start + 2
+	for ; i < len(lines) && !isBlank(lines[i]) && !startsBlock(lines[i]); i++ {
+		b.rows = append(b.rows, p.tableRow(lines[i]))
+	}
+
+	return b, i
+}
+
+// tableRow splits a table row in cells, at the pipes not escaped.
+func (p markdownParser) tableRow(line string) [][]inline {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	cells := [][]inline{}
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, p.inlines(strings.TrimSpace(cell.String())))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+
+	return append(cells, p.inlines(strings.TrimSpace(cell.String())))
+}
+
+// htmlBlockEnd returns the line after a block of HTML, which ends at a
+// blank line or, for comments, at the end of the comment.
+func htmlBlockEnd(lines []string, start int) int {
+	if strings.HasPrefix(strings.TrimSpace(lines[start]), "<!--") {
+		for i := start; i < len(lines); i++ {
+			if strings.Contains(lines[i], "-->") {
+				return i + 1
+			}
+		}
+		return len(lines)
+	}
+
+	i := start
+	for i < len(lines) && !isBlank(lines[i]) {
+		i++
+	}
+
+	return i
+}
+
+// joinBlocks joins the content of blocks in a single paragraph, one line
+// each.
+func joinBlocks(blocks []block) []inline {
+	inlines := []inline{}
+	for _, b := range blocks {
+		content := b.inlines
+		switch b.kind {
+		case blockCode:
+			content = []inline{textInline(b.code)}
+		case blockImage:
+			content = []inline{textInline(b.alt)}
+		case blockList:
+			content = nil
+			for i, item := range b.items {
+				if i > 0 {
+					content = append(content, textInline("\n"))
+				}
+				content = append(content, item...)
+			}
+		}
+		if len(content) == 0 {
+			continue
+		}
+
+		if len(inlines) > 0 {
+			inlines = append(inlines, textInline("\n"))
+		}
+		inlines = append(inlines, content...)
+	}
+
+	return inlines
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
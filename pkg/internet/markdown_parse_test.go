@@ -0,0 +1,198 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMarkdownRoundTrip(t *testing.T) {
+	// arrange
+	markdown := markdownRenderer{}.render(sampleDocument())
+
+	// act
+	d := parseMarkdown(markdown)
+
+	// assert
+	assert.Equal(t, "Go tips", d.title)
+	assert.Equal(t, "By Renato Torres", plainText(d.blocks[0].inlines))
+	d.blocks = d.blocks[1:]
+	d.author = "Renato Torres"
+	assert.Equal(t, markdown, markdownRenderer{}.render(d))
+}
+
+func TestParseMarkdownBlocks(t *testing.T) {
+	testCases := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{"atx heading", "### Small things ###", "### Small things"},
+		{"setext heading", "Small things\n---", "## Small things"},
+		{"paragraph lines", "one\ntwo", "one two"},
+		{"hard break", "one  \ntwo", "one\ntwo"},
+		{"fenced code", "~~~ go\nfunc main() {}\n~~~", "```go\nfunc main() {}\n```"},
+		{"indented code", "    x := 1\n\n    y := 2", "```\nx := 1\n\ny := 2\n```"},
+		{"bullet list", "* one\n* two", "- one\n- two"},
+		{"ordered list", "3) one\n4) two", "1. one\n2. two"},
+		{"nested list", "- one\n  - two", "- one\n- two"},
+		{"task list", "- [ ] todo\n- [x] done", "- ☐ todo\n- ☑ done"},
+		{"blockquote", "> one\ntwo", "> one two"},
+		{"rule", "***", "---"},
+		{"table", "a | b\n:-- | --:\n1 | 2", "| a | b |\n| --- | --- |\n| 1 | 2 |"},
+		{"image", "![alt](https://x.com/a.png \"title\")", "![alt](https://x.com/a.png)"},
+		{"linked image", "[![alt](a.png)](https://x.com)", "[![alt](a.png)](https://x.com)"},
+		{"html comment", "<!-- hidden -->", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			d := parseMarkdown("# Title\n\n" + tc.markdown)
+
+			// assert
+			output := []string{}
+			for _, b := range d.blocks {
+				output = append(output, markdownRenderer{}.block(b))
+			}
+			if tc.want == "" {
+				assert.Empty(t, output)
+			} else {
+				assert.Equal(t, []string{tc.want}, output)
+			}
+		})
+	}
+}
+
+func TestParseMarkdownInlines(t *testing.T) {
+	testCases := []struct {
+		markdown string
+		want     string
+	}{
+		{"a *b* _c_ **d** __e__ ***f***", "a *b* *c* **d** **e** ***f***"},
+		{"**a *b* c**", "**a *b* c**"},
+		{"snake_case_name and 2*3*4", "snake\\_case\\_name and 2*3*4"},
+		{"x `a``b` y", "x `` a``b `` y"},
+		{"\\*not emphasis\\*", "\\*not emphasis\\*"},
+		{"[Go](https://go.dev \"The Go site\")", "[Go](https://go.dev)"},
+		{"[Go][ref] and [ref] and [Ref][]\n\n[ref]: https://go.dev", "[Go](https://go.dev) and [ref](https://go.dev) and [Ref](https://go.dev)"},
+		{"[missing][nowhere]", "\\[missing\\]\\[nowhere\\]"},
+		{"<https://go.dev> <gopher@go.dev>", "[https://go.dev](https://go.dev) [gopher@go.dev](mailto:gopher@go.dev)"},
+		{"see www.go.dev/doc.", "see [www.go.dev/doc](http://www.go.dev/doc)."},
+		{"~~gone~~ &amp; &copy; &#35;", "gone & © #"},
+		{"a <span>b</span> c", "a b c"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.markdown, func(t *testing.T) {
+			// act
+			d := parseMarkdown(tc.markdown)
+
+			// assert
+			assert.Len(t, d.blocks, 1)
+			assert.Equal(t, tc.want, markdownRenderer{}.inlines(d.blocks[0].inlines))
+		})
+	}
+}
+
+var (
+	// the line endings and indentation between tags, which the spec and
+	// the HTML renderer write differently
+	specHtmlSpaces = regexp.MustCompile(`>\s+<|\s+</code>`)
+	// soft line breaks are line endings in the spec and spaces in the
+	// documents, both shown as spaces
+	specHtmlParagraph = regexp.MustCompile(`(?s)<p>.*?</p>`)
+)
+
+// normalizeSpecHtml makes the HTML of the spec and of the renderer
+// comparable: without line endings between tags nor soft line breaks,
+// with void elements written without the slash, quotes escaped the same
+// way and without the rel of the links.
+func normalizeSpecHtml(html string) string {
+	html = strings.NewReplacer(" />", ">", "&#34;", "&quot;", ` rel="nofollow noopener"`, "").Replace(html)
+	html = specHtmlParagraph.ReplaceAllStringFunc(html, func(paragraph string) string {
+		return strings.ReplaceAll(paragraph, "\n", " ")
+	})
+	return specHtmlSpaces.ReplaceAllStringFunc(html, func(spaces string) string {
+		return strings.TrimSpace(spaces[:1]) + strings.TrimSpace(spaces[1:])
+	})
+}
+
+func TestParseMarkdownCommonMarkExamples(t *testing.T) {
+	// examples of the CommonMark and GitHub Flavored Markdown specs for
+	// the constructs the document model has
+	testCases := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{"thematic breaks", "***\n---\n___", "<hr />\n<hr />\n<hr />"},
+		{"thematic break with spaces", " - - -", "<hr />"},
+		{"atx headings", "## foo\n### foo\n#### foo\n##### foo\n###### foo", "<h2>foo</h2>\n<h3>foo</h3>\n<h4>foo</h4>\n<h5>foo</h5>\n<h6>foo</h6>"},
+		{"atx heading closing sequence", "## foo ##\n  ###   bar    ###", "<h2>foo</h2>\n<h3>bar</h3>"},
+		{"setext heading", "Foo *bar*\n---------", "<h2>Foo <em>bar</em></h2>"},
+		{"indented code", "    a simple\n      indented code block", "<pre><code>a simple\n  indented code block\n</code></pre>"},
+		{"fenced code", "```\n<\n >\n```", "<pre><code>&lt;\n &gt;\n</code></pre>"},
+		{"fenced code with tildes", "~~~\naaa\n~~~", "<pre><code>aaa\n</code></pre>"},
+		{"fenced code info string", "```ruby\ndef foo(x)\n  return 3\nend\n```", "<pre><code class=\"language-ruby\">def foo(x)\n  return 3\nend\n</code></pre>"},
+		{"paragraphs", "aaa\n\nbbb", "<p>aaa</p>\n<p>bbb</p>"},
+		{"blockquote lazy continuation", "> bar\nbaz\n> foo", "<blockquote>\n<p>bar\nbaz\nfoo</p>\n</blockquote>"},
+		{"bullet list", "- foo\n- bar", "<ul>\n<li>foo</li>\n<li>bar</li>\n</ul>"},
+		{"ordered list", "1. foo\n2. bar", "<ol>\n<li>foo</li>\n<li>bar</li>\n</ol>"},
+		{"backslash escapes", "\\*not emphasized*\n\\[not a link](/foo)", "<p>*not emphasized*\n[not a link](/foo)</p>"},
+		{"entity references", "&amp; &copy; &AElig; &#35; &#x22;", "<p>&amp; © Æ # &quot;</p>"},
+		{"code spans", "`` foo ` bar ``", "<p><code>foo ` bar</code></p>"},
+		{"code span line endings", "``\nfoo\nbar  \nbaz\n``", "<p><code>foo bar   baz</code></p>"},
+		{"emphasis", "*foo bar*", "<p><em>foo bar</em></p>"},
+		{"not emphasis after a space", "a * foo bar*", "<p>a * foo bar*</p>"},
+		{"no emphasis inside words with underscores", "foo_bar_", "<p>foo_bar_</p>"},
+		{"emphasis inside words with asterisks", "foo*bar*", "<p>foo<em>bar</em></p>"},
+		{"strong emphasis", "**foo bar**", "<p><strong>foo bar</strong></p>"},
+		{"strong emphasis with underscores", "__foo bar__", "<p><strong>foo bar</strong></p>"},
+		{"nested emphasis", "*foo **bar** baz*", "<p><em>foo <strong>bar</strong> baz</em></p>"},
+		{"inline link", "[link](/uri)", "<p><a href=\"/uri\">link</a></p>"},
+		{"inline link with angle brackets", "[link](<foo bar>)", "<p><a href=\"foo%20bar\">link</a></p>"},
+		{"full reference link", "[foo][bar]\n\n[bar]: /url", "<p><a href=\"/url\">foo</a></p>"},
+		{"collapsed reference link", "[foo][]\n\n[foo]: /url", "<p><a href=\"/url\">foo</a></p>"},
+		{"shortcut reference link", "[Foo]\n\n[foo]: /url", "<p><a href=\"/url\">Foo</a></p>"},
+		{"uri autolink", "<http://foo.bar.baz>", "<p><a href=\"http://foo.bar.baz\">http://foo.bar.baz</a></p>"},
+		{"email autolink", "<foo@bar.example.com>", "<p><a href=\"mailto:foo@bar.example.com\">foo@bar.example.com</a></p>"},
+		{"hard line break", "foo  \nbaz", "<p>foo<br />\nbaz</p>"},
+		{"soft line break", "foo\nbaz", "<p>foo\nbaz</p>"},
+		{"table", "| foo | bar |\n| --- | --- |\n| baz | bim |", "<table>\n<thead>\n<tr>\n<th>foo</th>\n<th>bar</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td>baz</td>\n<td>bim</td>\n</tr>\n</tbody>\n</table>"},
+		{"extended www autolink", "www.commonmark.org", "<p><a href=\"http://www.commonmark.org\">www.commonmark.org</a></p>"},
+		{"extended url autolink", "Visit https://encrypted.google.com/search?q=Markup+(business)", "<p>Visit <a href=\"https://encrypted.google.com/search?q=Markup+(business)\">https://encrypted.google.com/search?q=Markup+(business)</a></p>"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			d := parseMarkdown("# Title\n\n" + tc.markdown)
+
+			// assert
+			output := []string{}
+			for _, b := range d.blocks {
+				output = append(output, htmlRenderer{}.block(b))
+			}
+			assert.Equal(t, normalizeSpecHtml(tc.want), normalizeSpecHtml(strings.Join(output, "\n")))
+		})
+	}
+}
@@ -0,0 +1,380 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+const (
+	defaultBulkParallelism = 2
+	mediumPostsPageSize    = 25
+	// maxBulkRateLimit is the highest rate limit, in posts per second
+	maxBulkRateLimit = 1000
+)
+
+var (
+	mediumUsername        = regexp.MustCompile(`^[A-Za-z0-9_.]{1,30}$`)
+	mediumPublicationSlug = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,99}$`)
+	// first path segments of medium.com URLs that are not publications
+	mediumReservedPaths = map[string]bool{"p": true, "m": true, "me": true, "tag": true, "tags": true, "search": true, "topics": true}
+)
+
+// mediumSource is a user or a publication, whose posts are exported in
+// bulk.
+type mediumSource struct {
+	kind string
+	name string
+}
+
+func (m mediumSource) String() string {
+	if m.kind == "user" {
+		return "@" + m.name
+	}
+
+	return m.name
+}
+
+type mediumPostSummary struct {
+	Id    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type mediumPostsResponse struct {
+	Data struct {
+		User       *mediumPostsOwner `json:"user"`
+		Collection *mediumPostsOwner `json:"collection"`
+	} `json:"data"`
+}
+
+type mediumPostsOwner struct {
+	Id                      string `json:"id"`
+	Name                    string `json:"name"`
+	HomepagePostsConnection struct {
+		Posts      []mediumPostSummary `json:"posts"`
+		PagingInfo struct {
+			Next *struct {
+				From  string `json:"from"`
+				Limit int    `json:"limit"`
+			} `json:"next"`
+		} `json:"pagingInfo"`
+	} `json:"homepagePostsConnection"`
+}
+
+// ExportMediumPosts exports all the posts of a Medium user or
+// publication, like ExportMediumToMd does for a single post. The source
+// is a profile or publication URL, or a username prefixed with @.
+//
+// A post failing does not stop the others, its error is in its result.
+// If the export is interrupted, the partial output is returned with the
+// checkpoint to resume from.
+func (s *Service) ExportMediumPosts(source string, options internet.ExportMediumPostsOptions) (internet.ExportMediumPostsOutput, error) {
+	return s.ExportMediumPostsContext(context.Background(), source, options)
+}
+
+func (s *Service) ExportMediumPostsContext(ctx context.Context, source string, options internet.ExportMediumPostsOptions) (internet.ExportMediumPostsOutput, error) {
+	output := internet.ExportMediumPostsOutput{Source: source}
+
+	_, err := validateExportOptions(options.ExportMediumToMdOptions)
+	if err != nil {
+		return output, err
+	}
+	if !(options.RateLimit >= 0 && options.RateLimit <= maxBulkRateLimit) {
+		return output, fmt.Errorf("invalid rate limit %v, it must be between 0 and %d posts per second", options.RateLimit, maxBulkRateLimit)
+	}
+
+	src, err := parseMediumSource(source)
+	if err != nil {
+		return output, err
+	}
+	output.Kind = src.kind
+
+	if options.Checkpoint.Source != "" && options.Checkpoint.Source != src.String() {
+		return output, fmt.Errorf("the checkpoint is of %s, not of %s", options.Checkpoint.Source, src.String())
+	}
+
+	name, posts, err := s.listMediumPosts(ctx, src)
+	if err != nil {
+		return output, fmt.Errorf("error listing posts: %w", err)
+	}
+	output.Name = name
+
+	output.Checkpoint = internet.MediumExportCheckpoint{
+		Source: src.String(),
+		Done:   append([]string{}, options.Checkpoint.Done...),
+	}
+	done := map[string]bool{}
+	for _, id := range options.Checkpoint.Done {
+		done[id] = true
+	}
+
+	results := make([]internet.ExportMediumPostsResult, len(posts))
+	attempted := make([]bool, len(posts))
+
+	var mutex sync.Mutex
+	report := func(i int, result internet.ExportMediumPostsResult) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		results[i] = result
+		attempted[i] = true
+		switch {
+		case result.Skipped:
+			output.Skipped++
+		case result.Err != nil:
+			output.Failed++
+		default:
+			output.Exported++
+			output.Checkpoint.Done = append(output.Checkpoint.Done, result.PostId)
+		}
+
+		if options.OnResult != nil {
+			checkpoint := output.Checkpoint
+			checkpoint.Done = append([]string{}, checkpoint.Done...)
+			options.OnResult(result, checkpoint)
+		}
+	}
+
+	parallelism := options.Parallelism
+	if parallelism <= 0 {
+		parallelism = defaultBulkParallelism
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				result := internet.ExportMediumPostsResult{PostId: posts[job].Id, Title: posts[job].Title}
+				result.Export, result.Err = s.ExportMediumToMdContext(ctx, posts[job].Id, options.ExportMediumToMdOptions)
+				report(job, result)
+			}
+		}()
+	}
+
+	var ticker *time.Ticker
+	if options.RateLimit > 0 {
+		ticker = time.NewTicker(time.Duration(float64(time.Second) / options.RateLimit))
+		defer ticker.Stop()
+	}
+
+	started := 0
+	for i, post := range posts {
+		if done[post.Id] {
+			report(i, internet.ExportMediumPostsResult{PostId: post.Id, Title: post.Title, Skipped: true})
+			continue
+		}
+
+		if ticker != nil && started > 0 {
+			select {
+			case <-ctx.Done():
+			case <-ticker.C:
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+		case jobs <- i:
+			started++
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, result := range results {
+		if attempted[i] {
+			output.Results = append(output.Results, result)
+		}
+	}
+
+	return output, ctx.Err()
+}
+
+// parseMediumSource parses a Medium profile or publication URL, or a
+// username prefixed with @.
+func parseMediumSource(input string) (mediumSource, error) {
+	value := strings.TrimSpace(input)
+	invalid := &internet.InvalidMediumInputError{Input: input, Reason: "not a Medium user nor publication"}
+
+	if strings.HasPrefix(value, "@") {
+		return newMediumSource("user", value[1:], invalid)
+	}
+
+	if !strings.Contains(value, "://") {
+		value = "https://" + value
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return mediumSource{}, invalid
+	}
+
+	host := strings.ToLower(u.Hostname())
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	switch {
+	case host == "medium.com" || host == "www.medium.com":
+		first := segments[0]
+		if strings.HasPrefix(first, "@") {
+			return newMediumSource("user", first[1:], invalid)
+		}
+		if mediumReservedPaths[first] {
+			return mediumSource{}, invalid
+		}
+		return newMediumSource("publication", first, invalid)
+	case strings.HasSuffix(host, ".medium.com") && host != mediumShortLinkHost:
+		return newMediumSource("user", strings.TrimSuffix(host, ".medium.com"), invalid)
+	}
+
+	return mediumSource{}, invalid
+}
+
+func newMediumSource(kind, name string, invalid error) (mediumSource, error) {
+	valid := mediumUsername
+	if kind == "publication" {
+		valid = mediumPublicationSlug
+	}
+	if !valid.MatchString(name) {
+		return mediumSource{}, invalid
+	}
+
+	return mediumSource{kind: kind, name: name}, nil
+}
+
+// listMediumPosts returns the name of the user or publication and all
+// its posts, going through all the pages.
+func (s *Service) listMediumPosts(ctx context.Context, source mediumSource) (string, []mediumPostSummary, error) {
+	name := ""
+	posts := []mediumPostSummary{}
+	seen := map[string]bool{}
+
+	cursor := ""
+	cursors := map[string]bool{cursor: true}
+	for {
+		owner, err := s.getPostsPage(ctx, source, cursor)
+		if err != nil {
+			return name, posts, err
+		}
+
+		name = owner.Name
+		added := 0
+		for _, post := range owner.HomepagePostsConnection.Posts {
+			if !seen[post.Id] {
+				seen[post.Id] = true
+				posts = append(posts, post)
+				added++
+			}
+		}
+
+		// a cursor seen before or a page without new posts would never end
+		next := owner.HomepagePostsConnection.PagingInfo.Next
+		if next == nil || cursors[next.From] || added == 0 {
+			return name, posts, nil
+		}
+		cursor = next.From
+		cursors[cursor] = true
+	}
+}
+
+// mediumPostsQuery returns the query of a page of the posts of a user
+// or publication, starting at the cursor.
+func mediumPostsQuery(source mediumSource, cursor string) (graphQLRequest, error) {
+	paging := map[string]interface{}{"limit": mediumPostsPageSize}
+	if cursor != "" {
+		paging["from"] = cursor
+	}
+
+	connection := gqlField("homepagePostsConnection",
+		gqlField("posts", gqlField("id"), gqlField("title")),
+		gqlField("pagingInfo", gqlField("next", gqlField("from"), gqlField("limit"))),
+	).withArgument("paging", "paging")
+
+	query := graphQLQuery{
+		operation: "UserPostsQuery",
+		variables: []graphQLVariable{
+			{name: "username", typ: "ID!", value: source.name},
+			{name: "paging", typ: "PagingOptions", value: paging},
+		},
+		fields: []graphQLField{
+			gqlField("user", gqlField("id"), gqlField("name"), connection).withArgument("username", "username"),
+		},
+	}
+	if source.kind == "publication" {
+		query.operation = "PublicationPostsQuery"
+		query.variables[0].name = "slug"
+		query.fields = []graphQLField{
+			gqlField("collection", gqlField("id"), gqlField("name"), connection).withArgument("slug", "slug"),
+		}
+	}
+
+	return query.request()
+}
+
+func (s *Service) getPostsPage(ctx context.Context, source mediumSource, cursor string) (mediumPostsOwner, error) {
+	owner := mediumPostsOwner{}
+
+	query, err := mediumPostsQuery(source, cursor)
+	if err != nil {
+		return owner, err
+	}
+
+	data, err := json.Marshal(query)
+	if err != nil {
+		return owner, fmt.Errorf("error marshling request: %s", err.Error())
+	}
+
+	field := "user"
+	if source.kind == "publication" {
+		field = "collection"
+	}
+
+	response, err := s.queryMedium(ctx, data, "", field, nil)
+	var notFound *internet.MediumPostNotFoundError
+	if errors.As(err, &notFound) {
+		return owner, &internet.MediumSourceNotFoundError{Kind: source.kind, Name: source.name}
+	}
+	if err != nil {
+		return owner, err
+	}
+
+	posts := mediumPostsResponse{}
+	err = json.Unmarshal(response.body, &posts)
+	if err != nil {
+		return owner, fmt.Errorf("error un-marshalling medium response: %s", err.Error())
+	}
+
+	if source.kind == "publication" {
+		return *posts.Data.Collection, nil
+	}
+
+	return *posts.Data.User, nil
+}
@@ -0,0 +1,261 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBulkService(t *testing.T) (*Service, func()) {
+	mediumServer := newMediumServer(t)
+	imagesServer, _ := newImagesServer(t, 0)
+	s := newMediumService(mediumServer, WithMediumImagesEndpoint(imagesServer.URL), WithGistClient(stubGistClient{}))
+
+	return s, func() {
+		mediumServer.Close()
+		imagesServer.Close()
+	}
+}
+
+func TestExportMediumPosts(t *testing.T) {
+	// arrange
+	s, closeServers := newBulkService(t)
+	defer closeServers()
+
+	// act
+	output, err := s.ExportMediumPosts("https://medium.com/@renato0307", internet.ExportMediumPostsOptions{Parallelism: 3})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "user", output.Kind)
+	assert.Equal(t, "Renato Torres", output.Name)
+	assert.Equal(t, 3, output.Exported)
+	assert.Equal(t, 1, output.Failed)
+	assert.Equal(t, 0, output.Skipped)
+	assert.Len(t, output.Results, 4)
+
+	ids := []string{}
+	for _, result := range output.Results {
+		ids = append(ids, result.PostId)
+	}
+	assert.Equal(t, []string{"f744fbff033e", "5d3c2b1a0f9e", "a2371a1c11b7", "0badc0ffee00"}, ids)
+
+	assert.Nil(t, output.Results[0].Err)
+	assert.Equal(t, "Writing a command line toolbox in Go", output.Results[0].Title)
+	assert.Equal(t, "index.md", output.Results[0].Export.Files[0].Path)
+	notFound := &internet.MediumPostNotFoundError{}
+	assert.True(t, errors.As(output.Results[3].Err, &notFound))
+
+	assert.Equal(t, "@renato0307", output.Checkpoint.Source)
+	assert.ElementsMatch(t, []string{"f744fbff033e", "5d3c2b1a0f9e", "a2371a1c11b7"}, output.Checkpoint.Done)
+}
+
+func TestExportMediumPostsResumesFromCheckpoint(t *testing.T) {
+	// arrange
+	s, closeServers := newBulkService(t)
+	defer closeServers()
+
+	checkpoints := []internet.MediumExportCheckpoint{}
+	options := internet.ExportMediumPostsOptions{
+		Checkpoint: internet.MediumExportCheckpoint{Source: "@renato0307", Done: []string{"f744fbff033e", "5d3c2b1a0f9e"}},
+		OnResult: func(result internet.ExportMediumPostsResult, checkpoint internet.MediumExportCheckpoint) {
+			checkpoints = append(checkpoints, checkpoint)
+		},
+	}
+
+	// act
+	output, err := s.ExportMediumPosts("@renato0307", options)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, output.Exported)
+	assert.Equal(t, 1, output.Failed)
+	assert.Equal(t, 2, output.Skipped)
+	assert.True(t, output.Results[0].Skipped)
+	assert.True(t, output.Results[1].Skipped)
+	assert.Empty(t, output.Results[0].Export.Files)
+	assert.Equal(t, []string{"f744fbff033e", "5d3c2b1a0f9e", "a2371a1c11b7"}, output.Checkpoint.Done)
+	assert.Len(t, checkpoints, 4)
+	assert.Equal(t, output.Checkpoint, checkpoints[3])
+}
+
+func TestExportMediumPostsOfPublication(t *testing.T) {
+	// arrange
+	s, closeServers := newBulkService(t)
+	defer closeServers()
+
+	// act
+	output, err := s.ExportMediumPosts("medium.com/gophers", internet.ExportMediumPostsOptions{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "publication", output.Kind)
+	assert.Equal(t, "Gophers", output.Name)
+	assert.Equal(t, 1, output.Exported)
+	assert.Equal(t, "gophers", output.Checkpoint.Source)
+}
+
+func TestExportMediumPostsSourceNotFound(t *testing.T) {
+	// arrange
+	s, closeServers := newBulkService(t)
+	defer closeServers()
+
+	// act
+	_, err := s.ExportMediumPosts("@nobody", internet.ExportMediumPostsOptions{})
+
+	// assert
+	notFound := &internet.MediumSourceNotFoundError{}
+	assert.True(t, errors.As(err, &notFound))
+	assert.Equal(t, `medium user "nobody" not found`, notFound.Error())
+}
+
+func TestExportMediumPostsInvalidOptions(t *testing.T) {
+	tests := []struct {
+		source   string
+		options  internet.ExportMediumPostsOptions
+		expected string
+	}{
+		{"@renato0307", internet.ExportMediumPostsOptions{Checkpoint: internet.MediumExportCheckpoint{Source: "gophers"}}, "the checkpoint is of gophers, not of @renato0307"},
+		{"@renato0307", internet.ExportMediumPostsOptions{RateLimit: -1}, "invalid rate limit"},
+		{"@renato0307", internet.ExportMediumPostsOptions{RateLimit: 2e9}, "invalid rate limit 2e+09, it must be between 0 and 1000 posts per second"},
+		{"@renato0307", internet.ExportMediumPostsOptions{RateLimit: math.Inf(1)}, "invalid rate limit +Inf"},
+		{"@renato0307", internet.ExportMediumPostsOptions{RateLimit: math.NaN()}, "invalid rate limit NaN"},
+		{"@renato0307", internet.ExportMediumPostsOptions{ExportMediumToMdOptions: internet.ExportMediumToMdOptions{Archive: "rar"}}, "unsupported archive format"},
+		{"https://medium.com/p/f744fbff033e", internet.ExportMediumPostsOptions{}, "not a Medium user nor publication"},
+	}
+
+	for _, test := range tests {
+		// act
+		s := Service{}
+		_, err := s.ExportMediumPosts(test.source, test.options)
+
+		// assert
+		assert.NotNil(t, err, test.source)
+		assert.Contains(t, err.Error(), test.expected)
+	}
+}
+
+func TestExportMediumPostsCancelled(t *testing.T) {
+	// arrange
+	s, closeServers := newBulkService(t)
+	defer closeServers()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	options := internet.ExportMediumPostsOptions{
+		Parallelism: 1,
+		OnResult: func(result internet.ExportMediumPostsResult, checkpoint internet.MediumExportCheckpoint) {
+			cancel()
+		},
+	}
+
+	// act
+	output, err := s.ExportMediumPostsContext(ctx, "@renato0307", options)
+
+	// assert
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, "f744fbff033e", output.Results[0].PostId)
+	assert.Less(t, len(output.Results), 4)
+	assert.Contains(t, output.Checkpoint.Done, "f744fbff033e")
+	assert.NotContains(t, output.Checkpoint.Done, "a2371a1c11b7")
+}
+
+func TestExportMediumPostsRateLimit(t *testing.T) {
+	// arrange
+	s, closeServers := newBulkService(t)
+	defer closeServers()
+
+	// act
+	start := time.Now()
+	output, err := s.ExportMediumPosts("@renato0307", internet.ExportMediumPostsOptions{Parallelism: 4, RateLimit: 20})
+	elapsed := time.Since(start)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Len(t, output.Results, 4)
+	assert.GreaterOrEqual(t, int64(elapsed), int64(150*time.Millisecond))
+}
+
+func TestParseMediumSource(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected mediumSource
+		valid    bool
+	}{
+		{"@renato0307", mediumSource{"user", "renato0307"}, true},
+		{"https://medium.com/@renato0307", mediumSource{"user", "renato0307"}, true},
+		{"medium.com/@renato0307/", mediumSource{"user", "renato0307"}, true},
+		{"https://renato0307.medium.com", mediumSource{"user", "renato0307"}, true},
+		{"https://medium.com/gophers", mediumSource{"publication", "gophers"}, true},
+		{"https://medium.com/gophers/latest", mediumSource{"publication", "gophers"}, true},
+		{"https://medium.com/p/f744fbff033e", mediumSource{}, false},
+		{"https://link.medium.com/abc", mediumSource{}, false},
+		{"https://example.com/@renato0307", mediumSource{}, false},
+		{`@renato") { id }`, mediumSource{}, false},
+		{"https://medium.com/Gophers Blog", mediumSource{}, false},
+	}
+
+	for _, test := range tests {
+		// act
+		output, err := parseMediumSource(test.input)
+
+		// assert
+		assert.Equal(t, test.valid, err == nil, test.input)
+		assert.Equal(t, test.expected, output, test.input)
+	}
+}
+
+func TestMediumPostsQuery(t *testing.T) {
+	// act
+	request, err := mediumPostsQuery(mediumSource{"publication", "gophers"}, "1639573200000")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "PublicationPostsQuery", request.OperationName)
+	assert.Contains(t, request.Query, "query PublicationPostsQuery($slug: ID!, $paging: PagingOptions) {\n  collection(slug: $slug) {\n")
+	assert.Contains(t, request.Query, "homepagePostsConnection(paging: $paging)")
+	assert.Equal(t, map[string]interface{}{"limit": mediumPostsPageSize, "from": "1639573200000"}, request.Variables["paging"])
+}
+
+func TestListMediumPostsCyclicPaging(t *testing.T) {
+	// arrange
+	s, closeServers := newBulkService(t)
+	defer closeServers()
+	source, err := parseMediumSource("@cyclic")
+	assert.Nil(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// act
+	name, posts, err := s.listMediumPosts(ctx, source)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "Cyclic Writer", name)
+	ids := []string{}
+	for _, post := range posts {
+		ids = append(ids, post.Id)
+	}
+	assert.Equal(t, []string{"1a2b3c4d5e6f", "2b3c4d5e6f70", "3c4d5e6f7081"}, ids)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+// queryPost sends the query of a post to Medium, unless the post is in
+// the cache of the service and has not expired, returning the body of
+// the response and the cache status.
+//
+// Expired posts are revalidated: Medium is asked for the post, with the
+// validators it sent before, and the cached post is kept if it did not
+// change. If Medium fails, the expired post is used.
+//
+// The cache is best effort: a post fetched from Medium is returned even
+// if it can not be stored, e.g. in a full or read-only disk.
+func (s *Service) queryPost(ctx context.Context, query []byte, postId string, bypassCache bool) ([]byte, string, error) {
+	if s.cache.posts == nil {
+		response, err := s.queryMedium(ctx, query, postId, "post", nil)
+		return response.body, "", err
+	}
+
+	cached, ok, err := s.cache.posts.Get(postId)
+	if err != nil || bypassCache {
+		ok = false
+	}
+
+	if ok && time.Since(cached.FetchedAt) < s.cacheMaxAge() {
+		return cached.Data, internet.CacheHit, nil
+	}
+
+	header := http.Header{}
+	if ok && cached.ETag != "" {
+		header.Set("If-None-Match", cached.ETag)
+	}
+	if ok && cached.LastModified != "" {
+		header.Set("If-Modified-Since", cached.LastModified)
+	}
+
+	response, err := s.queryMedium(ctx, query, postId, "post", header)
+	if err != nil {
+		if ok && canUseStale(err) {
+			return cached.Data, internet.CacheStale, nil
+		}
+		return nil, "", err
+	}
+
+	status := internet.CacheMiss
+	if bypassCache {
+		status = internet.CacheBypass
+	}
+
+	switch {
+	case ok && response.notModified:
+		status = internet.CacheRevalidated
+	case ok && contentHash(response.body) == cached.Hash:
+		status = internet.CacheRevalidated
+		cached.ETag, cached.LastModified = response.etag, response.lastModified
+	case response.notModified:
+		// validators are only sent for cached posts
+		return nil, "", &internet.MediumUpstreamError{StatusCode: http.StatusNotModified, Message: "unexpected not modified response"}
+	default:
+		cached = CachedPost{
+			PostId:       postId,
+			Hash:         contentHash(response.body),
+			Data:         response.body,
+			ETag:         response.etag,
+			LastModified: response.lastModified,
+		}
+	}
+
+	cached.FetchedAt = time.Now()
+	s.cache.posts.Put(cached)
+
+	return cached.Data, status, nil
+}
+
+func (s *Service) cacheMaxAge() time.Duration {
+	if s.cache.maxAge <= 0 {
+		return defaultPostCacheMaxAge
+	}
+
+	return s.cache.maxAge
+}
+
+// canUseStale returns true for the errors after which an expired post
+// can still be used: the ones that do not mean the post changed or the
+// caller gave up.
+func canUseStale(err error) bool {
+	var notFound *internet.MediumPostNotFoundError
+	var paywalled *internet.MediumPaywalledError
+
+	return !errors.As(err, &notFound) &&
+		!errors.As(err, &paywalled) &&
+		!errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded)
+}
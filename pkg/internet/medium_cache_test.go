@@ -0,0 +1,201 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+// newCachingMediumServer returns a server answering with the recorded
+// post, or with not modified if the request has its ETag, and failing
+// with the status set in fail, if not zero.
+func newCachingMediumServer(t *testing.T, etag string, fail *int32) (*httptest.Server, *int32) {
+	requests := new(int32)
+	data, err := ioutil.ReadFile("testdata/medium/f744fbff033e.json")
+	assert.Nil(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		if status := atomic.LoadInt32(fail); status != 0 {
+			w.WriteHeader(int(status))
+			return
+		}
+
+		if etag != "" {
+			w.Header().Set("ETag", etag)
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+
+		w.Write(data)
+	}))
+
+	return server, requests
+}
+
+func TestConvertMediumToMdCache(t *testing.T) {
+	// arrange
+	server, requests := newCachingMediumServer(t, "", new(int32))
+	defer server.Close()
+	s := newMediumService(server, WithPostCache(NewMemoryPostCache(10, 0)))
+
+	// act
+	first, firstErr := s.ConvertMediumToMd("f744fbff033e")
+	second, secondErr := s.ConvertMediumToMd("f744fbff033e")
+	bypass, bypassErr := s.ConvertMediumToMdWithOptions("f744fbff033e", internet.ConvertMediumToMdOptions{BypassCache: true})
+
+	// assert
+	assert.Nil(t, firstErr)
+	assert.Nil(t, secondErr)
+	assert.Nil(t, bypassErr)
+	assert.Equal(t, internet.CacheMiss, first.CacheStatus)
+	assert.Equal(t, internet.CacheHit, second.CacheStatus)
+	assert.Equal(t, internet.CacheBypass, bypass.CacheStatus)
+	assert.Equal(t, first.Markdown, second.Markdown)
+	assert.Equal(t, int32(2), *requests)
+}
+
+func TestConvertMediumToMdWithoutCache(t *testing.T) {
+	// arrange
+	server, requests := newCachingMediumServer(t, "", new(int32))
+	defer server.Close()
+
+	// act
+	s := newMediumService(server)
+	s.ConvertMediumToMd("f744fbff033e")
+	output, err := s.ConvertMediumToMd("f744fbff033e")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Empty(t, output.CacheStatus)
+	assert.Equal(t, int32(2), *requests)
+}
+
+// failingPostCache is a PostCache that can not store posts.
+type failingPostCache struct{}
+
+func (c failingPostCache) Get(postId string) (CachedPost, bool, error) {
+	return CachedPost{}, false, nil
+}
+
+func (c failingPostCache) Put(post CachedPost) error {
+	return errors.New("no space left on device")
+}
+
+func TestConvertMediumToMdCacheFailingToStore(t *testing.T) {
+	// arrange
+	server, requests := newCachingMediumServer(t, "", new(int32))
+	defer server.Close()
+	s := newMediumService(server, WithPostCache(failingPostCache{}))
+
+	// act
+	output, err := s.ConvertMediumToMd("f744fbff033e")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, internet.CacheMiss, output.CacheStatus)
+	assert.Equal(t, "Writing a command line toolbox in Go", output.Title)
+	assert.Equal(t, int32(1), *requests)
+}
+
+func TestConvertMediumToMdCacheRevalidation(t *testing.T) {
+	tests := []struct {
+		name string
+		etag string
+	}{
+		{"not modified", `"v1"`},
+		{"same content", ""},
+	}
+
+	for _, test := range tests {
+		// arrange
+		server, requests := newCachingMediumServer(t, test.etag, new(int32))
+		cache := NewMemoryPostCache(10, 0)
+		s := newMediumService(server, WithPostCache(cache), WithPostCacheMaxAge(time.Nanosecond))
+
+		// act
+		s.ConvertMediumToMd("f744fbff033e")
+		before, _, _ := cache.Get("f744fbff033e")
+		output, err := s.ConvertMediumToMd("f744fbff033e")
+		after, _, _ := cache.Get("f744fbff033e")
+		server.Close()
+
+		// assert
+		assert.Nil(t, err, test.name)
+		assert.Equal(t, internet.CacheRevalidated, output.CacheStatus, test.name)
+		assert.NotEmpty(t, output.Markdown, test.name)
+		assert.Equal(t, before.Hash, after.Hash, test.name)
+		assert.True(t, after.FetchedAt.After(before.FetchedAt), test.name)
+		assert.Equal(t, int32(2), *requests, test.name)
+	}
+}
+
+func TestConvertMediumToMdCacheStale(t *testing.T) {
+	// arrange
+	fail := new(int32)
+	server, _ := newCachingMediumServer(t, "", fail)
+	defer server.Close()
+	s := newMediumService(server, WithPostCache(NewMemoryPostCache(10, 0)), WithPostCacheMaxAge(time.Nanosecond), WithMediumRetries(0))
+	s.ConvertMediumToMd("f744fbff033e")
+
+	// act
+	atomic.StoreInt32(fail, http.StatusServiceUnavailable)
+	stale, staleErr := s.ConvertMediumToMd("f744fbff033e")
+	atomic.StoreInt32(fail, http.StatusNotFound)
+	_, notFoundErr := s.ConvertMediumToMd("f744fbff033e")
+
+	// assert
+	assert.Nil(t, staleErr)
+	assert.Equal(t, internet.CacheStale, stale.CacheStatus)
+	assert.NotEmpty(t, stale.Markdown)
+	assert.NotNil(t, notFoundErr)
+}
+
+func TestExportMediumToMdWithDiskCache(t *testing.T) {
+	// arrange
+	server, requests := newCachingMediumServer(t, "", new(int32))
+	defer server.Close()
+	imagesServer, _ := newImagesServer(t, 0)
+	defer imagesServer.Close()
+	cache, err := NewDiskPostCache(t.TempDir())
+	assert.Nil(t, err)
+
+	// act
+	s := newMediumService(server, WithPostCache(cache), WithMediumImagesEndpoint(imagesServer.URL))
+	first, firstErr := s.ExportMediumToMd("f744fbff033e", internet.ExportMediumToMdOptions{})
+	other := newMediumService(server, WithPostCache(cache), WithMediumImagesEndpoint(imagesServer.URL))
+	second, secondErr := other.ExportMediumToMd("f744fbff033e", internet.ExportMediumToMdOptions{})
+
+	// assert
+	assert.Nil(t, firstErr)
+	assert.Nil(t, secondErr)
+	assert.Equal(t, internet.CacheMiss, first.CacheStatus)
+	assert.Equal(t, internet.CacheHit, second.CacheStatus)
+	assert.Equal(t, first.Markdown, second.Markdown)
+	assert.Equal(t, int32(1), *requests)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+// ConvertMedium converts a Medium post to one of the document formats:
+// markdown, html, asciidoc, rst, org or text. The post can be
+// identified by its ID or any of its URLs.
+func (s *Service) ConvertMedium(postId string, format string) (internet.ConvertMediumOutput, error) {
+	return s.ConvertMediumContext(context.Background(), postId, format)
+}
+
+func (s *Service) ConvertMediumContext(ctx context.Context, postId string, format string) (internet.ConvertMediumOutput, error) {
+	output := internet.ConvertMediumOutput{}
+
+	name := strings.ToLower(format)
+	documentFormat, ok := documentFormats[name]
+	if !ok {
+		return output, fmt.Errorf("unsupported format %q", format)
+	}
+
+	resolved, err := s.ResolveMediumPostIdContext(ctx, postId)
+	if err != nil {
+		return output, err
+	}
+
+	post, cacheStatus, err := s.getPostData(ctx, resolved.PostId, false)
+	if err != nil {
+		return output, fmt.Errorf("error getting post data: %w", err)
+	}
+
+	output.PostId = resolved.PostId
+	output.Title = post.Data.Post.Title
+	output.Format = name
+	output.ContentType = documentFormat.contentType
+	output.FileExtension = documentFormat.fileExtension
+	output.CacheStatus = cacheStatus
+	output.Content = documentFormat.renderer(s).render(s.mediumDocument(ctx, post, nil))
+
+	return output, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertMedium(t *testing.T) {
+	tests := []struct {
+		format        string
+		contentType   string
+		fileExtension string
+		expected      string
+	}{
+		{"markdown", "text/markdown", "md", "# Go tips for services\nBy Renato Torres\n"},
+		{"HTML", "text/html", "html", "<article>\n<h1>Go tips for services</h1>\n"},
+		{"asciidoc", "text/asciidoc", "adoc", "= Go tips for services\nRenato Torres\n"},
+		{"rst", "text/x-rst", "rst", "====================\nGo tips for services\n====================\n"},
+		{"org", "text/org", "org", "#+TITLE: Go tips for services\n#+AUTHOR: Renato Torres\n"},
+		{"text", "text/plain", "txt", "Go tips for services\n====================\n"},
+	}
+
+	server := newMediumServer(t)
+	defer server.Close()
+
+	for _, test := range tests {
+		// act
+		s := newMediumService(server)
+		output, err := s.ConvertMedium("5d3c2b1a0f9e", test.format)
+
+		// assert
+		assert.Nil(t, err)
+		assert.Equal(t, "5d3c2b1a0f9e", output.PostId)
+		assert.Equal(t, "Go tips for services", output.Title)
+		assert.Equal(t, strings.ToLower(test.format), output.Format)
+		assert.Equal(t, test.contentType, output.ContentType)
+		assert.Equal(t, test.fileExtension, output.FileExtension)
+		assert.True(t, strings.HasPrefix(output.Content, test.expected), output.Content)
+	}
+}
+
+func TestConvertMediumMatchesConvertMediumToMd(t *testing.T) {
+	// arrange
+	server := newMediumServer(t)
+	defer server.Close()
+	s := newMediumService(server)
+
+	// act
+	output, err := s.ConvertMedium("5d3c2b1a0f9e", "markdown")
+	markdown, mdErr := s.ConvertMediumToMd("5d3c2b1a0f9e")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, mdErr)
+	assert.Equal(t, markdown.Markdown, output.Content)
+}
+
+func TestConvertMediumToHTMLWithBlocks(t *testing.T) {
+	// arrange
+	server := newMediumServer(t)
+	defer server.Close()
+
+	// act
+	s := newMediumService(server)
+	output, err := s.ConvertMedium("5d3c2b1a0f9e", "html")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Contains(t, output.Content, "\n<pre><code class=\"language-go\">type Service struct {\n}</code></pre>\n")
+	assert.Contains(t, output.Content, "\n<ul>\n<li>Testable code</li>\n<li><strong>Clear</strong> dependencies</li>\n</ul>\n")
+	assert.Contains(t, output.Content, "\n<hr>\n")
+}
+
+func TestConvertMediumUnsupportedFormat(t *testing.T) {
+	// act
+	s := Service{}
+	_, err := s.ConvertMedium("5d3c2b1a0f9e", "docx")
+
+	// assert
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `unsupported format "docx"`)
+}
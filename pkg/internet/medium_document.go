@@ -0,0 +1,127 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"strings"
+)
+
+// mediumDocument parses a post into a document. The images are linked
+// to Medium, unless their ID is in images, which has the paths to use.
+func (s *Service) mediumDocument(ctx context.Context, post mediumPostResponse, images map[string]string) document {
+	d := document{
+		title:  post.Data.Post.Title,
+		author: post.Data.Post.Creator.Name,
+	}
+
+	bodyModel := post.Data.Post.Content.BodyModel
+	sectionStarts := map[int]bool{}
+	for _, section := range bodyModel.Sections {
+		if section.StartIndex > 0 {
+			sectionStarts[section.StartIndex] = true
+		}
+	}
+
+	paragraphs := bodyModel.Paragraphs
+	for i := 0; i < len(paragraphs); i++ {
+		paragraph := paragraphs[i]
+		if sectionStarts[i] {
+			d.blocks = append(d.blocks, block{kind: blockRule})
+		}
+
+		switch paragraph.Type {
+		case "H2", "H3":
+			d.blocks = append(d.blocks, block{kind: blockHeading, level: 2, inlines: paragraphInlines(paragraph)})
+		case "H4":
+			d.blocks = append(d.blocks, block{
+				kind:    blockHeading,
+				level:   3,
+				inlines: []inline{{kind: inlineEmphasis, children: paragraphInlines(paragraph)}},
+			})
+		case "P":
+			d.blocks = append(d.blocks, block{kind: blockParagraph, inlines: paragraphInlines(paragraph)})
+		case "IMG":
+			image, ok := images[paragraph.Metadata.Id]
+			if !ok {
+				image = s.mediumImageUrl(paragraph.Metadata.Id)
+			}
+			d.blocks = append(d.blocks, block{kind: blockImage, src: image, alt: paragraph.Text})
+		case "BQ", "PQ":
+			d.blocks = append(d.blocks, block{kind: blockQuote, inlines: paragraphInlines(paragraph)})
+		case "PRE":
+			end := groupEnd(paragraphs, i, sectionStarts, func(p mediumPostParagraph) bool {
+				return p.Type == "PRE" && p.CodeBlockMetadata.Lang == paragraph.CodeBlockMetadata.Lang
+			})
+			d.blocks = append(d.blocks, codeBlock(paragraphs[i:end]))
+			i = end - 1
+		case "ULI", "OLI":
+			end := groupEnd(paragraphs, i, sectionStarts, func(p mediumPostParagraph) bool {
+				return p.Type == paragraph.Type
+			})
+			d.blocks = append(d.blocks, listBlock(paragraphs[i:end]))
+			i = end - 1
+		case "IFRAME":
+			d.blocks = append(d.blocks, s.embedBlocks(ctx, paragraph)...)
+		case "MIXTAPE_EMBED":
+			d.blocks = append(d.blocks, mixtapeBlock(paragraph))
+		}
+	}
+
+	return d
+}
+
+func paragraphInlines(paragraph mediumPostParagraph) []inline {
+	return mediumInlines(paragraph.Text, paragraph.Markups)
+}
+
+// groupEnd returns the index after the last of the consecutive
+// paragraphs, starting at start, that belong to the same group.
+// Groups never cross sections.
+func groupEnd(paragraphs []mediumPostParagraph, start int, sectionStarts map[int]bool, sameGroup func(mediumPostParagraph) bool) int {
+	end := start + 1
+	for end < len(paragraphs) && !sectionStarts[end] && sameGroup(paragraphs[end]) {
+		end++
+	}
+
+	return end
+}
+
+// codeBlock joins PRE paragraphs in a single code block, using the
+// language detected or set in Medium.
+func codeBlock(paragraphs []mediumPostParagraph) block {
+	lines := []string{}
+	for _, paragraph := range paragraphs {
+		lines = append(lines, paragraph.Text)
+	}
+
+	return block{
+		kind: blockCode,
+		code: strings.Join(lines, "\n"),
+		lang: paragraphs[0].CodeBlockMetadata.Lang,
+	}
+}
+
+// listBlock joins ULI or OLI paragraphs in a single list.
+func listBlock(paragraphs []mediumPostParagraph) block {
+	b := block{kind: blockList, ordered: paragraphs[0].Type == "OLI"}
+	for _, paragraph := range paragraphs {
+		b.items = append(b.items, paragraphInlines(paragraph))
+	}
+
+	return b
+}
@@ -0,0 +1,167 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	gistUrl    = regexp.MustCompile(`^https?://gist\.github\.com/(?:[\w-]+/)?([0-9a-f]+)`)
+	youTubeUrl = regexp.MustCompile(`^https?://(?:www\.|m\.)?(?:youtube\.com/(?:watch\?(?:.*&)?v=|embed/|shorts/)|youtu\.be/)([\w-]{11})`)
+	vimeoUrl   = regexp.MustCompile(`^https?://(?:www\.|player\.)?vimeo\.com/(?:video/)?(\d+)`)
+	tweetUrl   = regexp.MustCompile(`^https?://(?:www\.|mobile\.)?(?:twitter|x)\.com/(\w+)/status(?:es)?/(\d+)`)
+)
+
+// embedSource returns the URL of the embedded media of an IFRAME
+// paragraph.
+//
+// Medium embeds most third party media through Embedly, in which case
+// the original URL is in the url or src query parameters.
+func embedSource(resource mediumPostParagraphIFrameMediaResource) string {
+	if resource.IFrameSrc != "" {
+		src, err := url.Parse(resource.IFrameSrc)
+		if err == nil && strings.HasSuffix(src.Host, "embedly.com") {
+			if original := src.Query().Get("url"); original != "" {
+				return original
+			}
+			if original := src.Query().Get("src"); original != "" {
+				return original
+			}
+		}
+	}
+
+	if resource.HRef != "" && !strings.HasPrefix(resource.HRef, "https://medium.com/media/") {
+		return resource.HRef
+	}
+
+	if resource.IFrameSrc != "" {
+		return resource.IFrameSrc
+	}
+
+	return resource.HRef
+}
+
+// embedBlocks parses the media embedded in an IFRAME paragraph.
+//
+// Gists are fetched and parsed as code, videos as linked thumbnails,
+// tweets as quoted links and everything else is left as an embed for the
+// renderers to handle.
+func (s *Service) embedBlocks(ctx context.Context, paragraph mediumPostParagraph) []block {
+	resource := paragraph.IFrame.MediaResource
+	source := embedSource(resource)
+	if source == "" {
+		return nil
+	}
+
+	title := resource.Title
+	if title == "" {
+		title = paragraph.Text
+	}
+
+	if match := gistUrl.FindStringSubmatch(source); match != nil {
+		gist, err := s.gists().Gist(ctx, match[1])
+		if err == nil && len(gist.Files) > 0 {
+			return gistBlocks(gist)
+		}
+		return []block{{
+			kind:    blockParagraph,
+			inlines: []inline{linkInline(firstNonEmpty(title, "View the gist on GitHub"), source)},
+		}}
+	}
+
+	if match := youTubeUrl.FindStringSubmatch(source); match != nil {
+		return []block{{
+			kind: blockImage,
+			src:  fmt.Sprintf("https://img.youtube.com/vi/%s/hqdefault.jpg", match[1]),
+			alt:  firstNonEmpty(title, "Watch on YouTube"),
+			href: fmt.Sprintf("https://www.youtube.com/watch?v=%s", match[1]),
+		}}
+	}
+
+	if match := vimeoUrl.FindStringSubmatch(source); match != nil {
+		return []block{{
+			kind: blockImage,
+			src:  fmt.Sprintf("https://vumbnail.com/%s.jpg", match[1]),
+			alt:  firstNonEmpty(title, "Watch on Vimeo"),
+			href: fmt.Sprintf("https://vimeo.com/%s", match[1]),
+		}}
+	}
+
+	if match := tweetUrl.FindStringSubmatch(source); match != nil {
+		return []block{{
+			kind:    blockQuote,
+			inlines: []inline{linkInline(fmt.Sprintf("Tweet by @%s", match[1]), source)},
+		}}
+	}
+
+	return []block{{
+		kind:   blockEmbed,
+		src:    resource.IFrameSrc,
+		href:   source,
+		title:  title,
+		width:  resource.IFrameWidth,
+		height: resource.IFrameHeight,
+	}}
+}
+
+// gistBlocks parses all the files of a gist as code blocks.
+func gistBlocks(gist Gist) []block {
+	blocks := []block{}
+	for _, file := range gist.Files {
+		blocks = append(blocks, block{
+			kind: blockCode,
+			code: strings.TrimRight(file.Content, "\n"),
+			lang: strings.ToLower(strings.ReplaceAll(file.Language, " ", "-")),
+		})
+	}
+
+	return blocks
+}
+
+// mixtapeBlock parses a MIXTAPE_EMBED paragraph, the preview card Medium
+// shows for links, as a quoted link.
+func mixtapeBlock(paragraph mediumPostParagraph) block {
+	href := paragraph.HRef
+	for _, markup := range paragraph.Markups {
+		if href == "" && markup.Type == "A" {
+			href = markupHRef(markup)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(paragraph.Text), "\n")
+	title := strings.TrimSpace(lines[0])
+	if href == "" {
+		return block{kind: blockQuote, inlines: []inline{textInline(title)}}
+	}
+
+	return block{kind: blockQuote, inlines: []inline{linkInline(firstNonEmpty(title, href), href)}}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbedSource(t *testing.T) {
+	tests := []struct {
+		resource mediumPostParagraphIFrameMediaResource
+		expected string
+	}{
+		{
+			mediumPostParagraphIFrameMediaResource{
+				HRef:      "https://medium.com/media/8e3b",
+				IFrameSrc: "https://cdn.embedly.com/widgets/media.html?src=https%3A%2F%2Fplayer.vimeo.com%2Fvideo%2F76979871&url=https%3A%2F%2Fvimeo.com%2F76979871",
+			},
+			"https://vimeo.com/76979871",
+		},
+		{
+			mediumPostParagraphIFrameMediaResource{HRef: "https://gist.github.com/user/0d8e"},
+			"https://gist.github.com/user/0d8e",
+		},
+		{
+			mediumPostParagraphIFrameMediaResource{HRef: "https://medium.com/media/8e3b", IFrameSrc: "https://codepen.io/embed/x"},
+			"https://codepen.io/embed/x",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, embedSource(test.resource))
+	}
+}
+
+func TestEmbedBlocksVideosAndTweets(t *testing.T) {
+	tests := []struct {
+		href     string
+		expected string
+	}{
+		{"https://youtu.be/ndmB0bj7eyw", "[![Watch on YouTube](https://img.youtube.com/vi/ndmB0bj7eyw/hqdefault.jpg)](https://www.youtube.com/watch?v=ndmB0bj7eyw)"},
+		{"https://www.youtube.com/embed/ndmB0bj7eyw?feature=oembed", "[![Watch on YouTube](https://img.youtube.com/vi/ndmB0bj7eyw/hqdefault.jpg)](https://www.youtube.com/watch?v=ndmB0bj7eyw)"},
+		{"https://vimeo.com/76979871", "[![Watch on Vimeo](https://vumbnail.com/76979871.jpg)](https://vimeo.com/76979871)"},
+		{"https://twitter.com/golang/status/1471184318843514880", "> [Tweet by @golang](https://twitter.com/golang/status/1471184318843514880)"},
+	}
+
+	for _, test := range tests {
+		// arrange
+		paragraph := mediumPostParagraph{Type: "IFRAME"}
+		paragraph.IFrame.MediaResource.HRef = test.href
+
+		// act
+		s := Service{}
+		output := markdownRenderer{}.block(s.embedBlocks(context.Background(), paragraph)[0])
+
+		// assert
+		assert.Equal(t, test.expected, output)
+	}
+}
+
+func TestGistBlocksWithBackticks(t *testing.T) {
+	// arrange
+	gist := Gist{Files: []GistFile{
+		{Filename: "README.md", Language: "Markdown", Content: "```go\n```\n"},
+		{Filename: "run.sh", Language: "Shell", Content: "go test ./...\n"},
+	}}
+
+	// act
+	output := markdownRenderer{}.render(document{blocks: gistBlocks(gist)})
+
+	// assert
+	assert.Contains(t, output, "\n````markdown\n```go\n```\n````\n\n```shell\ngo test ./...\n```\n")
+}
@@ -0,0 +1,377 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+const (
+	defaultExportAssetsDir = "images"
+	defaultExportWorkers   = 4
+	exportRetryDelay       = 200 * time.Millisecond
+	// maxExportImageSize is the size of the largest image downloaded
+	maxExportImageSize = 20 << 20
+)
+
+var imageExtensions = map[string]string{
+	"image/jpeg":    ".jpg",
+	"image/png":     ".png",
+	"image/gif":     ".gif",
+	"image/webp":    ".webp",
+	"image/svg+xml": ".svg",
+	"image/bmp":     ".bmp",
+}
+
+var archiveFormats = map[string]bool{"zip": true, "tar": true, "tar.gz": true, "tgz": true}
+
+var unsafeFilenameChars = regexp.MustCompile(`[^\w.-]+`)
+
+// ExportMediumToMd converts a Medium post to Markdown and downloads all
+// its images, so the post can be kept even if it is removed from Medium.
+// The images are linked with relative paths in the Markdown.
+func (s *Service) ExportMediumToMd(postId string, options internet.ExportMediumToMdOptions) (internet.ExportMediumToMdOutput, error) {
+	return s.ExportMediumToMdContext(context.Background(), postId, options)
+}
+
+func (s *Service) ExportMediumToMdContext(ctx context.Context, postId string, options internet.ExportMediumToMdOptions) (internet.ExportMediumToMdOutput, error) {
+	output := internet.ExportMediumToMdOutput{}
+
+	archiveFormat, err := validateExportOptions(options)
+	if err != nil {
+		return output, err
+	}
+
+	resolved, err := s.ResolveMediumPostIdContext(ctx, postId)
+	if err != nil {
+		return output, err
+	}
+
+	post, cacheStatus, err := s.getPostData(ctx, resolved.PostId, options.BypassCache)
+	if err != nil {
+		return output, fmt.Errorf("error getting post data: %w", err)
+	}
+
+	assetsDir := options.AssetsDir
+	if assetsDir == "" {
+		assetsDir = defaultExportAssetsDir
+	}
+
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, paragraph := range post.Data.Post.Content.BodyModel.Paragraphs {
+		id := paragraph.Metadata.Id
+		if paragraph.Type == "IMG" && id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if id := post.Data.Post.PreviewImage.Id; id != "" && !seen[id] {
+		ids = append(ids, id)
+	}
+
+	downloads := s.downloadImages(ctx, ids, options)
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
+	images := map[string]string{}
+	filenames := map[string]bool{}
+	files := []internet.ExportedFile{}
+	for i, download := range downloads {
+		if download.err != nil {
+			output.FailedImages = append(output.FailedImages, s.mediumImageUrl(ids[i]))
+			continue
+		}
+
+		filePath := path.Join(assetsDir, uniqueFilename(imageFilename(ids[i], download.contentType), filenames))
+		images[ids[i]] = filePath
+		files = append(files, internet.ExportedFile{
+			Path:        filePath,
+			ContentType: download.contentType,
+			Data:        download.data,
+		})
+	}
+
+	output.ConvertMediumToMdOutput = s.convertPost(ctx, resolved.PostId, post, options.ConvertMediumToMdOptions, images)
+	output.CacheStatus = cacheStatus
+	output.Files = append([]internet.ExportedFile{{
+		Path:        "index.md",
+		ContentType: "text/markdown; charset=utf-8",
+		Data:        []byte(output.Markdown),
+	}}, files...)
+
+	if archiveFormat != "" {
+		output.ArchiveFormat = archiveFormat
+		output.Archive, err = archiveFiles(output.Files, archiveFormat)
+		if err != nil {
+			return output, fmt.Errorf("error creating archive: %w", err)
+		}
+	}
+
+	return output, nil
+}
+
+// validateExportOptions checks the options of an export, returning the
+// archive format in lower case.
+func validateExportOptions(options internet.ExportMediumToMdOptions) (string, error) {
+	archiveFormat := strings.ToLower(options.Archive)
+	if archiveFormat != "" && !archiveFormats[archiveFormat] {
+		return "", fmt.Errorf("unsupported archive format %q", options.Archive)
+	}
+
+	// the files are written relative to the Markdown file, so the assets
+	// can not be outside of its directory
+	assetsDir := strings.ReplaceAll(options.AssetsDir, "\\", "/")
+	if path.IsAbs(assetsDir) || strings.Contains(assetsDir, ":") {
+		return "", fmt.Errorf("the assets directory %q must be a relative path", options.AssetsDir)
+	}
+	for _, segment := range strings.Split(assetsDir, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("the assets directory %q must not contain ..", options.AssetsDir)
+		}
+	}
+	if options.Retries < 0 {
+		return "", fmt.Errorf("the number of retries must not be negative")
+	}
+
+	return archiveFormat, validateFrontMatterOptions(options.ConvertMediumToMdOptions)
+}
+
+type imageDownload struct {
+	data        []byte
+	contentType string
+	err         error
+}
+
+// downloadImages downloads the images with a pool of workers, returning
+// the results in the same order as the IDs.
+func (s *Service) downloadImages(ctx context.Context, ids []string, options internet.ExportMediumToMdOptions) []imageDownload {
+	workers := options.Workers
+	if workers <= 0 {
+		workers = defaultExportWorkers
+	}
+
+	retries := options.Retries
+	downloads := make([]imageDownload, len(ids))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				downloads[job] = s.downloadImage(ctx, s.mediumImageUrl(ids[job]), retries)
+			}
+		}()
+	}
+
+	for i := range ids {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			downloads[i].err = ctx.Err()
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return downloads
+}
+
+// downloadImage downloads an image, retrying on network errors and on
+// server errors with a linear backoff.
+func (s *Service) downloadImage(ctx context.Context, url string, retries int) imageDownload {
+	download := imageDownload{}
+
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(exportRetryDelay * time.Duration(attempt)):
+			case <-ctx.Done():
+				download.err = ctx.Err()
+				return download
+			}
+		}
+
+		var retry bool
+		download, retry = s.tryDownloadImage(ctx, url)
+		if download.err == nil || !retry {
+			return download
+		}
+	}
+
+	return download
+}
+
+func (s *Service) tryDownloadImage(ctx context.Context, url string) (imageDownload, bool) {
+	download := imageDownload{}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		download.err = fmt.Errorf("error creating request: %s", err.Error())
+		return download, false
+	}
+	req.Header.Set("User-Agent", s.userAgentHeader())
+
+	resp, err := s.httpClient().Do(req)
+	if err != nil {
+		download.err = fmt.Errorf("error executing request: %w", err)
+		return download, ctx.Err() == nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		download.err = fmt.Errorf("unexpected status code %d downloading %s", resp.StatusCode, url)
+		return download, resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	}
+
+	download.data, err = ioutil.ReadAll(io.LimitReader(resp.Body, maxExportImageSize+1))
+	if err != nil {
+		download.err = fmt.Errorf("error reading response: %w", err)
+		return download, true
+	}
+	if len(download.data) > maxExportImageSize {
+		download.data = nil
+		download.err = fmt.Errorf("%s is larger than %d bytes", url, maxExportImageSize)
+		return download, false
+	}
+
+	// servers often answer with generic content types, so the content is
+	// checked when the header is not an image type
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !strings.HasPrefix(contentType, "image/") {
+		contentType, _, _ = mime.ParseMediaType(http.DetectContentType(download.data))
+	}
+	if !strings.HasPrefix(contentType, "image/") {
+		download.err = fmt.Errorf("%s is not an image but %s", url, contentType)
+		return download, false
+	}
+	download.contentType = contentType
+
+	return download, false
+}
+
+// imageFilename returns a safe file name for a Medium image ID, with the
+// extension of its content type, or "image" when nothing of the ID is left.
+func imageFilename(id, contentType string) string {
+	name := strings.Trim(unsafeFilenameChars.ReplaceAllString(id, "_"), "_.")
+	if strings.TrimSuffix(name, path.Ext(name)) == "" {
+		name = "image" + path.Ext(name)
+	}
+	extension, ok := imageExtensions[contentType]
+	if !ok {
+		return name
+	}
+
+	return strings.TrimSuffix(name, path.Ext(name)) + extension
+}
+
+// uniqueFilename returns the name with a number before the extension if
+// it is already used, adding the result to the used names.
+func uniqueFilename(name string, used map[string]bool) string {
+	extension := path.Ext(name)
+	base := strings.TrimSuffix(name, extension)
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s-%d%s", base, i, extension)
+	}
+	used[unique] = true
+
+	return unique
+}
+
+func archiveFiles(files []internet.ExportedFile, format string) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	switch format {
+	case "zip":
+		writer := zip.NewWriter(&buffer)
+		for _, file := range files {
+			w, err := writer.Create(file.Path)
+			if err != nil {
+				return nil, err
+			}
+			_, err = w.Write(file.Data)
+			if err != nil {
+				return nil, err
+			}
+		}
+		err := writer.Close()
+		if err != nil {
+			return nil, err
+		}
+	case "tar":
+		err := writeTar(&buffer, files)
+		if err != nil {
+			return nil, err
+		}
+	case "tar.gz", "tgz":
+		gz := gzip.NewWriter(&buffer)
+		err := writeTar(gz, files)
+		if err != nil {
+			return nil, err
+		}
+		err = gz.Close()
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported archive format %q", format)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func writeTar(w io.Writer, files []internet.ExportedFile) error {
+	writer := tar.NewWriter(w)
+	for _, file := range files {
+		err := writer.WriteHeader(&tar.Header{
+			Name:    file.Path,
+			Mode:    0644,
+			Size:    int64(len(file.Data)),
+			ModTime: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = writer.Write(file.Data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
@@ -0,0 +1,262 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+// a 1x1 transparent PNG
+var testPng = []byte{
+	0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d,
+	0x49, 0x48, 0x44, 0x52, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01,
+	0x08, 0x06, 0x00, 0x00, 0x00, 0x1f, 0x15, 0xc4, 0x89, 0x00, 0x00, 0x00,
+	0x0d, 0x49, 0x44, 0x41, 0x54, 0x78, 0x9c, 0x63, 0x00, 0x01, 0x00, 0x00,
+	0x05, 0x00, 0x01, 0x0d, 0x0a, 0x2d, 0xb4, 0x00, 0x00, 0x00, 0x00, 0x49,
+	0x45, 0x4e, 0x44, 0xae, 0x42, 0x60, 0x82,
+}
+
+func newImagesServer(t *testing.T, failures int32) (*httptest.Server, *int32) {
+	requests := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		// the content type is detected from the content
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(testPng)
+	}))
+
+	return server, &requests
+}
+
+func TestExportMediumToMd(t *testing.T) {
+	// arrange
+	mediumServer := newMediumServer(t)
+	defer mediumServer.Close()
+	imagesServer, requests := newImagesServer(t, 1)
+	defer imagesServer.Close()
+
+	// act
+	s := newMediumService(mediumServer, WithMediumImagesEndpoint(imagesServer.URL))
+	output, err := s.ExportMediumToMd("f744fbff033e", internet.ExportMediumToMdOptions{Retries: 1})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), *requests)
+	assert.Empty(t, output.FailedImages)
+	assert.Contains(t, output.Markdown, "![The architecture of canivete](images/1_3fJ8mS6X2o2XJ0kQeZQm2A.png)")
+	assert.Equal(t, "images/1_3fJ8mS6X2o2XJ0kQeZQm2A.png", output.PreviewImage)
+	assert.Len(t, output.Files, 2)
+	assert.Equal(t, "index.md", output.Files[0].Path)
+	assert.Equal(t, "images/1_3fJ8mS6X2o2XJ0kQeZQm2A.png", output.Files[1].Path)
+	assert.Equal(t, "image/png", output.Files[1].ContentType)
+	assert.Equal(t, testPng, output.Files[1].Data)
+	assert.Nil(t, output.Archive)
+}
+
+func TestExportMediumToMdKeepsFailedImagesAsLinks(t *testing.T) {
+	// arrange
+	mediumServer := newMediumServer(t)
+	defer mediumServer.Close()
+	imagesServer, requests := newImagesServer(t, 10)
+	defer imagesServer.Close()
+
+	// act
+	s := newMediumService(mediumServer, WithMediumImagesEndpoint(imagesServer.URL))
+	output, err := s.ExportMediumToMd("f744fbff033e", internet.ExportMediumToMdOptions{Retries: 1})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), *requests)
+	assert.Equal(t, []string{imagesServer.URL + "/1*3fJ8mS6X2o2XJ0kQeZQm2A.png"}, output.FailedImages)
+	assert.Contains(t, output.Markdown, "]("+imagesServer.URL+"/1*3fJ8mS6X2o2XJ0kQeZQm2A.png)")
+	assert.Len(t, output.Files, 1)
+}
+
+func TestExportMediumToMdWithoutRetries(t *testing.T) {
+	// arrange
+	mediumServer := newMediumServer(t)
+	defer mediumServer.Close()
+	imagesServer, requests := newImagesServer(t, 1)
+	defer imagesServer.Close()
+
+	// act
+	s := newMediumService(mediumServer, WithMediumImagesEndpoint(imagesServer.URL))
+	output, err := s.ExportMediumToMd("f744fbff033e", internet.ExportMediumToMdOptions{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), *requests)
+	assert.Len(t, output.FailedImages, 1)
+}
+
+func TestDownloadImageTooLarge(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(testPng)
+		w.Write(make([]byte, maxExportImageSize))
+	}))
+	defer server.Close()
+
+	// act
+	s := NewService(WithHTTPClient(server.Client()))
+	download := s.downloadImage(context.Background(), server.URL, 2)
+
+	// assert
+	assert.EqualError(t, download.err, server.URL+" is larger than 20971520 bytes")
+	assert.Nil(t, download.data)
+}
+
+func TestExportMediumToMdZip(t *testing.T) {
+	// arrange
+	mediumServer := newMediumServer(t)
+	defer mediumServer.Close()
+	imagesServer, _ := newImagesServer(t, 0)
+	defer imagesServer.Close()
+
+	// act
+	s := newMediumService(mediumServer, WithMediumImagesEndpoint(imagesServer.URL))
+	output, err := s.ExportMediumToMd("f744fbff033e", internet.ExportMediumToMdOptions{
+		AssetsDir: "assets",
+		Archive:   "zip",
+	})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "zip", output.ArchiveFormat)
+
+	archive, err := zip.NewReader(bytes.NewReader(output.Archive), int64(len(output.Archive)))
+	assert.Nil(t, err)
+	assert.Len(t, archive.File, 2)
+	assert.Equal(t, "index.md", archive.File[0].Name)
+	assert.Equal(t, "assets/1_3fJ8mS6X2o2XJ0kQeZQm2A.png", archive.File[1].Name)
+}
+
+func TestExportMediumToMdTarGz(t *testing.T) {
+	// arrange
+	mediumServer := newMediumServer(t)
+	defer mediumServer.Close()
+	imagesServer, _ := newImagesServer(t, 0)
+	defer imagesServer.Close()
+
+	// act
+	s := newMediumService(mediumServer, WithMediumImagesEndpoint(imagesServer.URL))
+	output, err := s.ExportMediumToMd("f744fbff033e", internet.ExportMediumToMdOptions{Archive: "tar.gz", Workers: 1})
+
+	// assert
+	assert.Nil(t, err)
+
+	gz, err := gzip.NewReader(bytes.NewReader(output.Archive))
+	assert.Nil(t, err)
+	reader := tar.NewReader(gz)
+	names := []string{}
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+		names = append(names, header.Name)
+	}
+	assert.Equal(t, []string{"index.md", "images/1_3fJ8mS6X2o2XJ0kQeZQm2A.png"}, names)
+}
+
+func TestExportMediumToMdUnsupportedArchive(t *testing.T) {
+	// act
+	s := Service{}
+	_, err := s.ExportMediumToMd("f744fbff033e", internet.ExportMediumToMdOptions{Archive: "rar"})
+
+	// assert
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unsupported archive format")
+}
+
+func TestExportMediumToMdNegativeRetries(t *testing.T) {
+	// act
+	s := Service{}
+	_, err := s.ExportMediumToMd("f744fbff033e", internet.ExportMediumToMdOptions{Retries: -1})
+
+	// assert
+	assert.EqualError(t, err, "the number of retries must not be negative")
+}
+
+func TestExportMediumToMdInvalidAssetsDir(t *testing.T) {
+	testCases := []struct {
+		name      string
+		assetsDir string
+		want      string
+	}{
+		{"parent", "../..", `the assets directory "../.." must not contain ..`},
+		{"parent inside", "images/../../x", `the assets directory "images/../../x" must not contain ..`},
+		{"windows parent", `images\..\..`, `the assets directory "images\\..\\.." must not contain ..`},
+		{"absolute", "/tmp/images", `the assets directory "/tmp/images" must be a relative path`},
+		{"windows absolute", `C:\images`, `the assets directory "C:\\images" must be a relative path`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			s := Service{}
+			_, err := s.ExportMediumToMd("f744fbff033e", internet.ExportMediumToMdOptions{AssetsDir: tc.assetsDir})
+
+			// assert
+			assert.EqualError(t, err, tc.want)
+		})
+	}
+}
+
+func TestImageFilename(t *testing.T) {
+	assert.Equal(t, "1_abc.jpg", imageFilename("1*abc.jpeg", "image/jpeg"))
+	assert.Equal(t, "0_x-y.gif", imageFilename("0*x-y", "image/gif"))
+	assert.Equal(t, "etc_passwd", imageFilename("../etc/passwd", "text/plain"))
+	assert.Equal(t, "image.png", imageFilename("***", "image/png"))
+	assert.Equal(t, "image", imageFilename("", "text/plain"))
+}
+
+func TestUniqueFilename(t *testing.T) {
+	// arrange
+	used := map[string]bool{}
+
+	// act
+	names := []string{
+		uniqueFilename("a.png", used),
+		uniqueFilename("a.png", used),
+		uniqueFilename("a.png", used),
+		uniqueFilename("a-2.png", used),
+		uniqueFilename("image", used),
+		uniqueFilename("image", used),
+	}
+
+	// assert
+	assert.Equal(t, []string{"a.png", "a-2.png", "a-3.png", "a-2-2.png", "image", "image-2"}, names)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"strings"
+	"unicode/utf16"
+)
+
+// mediumBodyModel converts a document to the paragraphs and markups of
+// Medium, the inverse of mediumDocument. Medium has fewer kinds of
+// blocks, so headings are H3 up to level 2 and H4 below, rules start
+// sections, and tables are preformatted text.
+func mediumBodyModel(d document) mediumPostContentBodyModel {
+	model := mediumPostContentBodyModel{
+		Paragraphs: []mediumPostParagraph{},
+		Sections:   []mediumPostSection{{StartIndex: 0}},
+	}
+
+	add := func(paragraphType string, inlines []inline) {
+		text, markups := mediumParagraphText(inlines)
+		model.Paragraphs = append(model.Paragraphs, mediumPostParagraph{Type: paragraphType, Text: text, Markups: markups})
+	}
+
+	for _, b := range d.blocks {
+		switch b.kind {
+		case blockHeading:
+			if b.level <= 2 {
+				add("H3", b.inlines)
+			} else {
+				add("H4", b.inlines)
+			}
+		case blockParagraph:
+			add("P", b.inlines)
+		case blockImage:
+			model.Paragraphs = append(model.Paragraphs, mediumPostParagraph{
+				Type:     "IMG",
+				Text:     b.alt,
+				Markups:  []mediumPostParagraphMarkup{},
+				Metadata: mediumPostParagraphMetadata{Id: b.src},
+			})
+		case blockCode:
+			model.Paragraphs = append(model.Paragraphs, mediumPostParagraph{
+				Type:              "PRE",
+				Text:              b.code,
+				Markups:           []mediumPostParagraphMarkup{},
+				CodeBlockMetadata: mediumPostParagraphCodeBlockMetadata{Lang: b.lang},
+			})
+		case blockList:
+			paragraphType := "ULI"
+			if b.ordered {
+				paragraphType = "OLI"
+			}
+			for _, item := range b.items {
+				add(paragraphType, item)
+			}
+		case blockQuote:
+			add("BQ", b.inlines)
+		case blockRule:
+			// a section can not be empty
+			start := len(model.Paragraphs)
+			if start > model.Sections[len(model.Sections)-1].StartIndex {
+				model.Sections = append(model.Sections, mediumPostSection{StartIndex: start})
+			}
+		case blockTable:
+			model.Paragraphs = append(model.Paragraphs, mediumPostParagraph{
+				Type:    "PRE",
+				Text:    textRenderer{}.table(b),
+				Markups: []mediumPostParagraphMarkup{},
+			})
+		case blockEmbed:
+			add("P", []inline{linkInline(firstNonEmpty(b.title, b.href), b.href)})
+		}
+	}
+
+	return model
+}
+
+// mediumParagraphText returns the text of inlines and their markups,
+// with the offsets in UTF-16 code units as Medium expects.
+func mediumParagraphText(inlines []inline) (string, []mediumPostParagraphMarkup) {
+	var builder strings.Builder
+	markups := []mediumPostParagraphMarkup{}
+	position := 0
+
+	var walk func(inlines []inline)
+	walk = func(inlines []inline) {
+		for _, node := range inlines {
+			if node.kind == inlineText {
+				builder.WriteString(node.text)
+				position += len(utf16.Encode([]rune(node.text)))
+				continue
+			}
+
+			start := position
+			walk(node.children)
+			if position == start {
+				continue
+			}
+
+			markup := mediumPostParagraphMarkup{Start: start, End: position}
+			switch node.kind {
+			case inlineStrong:
+				markup.Type = "STRONG"
+			case inlineEmphasis:
+				markup.Type = "EM"
+			case inlineCode:
+				markup.Type = "CODE"
+			case inlineLink:
+				markup.Type = "A"
+				markup.HRef = node.href
+				markup.AnchorType = "LINK"
+			}
+			markups = append(markups, markup)
+		}
+	}
+	walk(inlines)
+
+	return builder.String(), markups
+}
@@ -0,0 +1,86 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMediumParagraphText(t *testing.T) {
+	// arrange
+	inlines := parseMarkdown("😀 **bold** *é* `code` [Go](https://go.dev)").blocks[0].inlines
+
+	// act
+	text, markups := mediumParagraphText(inlines)
+
+	// assert
+	assert.Equal(t, "😀 bold é code Go", text)
+	assert.Equal(t, []mediumPostParagraphMarkup{
+		{Type: "STRONG", Start: 3, End: 7},
+		{Type: "EM", Start: 8, End: 9},
+		{Type: "CODE", Start: 10, End: 14},
+		{Type: "A", HRef: "https://go.dev", AnchorType: "LINK", Start: 15, End: 17},
+	}, markups)
+}
+
+func TestMediumBodyModel(t *testing.T) {
+	// arrange
+	d := parseMarkdown("# Go tips\n\n## Small\n\n#### Smaller\n\ntext\n\n---\n\n---\n\n" +
+		"![The gopher](https://example.com/gopher.png)\n\n```go\nx := 1\n```\n\n- one\n\n> quote\n\n" +
+		"| a | b |\n| - | - |\n| 1 | 2 |")
+
+	// act
+	model := mediumBodyModel(d)
+
+	// assert
+	types := []string{}
+	for _, p := range model.Paragraphs {
+		types = append(types, p.Type)
+	}
+	assert.Equal(t, []string{"H3", "H4", "P", "IMG", "PRE", "ULI", "BQ", "PRE"}, types)
+	assert.Equal(t, []mediumPostSection{{StartIndex: 0}, {StartIndex: 3}}, model.Sections)
+	assert.Equal(t, "https://example.com/gopher.png", model.Paragraphs[3].Metadata.Id)
+	assert.Equal(t, "go", model.Paragraphs[4].CodeBlockMetadata.Lang)
+	assert.Equal(t, "a  b\n-  -\n1  2", model.Paragraphs[7].Text)
+}
+
+func TestMediumBodyModelRoundTrip(t *testing.T) {
+	// arrange
+	markdown := "# Go tips\n" +
+		"\n## Small things\n" +
+		"\nUse **small** `interfaces` and read [Effective *Go*](https://go.dev/doc/effective_go).\n" +
+		"\n![The gopher](https://example.com/gopher.png)\n" +
+		"\n```go\ntype Service struct {\n}\n```\n" +
+		"\n- Testable\n- Clear\n" +
+		"\n1. Define\n2. Implement\n" +
+		"\n> Simplicity is complicated.\n" +
+		"\n---\n" +
+		"\nThe end.\n"
+	d := parseMarkdown(markdown)
+	post := mediumPostResponse{}
+	post.Data.Post.Title = d.title
+	post.Data.Post.Content.BodyModel = mediumBodyModel(d)
+
+	// act
+	output := markdownRenderer{}.render(NewService().mediumDocument(context.Background(), post, nil))
+
+	// assert
+	assert.Equal(t, markdown, output)
+}
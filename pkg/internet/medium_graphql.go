@@ -0,0 +1,240 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+type mediumGraphQLResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []mediumGraphQLError       `json:"errors"`
+}
+
+type mediumGraphQLError struct {
+	Message    string `json:"message"`
+	Extensions struct {
+		Code string `json:"code"`
+	} `json:"extensions"`
+}
+
+// mediumResponse is the response of a GraphQL query sent to Medium.
+type mediumResponse struct {
+	body []byte
+	// notModified is true if the query had conditions and Medium answered
+	// the result did not change
+	notModified  bool
+	etag         string
+	lastModified string
+}
+
+// queryMedium sends a GraphQL query to Medium, with the extra headers,
+// and returns the response after checking it has the field with the
+// result.
+//
+// Queries rate limited or failed with a server error are retried with an
+// exponential backoff with jitter, waiting what Medium asks for in the
+// Retry-After header if it does. Network errors are not retried, as the
+// request timeout would be multiplied.
+func (s *Service) queryMedium(ctx context.Context, query []byte, postId string, field string, header http.Header) (mediumResponse, error) {
+	retries, base, max := s.mediumRetryPolicy()
+
+	for attempt := 0; ; attempt++ {
+		response, retryAfter, err := s.tryQueryMedium(ctx, query, postId, field, header)
+		if err == nil || attempt >= retries || !retryable(err) {
+			return response, err
+		}
+
+		delay := retryAfter
+		if delay == 0 {
+			delay = backoff(base, max, attempt)
+		}
+		if delay > max {
+			return response, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return response, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// tryQueryMedium sends a GraphQL query to Medium once, returning the
+// delay asked for in the Retry-After header, if any, on errors.
+func (s *Service) tryQueryMedium(ctx context.Context, query []byte, postId string, field string, header http.Header) (mediumResponse, time.Duration, error) {
+	response := mediumResponse{}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.mediumUrl(), bytes.NewBuffer(query))
+	if err != nil {
+		return response, 0, fmt.Errorf("error creating request: %s", err.Error())
+	}
+
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", s.userAgentHeader())
+
+	resp, err := s.httpClient().Do(req)
+	if err != nil {
+		return response, 0, fmt.Errorf("error executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return response, 0, fmt.Errorf("error reading response: %s", err.Error())
+	}
+
+	response.etag = resp.Header.Get("ETag")
+	response.lastModified = resp.Header.Get("Last-Modified")
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		response.notModified = true
+		return response, 0, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return response, 0, &internet.MediumPostNotFoundError{PostId: postId}
+	case resp.StatusCode == http.StatusPaymentRequired:
+		return response, 0, &internet.MediumPaywalledError{PostId: postId}
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return response, retryAfter, &internet.MediumRateLimitedError{RetryAfter: retryAfter}
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return response, retryAfter, &internet.MediumUpstreamError{
+			StatusCode: resp.StatusCode,
+			Message:    firstNonEmpty(strings.TrimSpace(string(body)), http.StatusText(resp.StatusCode)),
+		}
+	}
+
+	result := mediumGraphQLResponse{}
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return response, 0, fmt.Errorf("error un-marshalling medium response: %s", err.Error())
+	}
+
+	if len(result.Errors) > 0 {
+		return response, retryAfter, graphQLError(result.Errors, resp.StatusCode, postId, retryAfter)
+	}
+
+	data, ok := result.Data[field]
+	if !ok || string(data) == "null" {
+		return response, 0, &internet.MediumPostNotFoundError{PostId: postId}
+	}
+
+	response.body = body
+	return response, 0, nil
+}
+
+// graphQLError converts the errors of a GraphQL response into the error
+// they represent, using the code in the extensions. The messages are only
+// for people: errors without a known code, like the ones of the schema,
+// are upstream errors.
+func graphQLError(graphQLErrors []mediumGraphQLError, statusCode int, postId string, retryAfter time.Duration) error {
+	messages := []string{}
+	for _, e := range graphQLErrors {
+		switch strings.ToUpper(e.Extensions.Code) {
+		case "NOT_FOUND":
+			return &internet.MediumPostNotFoundError{PostId: postId}
+		case "RATE_LIMITED", "TOO_MANY_REQUESTS":
+			return &internet.MediumRateLimitedError{RetryAfter: retryAfter}
+		case "PAYWALLED", "MEMBER_ONLY":
+			return &internet.MediumPaywalledError{PostId: postId}
+		}
+
+		messages = append(messages, e.Message)
+	}
+
+	return &internet.MediumUpstreamError{StatusCode: statusCode, Message: strings.Join(messages, "; ")}
+}
+
+// retryable returns true for the errors that may go away by retrying:
+// rate limits and server errors.
+func retryable(err error) bool {
+	var rateLimited *internet.MediumRateLimitedError
+	if errors.As(err, &rateLimited) {
+		return true
+	}
+
+	var upstream *internet.MediumUpstreamError
+	return errors.As(err, &upstream) && upstream.StatusCode >= 500
+}
+
+var (
+	jitterMutex  sync.Mutex
+	jitterSource = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// backoff returns the delay before a retry: half of it doubles on each
+// attempt and the other half is random, so clients that failed together
+// do not retry together.
+func backoff(base, max time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 0; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+
+	jitterMutex.Lock()
+	defer jitterMutex.Unlock()
+
+	half := delay / 2
+	return half + time.Duration(jitterSource.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter parses the value of a Retry-After header, either a
+// number of seconds or an HTTP date, returning zero if it is not valid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil || !date.After(now) {
+		return 0
+	}
+
+	return date.Sub(now)
+}
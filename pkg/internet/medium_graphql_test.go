@@ -0,0 +1,270 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubResponse struct {
+	status     int
+	retryAfter string
+	body       string
+}
+
+// newStubServer returns a server answering with the responses in
+// order, repeating the last one, and counting the requests.
+func newStubServer(responses ...stubResponse) (*httptest.Server, *int32) {
+	requests := new(int32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(atomic.AddInt32(requests, 1)) - 1
+		if i >= len(responses) {
+			i = len(responses) - 1
+		}
+
+		response := responses[i]
+		if response.retryAfter != "" {
+			w.Header().Set("Retry-After", response.retryAfter)
+		}
+		w.WriteHeader(response.status)
+		w.Write([]byte(response.body))
+	}))
+
+	return server, requests
+}
+
+const stubPost = `{"data":{"post":{"id":"f744fbff033e","title":"Title","creator":{"name":"Author"}}}}`
+
+func newStubMediumService(server *httptest.Server, opts ...Option) *Service {
+	opts = append([]Option{WithMediumBackoff(time.Millisecond, 50*time.Millisecond)}, opts...)
+	return newMediumService(server, opts...)
+}
+
+func TestGetPostDataErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		response stubResponse
+		check    func(err error) bool
+	}{
+		{
+			name:     "http not found",
+			response: stubResponse{status: http.StatusNotFound},
+			check:    func(err error) bool { e := &internet.MediumPostNotFoundError{}; return errors.As(err, &e) },
+		},
+		{
+			name:     "null post",
+			response: stubResponse{status: http.StatusOK, body: `{"data":{"post":null}}`},
+			check:    func(err error) bool { e := &internet.MediumPostNotFoundError{}; return errors.As(err, &e) },
+		},
+		{
+			name:     "graphql not found",
+			response: stubResponse{status: http.StatusOK, body: `{"errors":[{"message":"Post not found","extensions":{"code":"NOT_FOUND"}}],"data":{"post":null}}`},
+			check:    func(err error) bool { e := &internet.MediumPostNotFoundError{}; return errors.As(err, &e) },
+		},
+		{
+			name:     "payment required",
+			response: stubResponse{status: http.StatusPaymentRequired},
+			check:    func(err error) bool { e := &internet.MediumPaywalledError{}; return errors.As(err, &e) },
+		},
+		{
+			name:     "locked post",
+			response: stubResponse{status: http.StatusOK, body: `{"data":{"post":{"id":"f744fbff033e","title":"Title","isLocked":true}}}`},
+			check:    func(err error) bool { e := &internet.MediumPaywalledError{}; return errors.As(err, &e) },
+		},
+		{
+			name:     "graphql member only",
+			response: stubResponse{status: http.StatusOK, body: `{"errors":[{"message":"denied","extensions":{"code":"MEMBER_ONLY"}}]}`},
+			check:    func(err error) bool { e := &internet.MediumPaywalledError{}; return errors.As(err, &e) },
+		},
+		{
+			name:     "graphql error",
+			response: stubResponse{status: http.StatusOK, body: `{"errors":[{"message":"Cannot query field \"foo\""}]}`},
+			check: func(err error) bool {
+				e := &internet.MediumUpstreamError{}
+				return errors.As(err, &e) && e.StatusCode == http.StatusOK && e.Message == `Cannot query field "foo"`
+			},
+		},
+		{
+			name:     "graphql schema error about members",
+			response: stubResponse{status: http.StatusOK, body: `{"errors":[{"message":"Cannot query field \"members\" on type \"Post\"","extensions":{"code":"GRAPHQL_VALIDATION_FAILED"}}]}`},
+			check: func(err error) bool {
+				e := &internet.MediumUpstreamError{}
+				return errors.As(err, &e) && e.Message == `Cannot query field "members" on type "Post"`
+			},
+		},
+		{
+			name:     "graphql schema error about a field not found",
+			response: stubResponse{status: http.StatusOK, body: `{"errors":[{"message":"Field 'x' not found on type 'Post'"}]}`},
+			check: func(err error) bool {
+				e := &internet.MediumUpstreamError{}
+				return errors.As(err, &e) && e.Message == "Field 'x' not found on type 'Post'"
+			},
+		},
+		{
+			name:     "graphql rate limit message without code",
+			response: stubResponse{status: http.StatusOK, body: `{"errors":[{"message":"Argument \"rate limit\" is unknown"}]}`},
+			check:    func(err error) bool { e := &internet.MediumUpstreamError{}; return errors.As(err, &e) },
+		},
+		{
+			name:     "bad request",
+			response: stubResponse{status: http.StatusBadRequest, body: "bad query"},
+			check: func(err error) bool {
+				e := &internet.MediumUpstreamError{}
+				return errors.As(err, &e) && e.StatusCode == http.StatusBadRequest && e.Message == "bad query"
+			},
+		},
+	}
+
+	for _, test := range tests {
+		// arrange
+		server, requests := newStubServer(test.response)
+
+		// act
+		s := newStubMediumService(server)
+		output, err := s.ConvertMediumToMd("f744fbff033e")
+		server.Close()
+
+		// assert
+		assert.True(t, test.check(err), "%s: %v", test.name, err)
+		assert.Empty(t, output.Markdown, test.name)
+		assert.Equal(t, int32(1), *requests, test.name)
+	}
+}
+
+func TestGetPostDataRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []stubResponse
+	}{
+		{"rate limited", []stubResponse{{status: http.StatusTooManyRequests, retryAfter: "0"}, {status: http.StatusOK, body: stubPost}}},
+		{"server error", []stubResponse{{status: http.StatusBadGateway}, {status: http.StatusServiceUnavailable}, {status: http.StatusOK, body: stubPost}}},
+		{"graphql rate limited", []stubResponse{{status: http.StatusOK, body: `{"errors":[{"message":"slow down","extensions":{"code":"RATE_LIMITED"}}]}`}, {status: http.StatusOK, body: stubPost}}},
+	}
+
+	for _, test := range tests {
+		// arrange
+		server, requests := newStubServer(test.responses...)
+
+		// act
+		s := newStubMediumService(server)
+		output, err := s.ConvertMediumToMd("f744fbff033e")
+		server.Close()
+
+		// assert
+		assert.Nil(t, err, test.name)
+		assert.Equal(t, "Title", output.Title, test.name)
+		assert.Equal(t, int32(len(test.responses)), *requests, test.name)
+	}
+}
+
+func TestGetPostDataGivesUpAfterRetries(t *testing.T) {
+	// arrange
+	server, requests := newStubServer(stubResponse{status: http.StatusTooManyRequests})
+	defer server.Close()
+
+	// act
+	s := newStubMediumService(server, WithMediumRetries(2))
+	_, err := s.ConvertMediumToMd("f744fbff033e")
+
+	// assert
+	rateLimited := &internet.MediumRateLimitedError{}
+	assert.True(t, errors.As(err, &rateLimited))
+	assert.Equal(t, int32(3), *requests)
+}
+
+func TestGetPostDataWithoutRetries(t *testing.T) {
+	// arrange
+	server, requests := newStubServer(stubResponse{status: http.StatusInternalServerError})
+	defer server.Close()
+
+	// act
+	s := newStubMediumService(server, WithMediumRetries(0))
+	_, err := s.ConvertMediumToMd("f744fbff033e")
+
+	// assert
+	upstream := &internet.MediumUpstreamError{}
+	assert.True(t, errors.As(err, &upstream))
+	assert.Equal(t, http.StatusInternalServerError, upstream.StatusCode)
+	assert.Equal(t, int32(1), *requests)
+}
+
+func TestGetPostDataRetryAfterTooLong(t *testing.T) {
+	// arrange
+	server, requests := newStubServer(stubResponse{status: http.StatusTooManyRequests, retryAfter: "3600"})
+	defer server.Close()
+
+	// act
+	s := newStubMediumService(server)
+	_, err := s.ConvertMediumToMd("f744fbff033e")
+
+	// assert
+	rateLimited := &internet.MediumRateLimitedError{}
+	assert.True(t, errors.As(err, &rateLimited))
+	assert.Equal(t, time.Hour, rateLimited.RetryAfter)
+	assert.Contains(t, err.Error(), "retry after 1h0m0s")
+	assert.Equal(t, int32(1), *requests)
+}
+
+func TestGetPostDataCancelledWhileWaiting(t *testing.T) {
+	// arrange
+	server, _ := newStubServer(stubResponse{status: http.StatusServiceUnavailable, retryAfter: "1"})
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// act
+	s := newMediumService(server, WithMediumBackoff(time.Millisecond, time.Minute))
+	_, err := s.ConvertMediumToMdContext(ctx, "f744fbff033e")
+
+	// assert
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt := 0; attempt < 10; attempt++ {
+		// act
+		delay := backoff(100*time.Millisecond, time.Second, attempt)
+
+		// assert
+		expected := 100 * time.Millisecond << attempt
+		if expected > time.Second {
+			expected = time.Second
+		}
+		assert.GreaterOrEqual(t, int64(delay), int64(expected/2))
+		assert.LessOrEqual(t, int64(delay), int64(expected))
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2021, 12, 15, 13, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 120*time.Second, parseRetryAfter("120", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter("Wed, 15 Dec 2021 13:00:30 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("Wed, 15 Dec 2021 12:00:00 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("-1", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+}
@@ -0,0 +1,145 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+var (
+	// post IDs are hexadecimal, 12 characters long for recent posts
+	mediumPostId = regexp.MustCompile(`^[0-9a-f]{10,12}$`)
+	// the slug of a post ends with its ID, e.g. some-title-f744fbff033e
+	mediumSlugPostId = regexp.MustCompile(`-([0-9a-f]{10,12})$`)
+	// Medium post URLs found in the body of a short link page
+	mediumPostUrl = regexp.MustCompile(`https://[\w.-]*medium\.com/[^\s"'<>]*-[0-9a-f]{10,12}\b`)
+)
+
+const mediumShortLinkHost = "link.medium.com"
+
+// ResolveMediumPostId extracts the ID of a Medium post from a post ID or
+// any URL of the post, including the ones of publications, custom
+// domains and link.medium.com short links.
+func (s *Service) ResolveMediumPostId(input string) (internet.ResolveMediumPostIdOutput, error) {
+	return s.ResolveMediumPostIdContext(context.Background(), input)
+}
+
+func (s *Service) ResolveMediumPostIdContext(ctx context.Context, input string) (internet.ResolveMediumPostIdOutput, error) {
+	output := internet.ResolveMediumPostIdOutput{Input: input}
+
+	value := strings.TrimSpace(input)
+	if postId := strings.ToLower(value); mediumPostId.MatchString(postId) {
+		output.PostId = postId
+		return output, nil
+	}
+
+	if !strings.Contains(value, "://") && strings.Contains(value, "/") {
+		value = "https://" + value
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return output, &internet.InvalidMediumInputError{Input: input, Reason: "not a post ID nor a URL"}
+	}
+
+	if strings.EqualFold(u.Hostname(), mediumShortLinkHost) {
+		u, err = s.followShortLink(ctx, u)
+		if err != nil {
+			return output, err
+		}
+	}
+
+	postId, ok := postIdFromUrl(u)
+	if !ok {
+		return output, &internet.InvalidMediumInputError{Input: input, Reason: "the URL does not point to a post"}
+	}
+
+	output.PostId = postId
+	output.Url = u.String()
+
+	return output, nil
+}
+
+// postIdFromUrl extracts the post ID from the path of a post URL, which
+// can be /p/<id>, /<slug>-<id>, /@user/<slug>-<id> or
+// /publication/<slug>-<id>, or from the redirect URL of the global
+// identity page.
+func postIdFromUrl(u *url.URL) (string, bool) {
+	if redirect := u.Query().Get("redirectUrl"); redirect != "" {
+		redirectUrl, err := url.Parse(redirect)
+		if err == nil && redirectUrl.Host != "" {
+			return postIdFromUrl(redirectUrl)
+		}
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	last := strings.ToLower(segments[len(segments)-1])
+
+	if len(segments) >= 2 && segments[len(segments)-2] == "p" && mediumPostId.MatchString(last) {
+		return last, true
+	}
+
+	match := mediumSlugPostId.FindStringSubmatch(last)
+	if match == nil {
+		return "", false
+	}
+
+	return match[1], true
+}
+
+// followShortLink resolves a link.medium.com short link by following its
+// redirects. When the last page is not a post, the post URL is searched
+// in its content, as some short links redirect with JavaScript.
+func (s *Service) followShortLink(ctx context.Context, u *url.URL) (*url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %s", err.Error())
+	}
+	req.Header.Set("User-Agent", s.userAgentHeader())
+
+	resp, err := s.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving short link: %w", err)
+	}
+	defer resp.Body.Close()
+
+	final := resp.Request.URL
+	if _, ok := postIdFromUrl(final); ok && !strings.EqualFold(final.Hostname(), mediumShortLinkHost) {
+		return final, nil
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %s", err.Error())
+	}
+
+	found := mediumPostUrl.Find(body)
+	if found == nil {
+		return nil, &internet.InvalidMediumInputError{Input: u.String(), Reason: "the short link does not point to a post"}
+	}
+
+	return url.Parse(string(found))
+}
@@ -0,0 +1,140 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveMediumPostId(t *testing.T) {
+	inputs := []string{
+		"f744fbff033e",
+		" f744fbff033e\n",
+		"F744FBFF033E",
+		"https://medium.com/@renato0307/writing-a-command-line-toolbox-in-go-f744fbff033e",
+		"https://medium.com/@renato0307/writing-a-command-line-toolbox-in-go-f744fbff033e?source=rss----1&sk=abc#section",
+		"medium.com/@renato0307/writing-a-command-line-toolbox-in-go-f744fbff033e",
+		"https://medium.com/p/f744fbff033e",
+		"https://medium.com/amp/p/f744fbff033e",
+		"https://renato0307.medium.com/writing-a-command-line-toolbox-in-go-f744fbff033e",
+		"https://medium.com/some-publication/writing-a-command-line-toolbox-in-go-f744fbff033e",
+		"https://blog.example.com/writing-a-command-line-toolbox-in-go-f744fbff033e",
+		"https://medium.com/m/global-identity?redirectUrl=https%3A%2F%2Fblog.example.com%2Fwriting-f744fbff033e",
+	}
+
+	for _, input := range inputs {
+		// act
+		s := Service{}
+		output, err := s.ResolveMediumPostId(input)
+
+		// assert
+		assert.Nil(t, err, input)
+		assert.Equal(t, "f744fbff033e", output.PostId, input)
+		assert.Equal(t, input, output.Input)
+	}
+}
+
+func TestResolveMediumPostIdInvalid(t *testing.T) {
+	inputs := []string{
+		"",
+		"not a post",
+		"f744fbff033e\") { id }",
+		"ftp://medium.com/p/f744fbff033e",
+		"https://medium.com/@renato0307",
+		"https://medium.com/@renato0307/writing-a-command-line-toolbox-in-go",
+	}
+
+	for _, input := range inputs {
+		// act
+		s := Service{}
+		_, err := s.ResolveMediumPostId(input)
+
+		// assert
+		invalidInputErr := &internet.InvalidMediumInputError{}
+		assert.True(t, errors.As(err, &invalidInputErr), input)
+		assert.Equal(t, input, invalidInputErr.Input)
+	}
+}
+
+func TestResolveMediumPostIdShortLink(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redirect":
+			http.Redirect(w, r, "https://medium.com/@renato0307/writing-a-command-line-toolbox-in-go-f744fbff033e", http.StatusTemporaryRedirect)
+		case "/javascript":
+			w.Write([]byte(`<html><script>window.location = "https://medium.com/@renato0307/writing-a-command-line-toolbox-in-go-f744fbff033e";</script></html>`))
+		default:
+			w.Write([]byte(`<html>nothing here</html>`))
+		}
+	}))
+	defer server.Close()
+	s := NewService(WithTransport(rewriteHostTransport{server}))
+
+	// act
+	redirected, redirectErr := s.ResolveMediumPostId("https://link.medium.com/redirect")
+	javascript, javascriptErr := s.ResolveMediumPostId("https://link.medium.com/javascript")
+	_, unknownErr := s.ResolveMediumPostId("https://link.medium.com/unknown")
+
+	// assert
+	assert.Nil(t, redirectErr)
+	assert.Equal(t, "f744fbff033e", redirected.PostId)
+	assert.Nil(t, javascriptErr)
+	assert.Equal(t, "f744fbff033e", javascript.PostId)
+	assert.Equal(t, "https://medium.com/@renato0307/writing-a-command-line-toolbox-in-go-f744fbff033e", javascript.Url)
+	assert.NotNil(t, unknownErr)
+}
+
+func TestConvertMediumToMdFromUrl(t *testing.T) {
+	// arrange
+	server := newMediumServer(t)
+	defer server.Close()
+
+	// act
+	s := newMediumService(server)
+	output, err := s.ConvertMediumToMd("https://medium.com/@renato0307/writing-a-command-line-toolbox-in-go-f744fbff033e")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "f744fbff033e", output.PostId)
+	assert.Contains(t, output.Markdown, "# Writing a command line toolbox in Go\n")
+}
+
+// rewriteHostTransport sends all the requests to a test server,
+// whatever their host is.
+type rewriteHostTransport struct {
+	server *httptest.Server
+}
+
+func (t rewriteHostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	target, _ := http.NewRequest(req.Method, t.server.URL+req.URL.RequestURI(), req.Body)
+	target.Header = req.Header
+	resp, err := t.server.Client().Transport.RoundTrip(target.WithContext(req.Context()))
+	if err != nil {
+		return nil, err
+	}
+
+	// keep the original URL so redirects are resolved against it
+	resp.Request = req
+	return resp, nil
+}
@@ -0,0 +1,238 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"sort"
+	"unicode/utf16"
+)
+
+// markupPriority defines how markups starting and ending at the same
+// positions are nested, lower values are rendered outside
+var markupPriority = map[string]int{
+	"A":      0,
+	"STRONG": 1,
+	"EM":     2,
+	"CODE":   3,
+}
+
+type inlineMarkup struct {
+	mediumPostParagraphMarkup
+	index int
+}
+
+// mediumInlines parses the text of a paragraph with its markups.
+//
+// Medium markup offsets are UTF-16 code units, like JavaScript strings,
+// so the text is converted before being split, never inside a surrogate
+// pair. Overlapping markups are
+// closed and reopened as needed so the result is always well nested.
+func mediumInlines(text string, markups []mediumPostParagraphMarkup) []inline {
+	units := utf16.Encode([]rune(text))
+
+	active := []inlineMarkup{}
+	boundaries := map[int]bool{0: true, len(units): true}
+	for i, markup := range markups {
+		if _, ok := markupPriority[markup.Type]; !ok {
+			continue
+		}
+
+		start := codePointStart(units, clamp(markup.Start, 0, len(units)))
+		end := codePointStart(units, clamp(markup.End, 0, len(units)))
+		if start >= end {
+			continue
+		}
+
+		markup.Start, markup.End = start, end
+		active = append(active, inlineMarkup{markup, i})
+		boundaries[start] = true
+		boundaries[end] = true
+	}
+
+	positions := []int{}
+	for position := range boundaries {
+		positions = append(positions, position)
+	}
+	sort.Ints(positions)
+
+	b := inlineBuilder{}
+	for i := 0; i < len(positions)-1; i++ {
+		start, end := positions[i], positions[i+1]
+
+		// keep the open markups that continue, as long as they are
+		// not inside one that ends, and open the others
+		continuing := map[int]bool{}
+		for _, markup := range active {
+			if markup.Start <= start && markup.End >= end {
+				continuing[markup.index] = true
+			}
+		}
+
+		common := 0
+		for common < len(b.stack) && continuing[b.stack[common].index] {
+			common++
+		}
+
+		// nothing can be opened inside code
+		if len(continuing) > common {
+			for j := 0; j < common; j++ {
+				if b.stack[j].Type == "CODE" {
+					common = j
+					break
+				}
+			}
+		}
+
+		opening := []inlineMarkup{}
+		for _, markup := range active {
+			if continuing[markup.index] && !b.isOpen(markup.index, common) {
+				opening = append(opening, markup)
+			}
+		}
+		sortMarkups(opening)
+
+		for len(b.stack) > common {
+			b.close()
+		}
+		for _, markup := range opening {
+			b.open(markup)
+		}
+
+		b.write(string(utf16.Decode(units[start:end])))
+	}
+	for len(b.stack) > 0 {
+		b.close()
+	}
+
+	return b.root
+}
+
+// sortMarkups sorts the markups from the outermost to the innermost,
+// the ones ending later being outside.
+func sortMarkups(markups []inlineMarkup) {
+	sort.SliceStable(markups, func(i, j int) bool {
+		if markups[i].Type == "CODE" || markups[j].Type == "CODE" {
+			return markupPriority[markups[i].Type] < markupPriority[markups[j].Type]
+		}
+		if markups[i].End != markups[j].End {
+			return markups[i].End > markups[j].End
+		}
+		if markups[i].Start != markups[j].Start {
+			return markups[i].Start < markups[j].Start
+		}
+		return markupPriority[markups[i].Type] < markupPriority[markups[j].Type]
+	})
+}
+
+var markupKinds = map[string]inlineKind{
+	"A":      inlineLink,
+	"STRONG": inlineStrong,
+	"EM":     inlineEmphasis,
+	"CODE":   inlineCode,
+}
+
+type openMarkup struct {
+	inlineMarkup
+	node inline
+}
+
+type inlineBuilder struct {
+	root  []inline
+	stack []openMarkup
+}
+
+// isOpen returns true if the markup is in the first count elements of
+// the stack.
+func (b *inlineBuilder) isOpen(index, count int) bool {
+	for _, markup := range b.stack[:count] {
+		if markup.index == index {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *inlineBuilder) open(markup inlineMarkup) {
+	node := inline{kind: markupKinds[markup.Type]}
+	if node.kind == inlineLink {
+		node.href = markupHRef(markup.mediumPostParagraphMarkup)
+	}
+
+	b.stack = append(b.stack, openMarkup{markup, node})
+}
+
+// close adds the innermost open markup to its parent, unless it is
+// empty.
+func (b *inlineBuilder) close() {
+	markup := b.stack[len(b.stack)-1]
+	b.stack = b.stack[:len(b.stack)-1]
+
+	if len(markup.node.children) > 0 {
+		b.append(markup.node)
+	}
+}
+
+func (b *inlineBuilder) write(text string) {
+	if text != "" {
+		b.append(textInline(text))
+	}
+}
+
+func (b *inlineBuilder) append(node inline) {
+	if len(b.stack) == 0 {
+		b.root = append(b.root, node)
+		return
+	}
+
+	top := &b.stack[len(b.stack)-1].node
+	top.children = append(top.children, node)
+}
+
+// markupHRef returns the destination of a link, pointing mentions to
+// the Medium profile of the user.
+func markupHRef(markup mediumPostParagraphMarkup) string {
+	href := markup.HRef
+	if href == "" && markup.UserId != "" {
+		href = "https://medium.com/u/" + markup.UserId
+	}
+
+	return href
+}
+
+// codePointStart moves a position inside a surrogate pair back to the
+// start of the pair, so splitting there does not break the code point.
+func codePointStart(units []uint16, position int) int {
+	if position > 0 && position < len(units) &&
+		units[position] >= 0xdc00 && units[position] <= 0xdfff &&
+		units[position-1] >= 0xd800 && units[position-1] <= 0xdbff {
+		return position - 1
+	}
+
+	return position
+}
+
+func clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+
+	return value
+}
@@ -0,0 +1,181 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMediumInlinesToMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		markups  []mediumPostParagraphMarkup
+		expected string
+	}{
+		{
+			name:     "plain text is escaped",
+			text:     "use *ptr and snake_case [sic]",
+			expected: `use \*ptr and snake\_case \[sic\]`,
+		},
+		{
+			name: "link keeps the text after it",
+			text: "see the docs for more",
+			markups: []mediumPostParagraphMarkup{
+				{Type: "A", HRef: "https://go.dev", Start: 8, End: 12},
+			},
+			expected: "see the [docs](https://go.dev) for more",
+		},
+		{
+			name: "strong, emphasis and code",
+			text: "bold italic code",
+			markups: []mediumPostParagraphMarkup{
+				{Type: "STRONG", Start: 0, End: 4},
+				{Type: "EM", Start: 5, End: 11},
+				{Type: "CODE", Start: 12, End: 16},
+			},
+			expected: "**bold** *italic* `code`",
+		},
+		{
+			name: "nested ranges",
+			text: "a very important link",
+			markups: []mediumPostParagraphMarkup{
+				{Type: "A", HRef: "https://example.com", Start: 0, End: 21},
+				{Type: "STRONG", Start: 7, End: 16},
+			},
+			expected: "[a very **important** link](https://example.com)",
+		},
+		{
+			name: "same range",
+			text: "both",
+			markups: []mediumPostParagraphMarkup{
+				{Type: "EM", Start: 0, End: 4},
+				{Type: "STRONG", Start: 0, End: 4},
+			},
+			expected: "***both***",
+		},
+		{
+			name: "overlapping ranges are closed and reopened",
+			text: "one two three",
+			markups: []mediumPostParagraphMarkup{
+				{Type: "STRONG", Start: 0, End: 7},
+				{Type: "EM", Start: 4, End: 13},
+			},
+			expected: "**one *two*** *three*",
+		},
+		{
+			name: "adjacent ranges",
+			text: "onetwo",
+			markups: []mediumPostParagraphMarkup{
+				{Type: "STRONG", Start: 0, End: 3},
+				{Type: "EM", Start: 3, End: 6},
+			},
+			expected: "**one***two*",
+		},
+		{
+			name: "spaces are moved outside the delimiters",
+			text: "a bold word",
+			markups: []mediumPostParagraphMarkup{
+				{Type: "STRONG", Start: 1, End: 7},
+			},
+			expected: "a **bold** word",
+		},
+		{
+			name: "offsets are utf-16 code units",
+			text: "🚀 café ünïcode",
+			markups: []mediumPostParagraphMarkup{
+				{Type: "EM", Start: 3, End: 7},
+				{Type: "STRONG", Start: 8, End: 15},
+			},
+			expected: "🚀 *café* **ünïcode**",
+		},
+		{
+			name: "offsets inside surrogate pairs move to the code point start",
+			text: "é😀ab c",
+			markups: []mediumPostParagraphMarkup{
+				{Type: "STRONG", Start: 2, End: 5},
+			},
+			expected: "é**😀ab** c",
+		},
+		{
+			name: "ends inside surrogate pairs move to the code point start",
+			text: "é😀ab c",
+			markups: []mediumPostParagraphMarkup{
+				{Type: "EM", Start: 0, End: 2},
+			},
+			expected: "*é*😀ab c",
+		},
+		{
+			name: "code is not escaped",
+			text: "call a_b*c or `x`",
+			markups: []mediumPostParagraphMarkup{
+				{Type: "CODE", Start: 5, End: 10},
+				{Type: "CODE", Start: 14, End: 17},
+			},
+			expected: "call `a_b*c` or `` `x` ``",
+		},
+		{
+			name: "user mentions link to the profile",
+			text: "by Renato",
+			markups: []mediumPostParagraphMarkup{
+				{Type: "A", UserId: "6b1e3b4f0a1c", AnchorType: "USER", Start: 3, End: 9},
+			},
+			expected: "by [Renato](https://medium.com/u/6b1e3b4f0a1c)",
+		},
+		{
+			name: "invalid and unknown markups are ignored",
+			text: "text",
+			markups: []mediumPostParagraphMarkup{
+				{Type: "STRONG", Start: 3, End: 1},
+				{Type: "STRONG", Start: 2, End: 2},
+				{Type: "UNKNOWN", Start: 0, End: 4},
+				{Type: "EM", Start: 2, End: 40},
+			},
+			expected: "te*xt*",
+		},
+	}
+
+	for _, test := range tests {
+		// act
+		output := markdownRenderer{}.inlines(mediumInlines(test.text, test.markups))
+
+		// assert
+		assert.Equal(t, test.expected, output, test.name)
+	}
+}
+
+func TestMediumInlinesNesting(t *testing.T) {
+	// arrange
+	markups := []mediumPostParagraphMarkup{
+		{Type: "STRONG", Start: 0, End: 7},
+		{Type: "EM", Start: 4, End: 13},
+	}
+
+	// act
+	output := mediumInlines("one two three", markups)
+
+	// assert
+	assert.Equal(t, []inline{
+		{kind: inlineStrong, children: []inline{
+			textInline("one "),
+			{kind: inlineEmphasis, children: []inline{textInline("two")}},
+		}},
+		{kind: inlineEmphasis, children: []inline{textInline(" three")}},
+	}, output)
+}
@@ -0,0 +1,455 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/renato0307/canivete-core/interface/internet"
+	"gopkg.in/yaml.v3"
+)
+
+// MediumPost is a post to publish on Medium.
+type MediumPost struct {
+	Title string
+	// ContentFormat is the format of the content: html or markdown
+	ContentFormat string
+	Content       string
+	Tags          []string
+	CanonicalUrl  string
+	// PublishStatus is draft, public or unlisted
+	PublishStatus string
+}
+
+// PublishedMediumPost is a post published on Medium.
+type PublishedMediumPost struct {
+	Id            string
+	Title         string
+	AuthorId      string
+	Url           string
+	CanonicalUrl  string
+	PublishStatus string
+	Tags          []string
+	PublishedAt   time.Time
+}
+
+// MediumPublisher publishes posts on Medium.
+type MediumPublisher interface {
+	Publish(ctx context.Context, post MediumPost) (PublishedMediumPost, error)
+}
+
+type mediumApiPublisher struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+// NewMediumApiPublisher creates a MediumPublisher using the Medium API
+// available at endpoint, or the public one if endpoint is empty,
+// authenticated with an integration token.
+func NewMediumApiPublisher(client *http.Client, endpoint, token string) MediumPublisher {
+	if endpoint == "" {
+		endpoint = defaultMediumApiEndpoint
+	}
+
+	return &mediumApiPublisher{client: client, endpoint: strings.TrimRight(endpoint, "/"), token: token}
+}
+
+type mediumApiUserResponse struct {
+	Data struct {
+		Id       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"data"`
+}
+
+type mediumApiPostRequest struct {
+	Title         string   `json:"title"`
+	ContentFormat string   `json:"contentFormat"`
+	Content       string   `json:"content"`
+	Tags          []string `json:"tags,omitempty"`
+	CanonicalUrl  string   `json:"canonicalUrl,omitempty"`
+	PublishStatus string   `json:"publishStatus,omitempty"`
+}
+
+type mediumApiPostResponse struct {
+	Data struct {
+		Id            string   `json:"id"`
+		Title         string   `json:"title"`
+		AuthorId      string   `json:"authorId"`
+		Url           string   `json:"url"`
+		CanonicalUrl  string   `json:"canonicalUrl"`
+		PublishStatus string   `json:"publishStatus"`
+		Tags          []string `json:"tags"`
+		PublishedAt   int64    `json:"publishedAt"`
+	} `json:"data"`
+}
+
+type mediumApiErrorResponse struct {
+	Errors []struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	} `json:"errors"`
+}
+
+// Publish creates the post as the user of the token, who is got first
+// as the API needs their ID.
+func (c *mediumApiPublisher) Publish(ctx context.Context, post MediumPost) (PublishedMediumPost, error) {
+	published := PublishedMediumPost{}
+
+	if c.token == "" {
+		return published, &internet.MediumUnauthorizedError{Message: "no token"}
+	}
+
+	user := mediumApiUserResponse{}
+	err := c.do(ctx, "GET", "/me", nil, &user)
+	if err != nil {
+		return published, fmt.Errorf("error getting medium user: %w", err)
+	}
+
+	request := mediumApiPostRequest{
+		Title:         post.Title,
+		ContentFormat: post.ContentFormat,
+		Content:       post.Content,
+		Tags:          post.Tags,
+		CanonicalUrl:  post.CanonicalUrl,
+		PublishStatus: post.PublishStatus,
+	}
+	response := mediumApiPostResponse{}
+	err = c.do(ctx, "POST", "/users/"+url.PathEscape(user.Data.Id)+"/posts", request, &response)
+	if err != nil {
+		return published, fmt.Errorf("error publishing medium post: %w", err)
+	}
+
+	published = PublishedMediumPost{
+		Id:            response.Data.Id,
+		Title:         response.Data.Title,
+		AuthorId:      response.Data.AuthorId,
+		Url:           response.Data.Url,
+		CanonicalUrl:  response.Data.CanonicalUrl,
+		PublishStatus: response.Data.PublishStatus,
+		Tags:          response.Data.Tags,
+		PublishedAt:   fromUnixMilli(response.Data.PublishedAt),
+	}
+
+	return published, nil
+}
+
+// do sends a request to the API, with the JSON of body if not nil, and
+// decodes the JSON response into v.
+func (c *mediumApiPublisher) do(ctx context.Context, method, path string, body interface{}, v interface{}) error {
+	var data []byte
+	if body != nil {
+		var err error
+		data, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error marshling request: %s", err.Error())
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("error creating request: %s", err.Error())
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Charset", "utf-8")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response: %s", err.Error())
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return mediumApiError(resp, responseBody)
+	}
+
+	err = json.Unmarshal(responseBody, v)
+	if err != nil {
+		return fmt.Errorf("error un-marshalling medium response: %s", err.Error())
+	}
+
+	return nil
+}
+
+// mediumApiError returns the error for a response of the API that
+// failed, with the messages of its errors.
+func mediumApiError(resp *http.Response, body []byte) error {
+	messages := []string{}
+	response := mediumApiErrorResponse{}
+	if json.Unmarshal(body, &response) == nil {
+		for _, e := range response.Errors {
+			messages = append(messages, e.Message)
+		}
+	}
+	message := strings.Join(messages, "; ")
+	if message == "" {
+		message = http.StatusText(resp.StatusCode)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return &internet.MediumUnauthorizedError{Message: message}
+	case http.StatusTooManyRequests:
+		return &internet.MediumRateLimitedError{RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())}
+	}
+
+	return &internet.MediumUpstreamError{StatusCode: resp.StatusCode, Message: message}
+}
+
+var mediumPublishStatuses = map[string]bool{"draft": true, "public": true, "unlisted": true}
+
+// mediumMaxTags is the maximum number of tags of a Medium post.
+const mediumMaxTags = 5
+
+// PublishMdToMedium publishes a post written in Markdown on Medium. The
+// Markdown is converted to the paragraphs of Medium, the inverse of
+// ConvertMediumToMd, and sent as HTML.
+func (s *Service) PublishMdToMedium(markdown string, options internet.PublishMdToMediumOptions) (internet.PublishMdToMediumOutput, error) {
+	return s.PublishMdToMediumContext(context.Background(), markdown, options)
+}
+
+func (s *Service) PublishMdToMediumContext(ctx context.Context, markdown string, options internet.PublishMdToMediumOptions) (internet.PublishMdToMediumOutput, error) {
+	output := internet.PublishMdToMediumOutput{}
+
+	status := strings.ToLower(options.PublishStatus)
+	if status == "" {
+		status = "draft"
+	}
+	if !mediumPublishStatuses[status] {
+		return output, fmt.Errorf("invalid publish status %q, use draft, public or unlisted", options.PublishStatus)
+	}
+
+	meta, body, err := splitFrontMatter(markdown)
+	if err != nil {
+		return output, err
+	}
+	d := parseMarkdown(body)
+	d.title = firstNonEmpty(options.Title, meta.title, d.title)
+	if d.title == "" {
+		return output, fmt.Errorf("the post has no title")
+	}
+
+	tags := options.Tags
+	if len(tags) == 0 {
+		tags = meta.tags
+	}
+	if len(tags) > mediumMaxTags {
+		return output, fmt.Errorf("a post can have up to %d tags, got %d", mediumMaxTags, len(tags))
+	}
+
+	post := mediumPostResponse{}
+	post.Data.Post.Title = d.title
+	post.Data.Post.Content.BodyModel = mediumBodyModel(d)
+	content := htmlRenderer{}.render(s.mediumDocument(ctx, post, nil))
+
+	published, err := s.mediumPublisher().Publish(ctx, MediumPost{
+		Title:         d.title,
+		ContentFormat: "html",
+		Content:       content,
+		Tags:          tags,
+		CanonicalUrl:  firstNonEmpty(options.CanonicalUrl, meta.canonicalUrl),
+		PublishStatus: status,
+	})
+	if err != nil {
+		return output, err
+	}
+
+	output = internet.PublishMdToMediumOutput{
+		PostId:        published.Id,
+		Title:         firstNonEmpty(published.Title, d.title),
+		Url:           published.Url,
+		PublishStatus: firstNonEmpty(published.PublishStatus, status),
+		Tags:          published.Tags,
+		PublishedAt:   published.PublishedAt,
+		Content:       content,
+	}
+
+	return output, nil
+}
+
+// draftFrontMatter has the fields of the front matter of a draft used
+// to publish it.
+type draftFrontMatter struct {
+	title        string
+	tags         []string
+	canonicalUrl string
+}
+
+// splitFrontMatter separates the YAML or TOML front matter from the
+// Markdown, reading the fields written by ConvertMediumToMdWithOptions
+// and the ones other tools commonly use for the same data.
+func splitFrontMatter(markdown string) (draftFrontMatter, string, error) {
+	meta := draftFrontMatter{}
+	delimiter, text, body, ok := cutFrontMatter(markdown)
+	if !ok {
+		return meta, markdown, nil
+	}
+
+	var fields []frontMatterEntry
+	var err error
+	if delimiter == "+++" {
+		fields, err = tomlFrontMatter(text)
+	} else {
+		fields, err = yamlFrontMatter(text)
+	}
+	if err != nil {
+		return meta, body, fmt.Errorf("invalid front matter: %w", err)
+	}
+
+	for _, field := range fields {
+		switch strings.ToLower(field.key) {
+		case "title":
+			meta.title = frontMatterString(field.value)
+		case "tags", "categories":
+			if len(meta.tags) == 0 {
+				meta.tags = frontMatterStrings(field.value)
+			}
+		case "canonicalurl", "canonical_url", "canonical":
+			meta.canonicalUrl = frontMatterString(field.value)
+		}
+	}
+
+	return meta, body, nil
+}
+
+// cutFrontMatter returns the delimiter and the text of the front matter
+// at the start of the Markdown, between lines with only --- or +++, and
+// the Markdown after it.
+func cutFrontMatter(markdown string) (string, string, string, bool) {
+	lines := strings.SplitAfter(markdown, "\n")
+	delimiter := strings.TrimRight(lines[0], " \t\r\n")
+	if delimiter != "---" && delimiter != "+++" {
+		return "", "", markdown, false
+	}
+
+	start := len(lines[0])
+	offset := start
+	for _, line := range lines[1:] {
+		if strings.TrimRight(line, " \t\r\n") == delimiter {
+			return delimiter, markdown[start:offset], markdown[offset+len(line):], true
+		}
+		offset += len(line)
+	}
+
+	return "", "", markdown, false
+}
+
+// frontMatterEntry is a top level field of a parsed front matter, in the
+// order of the document.
+type frontMatterEntry struct {
+	key   string
+	value *yaml.Node
+}
+
+func yamlFrontMatter(text string) ([]frontMatterEntry, error) {
+	document := yaml.Node{}
+	if err := yaml.Unmarshal([]byte(text), &document); err != nil {
+		return nil, err
+	}
+	if len(document.Content) == 0 {
+		return nil, nil
+	}
+
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("the front matter is not a mapping")
+	}
+
+	fields := []frontMatterEntry{}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		fields = append(fields, frontMatterEntry{key: root.Content[i].Value, value: root.Content[i+1]})
+	}
+
+	return fields, nil
+}
+
+func tomlFrontMatter(text string) ([]frontMatterEntry, error) {
+	values := map[string]interface{}{}
+	meta, err := toml.Decode(text, &values)
+	if err != nil {
+		return nil, err
+	}
+
+	// the values are converted to YAML nodes, to be read as the ones of
+	// a YAML front matter
+	fields := []frontMatterEntry{}
+	for _, key := range meta.Keys() {
+		if len(key) != 1 {
+			continue
+		}
+
+		value := &yaml.Node{}
+		if err := value.Encode(values[key[0]]); err != nil {
+			return nil, err
+		}
+		fields = append(fields, frontMatterEntry{key: key[0], value: value})
+	}
+
+	return fields, nil
+}
+
+// frontMatterString returns the value of a scalar, empty for null and
+// for other kinds of values.
+func frontMatterString(node *yaml.Node) string {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind != yaml.ScalarNode || node.Tag == "!!null" {
+		return ""
+	}
+
+	return node.Value
+}
+
+// frontMatterStrings returns the values of a list, or of a scalar as a
+// list with one item, skipping the empty ones.
+func frontMatterStrings(node *yaml.Node) []string {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	items := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		items = node.Content
+	}
+
+	values := []string{}
+	for _, item := range items {
+		if value := frontMatterString(item); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,257 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+// newMediumApiServer starts a stand-in for the Medium API accepting only
+// the token "secret", which records the posts created in posts.
+func newMediumApiServer(t *testing.T, posts *[]mediumApiPostRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"errors":[{"message":"Token was invalid.","code":6003}]}`))
+			return
+		}
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v1/me":
+			w.Write([]byte(`{"data":{"id":"5303d74c64f66366f00cb9b2a94f3251bf5","username":"renato0307"}}`))
+		case r.Method == "POST" && r.URL.Path == "/v1/users/5303d74c64f66366f00cb9b2a94f3251bf5/posts":
+			post := mediumApiPostRequest{}
+			err := json.NewDecoder(r.Body).Decode(&post)
+			if err != nil {
+				t.Fatal(err)
+			}
+			*posts = append(*posts, post)
+
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+				"id":            "e6f36a",
+				"title":         post.Title,
+				"authorId":      "5303d74c64f66366f00cb9b2a94f3251bf5",
+				"url":           "https://medium.com/@renato0307/e6f36a",
+				"canonicalUrl":  post.CanonicalUrl,
+				"publishStatus": post.PublishStatus,
+				"publishedAt":   1442286338435,
+				"tags":          post.Tags,
+			}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestPublishMdToMedium(t *testing.T) {
+	// arrange
+	posts := []mediumApiPostRequest{}
+	server := newMediumApiServer(t, &posts)
+	defer server.Close()
+	s := NewService(WithMediumApiEndpoint(server.URL+"/v1/"), WithMediumToken("secret"))
+
+	// act
+	output, err := s.PublishMdToMedium("# Go tips\n\nUse **small** interfaces.\n",
+		internet.PublishMdToMediumOptions{Tags: []string{"go"}, PublishStatus: "Public"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "e6f36a", output.PostId)
+	assert.Equal(t, "Go tips", output.Title)
+	assert.Equal(t, "https://medium.com/@renato0307/e6f36a", output.Url)
+	assert.Equal(t, "public", output.PublishStatus)
+	assert.Equal(t, []string{"go"}, output.Tags)
+	assert.Equal(t, time.Date(2015, 9, 15, 3, 5, 38, 435000000, time.UTC), output.PublishedAt.UTC())
+	assert.Contains(t, output.Content, "<p>Use <strong>small</strong> interfaces.</p>")
+	assert.Equal(t, []mediumApiPostRequest{{
+		Title:         "Go tips",
+		ContentFormat: "html",
+		Content:       output.Content,
+		Tags:          []string{"go"},
+		PublishStatus: "public",
+	}}, posts)
+}
+
+func TestPublishMdToMediumFrontMatter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		markdown string
+	}{
+		{"yaml", "---\ntitle: \"Go: tips\"\ntags:\n  - go\n  - testing\ncanonicalURL: https://renato.dev/go-tips\n---\nText.\n"},
+		{"yaml inline list", "---\ntitle: 'Go: tips'\ntags: [go, \"testing\"]\ncanonical_url: https://renato.dev/go-tips\n---\n# Ignored\n\nText.\n"},
+		{"toml", "+++\ntitle = \"Go: tips\"\ntags = [\"go\", \"testing\"]\ncanonicalURL = \"https://renato.dev/go-tips\"\n+++\nText.\n"},
+		{"yaml comments", "---\n# exported\ntitle: \"Go: tips\" # the title\ntags:\n  - go # the language\n  # - skipped\n  - testing\ncanonicalURL: https://renato.dev/go-tips\n---\nText.\n"},
+		{"yaml multi-line inline list", "---\ntitle: >-\n  Go:\n  tips\ncategories: [\n  go,\n  testing\n]\ncanonical: \"https://renato.dev/go-tips\"\n---\nText.\n"},
+		{"toml multi-line array", "+++\ntitle = \"\"\"Go: tips\"\"\"\ntags = [\n  \"go\", # the language\n  'testing',\n]\ncanonicalURL = \"https://renato.dev/go-tips\"\ndate = 2021-12-15T10:00:00Z\n+++\nText.\n"},
+		{"toml comments", "+++\n# exported\ntitle = \"Go: tips\" # the title\ntags = [\"go\", \"testing\"]\ncanonicalURL = \"https://renato.dev/go-tips\"\n\n[params]\ntitle = \"ignored\"\n+++\nText.\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			posts := []mediumApiPostRequest{}
+			server := newMediumApiServer(t, &posts)
+			defer server.Close()
+			s := NewService(WithMediumApiEndpoint(server.URL+"/v1"), WithMediumToken("secret"))
+
+			// act
+			output, err := s.PublishMdToMedium(tc.markdown, internet.PublishMdToMediumOptions{})
+
+			// assert
+			assert.Nil(t, err)
+			assert.Equal(t, "Go: tips", output.Title)
+			assert.Equal(t, "draft", output.PublishStatus)
+			assert.Equal(t, []string{"go", "testing"}, posts[0].Tags)
+			assert.Equal(t, "https://renato.dev/go-tips", posts[0].CanonicalUrl)
+			assert.NotContains(t, posts[0].Content, "Ignored")
+		})
+	}
+}
+
+func TestSplitFrontMatterReadsTheWrittenFrontMatter(t *testing.T) {
+	for _, format := range []string{"yaml", "toml"} {
+		// arrange
+		post := internet.ConvertMediumToMdOutput{
+			Title:        `Go: "tips" # 1`,
+			Tags:         []string{"go", "a: b"},
+			CanonicalUrl: "https://renato.dev/go-tips?a=1#top",
+		}
+		markdown := frontMatter(post, internet.ConvertMediumToMdOptions{FrontMatter: format}) + "Text.\n"
+
+		// act
+		meta, body, err := splitFrontMatter(markdown)
+
+		// assert
+		assert.Nil(t, err, format)
+		assert.Equal(t, post.Title, meta.title, format)
+		assert.Equal(t, post.Tags, meta.tags, format)
+		assert.Equal(t, post.CanonicalUrl, meta.canonicalUrl, format)
+		assert.Equal(t, "\nText.\n", body, format)
+	}
+}
+
+func TestPublishMdToMediumUnauthorized(t *testing.T) {
+	testCases := []struct {
+		name    string
+		token   string
+		message string
+	}{
+		{"invalid token", "wrong", "Token was invalid."},
+		{"no token", "", "no token"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			posts := []mediumApiPostRequest{}
+			server := newMediumApiServer(t, &posts)
+			defer server.Close()
+			s := NewService(WithMediumApiEndpoint(server.URL+"/v1"), WithMediumToken(tc.token))
+
+			// act
+			_, err := s.PublishMdToMedium("# Go tips\n\nText.\n", internet.PublishMdToMediumOptions{})
+
+			// assert
+			var unauthorized *internet.MediumUnauthorizedError
+			assert.True(t, errors.As(err, &unauthorized))
+			assert.Equal(t, tc.message, unauthorized.Message)
+			assert.Empty(t, posts)
+		})
+	}
+}
+
+func TestPublishMdToMediumUpstreamError(t *testing.T) {
+	testCases := []struct {
+		name   string
+		status int
+		want   error
+	}{
+		{"rate limited", http.StatusTooManyRequests, &internet.MediumRateLimitedError{RetryAfter: 30 * time.Second}},
+		{"server error", http.StatusBadGateway, &internet.MediumUpstreamError{StatusCode: http.StatusBadGateway, Message: "Bad Gateway"}},
+		{"bad request", http.StatusBadRequest, &internet.MediumUpstreamError{StatusCode: http.StatusBadRequest, Message: "Invalid publish status."}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "30")
+				w.WriteHeader(tc.status)
+				if tc.status == http.StatusBadRequest {
+					w.Write([]byte(`{"errors":[{"message":"Invalid publish status.","code":2004}]}`))
+				}
+			}))
+			defer server.Close()
+			s := NewService(WithMediumApiEndpoint(server.URL), WithMediumToken("secret"))
+
+			// act
+			_, err := s.PublishMdToMedium("# Go tips\n\nText.\n", internet.PublishMdToMediumOptions{})
+
+			// assert
+			assert.Equal(t, tc.want, errors.Unwrap(err))
+		})
+	}
+}
+
+func TestPublishMdToMediumInvalidOptions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		markdown string
+		options  internet.PublishMdToMediumOptions
+	}{
+		{"invalid status", "# Go tips", internet.PublishMdToMediumOptions{PublishStatus: "private"}},
+		{"too many tags", "# Go tips", internet.PublishMdToMediumOptions{Tags: []string{"a", "b", "c", "d", "e", "f"}}},
+		{"no title", "Text.", internet.PublishMdToMediumOptions{}},
+		{"invalid yaml front matter", "---\ntitle: [Go tips\n---\nText.", internet.PublishMdToMediumOptions{}},
+		{"yaml front matter that is not a mapping", "---\n- Go tips\n---\nText.", internet.PublishMdToMediumOptions{}},
+		{"invalid toml front matter", "+++\ntitle \"Go tips\"\n+++\nText.", internet.PublishMdToMediumOptions{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			s := NewService(WithMediumPublisher(failingMediumPublisher{t}))
+
+			// act
+			_, err := s.PublishMdToMedium(tc.markdown, tc.options)
+
+			// assert
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+// failingMediumPublisher fails the test if a post is published.
+type failingMediumPublisher struct {
+	t *testing.T
+}
+
+func (p failingMediumPublisher) Publish(ctx context.Context, post MediumPost) (PublishedMediumPost, error) {
+	p.t.Fatal("unexpected publish")
+	return PublishedMediumPost{}, nil
+}
@@ -17,21 +17,14 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 package internet
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
-	"strings"
 	"time"
 
 	"github.com/renato0307/canivete-core/interface/internet"
 )
 
-type mediumQuery struct {
-	Query string `json:"query"`
-}
-
 type mediumPostResponse struct {
 	Data mediumData `json:"data"`
 }
@@ -41,14 +34,40 @@ type mediumData struct {
 }
 
 type mediumPost struct {
-	Title   string            `json:"title"`
-	Creator mediumPostCreator `json:"creator"`
-	Content mediumPostContent `json:"content"`
+	Id                     string                    `json:"id"`
+	Title                  string                    `json:"title"`
+	CreatedAt              int64                     `json:"createdAt"`
+	FirstPublishedAt       int64                     `json:"firstPublishedAt"`
+	LatestPublishedAt      int64                     `json:"latestPublishedAt"`
+	UpdatedAt              int64                     `json:"updatedAt"`
+	MediumUrl              string                    `json:"mediumUrl"`
+	CanonicalUrl           string                    `json:"canonicalUrl"`
+	ReadingTime            float64                   `json:"readingTime"`
+	IsLocked               bool                      `json:"isLocked"`
+	PreviewImage           mediumPostPreviewImage    `json:"previewImage"`
+	Tags                   []mediumPostTag           `json:"tags"`
+	ExtendedPreviewContent mediumPostExtendedPreview `json:"extendedPreviewContent"`
+	Creator                mediumPostCreator         `json:"creator"`
+	Content                mediumPostContent         `json:"content"`
+}
+
+type mediumPostPreviewImage struct {
+	Id string `json:"id"`
+}
+
+type mediumPostTag struct {
+	Id           string `json:"id"`
+	DisplayTitle string `json:"displayTitle"`
+}
+
+type mediumPostExtendedPreview struct {
+	Subtitle string `json:"subtitle"`
 }
 
 type mediumPostCreator struct {
-	Name string `json:"name"`
-	Id   string `json:"id"`
+	Name     string `json:"name"`
+	Id       string `json:"id"`
+	Username string `json:"username"`
 }
 
 type mediumPostContent struct {
@@ -57,16 +76,23 @@ type mediumPostContent struct {
 
 type mediumPostContentBodyModel struct {
 	Paragraphs []mediumPostParagraph `json:"paragraphs"`
+	Sections   []mediumPostSection   `json:"sections"`
+}
+
+type mediumPostSection struct {
+	Name       string `json:"name"`
+	StartIndex int    `json:"startIndex"`
 }
 
 type mediumPostParagraph struct {
-	Text     string                      `json:"text"`
-	Type     string                      `json:"type"`
-	HRef     string                      `json:"href"`
-	IFrame   mediumPostParagraphIFrame   `json:"iframe"`
-	Layout   string                      `json:"layout"`
-	Markups  []mediumPostParagraphMarkup `json:"markups"`
-	Metadata mediumPostParagraphMetadata `json:"metadata"`
+	Text              string                               `json:"text"`
+	Type              string                               `json:"type"`
+	HRef              string                               `json:"href"`
+	IFrame            mediumPostParagraphIFrame            `json:"iframe"`
+	Layout            string                               `json:"layout"`
+	Markups           []mediumPostParagraphMarkup          `json:"markups"`
+	Metadata          mediumPostParagraphMetadata          `json:"metadata"`
+	CodeBlockMetadata mediumPostParagraphCodeBlockMetadata `json:"codeBlockMetadata"`
 }
 
 type mediumPostParagraphMarkup struct {
@@ -74,6 +100,7 @@ type mediumPostParagraphMarkup struct {
 	Title      string `json:"title"`
 	Type       string `json:"type"`
 	HRef       string `json:"href"`
+	UserId     string `json:"userId"`
 	Start      int    `json:"start"`
 	End        int    `json:"end"`
 	Rel        string `json:"rel"`
@@ -87,156 +114,210 @@ type mediumPostParagraphMetadata struct {
 	OriginalHeight int    `json:"originalHeight"`
 }
 
+type mediumPostParagraphCodeBlockMetadata struct {
+	Lang string `json:"lang"`
+	Mode string `json:"mode"`
+}
+
 type mediumPostParagraphIFrame struct {
 	MediaResource mediumPostParagraphIFrameMediaResource `json:"mediaResource"`
 }
 
 type mediumPostParagraphIFrameMediaResource struct {
+	Title        string `json:"title"`
 	HRef         string `json:"href"`
 	IFrameSrc    string `json:"iframeSrc"`
 	IFrameWidth  int    `json:"iframeWidth"`
 	IFrameHeight int    `json:"iframeHeight"`
 }
 
+// ConvertMediumToMd converts a Medium post to Markdown. The post can be
+// identified by its ID or any of its URLs.
 func (s *Service) ConvertMediumToMd(postId string) (internet.ConvertMediumToMdOutput, error) {
+	return s.ConvertMediumToMdContext(context.Background(), postId)
+}
+
+func (s *Service) ConvertMediumToMdContext(ctx context.Context, postId string) (internet.ConvertMediumToMdOutput, error) {
+	return s.ConvertMediumToMdWithOptionsContext(ctx, postId, internet.ConvertMediumToMdOptions{})
+}
+
+// ConvertMediumToMdWithOptions converts a Medium post to Markdown,
+// optionally prepending front matter for static site generators.
+func (s *Service) ConvertMediumToMdWithOptions(postId string, options internet.ConvertMediumToMdOptions) (internet.ConvertMediumToMdOutput, error) {
+	return s.ConvertMediumToMdWithOptionsContext(context.Background(), postId, options)
+}
+
+func (s *Service) ConvertMediumToMdWithOptionsContext(ctx context.Context, postId string, options internet.ConvertMediumToMdOptions) (internet.ConvertMediumToMdOutput, error) {
 	output := internet.ConvertMediumToMdOutput{}
 
-	result, err := getPostData(postId)
+	err := validateFrontMatterOptions(options)
 	if err != nil {
-		return output, fmt.Errorf("error getting post data: %s", err.Error())
+		return output, err
 	}
 
-	output.Markdown = postToMarkdown(result)
-	output.PostId = postId
+	resolved, err := s.ResolveMediumPostIdContext(ctx, postId)
+	if err != nil {
+		return output, err
+	}
+
+	result, cacheStatus, err := s.getPostData(ctx, resolved.PostId, options.BypassCache)
+	if err != nil {
+		return output, fmt.Errorf("error getting post data: %w", err)
+	}
+
+	output = s.convertPost(ctx, resolved.PostId, result, options, nil)
+	output.CacheStatus = cacheStatus
 
 	return output, nil
 }
 
-func getPostData(postId string) (mediumPostResponse, error) {
+// convertPost converts a post to Markdown and fills the output with its
+// metadata. The images are linked to Medium unless their ID is in
+// images, which has the paths to use.
+func (s *Service) convertPost(ctx context.Context, postId string, post mediumPostResponse, options internet.ConvertMediumToMdOptions, images map[string]string) internet.ConvertMediumToMdOutput {
+	data := post.Data.Post
+	output := internet.ConvertMediumToMdOutput{
+		PostId:       postId,
+		Title:        data.Title,
+		Subtitle:     data.ExtendedPreviewContent.Subtitle,
+		Author:       data.Creator.Name,
+		PublishedAt:  fromUnixMilli(firstNonZero(data.FirstPublishedAt, data.CreatedAt)),
+		UpdatedAt:    fromUnixMilli(firstNonZero(data.LatestPublishedAt, data.UpdatedAt)),
+		Tags:         []string{},
+		CanonicalUrl: firstNonEmpty(data.CanonicalUrl, data.MediumUrl),
+		ReadingTime:  time.Duration(data.ReadingTime * float64(time.Minute)).Round(time.Second),
+	}
 
-	post := mediumPostResponse{}
+	for _, tag := range data.Tags {
+		output.Tags = append(output.Tags, firstNonEmpty(tag.DisplayTitle, tag.Id))
+	}
 
-	url := "https://medium.com/_/graphql"
-
-	query := fmt.Sprintf(
-		`
-		query {
-			post(id: "%s") {
-			  title
-			  createdAt
-			  creator {
-				id
-				name
-			  }
-			  content {
-				bodyModel {
-				  paragraphs {
-					text
-					type
-					href
-					layout
-					markups {
-					  title
-					  type
-					  href
-					  userId
-					  start
-					  end
-					  anchorType
-					}
-					iframe {
-					  mediaResource {
-						href
-						iframeSrc
-						iframeWidth
-						iframeHeight
-					  }
-					}
-					metadata {
-					  id
-					  originalWidth
-					  originalHeight
-					}
-				  }
-				}
-			  }
-			}
-		  }
-		`,
-		postId)
-
-	queryStruct := mediumQuery{Query: query}
-
-	data, err := json.Marshal(queryStruct)
-	if err != nil {
-		return post, fmt.Errorf("error marshling request: %s", err.Error())
+	if id := data.PreviewImage.Id; id != "" {
+		image, ok := images[id]
+		if !ok {
+			image = s.mediumImageUrl(id)
+		}
+		output.PreviewImage = image
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(data))
-	if err != nil {
-		return post, fmt.Errorf("error creating request: %s", err.Error())
+	output.Markdown = frontMatter(output, options) + markdownRenderer{embedFallback: s.render.embedFallback}.render(s.mediumDocument(ctx, post, images))
+
+	return output
+}
+
+func fromUnixMilli(milliseconds int64) time.Time {
+	if milliseconds == 0 {
+		return time.Time{}
 	}
 
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	return time.Unix(0, milliseconds*int64(time.Millisecond)).UTC()
+}
+
+func firstNonZero(values ...int64) int64 {
+	for _, value := range values {
+		if value != 0 {
+			return value
+		}
+	}
 
-	// Set client timeout
-	client := &http.Client{Timeout: time.Second * 10}
+	return 0
+}
+
+// mediumPostFields are the fields of a post used in the conversions.
+var mediumPostFields = []graphQLField{
+	gqlField("id"),
+	gqlField("title"),
+	gqlField("createdAt"),
+	gqlField("firstPublishedAt"),
+	gqlField("latestPublishedAt"),
+	gqlField("updatedAt"),
+	gqlField("mediumUrl"),
+	gqlField("canonicalUrl"),
+	gqlField("readingTime"),
+	gqlField("isLocked"),
+	gqlField("previewImage", gqlField("id")),
+	gqlField("tags", gqlField("id"), gqlField("displayTitle")),
+	gqlField("extendedPreviewContent", gqlField("subtitle")),
+	gqlField("creator", gqlField("id"), gqlField("name"), gqlField("username")),
+	gqlField("content",
+		gqlField("bodyModel",
+			gqlField("paragraphs",
+				gqlField("text"),
+				gqlField("type"),
+				gqlField("href"),
+				gqlField("layout"),
+				gqlField("markups",
+					gqlField("title"),
+					gqlField("type"),
+					gqlField("href"),
+					gqlField("userId"),
+					gqlField("start"),
+					gqlField("end"),
+					gqlField("anchorType"),
+				),
+				gqlField("iframe",
+					gqlField("mediaResource",
+						gqlField("title"),
+						gqlField("href"),
+						gqlField("iframeSrc"),
+						gqlField("iframeWidth"),
+						gqlField("iframeHeight"),
+					),
+				),
+				gqlField("metadata", gqlField("id"), gqlField("originalWidth"), gqlField("originalHeight")),
+				gqlField("codeBlockMetadata", gqlField("lang"), gqlField("mode")),
+			),
+			gqlField("sections", gqlField("name"), gqlField("startIndex")),
+		),
+	),
+}
+
+// mediumPostQuery returns the query of a post. Only post IDs are
+// accepted, even though the ID is passed as a variable.
+func mediumPostQuery(postId string) (graphQLRequest, error) {
+	if !mediumPostId.MatchString(postId) {
+		return graphQLRequest{}, &internet.InvalidMediumInputError{Input: postId, Reason: "not a post ID"}
+	}
+
+	query := graphQLQuery{
+		operation: "PostQuery",
+		variables: []graphQLVariable{{name: "id", typ: "ID!", value: postId}},
+		fields:    []graphQLField{gqlField("post", mediumPostFields...).withArgument("id", "id")},
+	}
 
-	// Send request
-	resp, err := client.Do(req)
+	return query.request()
+}
+
+// getPostData gets a post from the cache of the service or from Medium,
+// returning the cache status.
+func (s *Service) getPostData(ctx context.Context, postId string, bypassCache bool) (mediumPostResponse, string, error) {
+
+	post := mediumPostResponse{}
+
+	query, err := mediumPostQuery(postId)
+	if err != nil {
+		return post, "", err
+	}
+
+	data, err := json.Marshal(query)
 	if err != nil {
-		return post, fmt.Errorf("error executing request: %s", err.Error())
+		return post, "", fmt.Errorf("error marshling request: %s", err.Error())
 	}
-	defer resp.Body.Close()
 
-	// Read response
-	body, err := ioutil.ReadAll(resp.Body)
+	body, cacheStatus, err := s.queryPost(ctx, data, postId, bypassCache)
 	if err != nil {
-		return post, fmt.Errorf("error reading response: %s", err.Error())
+		return post, "", err
 	}
 
 	// Convert response to the struct
 	err = json.Unmarshal(body, &post)
 	if err != nil {
-		return post, fmt.Errorf("error un-marshalling medium response: %s", err.Error())
+		return post, "", fmt.Errorf("error un-marshalling medium response: %s", err.Error())
 	}
 
-	return post, nil
-}
-
-func postToMarkdown(post mediumPostResponse) string {
-	var buffer bytes.Buffer
-	buffer.WriteString(fmt.Sprintf("# %s\n", post.Data.Post.Title))
-	buffer.WriteString(fmt.Sprintf("By %s\n", post.Data.Post.Creator.Name))
-
-	for _, paragraph := range post.Data.Post.Content.BodyModel.Paragraphs {
-		if paragraph.Type == "H3" {
-			buffer.WriteString(fmt.Sprintf("\n## %s\n", paragraph.Text))
-		} else if paragraph.Type == "H4" {
-			buffer.WriteString(fmt.Sprintf("\n### _%s_\n", paragraph.Text))
-		} else if paragraph.Type == "P" {
-			buffer.WriteString(fmt.Sprintf("\n%s\n", paragraph.Text))
-		} else if paragraph.Type == "IMG" {
-			buffer.WriteString(fmt.Sprintf("\n![%s](https://miro.medium.com/max/1400/%s)\n", paragraph.Text, paragraph.Metadata.Id))
-
-		}
-		if len(paragraph.Markups) > 0 {
-			textParts := []string{}
-			lastStartIndex := 0
-			for _, markup := range paragraph.Markups {
-				if markup.Type != "A" {
-					continue
-				}
-				textParts = append(textParts, paragraph.Text[lastStartIndex:markup.Start])
-				textParts = append(textParts, fmt.Sprintf("[%s](%s)",
-					paragraph.Text[markup.Start:markup.End],
-					markup.HRef))
-				lastStartIndex = markup.End
-			}
-			buffer.WriteString(fmt.Sprintf("\n%s\n", strings.Join(textParts, "")))
-		}
+	if post.Data.Post.IsLocked {
+		return post, "", &internet.MediumPaywalledError{PostId: postId}
 	}
 
-	return buffer.String()
+	return post, cacheStatus, nil
 }
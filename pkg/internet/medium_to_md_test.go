@@ -17,35 +17,336 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 package internet
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/renato0307/canivete-core/interface/internet"
 	"github.com/stretchr/testify/assert"
 )
 
+// newMediumServer starts a stand-in for the Medium GraphQL API that
+// answers with the recorded responses in testdata/medium.
+func newMediumServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "application/json; charset=utf-8", r.Header.Get("Content-Type"))
+
+		query := graphQLRequest{}
+		err := json.NewDecoder(r.Body).Decode(&query)
+		assert.Nil(t, err)
+
+		if query.OperationName == "UserPostsQuery" || query.OperationName == "PublicationPostsQuery" {
+			servePostsPage(w, query)
+			return
+		}
+		assert.Contains(t, query.Query, "post(id: $id)")
+
+		postId, ok := query.Variables["id"].(string)
+		if !ok || !mediumPostId.MatchString(postId) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		data, err := ioutil.ReadFile("testdata/medium/" + postId + ".json")
+		if err != nil {
+			w.Write([]byte(`{"data":{"post":null}}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+}
+
+// servePostsPage answers a query of the posts of a user or publication
+// with the page recorded in testdata/medium/lists for its cursor.
+func servePostsPage(w http.ResponseWriter, query graphQLRequest) {
+	name := "user-" + fmt.Sprint(query.Variables["username"])
+	if query.OperationName == "PublicationPostsQuery" {
+		name = "publication-" + fmt.Sprint(query.Variables["slug"])
+	}
+
+	paging, _ := query.Variables["paging"].(map[string]interface{})
+	if from, ok := paging["from"].(string); ok {
+		name += "-" + from
+	}
+
+	data, err := ioutil.ReadFile("testdata/medium/lists/" + name + ".json")
+	if err != nil {
+		w.Write([]byte(`{"data":{"user":null,"collection":null}}`))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func newMediumService(server *httptest.Server, opts ...Option) *Service {
+	opts = append([]Option{WithHTTPClient(server.Client()), WithMediumEndpoint(server.URL)}, opts...)
+	return NewService(opts...)
+}
+
 func TestConvertMediumToMd(t *testing.T) {
 	// arrange
 	postId := "f744fbff033e"
+	server := newMediumServer(t)
+	defer server.Close()
 
 	// act
-	s := Service{}
+	s := newMediumService(server)
 	output, err := s.ConvertMediumToMd(postId)
 
 	// assert
 	assert.Nil(t, err)
 	assert.Equal(t, postId, output.PostId)
-	assert.NotEmpty(t, output.Markdown)
+	assert.Contains(t, output.Markdown, "# Writing a command line toolbox in Go\nBy Renato Torres\n")
+	assert.Contains(t, output.Markdown, "\n## Writing a command line toolbox in Go\n")
+	assert.Contains(t, output.Markdown, "\n### *Architecture*\n")
+	assert.Contains(t, output.Markdown, "\nThe commands are built with [Cobra](https://github.com/spf13/cobra) and the logic lives in [canivete-core](https://github.com/renato0307/canivete-core).\n")
+	assert.Equal(t, 1, strings.Count(output.Markdown, "The commands are built"))
+	assert.Contains(t, output.Markdown, "![The architecture of canivete](https://miro.medium.com/max/1400/1*3fJ8mS6X2o2XJ0kQeZQm2A.png)")
+}
+
+func TestConvertMediumToMdMetadata(t *testing.T) {
+	// arrange
+	server := newMediumServer(t)
+	defer server.Close()
+
+	// act
+	s := newMediumService(server)
+	output, err := s.ConvertMediumToMd("f744fbff033e")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "Writing a command line toolbox in Go", output.Title)
+	assert.Equal(t, `How "canivete" keeps the small tasks in one place`, output.Subtitle)
+	assert.Equal(t, "Renato Torres", output.Author)
+	assert.Equal(t, time.Date(2021, 12, 15, 13, 0, 0, 0, time.UTC), output.PublishedAt)
+	assert.Equal(t, time.Date(2021, 12, 17, 13, 0, 0, 0, time.UTC), output.UpdatedAt)
+	assert.Equal(t, []string{"Golang", "Cli", "Programming"}, output.Tags)
+	assert.Equal(t, "https://medium.com/@renato0307/writing-a-command-line-toolbox-in-go-f744fbff033e", output.CanonicalUrl)
+	assert.Equal(t, 3*time.Minute+27*time.Second, output.ReadingTime)
+	assert.Equal(t, "https://miro.medium.com/max/1400/1*3fJ8mS6X2o2XJ0kQeZQm2A.png", output.PreviewImage)
+	assert.True(t, strings.HasPrefix(output.Markdown, "# Writing a command line toolbox in Go\n"))
+}
+
+func TestConvertMediumToMdWithFrontMatter(t *testing.T) {
+	tests := []struct {
+		options  internet.ConvertMediumToMdOptions
+		expected string
+	}{
+		{
+			internet.ConvertMediumToMdOptions{FrontMatter: "yaml"},
+			"---\n" +
+				"title: \"Writing a command line toolbox in Go\"\n" +
+				"description: \"How \\\"canivete\\\" keeps the small tasks in one place\"\n" +
+				"author: \"Renato Torres\"\n" +
+				"date: 2021-12-15T13:00:00Z\n" +
+				"lastmod: 2021-12-17T13:00:00Z\n" +
+				"tags: [\"Golang\", \"Cli\", \"Programming\"]\n" +
+				"canonicalURL: \"https://medium.com/@renato0307/writing-a-command-line-toolbox-in-go-f744fbff033e\"\n" +
+				"images: [\"https://miro.medium.com/max/1400/1*3fJ8mS6X2o2XJ0kQeZQm2A.png\"]\n" +
+				"---\n\n# Writing",
+		},
+		{
+			internet.ConvertMediumToMdOptions{FrontMatter: "toml", Flavour: "hugo"},
+			"+++\n" +
+				"title = \"Writing a command line toolbox in Go\"\n",
+		},
+		{
+			internet.ConvertMediumToMdOptions{FrontMatter: "yaml", Flavour: "jekyll"},
+			"---\n" +
+				"layout: \"post\"\n" +
+				"title: \"Writing a command line toolbox in Go\"\n",
+		},
+		{
+			internet.ConvertMediumToMdOptions{FrontMatter: "YAML", Flavour: "Astro"},
+			"---\n" +
+				"title: \"Writing a command line toolbox in Go\"\n" +
+				"description: \"How \\\"canivete\\\" keeps the small tasks in one place\"\n" +
+				"author: \"Renato Torres\"\n" +
+				"pubDate: 2021-12-15T13:00:00Z\n",
+		},
+	}
+
+	server := newMediumServer(t)
+	defer server.Close()
+
+	for _, test := range tests {
+		// act
+		s := newMediumService(server)
+		output, err := s.ConvertMediumToMdWithOptions("f744fbff033e", test.options)
+
+		// assert
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(output.Markdown, test.expected), output.Markdown)
+	}
+}
+
+func TestConvertMediumToMdWithInvalidFrontMatter(t *testing.T) {
+	// act
+	s := Service{}
+	_, tomlErr := s.ConvertMediumToMdWithOptions("f744fbff033e", internet.ConvertMediumToMdOptions{FrontMatter: "toml", Flavour: "jekyll"})
+	_, flavourErr := s.ConvertMediumToMdWithOptions("f744fbff033e", internet.ConvertMediumToMdOptions{FrontMatter: "yaml", Flavour: "gatsby"})
+
+	// assert
+	assert.NotNil(t, tomlErr)
+	assert.Contains(t, tomlErr.Error(), "jekyll does not support toml front matter")
+	assert.NotNil(t, flavourErr)
+	assert.Contains(t, flavourErr.Error(), "unsupported front matter flavour")
+}
+
+type stubGistClient struct {
+	gists map[string]Gist
+}
+
+func (c stubGistClient) Gist(ctx context.Context, id string) (Gist, error) {
+	gist, ok := c.gists[id]
+	if !ok {
+		return gist, fmt.Errorf("gist %s not found", id)
+	}
+
+	return gist, nil
 }
 
 func TestConvertMediumToMdWithIFrames(t *testing.T) {
 	// arrange
 	postId := "a2371a1c11b7"
+	server := newMediumServer(t)
+	defer server.Close()
+	gists := stubGistClient{gists: map[string]Gist{
+		"0d8e2b7e0e2a4e5c9a1f3b6d7c8e9f01": {
+			Files: []GistFile{{Filename: "servicetest.go", Language: "Go", Content: "package internet\n"}},
+		},
+	}}
 
 	// act
-	s := Service{}
+	s := newMediumService(server, WithGistClient(gists))
 	output, err := s.ConvertMediumToMd(postId)
 
 	// assert
 	assert.Nil(t, err)
 	assert.Equal(t, postId, output.PostId)
+	assert.Contains(t, output.Markdown, "# Testing Go services with embedded examples\n")
+	assert.Contains(t, output.Markdown, "\n```go\npackage internet\n```\n")
+	assert.Contains(t, output.Markdown, "\n[![Go Testing By Example](https://img.youtube.com/vi/ndmB0bj7eyw/hqdefault.jpg)](https://www.youtube.com/watch?v=ndmB0bj7eyw)\n")
+	assert.Contains(t, output.Markdown, "\n[Testing Go services](https://speakerdeck.com/renato0307/testing-go-services)\n")
+	assert.Contains(t, output.Markdown, "\n> [Mocking in Go with mockery](https://medium.com/@renato0307/mocking-in-go-1c3a5e7d9b0f)\n")
+}
+
+func TestConvertMediumToMdWithIFramesAsHTML(t *testing.T) {
+	// arrange
+	server := newMediumServer(t)
+	defer server.Close()
+
+	// act
+	s := newMediumService(server, WithGistClient(stubGistClient{}), WithEmbedFallback(EmbedFallbackHTML))
+	output, err := s.ConvertMediumToMd("a2371a1c11b7")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Contains(t, output.Markdown, "\n[servicetest.go](https://gist.github.com/renato0307/0d8e2b7e0e2a4e5c9a1f3b6d7c8e9f01)\n")
+	assert.Contains(t, output.Markdown, `<iframe src="https://speakerdeck.com/player/4f2a" width="710" height="399" frameborder="0" allowfullscreen></iframe>`)
+}
+
+func TestConvertMediumToMdWithBlocks(t *testing.T) {
+	// arrange
+	server := newMediumServer(t)
+	defer server.Close()
+
+	// act
+	s := newMediumService(server)
+	output, err := s.ConvertMediumToMd("5d3c2b1a0f9e")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "# Go tips for services\n"+
+		"By Renato Torres\n"+
+		"\n## Go tips for services\n"+
+		"\n## Small things that make a big difference\n"+
+		"\nStart with the service struct:\n"+
+		"\n```go\ntype Service struct {\n}\n```\n"+
+		"\nWhat we get:\n"+
+		"\n- Testable code\n- **Clear** dependencies\n"+
+		"\n1. Define the interface\n2. Implement it\n3. Generate the mocks\n"+
+		"\n> Accept interfaces, return structs.\n"+
+		"\n> Simplicity is complicated.\n"+
+		"\nRun the tests with:\n"+
+		"\n---\n"+
+		"\n```\ngo test ./...\n```\n"+
+		"\nThat's all!\n",
+		output.Markdown)
+}
+
+func TestConvertMediumToMdSendsUserAgent(t *testing.T) {
+	// arrange
+	userAgent := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"data":{"post":{"title":"Title"}}}`))
+	}))
+	defer server.Close()
+
+	// act
+	s := newMediumService(server, WithUserAgent("canivete-test/1.0"))
+	_, err := s.ConvertMediumToMd("f744fbff033e")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "canivete-test/1.0", userAgent)
+}
+
+func TestConvertMediumToMdTimeout(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	// act
+	s := newMediumService(server, WithTimeout(10*time.Millisecond))
+	_, err := s.ConvertMediumToMd("f744fbff033e")
+
+	// assert
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error getting post data")
+}
+
+func TestConvertMediumToMdContextDeadline(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// act
+	s := newMediumService(server)
+	_, err := s.ConvertMediumToMdContext(ctx, "f744fbff033e")
+
+	// assert
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestConvertMediumToMdWithTransport(t *testing.T) {
+	// arrange
+	server := newMediumServer(t)
+	defer server.Close()
+
+	// act
+	s := NewService(WithTransport(server.Client().Transport), WithMediumEndpoint(server.URL))
+	output, err := s.ConvertMediumToMd("f744fbff033e")
+
+	// assert
+	assert.Nil(t, err)
 	assert.NotEmpty(t, output.Markdown)
 }
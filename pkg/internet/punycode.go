@@ -0,0 +1,263 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Parameters of punycode, from RFC 3492.
+const (
+	punycodeBase        = 36
+	punycodeTMin        = 1
+	punycodeTMax        = 26
+	punycodeSkew        = 38
+	punycodeDamp        = 700
+	punycodeInitialBias = 72
+	punycodeInitialN    = 128
+	punycodeMaxRune     = 0x10ffff
+)
+
+// idnaPrefix is the prefix of the labels of a domain encoded in punycode.
+const idnaPrefix = "xn--"
+
+// idnaSeparators are the dots separating the labels of a domain, the full
+// stop and the ideographic ones IDNA maps to it.
+var idnaSeparators = strings.NewReplacer("。", ".", "．", ".", "｡", ".")
+
+// hostToAscii converts an internationalized domain to ASCII, encoding the
+// labels with other characters in punycode. Labels are only lowercased,
+// not mapped with the full IDNA tables.
+func hostToAscii(host string) (string, error) {
+	labels := strings.Split(idnaSeparators.Replace(host), ".")
+	for i, label := range labels {
+		label = strings.ToLower(label)
+		if isAscii(label) {
+			labels[i] = label
+			continue
+		}
+
+		encoded, err := punycodeEncode(label)
+		if err != nil {
+			return "", err
+		}
+		labels[i] = idnaPrefix + encoded
+	}
+
+	return strings.Join(labels, "."), nil
+}
+
+// hostToUnicode converts the labels of a domain encoded in punycode back
+// to Unicode. A label with the punycode prefix is only valid if it
+// decodes to non-ASCII characters that encode back to the same label.
+func hostToUnicode(host string) (string, error) {
+	labels := strings.Split(host, ".")
+	for i, label := range labels {
+		label = strings.ToLower(label)
+		if !strings.HasPrefix(label, idnaPrefix) {
+			continue
+		}
+
+		decoded, err := punycodeDecode(label[len(idnaPrefix):])
+		if err != nil {
+			return "", err
+		}
+		if decoded == "" || isAscii(decoded) {
+			return "", fmt.Errorf("invalid punycode label %q", label)
+		}
+		encoded, err := punycodeEncode(decoded)
+		if err != nil || idnaPrefix+encoded != label {
+			return "", fmt.Errorf("invalid punycode label %q", label)
+		}
+		labels[i] = decoded
+	}
+
+	return strings.Join(labels, "."), nil
+}
+
+func isAscii(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+// punycodeAdapt is the bias adaptation function of RFC 3492, section 6.1.
+func punycodeAdapt(delta, points int, first bool) int {
+	if first {
+		delta /= punycodeDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / points
+
+	k := 0
+	for delta > ((punycodeBase-punycodeTMin)*punycodeTMax)/2 {
+		delta /= punycodeBase - punycodeTMin
+		k += punycodeBase
+	}
+
+	return k + (punycodeBase-punycodeTMin+1)*delta/(delta+punycodeSkew)
+}
+
+// punycodeThreshold is the threshold t of the digit at position k.
+func punycodeThreshold(k, bias int) int {
+	switch {
+	case k <= bias:
+		return punycodeTMin
+	case k >= bias+punycodeTMax:
+		return punycodeTMax
+	}
+
+	return k - bias
+}
+
+func punycodeDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+
+	return byte('0' + d - 26)
+}
+
+func punycodeValue(c byte) (int, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c-'0') + 26, true
+	case c >= 'a' && c <= 'z':
+		return int(c - 'a'), true
+	case c >= 'A' && c <= 'Z':
+		return int(c - 'A'), true
+	}
+
+	return 0, false
+}
+
+// punycodeEncode encodes a label in punycode, as in RFC 3492, section 6.3.
+func punycodeEncode(label string) (string, error) {
+	runes := []rune(label)
+	var builder strings.Builder
+	for _, r := range runes {
+		if r < utf8.RuneSelf {
+			builder.WriteByte(byte(r))
+		}
+	}
+	basic := builder.Len()
+	handled := basic
+	if basic > 0 {
+		builder.WriteByte('-')
+	}
+
+	n, delta, bias := punycodeInitialN, 0, punycodeInitialBias
+	for handled < len(runes) {
+		// the smallest code point not handled yet
+		m := punycodeMaxRune + 1
+		for _, r := range runes {
+			if int(r) >= n && int(r) < m {
+				m = int(r)
+			}
+		}
+		if (m - n) > (punycodeMaxRune-delta)/(handled+1) {
+			return "", fmt.Errorf("label %q is too long for punycode", label)
+		}
+		delta += (m - n) * (handled + 1)
+		n = m
+
+		for _, r := range runes {
+			if int(r) < n {
+				delta++
+			}
+			if int(r) != n {
+				continue
+			}
+
+			q := delta
+			for k := punycodeBase; ; k += punycodeBase {
+				t := punycodeThreshold(k, bias)
+				if q < t {
+					break
+				}
+				builder.WriteByte(punycodeDigit(t + (q-t)%(punycodeBase-t)))
+				q = (q - t) / (punycodeBase - t)
+			}
+			builder.WriteByte(punycodeDigit(q))
+			bias = punycodeAdapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+		delta++
+		n++
+	}
+
+	return builder.String(), nil
+}
+
+// punycodeDecode decodes a label in punycode, as in RFC 3492, section 6.2.
+func punycodeDecode(encoded string) (string, error) {
+	output := []rune{}
+	start := 0
+	if last := strings.LastIndexByte(encoded, '-'); last >= 0 {
+		for i := 0; i < last; i++ {
+			if encoded[i] >= utf8.RuneSelf {
+				return "", fmt.Errorf("invalid punycode %q", encoded)
+			}
+			output = append(output, rune(encoded[i]))
+		}
+		start = last + 1
+	}
+
+	n, i, bias := punycodeInitialN, 0, punycodeInitialBias
+	for position := start; position < len(encoded); {
+		previous, w := i, 1
+		for k := punycodeBase; ; k += punycodeBase {
+			if position >= len(encoded) {
+				return "", fmt.Errorf("invalid punycode %q", encoded)
+			}
+			digit, ok := punycodeValue(encoded[position])
+			position++
+			if !ok || digit > (punycodeMaxRune-i)/w {
+				return "", fmt.Errorf("invalid punycode %q", encoded)
+			}
+			i += digit * w
+
+			t := punycodeThreshold(k, bias)
+			if digit < t {
+				break
+			}
+			w *= punycodeBase - t
+		}
+
+		bias = punycodeAdapt(i-previous, len(output)+1, previous == 0)
+		n += i / (len(output) + 1)
+		i %= len(output) + 1
+		if n > punycodeMaxRune {
+			return "", fmt.Errorf("invalid punycode %q", encoded)
+		}
+
+		output = append(output, 0)
+		copy(output[i+1:], output[i:])
+		output[i] = rune(n)
+		i++
+	}
+
+	return string(output), nil
+}
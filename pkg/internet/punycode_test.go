@@ -0,0 +1,102 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPunycode(t *testing.T) {
+	testCases := []struct {
+		decoded string
+		encoded string
+	}{
+		{"münchen", "mnchen-3ya"},
+		{"bücher", "bcher-kva"},
+		{"日本語", "wgv71a119e"},
+		{"пример", "e1afmkfd"},
+		{"3年b組金八先生", "3b-ww4c5e180e575a65lsy2b"},
+		{"-> $1.00 <-", "-> $1.00 <--"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.decoded, func(t *testing.T) {
+			// act
+			encoded, encodeErr := punycodeEncode(tc.decoded)
+			decoded, decodeErr := punycodeDecode(tc.encoded)
+
+			// assert
+			assert.Nil(t, encodeErr)
+			assert.Nil(t, decodeErr)
+			assert.Equal(t, tc.encoded, encoded)
+			assert.Equal(t, tc.decoded, decoded)
+		})
+	}
+}
+
+func TestPunycodeDecodeInvalid(t *testing.T) {
+	testCases := []string{"mnchen-3y", "ab-c!d", "é-abc", "99999999999"}
+
+	for _, tc := range testCases {
+		t.Run(tc, func(t *testing.T) {
+			// act
+			_, err := punycodeDecode(tc)
+
+			// assert
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestHostToAscii(t *testing.T) {
+	// act
+	host, err := hostToAscii("Bücher.例え。JP")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "xn--bcher-kva.xn--r8jz45g.jp", host)
+}
+
+func TestHostToUnicode(t *testing.T) {
+	// act
+	host, err := hostToUnicode("xn--bcher-kva.xn--r8jz45g.jp")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "bücher.例え.jp", host)
+}
+
+func TestHostToUnicodeInvalid(t *testing.T) {
+	testCases := []string{
+		"xn--.com",
+		"xn--abc-.com",
+		"xn--bcher-kva9.com",
+		"example.xn--ab-",
+	}
+
+	for _, host := range testCases {
+		t.Run(host, func(t *testing.T) {
+			// act
+			_, err := hostToUnicode(host)
+
+			// assert
+			assert.NotNil(t, err)
+		})
+	}
+}
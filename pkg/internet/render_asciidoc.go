@@ -0,0 +1,159 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"fmt"
+	"strings"
+)
+
+// asciidocRenderer renders documents as AsciiDoc, as processed by
+// Asciidoctor.
+type asciidocRenderer struct{}
+
+func (r asciidocRenderer) render(d document) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("= %s\n", escapeAsciidoc(d.title)))
+	if d.author != "" {
+		builder.WriteString(fmt.Sprintf("%s\n", d.author))
+	}
+
+	for _, b := range d.blocks {
+		if text := r.block(b); text != "" {
+			builder.WriteString(fmt.Sprintf("\n%s\n", text))
+		}
+	}
+
+	return builder.String()
+}
+
+func (r asciidocRenderer) block(b block) string {
+	switch b.kind {
+	case blockHeading:
+		return fmt.Sprintf("%s %s", strings.Repeat("=", clamp(b.level, 1, 6)), r.inlines(b.inlines))
+	case blockParagraph:
+		return r.inlines(b.inlines)
+	case blockImage:
+		attributes := asciidocAttribute(b.alt)
+		if b.href != "" {
+			attributes += ",link=" + asciidocAttribute(b.href)
+		}
+		return fmt.Sprintf("image::%s[%s]", b.src, attributes)
+	case blockCode:
+		delimiter := fence("-", 4, b.code)
+		if b.lang == "" {
+			return fmt.Sprintf("%s\n%s\n%s", delimiter, b.code, delimiter)
+		}
+		return fmt.Sprintf("[source,%s]\n%s\n%s\n%s", b.lang, delimiter, b.code, delimiter)
+	case blockList:
+		marker := "*"
+		if b.ordered {
+			marker = "."
+		}
+		items := []string{}
+		for _, item := range b.items {
+			items = append(items, fmt.Sprintf("%s %s", marker, r.inlines(item)))
+		}
+		return strings.Join(items, "\n")
+	case blockQuote:
+		return fmt.Sprintf("____\n%s\n____", r.inlines(b.inlines))
+	case blockRule:
+		return "'''"
+	case blockEmbed:
+		return r.inlines([]inline{linkInline(firstNonEmpty(b.title, b.href), b.href)})
+	case blockTable:
+		return r.table(b)
+	}
+
+	return ""
+}
+
+// table renders a table, the first row being the header. The cell
+// separator is escaped in the cells, before any other substitution, so
+// it is also escaped in code.
+func (r asciidocRenderer) table(b block) string {
+	columns := tableColumns(b.rows)
+	if columns == 0 {
+		return ""
+	}
+
+	cell := func(inlines []inline) string {
+		return strings.ReplaceAll(r.inlines(inlines), "|", `\|`)
+	}
+
+	lines := []string{fmt.Sprintf(`[cols="%d*",options="header"]`, columns), "|==="}
+	for i, row := range b.rows {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "| "+strings.Join(tableCells(row, columns, cell), " | "))
+	}
+	lines = append(lines, "|===")
+
+	return strings.Join(lines, "\n")
+}
+
+// inlines renders inlines with the unconstrained formatting marks, which
+// also work inside words. Line breaks are kept with the hard line break
+// mark.
+func (r asciidocRenderer) inlines(inlines []inline) string {
+	var builder strings.Builder
+	for _, node := range inlines {
+		switch node.kind {
+		case inlineText:
+			builder.WriteString(strings.ReplaceAll(escapeAsciidoc(node.text), "\n", " +\n"))
+		case inlineStrong:
+			builder.WriteString(delimit("**", "**", r.inlines(node.children)))
+		case inlineEmphasis:
+			builder.WriteString(delimit("__", "__", r.inlines(node.children)))
+		case inlineCode:
+			// the passthrough keeps the code as is
+			builder.WriteString(delimit("``pass:[", "]``", strings.ReplaceAll(plainText(node.children), "]", `\]`)))
+		case inlineLink:
+			builder.WriteString(delimit(fmt.Sprintf("link:++%s++[", node.href), "]", r.inlines(node.children)))
+		}
+	}
+
+	return builder.String()
+}
+
+// asciidocEscaper replaces the characters that could start formatting
+// marks, attribute references or macros with the built-in attributes for
+// them or, when there is none, with a passthrough.
+var asciidocEscaper = strings.NewReplacer(
+	`\`, "{backslash}",
+	`*`, "{asterisk}",
+	"`", "{backtick}",
+	`+`, "{plus}",
+	`^`, "{caret}",
+	`~`, "{tilde}",
+	`[`, "{startsb}",
+	`]`, "{endsb}",
+	`_`, "pass:[_]",
+	`#`, "pass:[#]",
+	`{`, "pass:[{]",
+)
+
+// escapeAsciidoc escapes plain text.
+func escapeAsciidoc(text string) string {
+	return asciidocEscaper.Replace(text)
+}
+
+// asciidocAttribute quotes the value of a macro attribute.
+func asciidocAttribute(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
@@ -0,0 +1,62 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsciidocRenderer(t *testing.T) {
+	// act
+	output := asciidocRenderer{}.render(sampleDocument())
+
+	// assert
+	assert.Equal(t, "= Go tips\n"+
+		"Renato Torres\n"+
+		"\n== Small things\n"+
+		"\nUse **small** ``pass:[interfaces]`` and read link:++https://go.dev/doc/effective_go++[Effective __Go__].\n"+
+		"\nimage::https://example.com/gopher.png[\"The gopher\"]\n"+
+		"\n[source,go]\n----\ntype Service struct {\n}\n----\n"+
+		"\n* Testable\n* Clear\n"+
+		"\n. Define\n. Implement\n"+
+		"\n____\nSimplicity is complicated.\n____\n"+
+		"\n'''\n"+
+		"\nlink:++https://speakerdeck.com/renato0307/go++[Slides]\n"+
+		"\n[cols=\"2*\",options=\"header\"]\n|===\n| Operator | Meaning\n\n| ``pass:[\\|]`` | Bitwise or\n\n| ``pass:[&&]`` | Logical and\n|===\n",
+		output)
+}
+
+func TestEscapeAsciidoc(t *testing.T) {
+	assert.Equal(t, "a{asterisk}b{asterisk} pass:[_]c{endsb} {plus}1 pass:[{]attr}", escapeAsciidoc("a*b* _c] +1 {attr}"))
+}
+
+func TestAsciidocRendererLineBreaksAndLinkedImages(t *testing.T) {
+	// arrange
+	d := document{blocks: []block{
+		{kind: blockParagraph, inlines: []inline{textInline("first\nsecond")}},
+		{kind: blockImage, src: "https://img.youtube.com/vi/x/hqdefault.jpg", alt: "Watch", href: "https://www.youtube.com/watch?v=x"},
+	}}
+
+	// act
+	output := asciidocRenderer{}.render(d)
+
+	// assert
+	assert.Contains(t, output, "\nfirst +\nsecond\n")
+	assert.Contains(t, output, "\nimage::https://img.youtube.com/vi/x/hqdefault.jpg[\"Watch\",link=\"https://www.youtube.com/watch?v=x\"]\n")
+}
@@ -0,0 +1,174 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// htmlRenderer renders documents as an HTML fragment safe to include in
+// any page: all the text is escaped, only links with safe schemes are
+// kept and embedded media is rendered as links instead of iframes.
+type htmlRenderer struct{}
+
+func (r htmlRenderer) render(d document) string {
+	var builder strings.Builder
+	builder.WriteString("<article>\n")
+	builder.WriteString(fmt.Sprintf("<h1>%s</h1>\n", html.EscapeString(d.title)))
+	if d.author != "" {
+		builder.WriteString(fmt.Sprintf("<p class=\"byline\">By %s</p>\n", html.EscapeString(d.author)))
+	}
+
+	for _, b := range d.blocks {
+		if text := r.block(b); text != "" {
+			builder.WriteString(text)
+			builder.WriteString("\n")
+		}
+	}
+	builder.WriteString("</article>\n")
+
+	return builder.String()
+}
+
+var htmlLanguage = regexp.MustCompile(`^[\w+#.-]+$`)
+
+func (r htmlRenderer) block(b block) string {
+	switch b.kind {
+	case blockHeading:
+		level := clamp(b.level, 1, 6)
+		return fmt.Sprintf("<h%d>%s</h%d>", level, r.inlines(b.inlines), level)
+	case blockParagraph:
+		return fmt.Sprintf("<p>%s</p>", r.inlines(b.inlines))
+	case blockImage:
+		src := safeHRef(b.src)
+		if src == "" {
+			return ""
+		}
+		image := fmt.Sprintf(`<img src="%s" alt="%s">`, html.EscapeString(src), html.EscapeString(b.alt))
+		if href := safeHRef(b.href); href != "" {
+			image = fmt.Sprintf(`<a href="%s" rel="nofollow noopener">%s</a>`, html.EscapeString(href), image)
+		}
+		return fmt.Sprintf("<figure>%s</figure>", image)
+	case blockCode:
+		class := ""
+		if htmlLanguage.MatchString(b.lang) {
+			class = fmt.Sprintf(` class="language-%s"`, b.lang)
+		}
+		return fmt.Sprintf("<pre><code%s>%s</code></pre>", class, html.EscapeString(b.code))
+	case blockList:
+		tag := "ul"
+		if b.ordered {
+			tag = "ol"
+		}
+		items := []string{}
+		for _, item := range b.items {
+			items = append(items, fmt.Sprintf("<li>%s</li>", r.inlines(item)))
+		}
+		return fmt.Sprintf("<%s>\n%s\n</%s>", tag, strings.Join(items, "\n"), tag)
+	case blockQuote:
+		return fmt.Sprintf("<blockquote><p>%s</p></blockquote>", r.inlines(b.inlines))
+	case blockRule:
+		return "<hr>"
+	case blockEmbed:
+		return fmt.Sprintf("<p>%s</p>", r.inlines([]inline{linkInline(firstNonEmpty(b.title, b.href), b.href)}))
+	case blockTable:
+		return r.table(b)
+	}
+
+	return ""
+}
+
+// table renders a table, the first row being the header.
+func (r htmlRenderer) table(b block) string {
+	columns := tableColumns(b.rows)
+	if columns == 0 {
+		return ""
+	}
+
+	lines := []string{"<table>"}
+	for i, row := range b.rows {
+		tag := "td"
+		if i == 0 {
+			tag = "th"
+			lines = append(lines, "<thead>")
+		} else if i == 1 {
+			lines = append(lines, "<tbody>")
+		}
+
+		cells := ""
+		for _, cell := range tableCells(row, columns, r.inlines) {
+			cells += fmt.Sprintf("<%s>%s</%s>", tag, cell, tag)
+		}
+		lines = append(lines, fmt.Sprintf("<tr>%s</tr>", cells))
+
+		if i == 0 {
+			lines = append(lines, "</thead>")
+		}
+	}
+	if len(b.rows) > 1 {
+		lines = append(lines, "</tbody>")
+	}
+	lines = append(lines, "</table>")
+
+	return strings.Join(lines, "\n")
+}
+
+func (r htmlRenderer) inlines(inlines []inline) string {
+	var builder strings.Builder
+	for _, node := range inlines {
+		switch node.kind {
+		case inlineText:
+			builder.WriteString(strings.ReplaceAll(html.EscapeString(node.text), "\n", "<br>\n"))
+		case inlineStrong:
+			builder.WriteString(fmt.Sprintf("<strong>%s</strong>", r.inlines(node.children)))
+		case inlineEmphasis:
+			builder.WriteString(fmt.Sprintf("<em>%s</em>", r.inlines(node.children)))
+		case inlineCode:
+			builder.WriteString(fmt.Sprintf("<code>%s</code>", html.EscapeString(plainText(node.children))))
+		case inlineLink:
+			href := safeHRef(node.href)
+			if href == "" {
+				builder.WriteString(r.inlines(node.children))
+				continue
+			}
+			builder.WriteString(fmt.Sprintf(`<a href="%s" rel="nofollow noopener">%s</a>`, html.EscapeString(href), r.inlines(node.children)))
+		}
+	}
+
+	return builder.String()
+}
+
+// safeHRef returns the URL if it is relative or uses the http, https or
+// mailto schemes, and an empty string otherwise, so links can not run
+// scripts.
+func safeHRef(href string) string {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil || u.String() == "" {
+		return ""
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return u.String()
+	}
+
+	return ""
+}
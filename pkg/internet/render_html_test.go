@@ -0,0 +1,85 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLRenderer(t *testing.T) {
+	// act
+	output := htmlRenderer{}.render(sampleDocument())
+
+	// assert
+	assert.Equal(t, "<article>\n"+
+		"<h1>Go tips</h1>\n"+
+		"<p class=\"byline\">By Renato Torres</p>\n"+
+		"<h2>Small things</h2>\n"+
+		"<p>Use <strong>small </strong><code>interfaces</code> and read <a href=\"https://go.dev/doc/effective_go\" rel=\"nofollow noopener\">Effective <em>Go</em></a>.</p>\n"+
+		"<figure><img src=\"https://example.com/gopher.png\" alt=\"The gopher\"></figure>\n"+
+		"<pre><code class=\"language-go\">type Service struct {\n}</code></pre>\n"+
+		"<ul>\n<li>Testable</li>\n<li>Clear</li>\n</ul>\n"+
+		"<ol>\n<li>Define</li>\n<li>Implement</li>\n</ol>\n"+
+		"<blockquote><p>Simplicity is complicated.</p></blockquote>\n"+
+		"<hr>\n"+
+		"<p><a href=\"https://speakerdeck.com/renato0307/go\" rel=\"nofollow noopener\">Slides</a></p>\n"+
+		"<table>\n<thead>\n<tr><th>Operator</th><th>Meaning</th></tr>\n</thead>\n<tbody>\n"+
+		"<tr><td><code>|</code></td><td>Bitwise or</td></tr>\n"+
+		"<tr><td><code>&amp;&amp;</code></td><td>Logical and</td></tr>\n"+
+		"</tbody>\n</table>\n"+
+		"</article>\n",
+		output)
+}
+
+func TestHTMLRendererSanitizes(t *testing.T) {
+	// arrange
+	d := document{
+		title: "<script>alert(1)</script>",
+		blocks: []block{
+			{kind: blockParagraph, inlines: []inline{
+				linkInline("click", "javascript:alert(1)"),
+				textInline(" <b>not bold</b>\nnext line"),
+			}},
+			{kind: blockImage, src: "JavaScript:alert(1)", alt: "x"},
+			{kind: blockImage, src: "https://example.com/a.png", alt: `" onerror="alert(1)`},
+			{kind: blockCode, lang: `go" onclick="alert(1)`, code: "</code><script>"},
+		},
+	}
+
+	// act
+	output := htmlRenderer{}.render(d)
+
+	// assert
+	assert.Equal(t, "<article>\n"+
+		"<h1>&lt;script&gt;alert(1)&lt;/script&gt;</h1>\n"+
+		"<p>click &lt;b&gt;not bold&lt;/b&gt;<br>\nnext line</p>\n"+
+		"<figure><img src=\"https://example.com/a.png\" alt=\"&#34; onerror=&#34;alert(1)\"></figure>\n"+
+		"<pre><code>&lt;/code&gt;&lt;script&gt;</code></pre>\n"+
+		"</article>\n",
+		output)
+}
+
+func TestSafeHRef(t *testing.T) {
+	assert.Equal(t, "https://go.dev", safeHRef("https://go.dev"))
+	assert.Equal(t, "mailto:renato.torres@pm.me", safeHRef("mailto:renato.torres@pm.me"))
+	assert.Equal(t, "images/gopher.png", safeHRef("images/gopher.png"))
+	assert.Equal(t, "", safeHRef(" javascript:alert(1)"))
+	assert.Equal(t, "", safeHRef("data:text/html;base64,PHNjcmlwdD4="))
+	assert.Equal(t, "", safeHRef("java\tscript:alert(1)"))
+}
@@ -0,0 +1,245 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// markdownRenderer renders documents as CommonMark, using the GitHub
+// extensions for fenced code blocks.
+type markdownRenderer struct {
+	// embedFallback defines how embedded media is rendered
+	embedFallback EmbedFallback
+}
+
+func (r markdownRenderer) render(d document) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("# %s\n", escapeMarkdown(d.title)))
+	if d.author != "" {
+		builder.WriteString(fmt.Sprintf("By %s\n", escapeMarkdown(d.author)))
+	}
+
+	for _, b := range d.blocks {
+		if text := r.block(b); text != "" {
+			builder.WriteString(fmt.Sprintf("\n%s\n", text))
+		}
+	}
+
+	return builder.String()
+}
+
+func (r markdownRenderer) block(b block) string {
+	switch b.kind {
+	case blockHeading:
+		return fmt.Sprintf("%s %s", strings.Repeat("#", b.level), r.inlines(b.inlines))
+	case blockParagraph:
+		return escapeLineStart(r.inlines(b.inlines))
+	case blockImage:
+		image := fmt.Sprintf("![%s](%s)", escapeMarkdown(b.alt), markdownHRef(b.src))
+		if b.href == "" {
+			return image
+		}
+		return fmt.Sprintf("[%s](%s)", image, markdownHRef(b.href))
+	case blockCode:
+		delimiter := fence("`", 3, b.code)
+		return fmt.Sprintf("%s%s\n%s\n%s", delimiter, b.lang, b.code, delimiter)
+	case blockList:
+		return r.list(b)
+	case blockQuote:
+		return quote(r.inlines(b.inlines))
+	case blockRule:
+		return "---"
+	case blockEmbed:
+		return r.embed(b)
+	case blockTable:
+		return r.table(b)
+	}
+
+	return ""
+}
+
+// list renders the items of a list, indenting their continuation lines
+// and the nested items under the item they are in.
+func (r markdownRenderer) list(b block) string {
+	items := []string{}
+	// indents and numbers have the indentation and the last number of
+	// the items at each depth, up to the one of the previous item
+	indents, numbers := []string{""}, []int{0}
+	for i, item := range b.items {
+		depth, ordered := 0, b.ordered
+		if i < len(b.depths) {
+			depth, ordered = clamp(b.depths[i], 0, len(indents)-1), b.itemsOrdered[i]
+		}
+		indents, numbers = indents[:depth+1], numbers[:depth+1]
+
+		marker := "-"
+		if ordered {
+			numbers[depth]++
+			marker = fmt.Sprintf("%d.", numbers[depth])
+		}
+
+		indent := indents[depth] + strings.Repeat(" ", len(marker)+1)
+		text := strings.ReplaceAll(escapeLineStart(r.inlines(item)), "\n", "\n"+indent)
+		items = append(items, fmt.Sprintf("%s%s %s", indents[depth], marker, text))
+		indents, numbers = append(indents, indent), append(numbers, 0)
+	}
+
+	return strings.Join(items, "\n")
+}
+
+// table renders a table with the GitHub extension, the first row being
+// the header. Cells can not span several lines, so line breaks are
+// rendered as HTML.
+func (r markdownRenderer) table(b block) string {
+	columns := tableColumns(b.rows)
+	if columns == 0 {
+		return ""
+	}
+
+	cell := func(inlines []inline) string {
+		text := strings.ReplaceAll(r.inlines(inlines), "|", `\|`)
+		return strings.ReplaceAll(text, "\n", "<br>")
+	}
+
+	lines := []string{}
+	for i, row := range b.rows {
+		lines = append(lines, "| "+strings.Join(tableCells(row, columns, cell), " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", columns))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// embed renders embedded media as a link or, if the embed fallback is
+// EmbedFallbackHTML, as an iframe.
+func (r markdownRenderer) embed(b block) string {
+	if r.embedFallback == EmbedFallbackHTML {
+		attributes := fmt.Sprintf(`src="%s"`, html.EscapeString(firstNonEmpty(b.src, b.href)))
+		if b.width > 0 && b.height > 0 {
+			attributes += fmt.Sprintf(` width="%d" height="%d"`, b.width, b.height)
+		}
+		return fmt.Sprintf(`<iframe %s frameborder="0" allowfullscreen></iframe>`, attributes)
+	}
+
+	return r.inlines([]inline{linkInline(firstNonEmpty(b.title, b.href), b.href)})
+}
+
+func (r markdownRenderer) inlines(inlines []inline) string {
+	var builder strings.Builder
+	for _, node := range inlines {
+		switch node.kind {
+		case inlineText:
+			builder.WriteString(escapeMarkdown(node.text))
+		case inlineStrong:
+			builder.WriteString(delimit("**", "**", r.inlines(node.children)))
+		case inlineEmphasis:
+			builder.WriteString(delimit("*", "*", r.inlines(node.children)))
+		case inlineCode:
+			// code is not escaped and, if it has backticks, needs a
+			// longer delimiter
+			code := plainText(node.children)
+			if strings.Contains(code, "`") {
+				builder.WriteString(delimit("`` ", " ``", code))
+			} else {
+				builder.WriteString(delimit("`", "`", code))
+			}
+		case inlineLink:
+			builder.WriteString(delimit("[", fmt.Sprintf("](%s)", markdownHRef(node.href)), r.inlines(node.children)))
+		}
+	}
+
+	return builder.String()
+}
+
+var markdownHRefEscaper = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29")
+
+// markdownHRef escapes the characters of a link destination that would
+// end it.
+func markdownHRef(href string) string {
+	return markdownHRefEscaper.Replace(href)
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`<`, `\<`,
+)
+
+// markdownEntity is an entity or character reference, which Markdown
+// replaces by the character it refers to.
+var markdownEntity = regexp.MustCompile(`&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[A-Za-z][A-Za-z0-9]{0,31});`)
+
+// escapeMarkdown escapes the characters of plain text that would
+// otherwise be interpreted as Markdown.
+func escapeMarkdown(text string) string {
+	text = markdownEscaper.Replace(text)
+	return markdownEntity.ReplaceAllStringFunc(text, func(entity string) string {
+		return `\` + entity
+	})
+}
+
+var orderedListStart = regexp.MustCompile(`^( {0,3})(\d+)([.)])(\s|$)`)
+
+// escapeLineStart escapes the start of each line of text that would
+// otherwise be interpreted as a heading, quote, list or rule.
+func escapeLineStart(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = escapeLine(line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func escapeLine(line string) string {
+	if orderedListStart.MatchString(line) {
+		return orderedListStart.ReplaceAllString(line, `$1$2\$3$4`)
+	}
+
+	// up to three spaces of indentation do not change the meaning
+	start := len(line) - len(strings.TrimLeft(line, " "))
+	if start > 3 || start == len(line) {
+		return line
+	}
+
+	switch line[start] {
+	case '#', '>', '-', '+', '=':
+		return line[:start] + `\` + line[start:]
+	}
+
+	return line
+}
+
+// quote renders text as a block quote, quoting all its lines.
+func quote(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+line, " ")
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,94 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownRenderer(t *testing.T) {
+	// act
+	output := markdownRenderer{}.render(sampleDocument())
+
+	// assert
+	assert.Equal(t, "# Go tips\n"+
+		"By Renato Torres\n"+
+		"\n## Small things\n"+
+		"\nUse **small** `interfaces` and read [Effective *Go*](https://go.dev/doc/effective_go).\n"+
+		"\n![The gopher](https://example.com/gopher.png)\n"+
+		"\n```go\ntype Service struct {\n}\n```\n"+
+		"\n- Testable\n- Clear\n"+
+		"\n1. Define\n2. Implement\n"+
+		"\n> Simplicity is complicated.\n"+
+		"\n---\n"+
+		"\n[Slides](https://speakerdeck.com/renato0307/go)\n"+
+		"\n| Operator | Meaning |\n| --- | --- |\n| `\\|` | Bitwise or |\n| `&&` | Logical and |\n",
+		output)
+}
+
+func TestMarkdownRendererEmbedAsHTML(t *testing.T) {
+	// arrange
+	b := block{kind: blockEmbed, src: "https://speakerdeck.com/player/4f2a", href: "https://speakerdeck.com/renato0307/go", width: 710, height: 399}
+
+	// act
+	output := markdownRenderer{embedFallback: EmbedFallbackHTML}.block(b)
+
+	// assert
+	assert.Equal(t, `<iframe src="https://speakerdeck.com/player/4f2a" width="710" height="399" frameborder="0" allowfullscreen></iframe>`, output)
+}
+
+func TestMarkdownRendererNestedList(t *testing.T) {
+	// arrange
+	b := block{
+		kind:         blockList,
+		items:        [][]inline{{textInline("one")}, {textInline("a\nb")}, {textInline("c")}, {textInline("two")}, {textInline("too deep")}},
+		depths:       []int{0, 1, 1, 0, 3},
+		itemsOrdered: []bool{false, true, true, false, false},
+	}
+
+	// act
+	output := markdownRenderer{}.block(b)
+
+	// assert
+	assert.Equal(t, "- one\n  1. a\n     b\n  2. c\n- two\n  - too deep", output)
+}
+
+func TestEscapeLineStart(t *testing.T) {
+	assert.Equal(t, `\# not a heading`, escapeLineStart("# not a heading"))
+	assert.Equal(t, `\- not a list`, escapeLineStart("- not a list"))
+	assert.Equal(t, `2021\. was a year`, escapeLineStart("2021. was a year"))
+	assert.Equal(t, "plain", escapeLineStart("plain"))
+	assert.Equal(t, "first line\n\\# not a heading\n  \\> not a quote\n3\\) not a list", escapeLineStart("first line\n# not a heading\n  > not a quote\n3) not a list"))
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	assert.Equal(t, `\&amp; and \&#169; stay as written`, escapeMarkdown("&amp; and &#169; stay as written"))
+	assert.Equal(t, "Q&A & more", escapeMarkdown("Q&A & more"))
+}
+
+func TestMarkdownRendererImageDestination(t *testing.T) {
+	// arrange
+	b := block{kind: blockImage, src: "https://example.com/a gopher (1).png", alt: "The gopher", href: "https://example.com/go (lang)"}
+
+	// act
+	output := markdownRenderer{}.block(b)
+
+	// assert
+	assert.Equal(t, "[![The gopher](https://example.com/a%20gopher%20%281%29.png)](https://example.com/go%20%28lang%29)", output)
+}
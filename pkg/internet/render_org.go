@@ -0,0 +1,155 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// orgRenderer renders documents as Org-mode.
+type orgRenderer struct{}
+
+func (r orgRenderer) render(d document) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("#+TITLE: %s\n", d.title))
+	if d.author != "" {
+		builder.WriteString(fmt.Sprintf("#+AUTHOR: %s\n", d.author))
+	}
+
+	for _, b := range d.blocks {
+		if text := r.block(b); text != "" {
+			builder.WriteString(fmt.Sprintf("\n%s\n", text))
+		}
+	}
+
+	return builder.String()
+}
+
+func (r orgRenderer) block(b block) string {
+	switch b.kind {
+	case blockHeading:
+		// the title is the level 1 heading, so sections start at level 2
+		level := b.level - 1
+		if level < 1 {
+			level = 1
+		}
+		return fmt.Sprintf("%s %s", strings.Repeat("*", level), strings.ReplaceAll(r.inlines(b.inlines), "\n", " "))
+	case blockParagraph:
+		return escapeOrgLines(r.inlines(b.inlines))
+	case blockImage:
+		image := fmt.Sprintf("[[%s]]", orgLinkEscaper.Replace(b.src))
+		if b.href != "" {
+			image = fmt.Sprintf("[[%s][%s]]", orgLinkEscaper.Replace(b.href), orgLinkEscaper.Replace(b.src))
+		}
+		if b.alt == "" {
+			return image
+		}
+		return fmt.Sprintf("#+CAPTION: %s\n%s", strings.ReplaceAll(b.alt, "\n", " "), image)
+	case blockCode:
+		return fmt.Sprintf("#+BEGIN_SRC %s\n%s\n#+END_SRC", b.lang, escapeOrgCode(b.code))
+	case blockList:
+		items := []string{}
+		for i, item := range b.items {
+			marker := "-"
+			if b.ordered {
+				marker = fmt.Sprintf("%d.", i+1)
+			}
+			text := strings.ReplaceAll(r.inlines(item), "\\\\\n", "\\\\\n"+strings.Repeat(" ", len(marker)+1))
+			items = append(items, fmt.Sprintf("%s %s", marker, text))
+		}
+		return strings.Join(items, "\n")
+	case blockQuote:
+		return fmt.Sprintf("#+BEGIN_QUOTE\n%s\n#+END_QUOTE", escapeOrgLines(r.inlines(b.inlines)))
+	case blockRule:
+		return "-----"
+	case blockEmbed:
+		return r.inlines([]inline{linkInline(firstNonEmpty(b.title, b.href), b.href)})
+	case blockTable:
+		return r.table(b)
+	}
+
+	return ""
+}
+
+// table renders a table, the first row being the header. Cells can not
+// span several lines nor have the column separator, which is replaced
+// with its entity.
+func (r orgRenderer) table(b block) string {
+	columns := tableColumns(b.rows)
+	if columns == 0 {
+		return ""
+	}
+
+	cell := func(inlines []inline) string {
+		text := strings.ReplaceAll(r.inlines(inlines), "\\\\\n", " ")
+		return strings.ReplaceAll(text, "|", `\vert{}`)
+	}
+
+	lines := []string{}
+	for i, row := range b.rows {
+		lines = append(lines, "| "+strings.Join(tableCells(row, columns, cell), " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat("---+", columns-1)+"---|")
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// inlines renders inlines with the Org-mode emphasis markers. Line breaks
+// are kept with the forced line break mark.
+func (r orgRenderer) inlines(inlines []inline) string {
+	var builder strings.Builder
+	for _, node := range inlines {
+		switch node.kind {
+		case inlineText:
+			builder.WriteString(strings.ReplaceAll(node.text, "\n", "\\\\\n"))
+		case inlineStrong:
+			builder.WriteString(delimit("*", "*", r.inlines(node.children)))
+		case inlineEmphasis:
+			builder.WriteString(delimit("/", "/", r.inlines(node.children)))
+		case inlineCode:
+			builder.WriteString(delimit("~", "~", plainText(node.children)))
+		case inlineLink:
+			description := strings.NewReplacer("[", "{", "]", "}").Replace(r.inlines(node.children))
+			builder.WriteString(delimit(fmt.Sprintf("[[%s][", orgLinkEscaper.Replace(node.href)), "]]", description))
+		}
+	}
+
+	return builder.String()
+}
+
+var orgLinkEscaper = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`)
+
+var orgLineStart = regexp.MustCompile(`(?m)^(\*+\s|#\+|:\w*:|-----)`)
+
+// escapeOrgLines escapes the start of the lines of text that would
+// otherwise be interpreted as headings, keywords, drawers or rules, with
+// a zero width space.
+func escapeOrgLines(text string) string {
+	return orgLineStart.ReplaceAllString(text, "\u200b$1")
+}
+
+var orgCodeLineStart = regexp.MustCompile(`(?m)^(\s*)(\*|#\+|,\*|,#\+)`)
+
+// escapeOrgCode escapes the lines of code that would otherwise end the
+// block or be interpreted as headings, prefixing them with a comma.
+func escapeOrgCode(code string) string {
+	return orgCodeLineStart.ReplaceAllString(code, "$1,$2")
+}
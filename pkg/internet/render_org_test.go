@@ -0,0 +1,57 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrgRenderer(t *testing.T) {
+	// act
+	output := orgRenderer{}.render(sampleDocument())
+
+	// assert
+	assert.Equal(t, "#+TITLE: Go tips\n#+AUTHOR: Renato Torres\n"+
+		"\n* Small things\n"+
+		"\nUse *small* ~interfaces~ and read [[https://go.dev/doc/effective_go][Effective /Go/]].\n"+
+		"\n#+CAPTION: The gopher\n[[https://example.com/gopher.png]]\n"+
+		"\n#+BEGIN_SRC go\ntype Service struct {\n}\n#+END_SRC\n"+
+		"\n- Testable\n- Clear\n"+
+		"\n1. Define\n2. Implement\n"+
+		"\n#+BEGIN_QUOTE\nSimplicity is complicated.\n#+END_QUOTE\n"+
+		"\n-----\n"+
+		"\n[[https://speakerdeck.com/renato0307/go][Slides]]\n"+
+		"\n| Operator | Meaning |\n|---+---|\n| ~\\vert{}~ | Bitwise or |\n| ~&&~ | Logical and |\n",
+		output)
+}
+
+func TestOrgRendererEscaping(t *testing.T) {
+	// arrange
+	d := document{blocks: []block{
+		{kind: blockParagraph, inlines: []inline{textInline("* not a heading\n#+not a keyword")}},
+		{kind: blockCode, lang: "org", code: "* heading\n#+END_SRC"},
+	}}
+
+	// act
+	output := orgRenderer{}.render(d)
+
+	// assert
+	assert.Contains(t, output, "\n\u200b* not a heading\\\\\n\u200b#+not a keyword\n")
+	assert.Contains(t, output, "\n#+BEGIN_SRC org\n,* heading\n,#+END_SRC\n#+END_SRC\n")
+}
@@ -0,0 +1,179 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"fmt"
+	"strings"
+)
+
+// rstHeadingUnderlines has the characters that underline each level of
+// heading, the title being the first.
+var rstHeadingUnderlines = []string{"=", "-", "~", "^", `"`, "'"}
+
+// rstRenderer renders documents as reStructuredText.
+//
+// reStructuredText does not allow nested inline markup, so only the
+// outermost markup of nested inlines is kept.
+type rstRenderer struct{}
+
+func (r rstRenderer) render(d document) string {
+	var builder strings.Builder
+	title := escapeRst(d.title)
+	overline := underline(title, "=")
+	builder.WriteString(fmt.Sprintf("%s\n%s\n%s\n", overline, title, overline))
+	if d.author != "" {
+		builder.WriteString(fmt.Sprintf("\n:Author: %s\n", escapeRst(d.author)))
+	}
+
+	for _, b := range d.blocks {
+		if text := r.block(b); text != "" {
+			builder.WriteString(fmt.Sprintf("\n%s\n", text))
+		}
+	}
+
+	return builder.String()
+}
+
+func (r rstRenderer) block(b block) string {
+	switch b.kind {
+	case blockHeading:
+		text := strings.ReplaceAll(r.inlines(b.inlines), "\n", " ")
+		char := rstHeadingUnderlines[clamp(b.level, 1, len(rstHeadingUnderlines))-1]
+		return fmt.Sprintf("%s\n%s", text, underline(text, char))
+	case blockParagraph:
+		return r.paragraph(r.inlines(b.inlines))
+	case blockImage:
+		image := fmt.Sprintf(".. image:: %s\n   :alt: %s", b.src, strings.ReplaceAll(b.alt, "\n", " "))
+		if b.href != "" {
+			image += fmt.Sprintf("\n   :target: %s", b.href)
+		}
+		return image
+	case blockCode:
+		directive := "::"
+		if b.lang != "" {
+			directive = fmt.Sprintf(".. code-block:: %s", b.lang)
+		}
+		return fmt.Sprintf("%s\n\n%s", directive, indent(b.code, "   "))
+	case blockList:
+		items := []string{}
+		for _, item := range b.items {
+			marker := "-"
+			if b.ordered {
+				marker = "#."
+			}
+			text := strings.ReplaceAll(r.paragraph(r.inlines(item)), "\n", "\n"+strings.Repeat(" ", len(marker)+1))
+			items = append(items, fmt.Sprintf("%s %s", marker, text))
+		}
+		return strings.Join(items, "\n")
+	case blockQuote:
+		return indent(r.paragraph(r.inlines(b.inlines)), "    ")
+	case blockRule:
+		return "----"
+	case blockEmbed:
+		return r.inlines([]inline{linkInline(firstNonEmpty(b.title, b.href), b.href)})
+	case blockTable:
+		return r.table(b)
+	}
+
+	return ""
+}
+
+// table renders a table with the list-table directive, which does not
+// need the columns to be aligned, the first row being the header.
+func (r rstRenderer) table(b block) string {
+	columns := tableColumns(b.rows)
+	if columns == 0 {
+		return ""
+	}
+
+	cell := func(inlines []inline) string {
+		return strings.ReplaceAll(r.paragraph(r.inlines(inlines)), "\n", "\n     ")
+	}
+
+	lines := []string{".. list-table::", "   :header-rows: 1", ""}
+	for _, row := range b.rows {
+		for i, text := range tableCells(row, columns, cell) {
+			marker := "     -"
+			if i == 0 {
+				marker = "   * -"
+			}
+			lines = append(lines, strings.TrimRight(marker+" "+text, " "))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// paragraph keeps the line breaks of text with a line block.
+func (r rstRenderer) paragraph(text string) string {
+	if !strings.Contains(text, "\n") {
+		return text
+	}
+
+	return "| " + strings.ReplaceAll(text, "\n", "\n| ")
+}
+
+func (r rstRenderer) inlines(inlines []inline) string {
+	var builder strings.Builder
+	for _, node := range inlines {
+		content := escapeRst(plainText(node.children))
+		switch node.kind {
+		case inlineText:
+			builder.WriteString(escapeRst(node.text))
+		case inlineStrong:
+			builder.WriteString(delimit("**", "**", content))
+		case inlineEmphasis:
+			builder.WriteString(delimit("*", "*", content))
+		case inlineCode:
+			builder.WriteString(delimit("``", "``", plainText(node.children)))
+		case inlineLink:
+			// anonymous hyperlinks do not clash when several links have
+			// the same text
+			builder.WriteString(delimit("`", fmt.Sprintf(" <%s>`__", node.href), content))
+		}
+	}
+
+	return builder.String()
+}
+
+var rstEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`*`, `\*`,
+	"`", "\\`",
+	`_`, `\_`,
+	`|`, `\|`,
+	`<`, `\<`,
+)
+
+// escapeRst escapes the characters of plain text that would otherwise be
+// interpreted as inline markup, references or substitutions.
+func escapeRst(text string) string {
+	return rstEscaper.Replace(text)
+}
+
+// indent indents all the non empty lines of text.
+func indent(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
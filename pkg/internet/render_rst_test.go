@@ -0,0 +1,58 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRstRenderer(t *testing.T) {
+	// act
+	output := rstRenderer{}.render(sampleDocument())
+
+	// assert
+	assert.Equal(t, "=======\nGo tips\n=======\n"+
+		"\n:Author: Renato Torres\n"+
+		"\nSmall things\n------------\n"+
+		"\nUse **small** ``interfaces`` and read `Effective Go <https://go.dev/doc/effective_go>`__.\n"+
+		"\n.. image:: https://example.com/gopher.png\n   :alt: The gopher\n"+
+		"\n.. code-block:: go\n\n   type Service struct {\n   }\n"+
+		"\n- Testable\n- Clear\n"+
+		"\n#. Define\n#. Implement\n"+
+		"\n    Simplicity is complicated.\n"+
+		"\n----\n"+
+		"\n`Slides <https://speakerdeck.com/renato0307/go>`__\n"+
+		"\n.. list-table::\n   :header-rows: 1\n\n   * - Operator\n     - Meaning\n   * - ``|``\n     - Bitwise or\n   * - ``&&``\n     - Logical and\n",
+		output)
+}
+
+func TestRstRendererLineBlocksAndEscaping(t *testing.T) {
+	// arrange
+	d := document{blocks: []block{
+		{kind: blockParagraph, inlines: []inline{textInline("a_b *c* |d|\nnext")}},
+		{kind: blockCode, code: "go test ./..."},
+	}}
+
+	// act
+	output := rstRenderer{}.render(d)
+
+	// assert
+	assert.Contains(t, output, "\n| a\\_b \\*c\\* \\|d\\|\n| next\n")
+	assert.Contains(t, output, "\n::\n\n   go test ./...\n")
+}
@@ -0,0 +1,150 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// textRenderer renders documents as plain text, keeping the URLs of the
+// links and images next to their text.
+type textRenderer struct{}
+
+func (r textRenderer) render(d document) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("%s\n%s\n", d.title, underline(d.title, "=")))
+	if d.author != "" {
+		builder.WriteString(fmt.Sprintf("By %s\n", d.author))
+	}
+
+	for _, b := range d.blocks {
+		if text := r.block(b); text != "" {
+			builder.WriteString(fmt.Sprintf("\n%s\n", text))
+		}
+	}
+
+	return builder.String()
+}
+
+func (r textRenderer) block(b block) string {
+	switch b.kind {
+	case blockHeading:
+		text := strings.ReplaceAll(r.inlines(b.inlines), "\n", " ")
+		return fmt.Sprintf("%s\n%s", text, underline(text, "-"))
+	case blockParagraph:
+		return r.inlines(b.inlines)
+	case blockImage:
+		image := fmt.Sprintf("[Image: %s] %s", firstNonEmpty(b.alt, b.src), b.src)
+		if b.href != "" {
+			image += fmt.Sprintf(" (%s)", b.href)
+		}
+		return image
+	case blockCode:
+		return indent(b.code, "    ")
+	case blockList:
+		items := []string{}
+		for i, item := range b.items {
+			marker := "-"
+			if b.ordered {
+				marker = fmt.Sprintf("%d.", i+1)
+			}
+			text := strings.ReplaceAll(r.inlines(item), "\n", "\n"+strings.Repeat(" ", len(marker)+1))
+			items = append(items, fmt.Sprintf("%s %s", marker, text))
+		}
+		return strings.Join(items, "\n")
+	case blockQuote:
+		return quote(r.inlines(b.inlines))
+	case blockRule:
+		return "* * *"
+	case blockEmbed:
+		return r.inlines([]inline{linkInline(firstNonEmpty(b.title, b.href), b.href)})
+	case blockTable:
+		return r.table(b)
+	}
+
+	return ""
+}
+
+// table renders a table with its columns aligned and the header
+// underlined.
+func (r textRenderer) table(b block) string {
+	columns := tableColumns(b.rows)
+	if columns == 0 {
+		return ""
+	}
+
+	cell := func(inlines []inline) string {
+		return strings.ReplaceAll(r.inlines(inlines), "\n", " ")
+	}
+
+	rows := [][]string{}
+	widths := make([]int, columns)
+	for _, row := range b.rows {
+		cells := tableCells(row, columns, cell)
+		for i, text := range cells {
+			if width := utf8.RuneCountInString(text); width > widths[i] {
+				widths[i] = width
+			}
+		}
+		rows = append(rows, cells)
+	}
+
+	lines := []string{}
+	for i, cells := range rows {
+		for j, text := range cells {
+			cells[j] = text + strings.Repeat(" ", widths[j]-utf8.RuneCountInString(text))
+		}
+		lines = append(lines, strings.TrimRight(strings.Join(cells, "  "), " "))
+		if i == 0 {
+			rules := make([]string, columns)
+			for j, width := range widths {
+				rules[j] = strings.Repeat("-", width)
+			}
+			lines = append(lines, strings.Join(rules, "  "))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (r textRenderer) inlines(inlines []inline) string {
+	var builder strings.Builder
+	for _, node := range inlines {
+		switch node.kind {
+		case inlineText:
+			builder.WriteString(node.text)
+		case inlineLink:
+			text := r.inlines(node.children)
+			if strings.TrimSpace(text) == node.href {
+				builder.WriteString(text)
+				continue
+			}
+			builder.WriteString(delimit("", fmt.Sprintf(" (%s)", node.href), text))
+		default:
+			builder.WriteString(r.inlines(node.children))
+		}
+	}
+
+	return builder.String()
+}
+
+// underline returns a line as long as text.
+func underline(text, char string) string {
+	return strings.Repeat(char, utf8.RuneCountInString(text))
+}
@@ -0,0 +1,53 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextRenderer(t *testing.T) {
+	// act
+	output := textRenderer{}.render(sampleDocument())
+
+	// assert
+	assert.Equal(t, "Go tips\n=======\nBy Renato Torres\n"+
+		"\nSmall things\n------------\n"+
+		"\nUse small interfaces and read Effective Go (https://go.dev/doc/effective_go).\n"+
+		"\n[Image: The gopher] https://example.com/gopher.png\n"+
+		"\n    type Service struct {\n    }\n"+
+		"\n- Testable\n- Clear\n"+
+		"\n1. Define\n2. Implement\n"+
+		"\n> Simplicity is complicated.\n"+
+		"\n* * *\n"+
+		"\nSlides (https://speakerdeck.com/renato0307/go)\n"+
+		"\nOperator  Meaning\n--------  -----------\n|         Bitwise or\n&&        Logical and\n",
+		output)
+}
+
+func TestTextRendererBareLinks(t *testing.T) {
+	// arrange
+	inlines := []inline{linkInline("https://go.dev", "https://go.dev")}
+
+	// act
+	output := textRenderer{}.inlines(inlines)
+
+	// assert
+	assert.Equal(t, "https://go.dev", output)
+}
@@ -16,5 +16,379 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 */
 package internet
 
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/datetime"
+	datetimeService "github.com/renato0307/canivete-core/pkg/datetime"
+)
+
+const (
+	defaultMediumEndpoint       = "https://medium.com/_/graphql"
+	defaultMediumImagesEndpoint = "https://miro.medium.com/max/1400"
+	defaultTimeout              = time.Second * 10
+	defaultUserAgent            = "canivete"
+	defaultMediumRetries        = 3
+	defaultMediumBackoff        = time.Second
+	defaultMediumMaxBackoff     = time.Second * 30
+	defaultPostCacheMaxAge      = time.Hour
+	defaultDevtoEndpoint        = "https://dev.to/api"
+	defaultHashnodeEndpoint     = "https://gql.hashnode.com"
+	defaultMediumApiEndpoint    = "https://api.medium.com/v1"
+)
+
+// EmbedFallback defines how embedded media without a specific
+// rendering is converted.
+type EmbedFallback string
+
+const (
+	// EmbedFallbackLink renders embedded media as a Markdown link
+	EmbedFallbackLink EmbedFallback = "link"
+	// EmbedFallbackHTML renders embedded media as an HTML iframe
+	EmbedFallbackHTML EmbedFallback = "html"
+)
+
 type Service struct {
+	http       httpConfig
+	medium     mediumConfig
+	cache      cacheConfig
+	platforms  platformsConfig
+	render     renderConfig
+	dates      datetime.Interface
+	userAgents *UserAgentRules
+}
+
+// httpConfig configures the HTTP requests of a Service.
+type httpConfig struct {
+	client    *http.Client
+	transport http.RoundTripper
+	timeout   time.Duration
+	userAgent string
+}
+
+// mediumConfig configures how a Service talks to Medium.
+type mediumConfig struct {
+	endpoint       string
+	imagesEndpoint string
+	// retries is negative when retries are disabled, as zero means the
+	// default
+	retries     int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	apiEndpoint string
+	token       string
+	publisher   MediumPublisher
+}
+
+// cacheConfig configures the cache of the Medium posts.
+type cacheConfig struct {
+	posts  PostCache
+	maxAge time.Duration
+}
+
+// platformsConfig configures the platforms of posts other than Medium.
+type platformsConfig struct {
+	devtoEndpoint    string
+	hashnodeEndpoint string
+	extraSources     []Source
+}
+
+// renderConfig configures how the posts are rendered.
+type renderConfig struct {
+	gists         GistClient
+	embedFallback EmbedFallback
+}
+
+// Option configures a Service created with NewService.
+type Option func(*Service)
+
+// WithHTTPClient sets the HTTP client used for all the requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Service) {
+		s.http.client = client
+	}
+}
+
+// WithTransport sets the RoundTripper of the default HTTP client.
+// It is ignored if an HTTP client is also set.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(s *Service) {
+		s.http.transport = transport
+	}
+}
+
+// WithMediumEndpoint sets the URL of the Medium GraphQL API.
+func WithMediumEndpoint(url string) Option {
+	return func(s *Service) {
+		s.medium.endpoint = url
+	}
+}
+
+// WithMediumImagesEndpoint sets the base URL of the images of Medium
+// posts.
+func WithMediumImagesEndpoint(url string) Option {
+	return func(s *Service) {
+		s.medium.imagesEndpoint = url
+	}
+}
+
+// WithTimeout sets the timeout of the HTTP requests.
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *Service) {
+		s.http.timeout = timeout
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the HTTP requests.
+func WithUserAgent(userAgent string) Option {
+	return func(s *Service) {
+		s.http.userAgent = userAgent
+	}
+}
+
+// WithGistClient sets the client used to get the GitHub gists embedded
+// in Medium posts.
+func WithGistClient(client GistClient) Option {
+	return func(s *Service) {
+		s.render.gists = client
+	}
+}
+
+// WithEmbedFallback sets how embedded media, other than gists and
+// videos, is rendered. The default is EmbedFallbackLink.
+func WithEmbedFallback(fallback EmbedFallback) Option {
+	return func(s *Service) {
+		s.render.embedFallback = fallback
+	}
+}
+
+// WithMediumRetries sets how many times a Medium query is retried when
+// rate limited or on server errors, 3 by default. Zero disables retries.
+func WithMediumRetries(retries int) Option {
+	return func(s *Service) {
+		s.medium.retries = retries
+		if retries == 0 {
+			s.medium.retries = -1
+		}
+	}
+}
+
+// WithMediumBackoff sets the delay before the first retry of a Medium
+// query, doubled on each retry, and the longest delay to wait. The
+// defaults are 1 and 30 seconds.
+func WithMediumBackoff(base, max time.Duration) Option {
+	return func(s *Service) {
+		s.medium.backoff = base
+		s.medium.maxBackoff = max
+	}
+}
+
+// WithPostCache sets the cache of the Medium posts. There is no cache by
+// default.
+func WithPostCache(cache PostCache) Option {
+	return func(s *Service) {
+		s.cache.posts = cache
+	}
+}
+
+// WithPostCacheMaxAge sets how long a cached post is used without asking
+// Medium if it changed, 1 hour by default.
+func WithPostCacheMaxAge(maxAge time.Duration) Option {
+	return func(s *Service) {
+		s.cache.maxAge = maxAge
+	}
+}
+
+// WithDevtoEndpoint sets the base URL of the dev.to REST API.
+func WithDevtoEndpoint(url string) Option {
+	return func(s *Service) {
+		s.platforms.devtoEndpoint = url
+	}
+}
+
+// WithHashnodeEndpoint sets the URL of the Hashnode GraphQL API.
+func WithHashnodeEndpoint(url string) Option {
+	return func(s *Service) {
+		s.platforms.hashnodeEndpoint = url
+	}
+}
+
+// WithSource adds a source of posts, which takes precedence over the
+// built-in ones when detecting the source of a URL.
+func WithSource(source Source) Option {
+	return func(s *Service) {
+		s.platforms.extraSources = append(s.platforms.extraSources, source)
+	}
+}
+
+// WithMediumApiEndpoint sets the URL of the Medium API used to publish
+// posts.
+func WithMediumApiEndpoint(url string) Option {
+	return func(s *Service) {
+		s.medium.apiEndpoint = url
+	}
+}
+
+// WithMediumToken sets the integration token used to publish posts on
+// Medium.
+func WithMediumToken(token string) Option {
+	return func(s *Service) {
+		s.medium.token = token
+	}
+}
+
+// WithMediumPublisher sets the publisher of posts on Medium, replacing
+// the client of the Medium API.
+func WithMediumPublisher(publisher MediumPublisher) Option {
+	return func(s *Service) {
+		s.medium.publisher = publisher
+	}
+}
+
+// WithDatetime sets the datetime service rendering the dates, like the
+// validity of certificates.
+func WithDatetime(dates datetime.Interface) Option {
+	return func(s *Service) {
+		s.dates = dates
+	}
+}
+
+// WithUserAgentRules sets the rules parsing User-Agents, replacing the
+// rules embedded.
+func WithUserAgentRules(rules *UserAgentRules) Option {
+	return func(s *Service) {
+		s.userAgents = rules
+	}
+}
+
+// NewService creates a new internet service.
+//
+// A Service created without options, or declared as a zero value,
+// uses the public Medium endpoint and a client with a 10 seconds timeout.
+func NewService(opts ...Option) *Service {
+	s := &Service{}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *Service) httpClient() *http.Client {
+	if s.http.client != nil {
+		if s.http.timeout == 0 {
+			return s.http.client
+		}
+
+		client := *s.http.client
+		client.Timeout = s.http.timeout
+		return &client
+	}
+
+	timeout := s.http.timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	return &http.Client{Timeout: timeout, Transport: s.http.transport}
+}
+
+func (s *Service) mediumUrl() string {
+	if s.medium.endpoint == "" {
+		return defaultMediumEndpoint
+	}
+
+	return s.medium.endpoint
+}
+
+// mediumImageUrl returns the URL of an image of a post. Images not
+// uploaded to Medium, like the ones of posts to publish, have their URL
+// as ID.
+func (s *Service) mediumImageUrl(id string) string {
+	if strings.Contains(id, "://") {
+		return id
+	}
+
+	endpoint := s.medium.imagesEndpoint
+	if endpoint == "" {
+		endpoint = defaultMediumImagesEndpoint
+	}
+
+	return strings.TrimRight(endpoint, "/") + "/" + id
+}
+
+func (s *Service) devtoUrl() string {
+	if s.platforms.devtoEndpoint == "" {
+		return defaultDevtoEndpoint
+	}
+
+	return strings.TrimRight(s.platforms.devtoEndpoint, "/")
+}
+
+func (s *Service) hashnodeUrl() string {
+	if s.platforms.hashnodeEndpoint == "" {
+		return defaultHashnodeEndpoint
+	}
+
+	return s.platforms.hashnodeEndpoint
+}
+
+func (s *Service) userAgentHeader() string {
+	if s.http.userAgent == "" {
+		return defaultUserAgent
+	}
+
+	return s.http.userAgent
+}
+
+func (s *Service) gists() GistClient {
+	if s.render.gists == nil {
+		return NewGitHubGistClient(s.httpClient(), "")
+	}
+
+	return s.render.gists
+}
+
+func (s *Service) mediumPublisher() MediumPublisher {
+	if s.medium.publisher == nil {
+		return NewMediumApiPublisher(s.httpClient(), s.medium.apiEndpoint, s.medium.token)
+	}
+
+	return s.medium.publisher
+}
+
+func (s *Service) mediumRetryPolicy() (int, time.Duration, time.Duration) {
+	retries, base, max := s.medium.retries, s.medium.backoff, s.medium.maxBackoff
+	if retries == 0 {
+		retries = defaultMediumRetries
+	}
+	if retries < 0 {
+		retries = 0
+	}
+	if base <= 0 {
+		base = defaultMediumBackoff
+	}
+	if max <= 0 {
+		max = defaultMediumMaxBackoff
+	}
+
+	return retries, base, max
+}
+
+func (s *Service) datetime() datetime.Interface {
+	if s.dates == nil {
+		return &datetimeService.Service{}
+	}
+
+	return s.dates
+}
+
+func (s *Service) userAgentRules() (*UserAgentRules, error) {
+	if s.userAgents != nil {
+		return s.userAgents, nil
+	}
+
+	return DefaultUserAgentRules()
 }
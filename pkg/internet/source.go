@@ -0,0 +1,226 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+// Source is a blogging platform posts are converted to Markdown from.
+type Source interface {
+	// Name is the name of the source, used to choose it explicitly
+	Name() string
+	// Matches tells if the input, usually a URL, is a post of the source
+	Matches(input string) bool
+	// Post gets a post and converts it to Markdown, without front matter
+	Post(ctx context.Context, input string) (internet.ConvertMediumToMdOutput, error)
+}
+
+// ConvertPostToMd converts a post of any of the sources of the service
+// to Markdown. The source is detected from the URL of the post unless
+// given in the options.
+func (s *Service) ConvertPostToMd(input string, options internet.ConvertPostToMdOptions) (internet.ConvertPostToMdOutput, error) {
+	return s.ConvertPostToMdContext(context.Background(), input, options)
+}
+
+func (s *Service) ConvertPostToMdContext(ctx context.Context, input string, options internet.ConvertPostToMdOptions) (internet.ConvertPostToMdOutput, error) {
+	output := internet.ConvertPostToMdOutput{}
+
+	frontMatterOptions := internet.ConvertMediumToMdOptions{FrontMatter: options.FrontMatter, Flavour: options.Flavour}
+	err := validateFrontMatterOptions(frontMatterOptions)
+	if err != nil {
+		return output, err
+	}
+
+	source, err := s.source(input, options.Source)
+	if err != nil {
+		return output, err
+	}
+
+	post, err := source.Post(ctx, input)
+	if err != nil {
+		return output, err
+	}
+
+	post.Markdown = frontMatter(post, frontMatterOptions) + post.Markdown
+	output.ConvertMediumToMdOutput = post
+	output.Source = source.Name()
+
+	return output, nil
+}
+
+// sources returns the sources of the service, the ones added with
+// WithSource first. Medium is the last of the built-in ones as it also
+// matches the URLs of custom domains.
+func (s *Service) sources() []Source {
+	sources := append([]Source{}, s.platforms.extraSources...)
+
+	return append(sources, devtoSource{s}, hashnodeSource{s}, substackSource{s}, mediumPostSource{s})
+}
+
+// source returns the source named name or, if name is empty, the first
+// one matching the input.
+func (s *Service) source(input, name string) (Source, error) {
+	for _, source := range s.sources() {
+		if name != "" && strings.EqualFold(source.Name(), name) {
+			return source, nil
+		}
+		if name == "" && source.Matches(input) {
+			return source, nil
+		}
+	}
+
+	if name != "" {
+		return nil, &internet.InvalidPostInputError{Input: input, Reason: fmt.Sprintf("unknown source %q", name)}
+	}
+
+	return nil, &internet.InvalidPostInputError{Input: input, Reason: "not a post of any of the sources"}
+}
+
+// mediumPostSource is the Medium source, converting posts with
+// ConvertMediumToMd.
+type mediumPostSource struct {
+	s *Service
+}
+
+func (m mediumPostSource) Name() string {
+	return "medium"
+}
+
+func (m mediumPostSource) Matches(input string) bool {
+	if mediumPostId.MatchString(strings.TrimSpace(input)) {
+		return true
+	}
+
+	u, ok := sourceUrl(input)
+	if !ok {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "medium.com" || strings.HasSuffix(host, ".medium.com") {
+		return true
+	}
+	_, ok = postIdFromUrl(u)
+
+	return ok
+}
+
+func (m mediumPostSource) Post(ctx context.Context, input string) (internet.ConvertMediumToMdOutput, error) {
+	return m.s.ConvertMediumToMdContext(ctx, input)
+}
+
+// sourceUrl parses the input as an http or https URL, the scheme being
+// optional.
+func sourceUrl(input string) (*url.URL, bool) {
+	value := strings.TrimSpace(input)
+	if !strings.Contains(value, "://") && strings.Contains(value, "/") {
+		value = "https://" + value
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, false
+	}
+
+	return u, true
+}
+
+// pathSegments returns the non empty segments of the path of a URL.
+func pathSegments(u *url.URL) []string {
+	segments := []string{}
+	for _, segment := range strings.Split(u.Path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	return segments
+}
+
+// getSourceJSON sends a request to the API of a source and decodes its
+// JSON response into v.
+func (s *Service) getSourceJSON(req *http.Request, source, input string, v interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", s.userAgentHeader())
+
+	resp, err := s.httpClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("error getting %s post: %w", source, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return &internet.PostNotFoundError{Source: source, Input: input}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("error getting %s post: %s", source, resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response: %s", err.Error())
+	}
+
+	err = json.Unmarshal(body, v)
+	if err != nil {
+		return fmt.Errorf("error un-marshalling %s response: %s", source, err.Error())
+	}
+
+	return nil
+}
+
+// htmlPostMarkdown converts the HTML content of a post to Markdown, with
+// the links relative to base.
+func (s *Service) htmlPostMarkdown(content string, base *url.URL, title, author string) string {
+	converter := htmlConverter{base: base, title: title}
+	d := document{
+		title:  title,
+		author: author,
+		blocks: converter.convert(parseHTML(content)),
+	}
+
+	return markdownRenderer{embedFallback: s.render.embedFallback}.render(d)
+}
+
+// wordsPerMinute is the reading speed used by Medium to estimate the
+// reading time of a post.
+const wordsPerMinute = 265
+
+// readingTime estimates the time to read a number of words.
+func readingTime(words int) time.Duration {
+	return (time.Duration(words) * time.Minute / wordsPerMinute).Round(time.Second)
+}
+
+// parseSourceTime parses a RFC 3339 time, returning the zero time if it
+// is empty or invalid.
+func parseSourceTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t.UTC()
+}
@@ -0,0 +1,115 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+type devtoArticle struct {
+	Id                 int       `json:"id"`
+	Title              string    `json:"title"`
+	Description        string    `json:"description"`
+	PublishedAt        string    `json:"published_at"`
+	EditedAt           string    `json:"edited_at"`
+	Tags               []string  `json:"tags"`
+	Url                string    `json:"url"`
+	CanonicalUrl       string    `json:"canonical_url"`
+	ReadingTimeMinutes int       `json:"reading_time_minutes"`
+	CoverImage         string    `json:"cover_image"`
+	BodyHtml           string    `json:"body_html"`
+	User               devtoUser `json:"user"`
+}
+
+type devtoUser struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+}
+
+var devtoArticleId = regexp.MustCompile(`^\d+$`)
+
+// devtoSource gets the posts, called articles, of dev.to with its REST
+// API. Articles are identified by their URL, https://dev.to/<user>/<slug>,
+// or by their numeric ID.
+type devtoSource struct {
+	s *Service
+}
+
+func (d devtoSource) Name() string {
+	return "devto"
+}
+
+func (d devtoSource) Matches(input string) bool {
+	u, ok := sourceUrl(input)
+	if !ok {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+
+	return (host == "dev.to" || host == "www.dev.to") && len(pathSegments(u)) == 2
+}
+
+func (d devtoSource) Post(ctx context.Context, input string) (internet.ConvertMediumToMdOutput, error) {
+	output := internet.ConvertMediumToMdOutput{}
+
+	path := ""
+	if value := strings.TrimSpace(input); devtoArticleId.MatchString(value) {
+		path = "/articles/" + value
+	} else if u, ok := sourceUrl(input); ok && len(pathSegments(u)) == 2 {
+		segments := pathSegments(u)
+		path = "/articles/" + url.PathEscape(segments[0]) + "/" + url.PathEscape(segments[1])
+	} else {
+		return output, &internet.InvalidPostInputError{Input: input, Reason: "not a dev.to article ID nor URL"}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", d.s.devtoUrl()+path, nil)
+	if err != nil {
+		return output, fmt.Errorf("error creating request: %s", err.Error())
+	}
+
+	article := devtoArticle{}
+	err = d.s.getSourceJSON(req, d.Name(), input, &article)
+	if err != nil {
+		return output, err
+	}
+
+	base, _ := url.Parse(article.Url)
+	output = internet.ConvertMediumToMdOutput{
+		PostId:       strconv.Itoa(article.Id),
+		Title:        article.Title,
+		Subtitle:     article.Description,
+		Author:       article.User.Name,
+		PublishedAt:  parseSourceTime(article.PublishedAt),
+		UpdatedAt:    parseSourceTime(firstNonEmpty(article.EditedAt, article.PublishedAt)),
+		Tags:         append([]string{}, article.Tags...),
+		CanonicalUrl: firstNonEmpty(article.CanonicalUrl, article.Url),
+		ReadingTime:  time.Duration(article.ReadingTimeMinutes) * time.Minute,
+		PreviewImage: article.CoverImage,
+		Markdown:     d.s.htmlPostMarkdown(article.BodyHtml, base, article.Title, article.User.Name),
+	}
+
+	return output, nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+// newDevtoServer starts a server with the dev.to API answering with the
+// article recorded in testdata/devto, by ID or by user and slug.
+func newDevtoServer(t *testing.T) *httptest.Server {
+	article, err := ioutil.ReadFile("testdata/devto/1047353.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/articles/1047353", "/api/articles/renato0307/testing-http-clients-in-go-4k2j":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(article)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestDevtoSource(t *testing.T) {
+	testCases := []string{
+		"https://dev.to/renato0307/testing-http-clients-in-go-4k2j",
+		"dev.to/renato0307/testing-http-clients-in-go-4k2j/",
+		"1047353",
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc, func(t *testing.T) {
+			// arrange
+			server := newDevtoServer(t)
+			defer server.Close()
+
+			// act
+			s := NewService(WithDevtoEndpoint(server.URL + "/api"))
+			output, err := devtoSource{s}.Post(context.Background(), tc)
+
+			// assert
+			assert.Nil(t, err)
+			assert.Equal(t, "1047353", output.PostId)
+			assert.Equal(t, "Testing HTTP clients in Go", output.Title)
+			assert.Equal(t, "Test the code calling other services without the network", output.Subtitle)
+			assert.Equal(t, "Renato Torres", output.Author)
+			assert.Equal(t, time.Date(2021, 12, 15, 13, 0, 0, 0, time.UTC), output.PublishedAt)
+			assert.Equal(t, time.Date(2021, 12, 17, 9, 30, 0, 0, time.UTC), output.UpdatedAt)
+			assert.Equal(t, []string{"go", "testing"}, output.Tags)
+			assert.Equal(t, "https://dev.to/renato0307/testing-http-clients-in-go-4k2j", output.CanonicalUrl)
+			assert.Equal(t, 3*time.Minute, output.ReadingTime)
+			assert.Equal(t, "https://res.cloudinary.com/practicaldev/image/fetch/cover.png", output.PreviewImage)
+			assert.Equal(t, "# Testing HTTP clients in Go\nBy Renato Torres\n"+
+				"\n## Starting a test server\n"+
+				"\nAn `httptest.Server` answers with the handler we give it, so the requests **never leave the machine**.\n"+
+				"\n```go\nserver := httptest.NewServer(handler)\ndefer server.Close()\n```\n"+
+				"\n[![The test server](https://res.cloudinary.com/practicaldev/image/fetch/httptest.png)](https://res.cloudinary.com/practicaldev/image/fetch/httptest.png)\n"+
+				"\nRead more in the [post about contexts](https://dev.to/renato0307/contexts-in-go-1a2b).\n",
+				output.Markdown)
+		})
+	}
+}
+
+func TestDevtoSourceNotFound(t *testing.T) {
+	// arrange
+	server := newDevtoServer(t)
+	defer server.Close()
+
+	// act
+	s := NewService(WithDevtoEndpoint(server.URL + "/api"))
+	_, err := devtoSource{s}.Post(context.Background(), "https://dev.to/renato0307/missing")
+
+	// assert
+	var notFound *internet.PostNotFoundError
+	assert.True(t, errors.As(err, &notFound))
+	assert.Equal(t, "devto", notFound.Source)
+}
+
+func TestDevtoSourceWithInvalidInput(t *testing.T) {
+	// act
+	_, err := devtoSource{NewService()}.Post(context.Background(), "https://dev.to/renato0307")
+
+	// assert
+	var invalid *internet.InvalidPostInputError
+	assert.True(t, errors.As(err, &invalid))
+}
@@ -0,0 +1,195 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+type hashnodeResponse struct {
+	Data   hashnodeData           `json:"data"`
+	Errors []hashnodeGraphQLError `json:"errors"`
+}
+
+type hashnodeGraphQLError struct {
+	Message string `json:"message"`
+}
+
+type hashnodeData struct {
+	Publication *hashnodePublication `json:"publication"`
+}
+
+type hashnodePublication struct {
+	Post *hashnodePost `json:"post"`
+}
+
+type hashnodePost struct {
+	Id                string             `json:"id"`
+	Title             string             `json:"title"`
+	Subtitle          string             `json:"subtitle"`
+	Brief             string             `json:"brief"`
+	Url               string             `json:"url"`
+	CanonicalUrl      string             `json:"canonicalUrl"`
+	PublishedAt       string             `json:"publishedAt"`
+	UpdatedAt         string             `json:"updatedAt"`
+	ReadTimeInMinutes int                `json:"readTimeInMinutes"`
+	CoverImage        hashnodeCoverImage `json:"coverImage"`
+	Tags              []hashnodeTag      `json:"tags"`
+	Author            hashnodeAuthor     `json:"author"`
+	Content           hashnodeContent    `json:"content"`
+}
+
+type hashnodeCoverImage struct {
+	Url string `json:"url"`
+}
+
+type hashnodeTag struct {
+	Name string `json:"name"`
+}
+
+type hashnodeAuthor struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+}
+
+type hashnodeContent struct {
+	Html string `json:"html"`
+}
+
+// hashnodePostFields are the fields of a post used in the conversion.
+var hashnodePostFields = []graphQLField{
+	gqlField("id"),
+	gqlField("title"),
+	gqlField("subtitle"),
+	gqlField("brief"),
+	gqlField("url"),
+	gqlField("canonicalUrl"),
+	gqlField("publishedAt"),
+	gqlField("updatedAt"),
+	gqlField("readTimeInMinutes"),
+	gqlField("coverImage", gqlField("url")),
+	gqlField("tags", gqlField("name")),
+	gqlField("author", gqlField("name"), gqlField("username")),
+	gqlField("content", gqlField("html")),
+}
+
+// hashnodeSource gets the posts of Hashnode blogs with its GraphQL API.
+// Posts are identified by their URL, the host being the blog, either a
+// hashnode.dev subdomain or a custom domain, and the path the slug.
+type hashnodeSource struct {
+	s *Service
+}
+
+func (h hashnodeSource) Name() string {
+	return "hashnode"
+}
+
+func (h hashnodeSource) Matches(input string) bool {
+	u, ok := sourceUrl(input)
+	if !ok {
+		return false
+	}
+
+	return strings.HasSuffix(strings.ToLower(u.Hostname()), ".hashnode.dev") && len(pathSegments(u)) > 0
+}
+
+func (h hashnodeSource) Post(ctx context.Context, input string) (internet.ConvertMediumToMdOutput, error) {
+	output := internet.ConvertMediumToMdOutput{}
+
+	u, ok := sourceUrl(input)
+	if !ok || len(pathSegments(u)) == 0 {
+		return output, &internet.InvalidPostInputError{Input: input, Reason: "not a Hashnode post URL"}
+	}
+	segments := pathSegments(u)
+
+	query, err := hashnodePostQuery(strings.ToLower(u.Hostname()), segments[len(segments)-1])
+	if err != nil {
+		return output, err
+	}
+
+	data, err := json.Marshal(query)
+	if err != nil {
+		return output, fmt.Errorf("error marshling request: %s", err.Error())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", h.s.hashnodeUrl(), bytes.NewBuffer(data))
+	if err != nil {
+		return output, fmt.Errorf("error creating request: %s", err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	response := hashnodeResponse{}
+	err = h.s.getSourceJSON(req, h.Name(), input, &response)
+	if err != nil {
+		return output, err
+	}
+	if len(response.Errors) > 0 {
+		return output, fmt.Errorf("error getting hashnode post: %s", response.Errors[0].Message)
+	}
+	if response.Data.Publication == nil || response.Data.Publication.Post == nil {
+		return output, &internet.PostNotFoundError{Source: h.Name(), Input: input}
+	}
+
+	post := response.Data.Publication.Post
+	base, _ := url.Parse(post.Url)
+	output = internet.ConvertMediumToMdOutput{
+		PostId:       post.Id,
+		Title:        post.Title,
+		Subtitle:     firstNonEmpty(post.Subtitle, post.Brief),
+		Author:       post.Author.Name,
+		PublishedAt:  parseSourceTime(post.PublishedAt),
+		UpdatedAt:    parseSourceTime(firstNonEmpty(post.UpdatedAt, post.PublishedAt)),
+		Tags:         []string{},
+		CanonicalUrl: firstNonEmpty(post.CanonicalUrl, post.Url),
+		ReadingTime:  time.Duration(post.ReadTimeInMinutes) * time.Minute,
+		PreviewImage: post.CoverImage.Url,
+		Markdown:     h.s.htmlPostMarkdown(post.Content.Html, base, post.Title, post.Author.Name),
+	}
+	for _, tag := range post.Tags {
+		output.Tags = append(output.Tags, tag.Name)
+	}
+
+	return output, nil
+}
+
+// hashnodePostQuery returns the query of the post with the slug in the
+// blog with the host.
+func hashnodePostQuery(host, slug string) (graphQLRequest, error) {
+	query := graphQLQuery{
+		operation: "PostQuery",
+		variables: []graphQLVariable{
+			{name: "host", typ: "String!", value: host},
+			{name: "slug", typ: "String!", value: slug},
+		},
+		fields: []graphQLField{
+			gqlField("publication",
+				gqlField("post", hashnodePostFields...).withArgument("slug", "slug"),
+			).withArgument("host", "host"),
+		},
+	}
+
+	return query.request()
+}
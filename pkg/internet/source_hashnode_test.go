@@ -0,0 +1,125 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+// newHashnodeServer starts a server with the Hashnode GraphQL API
+// answering with the posts recorded in testdata/hashnode, named after
+// the host and the slug of the post.
+func newHashnodeServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := graphQLRequest{}
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		host, _ := request.Variables["host"].(string)
+		slug, _ := request.Variables["slug"].(string)
+		data, err := ioutil.ReadFile("testdata/hashnode/" + host + "-" + slug + ".json")
+		if err != nil {
+			w.Write([]byte(`{"data":{"publication":null}}`))
+			return
+		}
+		w.Write(data)
+	}))
+}
+
+func TestHashnodeSource(t *testing.T) {
+	// arrange
+	server := newHashnodeServer(t)
+	defer server.Close()
+
+	// act
+	s := NewService(WithHashnodeEndpoint(server.URL))
+	output, err := hashnodeSource{s}.Post(context.Background(), "https://renato.hashnode.dev/testing-http-clients-in-go")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "61b9e4a0f1c2d3e4f5a6b7c8", output.PostId)
+	assert.Equal(t, "Testing HTTP clients in Go", output.Title)
+	assert.Equal(t, "Most services call other services, and testing that code without hitting the network is easier than it looks.", output.Subtitle)
+	assert.Equal(t, "Renato Torres", output.Author)
+	assert.Equal(t, time.Date(2021, 12, 15, 13, 0, 0, 0, time.UTC), output.PublishedAt)
+	assert.Equal(t, time.Date(2021, 12, 17, 9, 30, 0, 0, time.UTC), output.UpdatedAt)
+	assert.Equal(t, []string{"Go", "Testing"}, output.Tags)
+	assert.Equal(t, "https://renato.hashnode.dev/testing-http-clients-in-go", output.CanonicalUrl)
+	assert.Equal(t, 4*time.Minute, output.ReadingTime)
+	assert.Equal(t, "https://cdn.hashnode.com/res/hashnode/image/upload/cover.png", output.PreviewImage)
+	assert.Equal(t, "# Testing HTTP clients in Go\nBy Renato Torres\n"+
+		"\n## Starting a test server\n"+
+		"\nAn `httptest.Server` answers with the handler we give it, so the requests **never leave the machine**.\n"+
+		"\n```go\nserver := httptest.NewServer(handler)\ndefer server.Close()\n```\n"+
+		"\n![The test server](https://cdn.hashnode.com/res/hashnode/image/upload/httptest.png)\n"+
+		"\n- Check the method\n- Check the headers\n"+
+		"\nRead more in the [post about contexts](https://renato.hashnode.dev/contexts-in-go).\n",
+		output.Markdown)
+}
+
+func TestHashnodeSourceNotFound(t *testing.T) {
+	// arrange
+	server := newHashnodeServer(t)
+	defer server.Close()
+
+	// act
+	s := NewService(WithHashnodeEndpoint(server.URL))
+	_, err := hashnodeSource{s}.Post(context.Background(), "https://renato.hashnode.dev/missing")
+
+	// assert
+	var notFound *internet.PostNotFoundError
+	assert.True(t, errors.As(err, &notFound))
+	assert.Equal(t, "hashnode", notFound.Source)
+}
+
+func TestHashnodeSourceGraphQLErrors(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"errors":[{"message":"Cannot query field \"post\""}]}`))
+	}))
+	defer server.Close()
+
+	// act
+	s := NewService(WithHashnodeEndpoint(server.URL))
+	_, err := hashnodeSource{s}.Post(context.Background(), "https://renato.hashnode.dev/testing-http-clients-in-go")
+
+	// assert
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `Cannot query field "post"`)
+}
+
+func TestHashnodePostQuery(t *testing.T) {
+	// act
+	query, err := hashnodePostQuery("renato.hashnode.dev", `x") { id } #`)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Contains(t, query.Query, "query PostQuery($host: String!, $slug: String!) {\n  publication(host: $host) {\n    post(slug: $slug) {\n      id\n")
+	assert.Equal(t, `x") { id } #`, query.Variables["slug"])
+}
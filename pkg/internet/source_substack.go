@@ -0,0 +1,131 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+type substackPost struct {
+	Id               int               `json:"id"`
+	Title            string            `json:"title"`
+	Subtitle         string            `json:"subtitle"`
+	Slug             string            `json:"slug"`
+	PostDate         string            `json:"post_date"`
+	UpdatedAt        string            `json:"updated_at"`
+	CanonicalUrl     string            `json:"canonical_url"`
+	CoverImage       string            `json:"cover_image"`
+	BodyHtml         string            `json:"body_html"`
+	Wordcount        int               `json:"wordcount"`
+	PublishedBylines []substackByline  `json:"publishedBylines"`
+	PostTags         []substackPostTag `json:"postTags"`
+}
+
+type substackByline struct {
+	Name   string `json:"name"`
+	Handle string `json:"handle"`
+}
+
+type substackPostTag struct {
+	Name string `json:"name"`
+}
+
+// substackSource gets the posts of Substack publications from the post
+// JSON each publication serves. Posts are identified by their URL,
+// https://<publication>.substack.com/p/<slug> or the same path in a
+// custom domain.
+type substackSource struct {
+	s *Service
+}
+
+func (ss substackSource) Name() string {
+	return "substack"
+}
+
+func (ss substackSource) Matches(input string) bool {
+	u, ok := sourceUrl(input)
+	if !ok {
+		return false
+	}
+
+	return strings.HasSuffix(strings.ToLower(u.Hostname()), ".substack.com") && substackSlug(u) != ""
+}
+
+func (ss substackSource) Post(ctx context.Context, input string) (internet.ConvertMediumToMdOutput, error) {
+	output := internet.ConvertMediumToMdOutput{}
+
+	u, ok := sourceUrl(input)
+	if !ok || substackSlug(u) == "" {
+		return output, &internet.InvalidPostInputError{Input: input, Reason: "not a Substack post URL"}
+	}
+
+	api := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/api/v1/posts/" + substackSlug(u)}
+	req, err := http.NewRequestWithContext(ctx, "GET", api.String(), nil)
+	if err != nil {
+		return output, fmt.Errorf("error creating request: %s", err.Error())
+	}
+
+	post := substackPost{}
+	err = ss.s.getSourceJSON(req, ss.Name(), input, &post)
+	if err != nil {
+		return output, err
+	}
+
+	authors := []string{}
+	for _, byline := range post.PublishedBylines {
+		authors = append(authors, byline.Name)
+	}
+	author := strings.Join(authors, ", ")
+
+	postUrl := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/p/" + firstNonEmpty(post.Slug, substackSlug(u))}
+	output = internet.ConvertMediumToMdOutput{
+		PostId:       strconv.Itoa(post.Id),
+		Title:        post.Title,
+		Subtitle:     post.Subtitle,
+		Author:       author,
+		PublishedAt:  parseSourceTime(post.PostDate),
+		UpdatedAt:    parseSourceTime(firstNonEmpty(post.UpdatedAt, post.PostDate)),
+		Tags:         []string{},
+		CanonicalUrl: firstNonEmpty(post.CanonicalUrl, postUrl.String()),
+		ReadingTime:  readingTime(post.Wordcount),
+		PreviewImage: post.CoverImage,
+		Markdown:     ss.s.htmlPostMarkdown(post.BodyHtml, &postUrl, post.Title, author),
+	}
+	for _, tag := range post.PostTags {
+		output.Tags = append(output.Tags, tag.Name)
+	}
+
+	return output, nil
+}
+
+// substackSlug returns the slug of a post URL, /p/<slug>, or an empty
+// string if it is not one.
+func substackSlug(u *url.URL) string {
+	segments := pathSegments(u)
+	if len(segments) != 2 || segments[0] != "p" {
+		return ""
+	}
+
+	return segments[1]
+}
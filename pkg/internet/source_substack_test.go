@@ -0,0 +1,118 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSubstackServer starts a server with the API of a Substack
+// publication answering with the posts recorded in testdata/substack.
+func newSubstackServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slug := strings.TrimPrefix(r.URL.Path, "/api/v1/posts/")
+		data, err := ioutil.ReadFile("testdata/substack/" + slug + ".json")
+		if err != nil || slug == r.URL.Path {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+}
+
+func TestSubstackSource(t *testing.T) {
+	// arrange
+	server := newSubstackServer(t)
+	defer server.Close()
+
+	// act
+	s := NewService()
+	output, err := substackSource{s}.Post(context.Background(), server.URL+"/p/testing-http-clients-in-go")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "47110815", output.PostId)
+	assert.Equal(t, "Testing HTTP clients in Go", output.Title)
+	assert.Equal(t, "Test the code calling other services without the network", output.Subtitle)
+	assert.Equal(t, "Renato Torres, Ana Silva", output.Author)
+	assert.Equal(t, time.Date(2021, 12, 15, 13, 0, 0, 0, time.UTC), output.PublishedAt)
+	assert.Equal(t, time.Date(2021, 12, 17, 9, 30, 0, 0, time.UTC), output.UpdatedAt)
+	assert.Equal(t, []string{"Go", "Testing"}, output.Tags)
+	assert.Equal(t, "https://gophernotes.substack.com/p/testing-http-clients-in-go", output.CanonicalUrl)
+	assert.Equal(t, 3*time.Minute, output.ReadingTime)
+	assert.Equal(t, "https://substackcdn.com/image/fetch/cover.png", output.PreviewImage)
+	assert.Equal(t, "# Testing HTTP clients in Go\nBy Renato Torres, Ana Silva\n"+
+		"\nMost services call other services, and testing that code without hitting the network is easier than it looks.\n"+
+		"\n### Starting a test server\n"+
+		"\nAn `httptest.Server` answers with the handler we give it, so the requests **never leave the machine**.\n"+
+		"\n```\nserver := httptest.NewServer(handler)\ndefer server.Close()\n```\n"+
+		"\n[![The client talks to the test server](https://substackcdn.com/image/fetch/w_1456/httptest.png)](https://substackcdn.com/image/fetch/httptest.png)\n"+
+		"\n> A little copying is better than a little dependency.\n"+
+		"\nRead more in the [post about contexts]("+server.URL+"/p/contexts-in-go).\n"+
+		"\nThanks for reading! Subscribe for free to receive new posts.\n",
+		output.Markdown)
+}
+
+func TestSubstackSourceNotFound(t *testing.T) {
+	// arrange
+	server := newSubstackServer(t)
+	defer server.Close()
+
+	// act
+	s := NewService()
+	_, err := substackSource{s}.Post(context.Background(), server.URL+"/p/missing")
+
+	// assert
+	var notFound *internet.PostNotFoundError
+	assert.True(t, errors.As(err, &notFound))
+	assert.Equal(t, "substack", notFound.Source)
+}
+
+func TestSubstackSlug(t *testing.T) {
+	testCases := []struct {
+		url  string
+		slug string
+	}{
+		{"https://gophernotes.substack.com/p/testing-http-clients-in-go", "testing-http-clients-in-go"},
+		{"https://gophernotes.substack.com/p/testing-http-clients-in-go/", "testing-http-clients-in-go"},
+		{"https://gophernotes.substack.com/archive", ""},
+		{"https://gophernotes.substack.com/p/a/comments", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.url, func(t *testing.T) {
+			// arrange
+			u, _ := sourceUrl(tc.url)
+
+			// act
+			slug := substackSlug(u)
+
+			// assert
+			assert.Equal(t, tc.slug, slug)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceDetection(t *testing.T) {
+	testCases := []struct {
+		input  string
+		source string
+	}{
+		{"https://dev.to/renato0307/testing-http-clients-in-go-4k2j", "devto"},
+		{"dev.to/renato0307/testing-http-clients-in-go-4k2j", "devto"},
+		{"https://renato.hashnode.dev/testing-http-clients-in-go", "hashnode"},
+		{"https://gophernotes.substack.com/p/testing-http-clients-in-go", "substack"},
+		{"f744fbff033e", "medium"},
+		{"https://medium.com/@renato0307/writing-a-command-line-toolbox-in-go-f744fbff033e", "medium"},
+		{"https://renato0307.medium.com/p/f744fbff033e", "medium"},
+		{"https://blog.example.com/writing-a-command-line-toolbox-in-go-f744fbff033e", "medium"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			// act
+			source, err := NewService().source(tc.input, "")
+
+			// assert
+			assert.Nil(t, err)
+			assert.Equal(t, tc.source, source.Name())
+		})
+	}
+}
+
+func TestSourceDetectionWithUnknownInput(t *testing.T) {
+	testCases := []struct {
+		input  string
+		source string
+	}{
+		{"https://example.com/about", ""},
+		{"https://dev.to/renato0307", ""},
+		{"https://gophernotes.substack.com/archive", ""},
+		{"https://dev.to/renato0307/testing-http-clients-in-go-4k2j", "blogger"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			// act
+			_, err := NewService().source(tc.input, tc.source)
+
+			// assert
+			var invalid *internet.InvalidPostInputError
+			assert.True(t, errors.As(err, &invalid))
+		})
+	}
+}
+
+func TestConvertPostToMd(t *testing.T) {
+	// arrange
+	server := newDevtoServer(t)
+	defer server.Close()
+
+	// act
+	s := NewService(WithDevtoEndpoint(server.URL + "/api"))
+	output, err := s.ConvertPostToMd("https://dev.to/renato0307/testing-http-clients-in-go-4k2j", internet.ConvertPostToMdOptions{FrontMatter: "yaml"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "devto", output.Source)
+	assert.Equal(t, "1047353", output.PostId)
+	assert.True(t, strings.HasPrefix(output.Markdown, "---\n"+
+		"title: \"Testing HTTP clients in Go\"\n"+
+		"description: \"Test the code calling other services without the network\"\n"+
+		"author: \"Renato Torres\"\n"+
+		"date: 2021-12-15T13:00:00Z\n"+
+		"lastmod: 2021-12-17T09:30:00Z\n"+
+		"tags: [\"go\", \"testing\"]\n"+
+		"canonicalURL: \"https://dev.to/renato0307/testing-http-clients-in-go-4k2j\"\n"+
+		"images: [\"https://res.cloudinary.com/practicaldev/image/fetch/cover.png\"]\n"+
+		"---\n\n# Testing HTTP clients in Go\n"), output.Markdown)
+}
+
+func TestConvertPostToMdFromMedium(t *testing.T) {
+	// arrange
+	server := newMediumServer(t)
+	defer server.Close()
+
+	// act
+	s := newMediumService(server)
+	output, err := s.ConvertPostToMd("https://medium.com/@renato0307/writing-a-command-line-toolbox-in-go-f744fbff033e", internet.ConvertPostToMdOptions{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "medium", output.Source)
+	assert.Equal(t, "f744fbff033e", output.PostId)
+	assert.True(t, strings.HasPrefix(output.Markdown, "# Writing a command line toolbox in Go\nBy Renato Torres\n"))
+}
+
+func TestConvertPostToMdWithSource(t *testing.T) {
+	// arrange
+	server := newSubstackServer(t)
+	defer server.Close()
+
+	// act
+	s := NewService()
+	output, err := s.ConvertPostToMd(server.URL+"/p/testing-http-clients-in-go", internet.ConvertPostToMdOptions{Source: "Substack"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "substack", output.Source)
+	assert.Equal(t, "47110815", output.PostId)
+}
+
+func TestConvertPostToMdWithInvalidFrontMatter(t *testing.T) {
+	// act
+	s := NewService()
+	_, err := s.ConvertPostToMd("https://dev.to/renato0307/testing-http-clients-in-go-4k2j", internet.ConvertPostToMdOptions{FrontMatter: "json"})
+
+	// assert
+	assert.NotNil(t, err)
+}
+
+// stubSource is a source converting every post of example.com to the
+// same Markdown.
+type stubSource struct{}
+
+func (stubSource) Name() string {
+	return "example"
+}
+
+func (stubSource) Matches(input string) bool {
+	return strings.HasPrefix(input, "https://example.com/")
+}
+
+func (stubSource) Post(ctx context.Context, input string) (internet.ConvertMediumToMdOutput, error) {
+	return internet.ConvertMediumToMdOutput{PostId: input, Title: "Example", Markdown: "# Example\n"}, nil
+}
+
+func TestConvertPostToMdWithExtraSource(t *testing.T) {
+	// act
+	s := NewService(WithSource(stubSource{}))
+	output, err := s.ConvertPostToMd("https://example.com/post", internet.ConvertPostToMdOptions{FrontMatter: "toml"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "example", output.Source)
+	assert.Equal(t, "+++\ntitle = \"Example\"\n+++\n\n# Example\n", output.Markdown)
+}
@@ -0,0 +1,646 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+// ParseUrl splits a URL into its components, decoding the path and the
+// query parameters.
+func (s *Service) ParseUrl(rawUrl string) (internet.ParseUrlOutput, error) {
+	return s.ParseUrlContext(context.Background(), rawUrl)
+}
+
+func (s *Service) ParseUrlContext(ctx context.Context, rawUrl string) (internet.ParseUrlOutput, error) {
+	output := internet.ParseUrlOutput{}
+
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
+	u, err := parseUrl(rawUrl)
+	if err != nil {
+		return output, err
+	}
+
+	parameters, err := parseQuery(rawUrl, u.RawQuery)
+	if err != nil {
+		return output, err
+	}
+
+	output = internet.ParseUrlOutput{
+		Url:        u.String(),
+		Scheme:     u.Scheme,
+		Host:       u.Hostname(),
+		Port:       u.Port(),
+		Path:       u.Path,
+		RawPath:    u.EscapedPath(),
+		Query:      queryOutput(parameters),
+		RawQuery:   u.RawQuery,
+		Fragment:   u.Fragment,
+		IsAbsolute: u.IsAbs(),
+	}
+	if u.Opaque != "" {
+		output.Path = u.Opaque
+		output.RawPath = u.Opaque
+	}
+	if u.User != nil {
+		output.Username = u.User.Username()
+		output.Password, output.HasPassword = u.User.Password()
+	}
+
+	return output, nil
+}
+
+// EditUrlQuery applies edits to the query parameters of a URL, in order.
+// The parameters not edited are kept as written.
+func (s *Service) EditUrlQuery(rawUrl string, edits []internet.UrlQueryEdit) (internet.EditUrlQueryOutput, error) {
+	return s.EditUrlQueryContext(context.Background(), rawUrl, edits)
+}
+
+func (s *Service) EditUrlQueryContext(ctx context.Context, rawUrl string, edits []internet.UrlQueryEdit) (internet.EditUrlQueryOutput, error) {
+	output := internet.EditUrlQueryOutput{}
+
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
+	u, err := parseUrl(rawUrl)
+	if err != nil {
+		return output, err
+	}
+
+	parameters, err := parseQuery(rawUrl, u.RawQuery)
+	if err != nil {
+		return output, err
+	}
+
+	for _, edit := range edits {
+		if edit.Name == "" {
+			return output, fmt.Errorf("the name of the query parameter to %s is empty", edit.Operation)
+		}
+
+		switch strings.ToLower(edit.Operation) {
+		case internet.UrlQuerySet:
+			parameters = setQueryParameter(parameters, edit.Name, edit.Value)
+		case internet.UrlQueryAdd:
+			parameters = append(parameters, newQueryParameter(edit.Name, edit.Value))
+		case internet.UrlQueryDelete:
+			parameters = filterQuery(parameters, func(p queryParameter) bool { return p.name != edit.Name })
+		default:
+			return output, fmt.Errorf("unsupported query operation %q, use set, add or delete", edit.Operation)
+		}
+	}
+
+	u.RawQuery = formatQuery(parameters)
+	u.ForceQuery = false
+	output = internet.EditUrlQueryOutput{
+		Url:   u.String(),
+		Query: queryOutput(parameters),
+	}
+
+	return output, nil
+}
+
+// EncodeUrlComponent percent-encodes a value to be used as a component
+// of a URL.
+func (s *Service) EncodeUrlComponent(value string, component string) (internet.UrlComponentOutput, error) {
+	return s.EncodeUrlComponentContext(context.Background(), value, component)
+}
+
+func (s *Service) EncodeUrlComponentContext(ctx context.Context, value string, component string) (internet.UrlComponentOutput, error) {
+	output := internet.UrlComponentOutput{}
+
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
+	component = strings.ToLower(component)
+	allowed, ok := urlComponents[component]
+	if !ok {
+		return output, unsupportedUrlComponent(component)
+	}
+
+	output = internet.UrlComponentOutput{
+		Component: component,
+		Encoded:   percentEncode(value, allowed, component == internet.UrlComponentQuery),
+		Decoded:   value,
+	}
+
+	return output, nil
+}
+
+// DecodeUrlComponent decodes a percent-encoded component of a URL.
+func (s *Service) DecodeUrlComponent(value string, component string) (internet.UrlComponentOutput, error) {
+	return s.DecodeUrlComponentContext(context.Background(), value, component)
+}
+
+func (s *Service) DecodeUrlComponentContext(ctx context.Context, value string, component string) (internet.UrlComponentOutput, error) {
+	output := internet.UrlComponentOutput{}
+
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
+	component = strings.ToLower(component)
+	if _, ok := urlComponents[component]; !ok {
+		return output, unsupportedUrlComponent(component)
+	}
+
+	var decoded string
+	var err error
+	if component == internet.UrlComponentQuery {
+		decoded, err = url.QueryUnescape(value)
+	} else {
+		decoded, err = url.PathUnescape(value)
+	}
+	if err != nil {
+		return output, &internet.InvalidUrlError{Url: value, Reason: err.Error()}
+	}
+
+	output = internet.UrlComponentOutput{
+		Component: component,
+		Encoded:   value,
+		Decoded:   decoded,
+	}
+
+	return output, nil
+}
+
+// NormalizeUrl converts a URL to its canonical form, following RFC 3986,
+// section 6: the scheme and host are lowercased, internationalized hosts
+// are encoded in punycode, percent-encodings are uppercased or decoded
+// if not needed, dot segments are removed and so is the default port of
+// the scheme.
+func (s *Service) NormalizeUrl(rawUrl string) (internet.NormalizeUrlOutput, error) {
+	return s.NormalizeUrlContext(context.Background(), rawUrl)
+}
+
+func (s *Service) NormalizeUrlContext(ctx context.Context, rawUrl string) (internet.NormalizeUrlOutput, error) {
+	output := internet.NormalizeUrlOutput{}
+
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
+	u, err := parseUrl(rawUrl)
+	if err != nil {
+		return output, err
+	}
+	if !u.IsAbs() {
+		return output, &internet.InvalidUrlError{Url: rawUrl, Reason: "the url has no scheme"}
+	}
+
+	var builder strings.Builder
+	builder.WriteString(u.Scheme)
+	builder.WriteString(":")
+
+	if u.Opaque != "" {
+		builder.WriteString(normalizePercentEncoding(u.Opaque))
+	} else {
+		host, err := normalizeHost(u)
+		if err != nil {
+			return output, &internet.InvalidUrlError{Url: rawUrl, Reason: err.Error()}
+		}
+		unicodeHost, err := hostToUnicode(host)
+		if err != nil {
+			return output, &internet.InvalidUrlError{Url: rawUrl, Reason: err.Error()}
+		}
+		output.Host = host
+		output.UnicodeHost = unicodeHost
+
+		path := removeDotSegments(normalizePercentEncoding(u.EscapedPath()))
+		// an empty authority, as in file:///tmp, is kept
+		if u.Host != "" || strings.HasPrefix(rawUrl[len(u.Scheme)+1:], "//") {
+			builder.WriteString("//")
+			if u.User != nil {
+				builder.WriteString(normalizePercentEncoding(u.User.String()))
+				builder.WriteString("@")
+			}
+			if strings.Contains(host, ":") {
+				host = "[" + host + "]"
+			}
+			builder.WriteString(host)
+			if port := u.Port(); port != "" && port != defaultPorts[u.Scheme] {
+				builder.WriteString(":" + port)
+			}
+			if path == "" {
+				path = "/"
+			}
+		}
+		builder.WriteString(path)
+	}
+
+	if u.RawQuery != "" {
+		builder.WriteString("?" + normalizePercentEncoding(u.RawQuery))
+	}
+	if u.Fragment != "" {
+		builder.WriteString("#" + normalizePercentEncoding(u.EscapedFragment()))
+	}
+
+	output.Url = builder.String()
+	output.Changed = output.Url != rawUrl
+
+	return output, nil
+}
+
+// StripUrlTracking removes the query parameters used to track where the
+// visitors of a page came from, like the utm_* of Google Analytics and
+// the click IDs of advertising networks.
+func (s *Service) StripUrlTracking(rawUrl string) (internet.StripUrlTrackingOutput, error) {
+	return s.StripUrlTrackingContext(context.Background(), rawUrl)
+}
+
+func (s *Service) StripUrlTrackingContext(ctx context.Context, rawUrl string) (internet.StripUrlTrackingOutput, error) {
+	output := internet.StripUrlTrackingOutput{}
+
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
+	u, err := parseUrl(rawUrl)
+	if err != nil {
+		return output, err
+	}
+
+	parameters, err := parseQuery(rawUrl, u.RawQuery)
+	if err != nil {
+		return output, err
+	}
+
+	removed := []string{}
+	parameters = filterQuery(parameters, func(p queryParameter) bool {
+		if isTrackingParameter(p.name) {
+			removed = append(removed, p.name)
+			return false
+		}
+		return true
+	})
+
+	if len(removed) > 0 {
+		u.RawQuery = formatQuery(parameters)
+		u.ForceQuery = false
+		rawUrl = u.String()
+	}
+	output = internet.StripUrlTrackingOutput{
+		Url:     rawUrl,
+		Removed: removed,
+	}
+
+	return output, nil
+}
+
+// ResolveUrl resolves a reference, like a link of a page, relative to the
+// base URL it was found at, as in RFC 3986, section 5.
+func (s *Service) ResolveUrl(base string, reference string) (internet.ResolveUrlOutput, error) {
+	return s.ResolveUrlContext(context.Background(), base, reference)
+}
+
+func (s *Service) ResolveUrlContext(ctx context.Context, base string, reference string) (internet.ResolveUrlOutput, error) {
+	output := internet.ResolveUrlOutput{}
+
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
+	baseUrl, err := parseUrl(base)
+	if err != nil {
+		return output, err
+	}
+	if !baseUrl.IsAbs() {
+		return output, &internet.InvalidUrlError{Url: base, Reason: "the base url is not absolute"}
+	}
+
+	referenceUrl, err := parseUrl(reference)
+	if err != nil {
+		return output, err
+	}
+
+	output.Url = baseUrl.ResolveReference(referenceUrl).String()
+
+	return output, nil
+}
+
+// parseUrl parses a URL, returning an InvalidUrlError if it is not valid.
+func parseUrl(rawUrl string) (*url.URL, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		reason := err.Error()
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			reason = urlErr.Err.Error()
+		}
+		return nil, &internet.InvalidUrlError{Url: rawUrl, Reason: reason}
+	}
+
+	return u, nil
+}
+
+// queryParameter is a decoded query parameter with the text it was
+// parsed from, to write it back as it was if not changed.
+type queryParameter struct {
+	name  string
+	value string
+	raw   string
+}
+
+func newQueryParameter(name, value string) queryParameter {
+	return queryParameter{
+		name:  name,
+		value: value,
+		raw:   url.QueryEscape(name) + "=" + url.QueryEscape(value),
+	}
+}
+
+// parseQuery parses the query of a URL into its parameters, in order,
+// unlike url.ParseQuery which groups them by name.
+func parseQuery(rawUrl string, rawQuery string) ([]queryParameter, error) {
+	parameters := []queryParameter{}
+	for _, raw := range strings.Split(rawQuery, "&") {
+		if raw == "" {
+			continue
+		}
+
+		rawName, rawValue := raw, ""
+		if i := strings.IndexByte(raw, '='); i >= 0 {
+			rawName, rawValue = raw[:i], raw[i+1:]
+		}
+
+		name, err := url.QueryUnescape(rawName)
+		if err != nil {
+			return nil, &internet.InvalidUrlError{Url: rawUrl, Reason: fmt.Sprintf("query parameter %q: %s", raw, err.Error())}
+		}
+		value, err := url.QueryUnescape(rawValue)
+		if err != nil {
+			return nil, &internet.InvalidUrlError{Url: rawUrl, Reason: fmt.Sprintf("query parameter %q: %s", raw, err.Error())}
+		}
+
+		parameters = append(parameters, queryParameter{name: name, value: value, raw: raw})
+	}
+
+	return parameters, nil
+}
+
+func formatQuery(parameters []queryParameter) string {
+	raw := make([]string, len(parameters))
+	for i, p := range parameters {
+		raw[i] = p.raw
+	}
+
+	return strings.Join(raw, "&")
+}
+
+func queryOutput(parameters []queryParameter) []internet.UrlQueryParameter {
+	output := make([]internet.UrlQueryParameter, len(parameters))
+	for i, p := range parameters {
+		output[i] = internet.UrlQueryParameter{Name: p.name, Value: p.value}
+	}
+
+	return output
+}
+
+// setQueryParameter replaces the value of the first parameter named name,
+// where it is, removing the others, or adds it at the end.
+func setQueryParameter(parameters []queryParameter, name, value string) []queryParameter {
+	edited := []queryParameter{}
+	found := false
+	for _, p := range parameters {
+		if p.name != name {
+			edited = append(edited, p)
+		} else if !found {
+			edited = append(edited, newQueryParameter(name, value))
+			found = true
+		}
+	}
+	if !found {
+		edited = append(edited, newQueryParameter(name, value))
+	}
+
+	return edited
+}
+
+func filterQuery(parameters []queryParameter, keep func(queryParameter) bool) []queryParameter {
+	filtered := []queryParameter{}
+	for _, p := range parameters {
+		if keep(p) {
+			filtered = append(filtered, p)
+		}
+	}
+
+	return filtered
+}
+
+// trackingParameters are the query parameters used for tracking, besides
+// the ones starting with utm_.
+var trackingParameters = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"gclsrc":  true,
+	"dclid":   true,
+	"gbraid":  true,
+	"wbraid":  true,
+	"msclkid": true,
+	"yclid":   true,
+	"twclid":  true,
+	"igshid":  true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"_hsenc":  true,
+	"_hsmi":   true,
+	"mkt_tok": true,
+}
+
+func isTrackingParameter(name string) bool {
+	name = strings.ToLower(name)
+	return strings.HasPrefix(name, "utm_") || trackingParameters[name]
+}
+
+// defaultPorts has the port of the schemes where it can be omitted.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"ws":    "80",
+	"wss":   "443",
+	"ftp":   "21",
+}
+
+// normalizeHost returns the host of a URL lowercased and, if it is a
+// domain, in ASCII.
+func normalizeHost(u *url.URL) (string, error) {
+	host := u.Hostname()
+	if host == "" || net.ParseIP(host) != nil {
+		return strings.ToLower(host), nil
+	}
+
+	return hostToAscii(host)
+}
+
+// removeDotSegments removes the . and .. segments of a path, as in RFC
+// 3986, section 5.2.4.
+func removeDotSegments(path string) string {
+	if !strings.Contains(path, ".") {
+		return path
+	}
+
+	input := path
+	output := []string{}
+	for input != "" {
+		switch {
+		case strings.HasPrefix(input, "../"):
+			input = input[3:]
+		case strings.HasPrefix(input, "./"):
+			input = input[2:]
+		case strings.HasPrefix(input, "/./"):
+			input = input[2:]
+		case input == "/.":
+			input = "/"
+		case strings.HasPrefix(input, "/../"):
+			input = input[3:]
+			if len(output) > 0 {
+				output = output[:len(output)-1]
+			}
+		case input == "/..":
+			input = "/"
+			if len(output) > 0 {
+				output = output[:len(output)-1]
+			}
+		case input == "." || input == "..":
+			input = ""
+		default:
+			// moves the first segment, with its leading slash, to the
+			// output
+			end := strings.IndexByte(input[1:], '/') + 1
+			if end == 0 {
+				end = len(input)
+			}
+			output = append(output, input[:end])
+			input = input[end:]
+		}
+	}
+
+	return strings.Join(output, "")
+}
+
+const upperHex = "0123456789ABCDEF"
+
+// normalizePercentEncoding uppercases the hexadecimal digits of the
+// percent-encodings and decodes the ones of unreserved characters.
+func normalizePercentEncoding(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+
+	var builder strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' || i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+			builder.WriteByte(s[i])
+			continue
+		}
+
+		c := unhex(s[i+1])<<4 | unhex(s[i+2])
+		if isUnreserved(c) {
+			builder.WriteByte(c)
+		} else {
+			builder.WriteByte('%')
+			builder.WriteByte(upperHex[c>>4])
+			builder.WriteByte(upperHex[c&15])
+		}
+		i += 2
+	}
+
+	return builder.String()
+}
+
+func isHex(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func unhex(c byte) byte {
+	switch {
+	case isDigit(c):
+		return c - '0'
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10
+	}
+
+	return c - 'A' + 10
+}
+
+// isUnreserved tells if a character is unreserved, never needing to be
+// percent-encoded, as in RFC 3986, section 2.3.
+func isUnreserved(c byte) bool {
+	return isAsciiLetter(c) || isDigit(c) || c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func isSubDelim(c byte) bool {
+	return strings.IndexByte("!$&'()*+,;=", c) >= 0
+}
+
+// urlComponents has, for each component, the characters that are not
+// percent-encoded in it, from RFC 3986, section 3.
+var urlComponents = map[string]func(c byte) bool{
+	internet.UrlComponentQuery: isUnreserved,
+	internet.UrlComponentPath: func(c byte) bool {
+		return isPathChar(c) || c == '/'
+	},
+	internet.UrlComponentSegment: isPathChar,
+	internet.UrlComponentFragment: func(c byte) bool {
+		return isPathChar(c) || c == '/' || c == '?'
+	},
+	internet.UrlComponentUserinfo: func(c byte) bool {
+		return isUnreserved(c) || isSubDelim(c) || c == ':'
+	},
+}
+
+// isPathChar tells if a character can be in a path segment, the pchar of
+// RFC 3986.
+func isPathChar(c byte) bool {
+	return isUnreserved(c) || isSubDelim(c) || c == ':' || c == '@'
+}
+
+func unsupportedUrlComponent(component string) error {
+	return fmt.Errorf("unsupported url component %q, use query, path, segment, fragment or userinfo", component)
+}
+
+// percentEncode encodes the bytes of s that are not allowed, with spaces
+// as + if form is true.
+func percentEncode(s string, allowed func(c byte) bool, form bool) string {
+	var builder strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case allowed(c):
+			builder.WriteByte(c)
+		case form && c == ' ':
+			builder.WriteByte('+')
+		default:
+			builder.WriteByte('%')
+			builder.WriteByte(upperHex[c>>4])
+			builder.WriteByte(upperHex[c&15])
+		}
+	}
+
+	return builder.String()
+}
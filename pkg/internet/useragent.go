@@ -0,0 +1,43 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"strings"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+// ParseUserAgent detects the browser, engine, operating system and
+// device of a User-Agent header, and if it is a crawler.
+func (s *Service) ParseUserAgent(userAgent string) (internet.ParseUserAgentOutput, error) {
+	return s.ParseUserAgentContext(context.Background(), userAgent)
+}
+
+func (s *Service) ParseUserAgentContext(ctx context.Context, userAgent string) (internet.ParseUserAgentOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return internet.ParseUserAgentOutput{}, err
+	}
+
+	rules, err := s.userAgentRules()
+	if err != nil {
+		return internet.ParseUserAgentOutput{}, err
+	}
+
+	return rules.Parse(strings.TrimSpace(userAgent)), nil
+}
@@ -0,0 +1,415 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"container/list"
+	_ "embed"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"sync"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultUserAgentCacheSize is how many User-Agents the rules keep
+// parsed, as access logs repeat a few of them over and over.
+const defaultUserAgentCacheSize = 10000
+
+// defaultUserAgentRulesYaml are the rules used by default, in the
+// format of uap-core.
+//
+//go:embed useragent_rules.yaml
+var defaultUserAgentRulesYaml []byte
+
+var (
+	defaultUserAgentRulesOnce  sync.Once
+	defaultUserAgentRules      *UserAgentRules
+	defaultUserAgentRulesError error
+)
+
+// userAgentRulesFile is a file of rules in the format of the
+// regexes.yaml of uap-core, https://github.com/ua-parser/uap-core, with
+// two more lists: engine_parsers, detecting the browser engine, and
+// device_type_parsers, detecting the type of device.
+type userAgentRulesFile struct {
+	UserAgentParsers  []userAgentRuleEntry `yaml:"user_agent_parsers"`
+	OsParsers         []userAgentRuleEntry `yaml:"os_parsers"`
+	DeviceParsers     []userAgentRuleEntry `yaml:"device_parsers"`
+	EngineParsers     []userAgentRuleEntry `yaml:"engine_parsers"`
+	DeviceTypeParsers []userAgentRuleEntry `yaml:"device_type_parsers"`
+}
+
+// userAgentRuleEntry is a rule of any of the lists, each list using
+// its own replacements.
+type userAgentRuleEntry struct {
+	Regex     string `yaml:"regex"`
+	RegexFlag string `yaml:"regex_flag"`
+
+	FamilyReplacement string `yaml:"family_replacement"`
+	V1Replacement     string `yaml:"v1_replacement"`
+	V2Replacement     string `yaml:"v2_replacement"`
+	V3Replacement     string `yaml:"v3_replacement"`
+	V4Replacement     string `yaml:"v4_replacement"`
+
+	OsReplacement   string `yaml:"os_replacement"`
+	OsV1Replacement string `yaml:"os_v1_replacement"`
+	OsV2Replacement string `yaml:"os_v2_replacement"`
+	OsV3Replacement string `yaml:"os_v3_replacement"`
+	OsV4Replacement string `yaml:"os_v4_replacement"`
+
+	DeviceReplacement string `yaml:"device_replacement"`
+	BrandReplacement  string `yaml:"brand_replacement"`
+	ModelReplacement  string `yaml:"model_replacement"`
+
+	EngineReplacement   string `yaml:"engine_replacement"`
+	EngineV1Replacement string `yaml:"engine_v1_replacement"`
+	EngineV2Replacement string `yaml:"engine_v2_replacement"`
+	EngineV3Replacement string `yaml:"engine_v3_replacement"`
+
+	DeviceType string `yaml:"device_type"`
+}
+
+// userAgentRule is a compiled rule. Each of its values is its
+// replacement, with $1 to $9 replaced by the groups matched, or, if it
+// has none, the group of its default.
+type userAgentRule struct {
+	regex *regexp.Regexp
+	// literals are the texts, in lower case, one of which any match
+	// contains, nil if unknown
+	literals     []string
+	replacements []string
+	// defaults are the group of each value without replacement, zero
+	// for none
+	defaults []int
+}
+
+// match returns the values of the rule for a User-Agent, if it matches.
+// The regex only runs if the User-Agent, in lower case, has one of the
+// literals of the rule, as most rules do not match most User-Agents.
+func (r userAgentRule) match(userAgent string, lower string) ([]string, bool) {
+	if r.literals != nil && !containsAny(lower, r.literals) {
+		return nil, false
+	}
+
+	indexes := r.regex.FindStringSubmatchIndex(userAgent)
+	if indexes == nil {
+		return nil, false
+	}
+
+	group := func(n int) string {
+		if 2*n+1 >= len(indexes) || indexes[2*n] < 0 {
+			return ""
+		}
+		return userAgent[indexes[2*n]:indexes[2*n+1]]
+	}
+
+	values := make([]string, len(r.replacements))
+	for i, replacement := range r.replacements {
+		if replacement == "" {
+			if r.defaults[i] > 0 {
+				values[i] = strings.TrimSpace(group(r.defaults[i]))
+			}
+			continue
+		}
+
+		value := strings.Builder{}
+		for j := 0; j < len(replacement); j++ {
+			if replacement[j] == '$' && j+1 < len(replacement) && replacement[j+1] >= '1' && replacement[j+1] <= '9' {
+				value.WriteString(group(int(replacement[j+1] - '0')))
+				j++
+				continue
+			}
+			value.WriteByte(replacement[j])
+		}
+		values[i] = strings.TrimSpace(value.String())
+	}
+
+	return values, true
+}
+
+// UserAgentRules are the rules detecting the browser, engine, operating
+// system and device of User-Agents. They are safe for concurrent use
+// and keep the last 10000 User-Agents parsed, so the ones repeated in
+// access logs are parsed once.
+type UserAgentRules struct {
+	browsers    []userAgentRule
+	oses        []userAgentRule
+	devices     []userAgentRule
+	engines     []userAgentRule
+	deviceTypes []userAgentRule
+
+	capacity int
+	mutex    sync.Mutex
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+// userAgentCacheEntry is a User-Agent parsed.
+type userAgentCacheEntry struct {
+	userAgent string
+	output    internet.ParseUserAgentOutput
+}
+
+// NewUserAgentRules compiles rules in the format of the regexes.yaml of
+// uap-core, so its latest version can be used, with the engine_parsers
+// and device_type_parsers lists of the rules embedded.
+func NewUserAgentRules(data []byte) (*UserAgentRules, error) {
+	file := userAgentRulesFile{}
+	err := yaml.Unmarshal(data, &file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading user agent rules: %w", err)
+	}
+
+	rules := &UserAgentRules{
+		capacity: defaultUserAgentCacheSize,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+	}
+
+	compile := func(name string, entries []userAgentRuleEntry, defaults []int, replacements func(userAgentRuleEntry) []string) ([]userAgentRule, error) {
+		compiled := []userAgentRule{}
+		for i, entry := range entries {
+			expression := entry.Regex
+			if entry.RegexFlag == "i" {
+				expression = "(?i)" + expression
+			}
+			regex, err := regexp.Compile(expression)
+			if err != nil {
+				return nil, fmt.Errorf("error compiling rule %d of %s: %w", i+1, name, err)
+			}
+			rule := userAgentRule{regex: regex, replacements: replacements(entry), defaults: defaults}
+			if tree, err := syntax.Parse(expression, syntax.Perl); err == nil {
+				rule.literals = requiredLiterals(tree.Simplify())
+			}
+			compiled = append(compiled, rule)
+		}
+		return compiled, nil
+	}
+
+	rules.browsers, err = compile("user_agent_parsers", file.UserAgentParsers, []int{1, 2, 3, 4, 5}, func(e userAgentRuleEntry) []string {
+		return []string{e.FamilyReplacement, e.V1Replacement, e.V2Replacement, e.V3Replacement, e.V4Replacement}
+	})
+	if err != nil {
+		return nil, err
+	}
+	rules.oses, err = compile("os_parsers", file.OsParsers, []int{1, 2, 3, 4, 5}, func(e userAgentRuleEntry) []string {
+		return []string{e.OsReplacement, e.OsV1Replacement, e.OsV2Replacement, e.OsV3Replacement, e.OsV4Replacement}
+	})
+	if err != nil {
+		return nil, err
+	}
+	rules.devices, err = compile("device_parsers", file.DeviceParsers, []int{1, 0, 1}, func(e userAgentRuleEntry) []string {
+		return []string{e.DeviceReplacement, e.BrandReplacement, e.ModelReplacement}
+	})
+	if err != nil {
+		return nil, err
+	}
+	rules.engines, err = compile("engine_parsers", file.EngineParsers, []int{1, 2, 3, 4}, func(e userAgentRuleEntry) []string {
+		return []string{e.EngineReplacement, e.EngineV1Replacement, e.EngineV2Replacement, e.EngineV3Replacement}
+	})
+	if err != nil {
+		return nil, err
+	}
+	rules.deviceTypes, err = compile("device_type_parsers", file.DeviceTypeParsers, []int{0}, func(e userAgentRuleEntry) []string {
+		return []string{e.DeviceType}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// DefaultUserAgentRules returns the rules embedded, compiled once.
+func DefaultUserAgentRules() (*UserAgentRules, error) {
+	defaultUserAgentRulesOnce.Do(func() {
+		defaultUserAgentRules, defaultUserAgentRulesError = NewUserAgentRules(defaultUserAgentRulesYaml)
+	})
+
+	return defaultUserAgentRules, defaultUserAgentRulesError
+}
+
+// Parse detects the browser, engine, operating system and device of a
+// User-Agent, in the order of the rules, the first one matching
+// winning.
+func (r *UserAgentRules) Parse(userAgent string) internet.ParseUserAgentOutput {
+	if output, ok := r.cached(userAgent); ok {
+		return output
+	}
+
+	lower := strings.ToLower(userAgent)
+	output := internet.ParseUserAgentOutput{
+		Browser: userAgentComponent(r.browsers, userAgent, lower),
+		Engine:  userAgentComponent(r.engines, userAgent, lower),
+		Os:      userAgentComponent(r.oses, userAgent, lower),
+		Device:  internet.UserAgentDevice{Family: "Other", Type: internet.UserAgentDeviceOther},
+	}
+
+	if values, ok := firstUserAgentMatch(r.devices, userAgent, lower); ok {
+		output.Device.Family = firstNonEmpty(values[0], "Other")
+		output.Device.Brand = values[1]
+		output.Device.Model = values[2]
+	}
+	// Spider is the device of the crawlers in uap-core
+	if output.Device.Family == "Spider" {
+		output.Device.Type = internet.UserAgentDeviceBot
+	} else if values, ok := firstUserAgentMatch(r.deviceTypes, userAgent, lower); ok && values[0] != "" {
+		output.Device.Type = values[0]
+	}
+	output.IsBot = output.Device.Type == internet.UserAgentDeviceBot
+
+	r.cache(userAgent, output)
+	return output
+}
+
+// firstUserAgentMatch returns the values of the first rule matching a
+// User-Agent.
+func firstUserAgentMatch(rules []userAgentRule, userAgent string, lower string) ([]string, bool) {
+	for _, rule := range rules {
+		if values, ok := rule.match(userAgent, lower); ok {
+			return values, true
+		}
+	}
+
+	return nil, false
+}
+
+// userAgentComponent returns the family and version of the first rule
+// matching a User-Agent, Other if none does.
+func userAgentComponent(rules []userAgentRule, userAgent string, lower string) internet.UserAgentComponent {
+	component := internet.UserAgentComponent{Family: "Other"}
+
+	values, ok := firstUserAgentMatch(rules, userAgent, lower)
+	if !ok {
+		return component
+	}
+
+	component.Family = firstNonEmpty(values[0], "Other")
+	parts := []*string{&component.Major, &component.Minor, &component.Patch, &component.PatchMinor}
+	version := []string{}
+	for i, value := range values[1:] {
+		if value == "" {
+			break
+		}
+		*parts[i] = value
+		version = append(version, value)
+	}
+	component.Version = strings.Join(version, ".")
+
+	return component
+}
+
+// requiredLiterals returns texts, in lower case, one of which any match
+// of a regex contains, or nil if there are none, like for .* or \d+.
+func requiredLiterals(re *syntax.Regexp) []string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		literal := strings.ToLower(string(re.Rune))
+		if literal == "" {
+			return nil
+		}
+		return []string{literal}
+	case syntax.OpCapture, syntax.OpPlus:
+		return requiredLiterals(re.Sub[0])
+	case syntax.OpRepeat:
+		if re.Min == 0 {
+			return nil
+		}
+		return requiredLiterals(re.Sub[0])
+	case syntax.OpAlternate:
+		literals := []string{}
+		for _, sub := range re.Sub {
+			subLiterals := requiredLiterals(sub)
+			if subLiterals == nil {
+				return nil
+			}
+			literals = append(literals, subLiterals...)
+		}
+		return literals
+	case syntax.OpConcat:
+		// the literals of the part with the longest shortest literal, as
+		// longer literals are found less often
+		var best []string
+		bestLength := 0
+		for _, sub := range re.Sub {
+			subLiterals := requiredLiterals(sub)
+			if subLiterals == nil {
+				continue
+			}
+			length := len(subLiterals[0])
+			for _, literal := range subLiterals[1:] {
+				if len(literal) < length {
+					length = len(literal)
+				}
+			}
+			if length > bestLength {
+				best, bestLength = subLiterals, length
+			}
+		}
+		return best
+	}
+
+	return nil
+}
+
+// containsAny tells if a text contains any of the literals.
+func containsAny(text string, literals []string) bool {
+	for _, literal := range literals {
+		if strings.Contains(text, literal) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *UserAgentRules) cached(userAgent string) (internet.ParseUserAgentOutput, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	element, ok := r.entries[userAgent]
+	if !ok {
+		return internet.ParseUserAgentOutput{}, false
+	}
+
+	r.order.MoveToFront(element)
+	return element.Value.(userAgentCacheEntry).output, true
+}
+
+func (r *UserAgentRules) cache(userAgent string, output internet.ParseUserAgentOutput) {
+	if r.capacity <= 0 {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.entries[userAgent]; ok {
+		return
+	}
+
+	r.entries[userAgent] = r.order.PushFront(userAgentCacheEntry{userAgent: userAgent, output: output})
+	for r.order.Len() > r.capacity {
+		oldest := r.order.Back()
+		r.order.Remove(oldest)
+		delete(r.entries, oldest.Value.(userAgentCacheEntry).userAgent)
+	}
+}
@@ -0,0 +1,196 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUserAgentRulesInvalid(t *testing.T) {
+	testCases := []struct {
+		name  string
+		rules string
+		err   string
+	}{
+		{"not yaml", "user_agent_parsers: [", "error reading user agent rules: yaml: line 1: did not find expected node content"},
+		{"bad regex", "os_parsers:\n  - regex: 'a'\n  - regex: '(b'\n", "error compiling rule 2 of os_parsers: error parsing regexp: missing closing ): `(b`"},
+		{"lookahead", "device_parsers:\n  - regex: 'a(?!b)'\n", "error compiling rule 1 of device_parsers: error parsing regexp: invalid or unsupported Perl syntax: `(?!`"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			_, err := NewUserAgentRules([]byte(tc.rules))
+
+			// assert
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestUserAgentRuleReplacements(t *testing.T) {
+	// arrange
+	rules, err := NewUserAgentRules([]byte(`
+os_parsers:
+  - regex: '(Haiku) R(\d+)'
+    os_v2_replacement: 'beta $2'
+device_parsers:
+  - regex: 'Model (\w+) by (\w+)'
+    regex_flag: 'i'
+    device_replacement: '$2 $1 $9'
+    brand_replacement: '$2'
+  - regex: 'Unbranded (\w+)'
+`))
+	assert.Nil(t, err)
+
+	// act
+	haiku := rules.Parse("Mozilla/5.0 (Haiku R1; x86_64) model X1 BY Acme")
+	unbranded := rules.Parse("Unbranded Z2")
+
+	// assert
+	assert.Equal(t, component("Haiku", "1", "beta 1", "", ""), haiku.Os)
+	assert.Equal(t, "Acme X1", haiku.Device.Family)
+	assert.Equal(t, "Acme", haiku.Device.Brand)
+	assert.Equal(t, "X1", haiku.Device.Model)
+	assert.Equal(t, "Z2", unbranded.Device.Family)
+	assert.Equal(t, "", unbranded.Device.Brand)
+	assert.Equal(t, "Z2", unbranded.Device.Model)
+}
+
+func TestUserAgentRulesCache(t *testing.T) {
+	// arrange
+	rules, err := NewUserAgentRules(defaultUserAgentRulesYaml)
+	assert.Nil(t, err)
+	rules.capacity = 2
+
+	// act
+	first := rules.Parse("curl/8.4.0")
+	rules.Parse("Wget/1.21.4")
+	rules.Parse("curl/8.4.0")
+	rules.Parse("python-requests/2.31.0")
+
+	// assert
+	assert.Equal(t, first, rules.Parse("curl/8.4.0"))
+	assert.Equal(t, 2, rules.order.Len())
+	_, wget := rules.entries["Wget/1.21.4"]
+	assert.False(t, wget)
+}
+
+func TestUserAgentRulesConcurrent(t *testing.T) {
+	// arrange
+	rules, err := NewUserAgentRules(defaultUserAgentRulesYaml)
+	assert.Nil(t, err)
+	rules.capacity = 8
+
+	// act
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				rules.Parse(fmt.Sprintf("curl/8.%d.%d", i, j%16))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// assert
+	assert.Equal(t, 8, rules.order.Len())
+	assert.Equal(t, "8.3.7", rules.Parse("curl/8.3.7").Browser.Version)
+}
+
+func TestRequiredLiterals(t *testing.T) {
+	testCases := []struct {
+		regex    string
+		expected []string
+	}{
+		{`(Chrome)/(\d+)\.(\d+)`, []string{"chrome"}},
+		{`(?i)(?:bot|crawler)(?:[/ ]|$)`, []string{"bot", "crawler"}},
+		{`(Googlebot|bingbot)/(\d+)`, []string{"googlebot", "bingbot"}},
+		{`Windows NT 10\.0`, []string{"windows nt 10.0"}},
+		{`(iPhone|iPad).*Version/(\d+)`, []string{"version/"}},
+		{`; *KF[A-Z]{2,4}`, []string{"kf"}},
+		{`(?:Mac)?Book`, []string{"book"}},
+		{`(?:Mac|)OS`, []string{"os"}},
+		{`\d+\.\d+`, []string{"."}},
+		{`\d+`, nil},
+		{`(?:Edge)?`, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.regex, func(t *testing.T) {
+			// arrange
+			tree, err := syntax.Parse(tc.regex, syntax.Perl)
+			assert.Nil(t, err)
+
+			// act
+			literals := requiredLiterals(tree.Simplify())
+
+			// assert
+			assert.ElementsMatch(t, tc.expected, literals)
+		})
+	}
+}
+
+// benchmarkUserAgents are User-Agents common in access logs.
+var benchmarkUserAgents = []string{
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.109 Safari/537.36",
+	"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1.2 Mobile/15E148 Safari/604.1",
+	"Mozilla/5.0 (Linux; Android 14; Pixel 8 Pro) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Mobile Safari/537.36",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15",
+	"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+	"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+	"curl/8.4.0",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+}
+
+// BenchmarkUserAgentRulesParse parses User-Agents as they repeat in
+// access logs, mostly from the cache.
+func BenchmarkUserAgentRulesParse(b *testing.B) {
+	rules, err := NewUserAgentRules(defaultUserAgentRulesYaml)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			rules.Parse(benchmarkUserAgents[i%len(benchmarkUserAgents)])
+		}
+	})
+}
+
+// BenchmarkUserAgentRulesParseUncached parses User-Agents never seen,
+// matching every rule.
+func BenchmarkUserAgentRulesParseUncached(b *testing.B) {
+	rules, err := NewUserAgentRules(defaultUserAgentRulesYaml)
+	if err != nil {
+		b.Fatal(err)
+	}
+	rules.capacity = 0
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rules.Parse(benchmarkUserAgents[i%len(benchmarkUserAgents)])
+	}
+}
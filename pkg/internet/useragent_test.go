@@ -0,0 +1,256 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+// component returns a UserAgentComponent with a version.
+func component(family, major, minor, patch, patchMinor string) internet.UserAgentComponent {
+	c := internet.UserAgentComponent{Family: family, Major: major, Minor: minor, Patch: patch, PatchMinor: patchMinor}
+	for _, part := range []string{major, minor, patch, patchMinor} {
+		if part == "" {
+			break
+		}
+		if c.Version != "" {
+			c.Version += "."
+		}
+		c.Version += part
+	}
+
+	return c
+}
+
+func TestParseUserAgent(t *testing.T) {
+	other := component("Other", "", "", "", "")
+	testCases := []struct {
+		name      string
+		userAgent string
+		expected  internet.ParseUserAgentOutput
+	}{
+		{
+			name:      "chrome on windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.109 Safari/537.36",
+			expected: internet.ParseUserAgentOutput{
+				Browser: component("Chrome", "120", "0", "6099", "109"),
+				Engine:  component("Blink", "120", "0", "6099", ""),
+				Os:      component("Windows", "10", "", "", ""),
+				Device:  internet.UserAgentDevice{Family: "Other", Type: internet.UserAgentDeviceDesktop},
+			},
+		},
+		{
+			name:      "safari on mac",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15",
+			expected: internet.ParseUserAgentOutput{
+				Browser: component("Safari", "17", "1", "", ""),
+				Engine:  component("WebKit", "605", "1", "15", ""),
+				Os:      component("Mac OS X", "10", "15", "7", ""),
+				Device:  internet.UserAgentDevice{Family: "Mac", Brand: "Apple", Model: "Mac", Type: internet.UserAgentDeviceDesktop},
+			},
+		},
+		{
+			name:      "chrome on iphone",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0.6099.119 Mobile/15E148 Safari/604.1",
+			expected: internet.ParseUserAgentOutput{
+				Browser: component("Chrome Mobile iOS", "120", "0", "6099", "119"),
+				Engine:  component("WebKit", "605", "1", "15", ""),
+				Os:      component("iOS", "17", "1", "", ""),
+				Device:  internet.UserAgentDevice{Family: "iPhone", Brand: "Apple", Model: "iPhone", Type: internet.UserAgentDeviceMobile},
+			},
+		},
+		{
+			name:      "safari on ipad",
+			userAgent: "Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.6 Mobile/15E148 Safari/604.1",
+			expected: internet.ParseUserAgentOutput{
+				Browser: component("Mobile Safari", "16", "6", "", ""),
+				Engine:  component("WebKit", "605", "1", "15", ""),
+				Os:      component("iOS", "16", "6", "", ""),
+				Device:  internet.UserAgentDevice{Family: "iPad", Brand: "Apple", Model: "iPad", Type: internet.UserAgentDeviceTablet},
+			},
+		},
+		{
+			name:      "samsung internet",
+			userAgent: "Mozilla/5.0 (Linux; Android 13; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Mobile Safari/537.36",
+			expected: internet.ParseUserAgentOutput{
+				Browser: component("Samsung Internet", "23", "0", "", ""),
+				Engine:  component("Blink", "115", "0", "0", ""),
+				Os:      component("Android", "13", "", "", ""),
+				Device:  internet.UserAgentDevice{Family: "Samsung SM-S918B", Brand: "Samsung", Model: "SM-S918B", Type: internet.UserAgentDeviceMobile},
+			},
+		},
+		{
+			name:      "android tablet",
+			userAgent: "Mozilla/5.0 (Linux; Android 12; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/119.0.0.0 Safari/537.36",
+			expected: internet.ParseUserAgentOutput{
+				Browser: component("Chrome", "119", "0", "0", "0"),
+				Engine:  component("Blink", "119", "0", "0", ""),
+				Os:      component("Android", "12", "", "", ""),
+				Device:  internet.UserAgentDevice{Family: "Samsung SM-X700", Brand: "Samsung", Model: "SM-X700", Type: internet.UserAgentDeviceTablet},
+			},
+		},
+		{
+			name:      "firefox on ubuntu",
+			userAgent: "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			expected: internet.ParseUserAgentOutput{
+				Browser: component("Firefox", "121", "0", "", ""),
+				Engine:  component("Gecko", "121", "0", "", ""),
+				Os:      component("Ubuntu", "", "", "", ""),
+				Device:  internet.UserAgentDevice{Family: "Other", Type: internet.UserAgentDeviceDesktop},
+			},
+		},
+		{
+			name:      "edge",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+			expected: internet.ParseUserAgentOutput{
+				Browser: component("Edge", "120", "0", "2210", "91"),
+				Engine:  component("Blink", "120", "0", "0", ""),
+				Os:      component("Windows", "10", "", "", ""),
+				Device:  internet.UserAgentDevice{Family: "Other", Type: internet.UserAgentDeviceDesktop},
+			},
+		},
+		{
+			name:      "internet explorer",
+			userAgent: "Mozilla/5.0 (Windows NT 6.1; WOW64; Trident/7.0; rv:11.0) like Gecko",
+			expected: internet.ParseUserAgentOutput{
+				Browser: component("IE", "11", "0", "", ""),
+				Engine:  component("Trident", "7", "0", "", ""),
+				Os:      component("Windows", "7", "", "", ""),
+				Device:  internet.UserAgentDevice{Family: "Other", Type: internet.UserAgentDeviceDesktop},
+			},
+		},
+		{
+			name:      "googlebot smartphone",
+			userAgent: "Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.129 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			expected: internet.ParseUserAgentOutput{
+				Browser: component("Googlebot", "2", "1", "", ""),
+				Engine:  component("Blink", "120", "0", "6099", ""),
+				Os:      component("Android", "6", "0", "1", ""),
+				Device:  internet.UserAgentDevice{Family: "Spider", Brand: "Spider", Model: "Desktop", Type: internet.UserAgentDeviceBot},
+				IsBot:   true,
+			},
+		},
+		{
+			name:      "unknown crawler",
+			userAgent: "MyCustomCrawler/3.2 (+https://example.com)",
+			expected: internet.ParseUserAgentOutput{
+				Browser: component("MyCustomCrawler", "3", "2", "", ""),
+				Engine:  other,
+				Os:      other,
+				Device:  internet.UserAgentDevice{Family: "Spider", Brand: "Spider", Model: "Desktop", Type: internet.UserAgentDeviceBot},
+				IsBot:   true,
+			},
+		},
+		{
+			name:      "device named bot",
+			userAgent: "Mozilla/5.0 (Linux; Android 9; CUBOT X19) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+			expected: internet.ParseUserAgentOutput{
+				Browser: component("Chrome Mobile", "120", "0", "0", "0"),
+				Engine:  component("Blink", "120", "0", "0", ""),
+				Os:      component("Android", "9", "", "", ""),
+				Device:  internet.UserAgentDevice{Family: "CUBOT X19", Brand: "Generic_Android", Model: "CUBOT X19", Type: internet.UserAgentDeviceMobile},
+			},
+		},
+		{
+			name:      "python requests",
+			userAgent: "python-requests/2.31.0",
+			expected: internet.ParseUserAgentOutput{
+				Browser: component("Python Requests", "2", "31", "0", ""),
+				Engine:  other,
+				Os:      other,
+				Device:  internet.UserAgentDevice{Family: "Other", Type: internet.UserAgentDeviceOther},
+			},
+		},
+		{
+			name:      "console",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64; Xbox; Xbox One) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/70.0.3538.102 Safari/537.36 Edge/18.19041",
+			expected: internet.ParseUserAgentOutput{
+				Browser: component("Edge", "18", "19041", "", ""),
+				Engine:  component("EdgeHTML", "18", "19041", "", ""),
+				Os:      component("Windows", "10", "", "", ""),
+				Device:  internet.UserAgentDevice{Family: "Xbox One", Brand: "Microsoft", Model: "Xbox One", Type: internet.UserAgentDeviceConsole},
+			},
+		},
+		{
+			name:      "smart tv",
+			userAgent: "Mozilla/5.0 (SMART-TV; LINUX; Tizen 6.0) AppleWebKit/537.36 (KHTML, like Gecko) 76.0.3809.146/6.0 TV Safari/537.36",
+			expected: internet.ParseUserAgentOutput{
+				Browser: other,
+				Engine:  component("WebKit", "537", "36", "", ""),
+				Os:      component("Tizen", "6", "0", "", ""),
+				Device:  internet.UserAgentDevice{Family: "Samsung SmartTV", Brand: "Samsung", Model: "SmartTV", Type: internet.UserAgentDeviceTv},
+			},
+		},
+		{
+			name:      "empty",
+			userAgent: " - ",
+			expected: internet.ParseUserAgentOutput{
+				Browser: other,
+				Engine:  other,
+				Os:      other,
+				Device:  internet.UserAgentDevice{Family: "Other", Type: internet.UserAgentDeviceOther},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// act
+			output, err := NewService().ParseUserAgent(tc.userAgent)
+
+			// assert
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, output)
+		})
+	}
+}
+
+func TestParseUserAgentWithRules(t *testing.T) {
+	// arrange
+	rules, err := NewUserAgentRules([]byte(`
+user_agent_parsers:
+  - regex: '(canivete)/(\d+)\.(\d+)'
+    family_replacement: 'Canivete CLI'
+`))
+	assert.Nil(t, err)
+	s := NewService(WithUserAgentRules(rules))
+
+	// act
+	output, err := s.ParseUserAgent("canivete/1.4")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, component("Canivete CLI", "1", "4", "", ""), output.Browser)
+	assert.Equal(t, "Other", output.Os.Family)
+	assert.Equal(t, internet.UserAgentDeviceOther, output.Device.Type)
+}
+
+func TestParseUserAgentCanceled(t *testing.T) {
+	// arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// act
+	_, err := NewService().ParseUserAgentContext(ctx, "curl/8.4.0")
+
+	// assert
+	assert.Equal(t, context.Canceled, err)
+}
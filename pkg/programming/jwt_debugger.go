@@ -17,6 +17,7 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 package programming
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -26,8 +27,16 @@ import (
 )
 
 func (p *Service) DebugJwt(tokenString string) (programming.JwtDebuggerOutput, error) {
+	return p.DebugJwtContext(context.Background(), tokenString)
+}
+
+func (p *Service) DebugJwtContext(ctx context.Context, tokenString string) (programming.JwtDebuggerOutput, error) {
 	output := programming.JwtDebuggerOutput{}
 
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+
 	parts := strings.Split(tokenString, ".")
 	if len(parts) != 3 {
 		return output, fmt.Errorf("invalid token - it must contain 3 parts")
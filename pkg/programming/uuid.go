@@ -17,10 +17,21 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 package programming
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/renato0307/canivete-core/interface/programming"
 )
 
 func (p *Service) NewUuid() programming.UuidOutput {
-	return programming.UuidOutput{UUID: uuid.New().String()}
+	output, _ := p.NewUuidContext(context.Background())
+	return output
+}
+
+func (p *Service) NewUuidContext(ctx context.Context) (programming.UuidOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return programming.UuidOutput{}, err
+	}
+
+	return programming.UuidOutput{UUID: uuid.New().String()}, nil
 }